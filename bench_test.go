@@ -0,0 +1,90 @@
+package cliargdax_test
+
+import (
+	"io"
+	"strconv"
+	"testing"
+
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+)
+
+func BenchmarkParse(b *testing.B) {
+	args := []string{"/path/to/app"}
+	for i := 0; i < 50; i++ {
+		args = append(args, "-abc", "--foo=bar", "--baz", "qux", "file")
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ds := cliargdax.NewDaxSrcWithArgs(args)
+		if err := ds.Setup(&noopAsyncGroup{}); err.IsNotOk() {
+			b.Fatal(err.Error())
+		}
+	}
+}
+
+func BenchmarkParseWithManyOpts(b *testing.B) {
+	var optCfgs []cliargs.OptCfg
+	args := []string{"/path/to/app"}
+	for i := 0; i < 50; i++ {
+		name := "opt" + strconv.Itoa(i)
+		optCfgs = append(optCfgs, cliargs.OptCfg{
+			Name: name, HasArg: true, IsArray: true,
+		})
+		args = append(args, "--"+name+"=v", "--"+name, "w", "file")
+	}
+	optCfgs = append(optCfgs,
+		cliargs.OptCfg{Name: "all", Aliases: []string{"a"}},
+		cliargs.OptCfg{Name: "brief", Aliases: []string{"b"}},
+		cliargs.OptCfg{
+			Name: "count", Aliases: []string{"c"}, HasArg: true, IsArray: true,
+		},
+	)
+	for i := 0; i < 50; i++ {
+		args = append(args, "-abc", strconv.Itoa(i))
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(args, optCfgs)
+		if err := ds.Setup(&noopAsyncGroup{}); err.IsNotOk() {
+			b.Fatal(err.Error())
+		}
+	}
+}
+
+func BenchmarkPrintHelp(b *testing.B) {
+	var optCfgs []cliargs.OptCfg
+	for i := 0; i < 100; i++ {
+		name := "option-" + strconv.Itoa(i)
+		optCfgs = append(optCfgs, cliargs.OptCfg{
+			Name:    name,
+			Aliases: []string{"alias-" + strconv.Itoa(i)},
+			HasArg:  i%2 == 0,
+			Desc: "This is the description of " + name + ", which is long " +
+				"enough to be wrapped into multiple lines in the help text.",
+		})
+	}
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"/path/to/app"}, optCfgs,
+	).HelpWidth(80)
+	if err := ds.Setup(&noopAsyncGroup{}); err.IsNotOk() {
+		b.Fatal(err.Error())
+	}
+	dc, err := ds.CreateDaxConn()
+	if err.IsNotOk() {
+		b.Fatal(err.Error())
+	}
+	conn := dc.(*cliargdax.DaxConn)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := conn.HelpTo(io.Discard); err.IsNotOk() {
+			b.Fatal(err.Error())
+		}
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"github.com/sttk/sabi/errs"
+)
+
+type /* error reasons */ (
+	// OptionOutsideBlock is the error reason which indicates that a
+	// sub-option of an option block is given before the first delimiter option
+	// of the block, while the block has no default block.
+	// The fields Option and Block are the sub-option and the delimiter option.
+	OptionOutsideBlock struct {
+		Option string
+		Block  string
+	}
+
+	// BlockOptionMissing is the error reason which indicates that a required
+	// sub-option of an option block is not given in a block.
+	// The fields Option and Block are the sub-option and the delimiter option,
+	// and the field Index is the index of the block, which starts from 0.
+	BlockOptionMissing struct {
+		Option string
+		Block  string
+		Index  int
+	}
+)
+
+// OptBlockCfg is the struct type to configure an option block, which is a
+// group of options starting with the delimiter option, like --server in
+// --server --host a --port 1 --server --host b --port 2.
+// The field Delimiter is the option which starts a new block, and the field
+// SubOpts is the options which belong to the current block.
+// The field Required is the sub-options which must be given in each block.
+// If the field DefaultBlock is true, the sub-options given before the first
+// delimiter belong to a default block, which is the first block and is made
+// only if such sub-options are given, otherwise they cause an error.
+// The delimiter and the sub-options must be configured as options.
+type OptBlockCfg struct {
+	Delimiter    string
+	SubOpts      []string
+	Required     []string
+	DefaultBlock bool
+}
+
+// OptBlock is the method to configure an option block.
+// The option arguments of each block can be retrieved by DaxConn#OptBlocks
+// method.
+// A sub-option which takes an option argument but is not an array can be
+// given in multiple blocks, and its last option argument is the option
+// argument of the parsing result, like LastWins of OnDuplicate method, unless
+// another policy is set for the option.
+// The same applies to the delimiter option if it takes an option argument.
+// After parsing, if a sub-option is given before the first delimiter while
+// DefaultBlock is false, Setup method returns an errs.Err with the reason:
+// OptionOutsideBlock, and if a required sub-option is not given in a block,
+// it returns an errs.Err with the reason: BlockOptionMissing.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) OptBlock(cfg OptBlockCfg) *DaxSrc {
+	ds.optBlocks = append(ds.optBlocks, cfg)
+	return ds
+}
+
+// OptBlocks is the method to retrieve the option arguments of the options in
+// each block of the option block of which delimiter option is specified, in
+// the order of the blocks.
+// Each block is a map of which keys are the names of the sub-options given in
+// the block and of which values are their option arguments, which are empty
+// arrays for options which take no option argument.
+// The map also has the delimiter option if it starts the block.
+// If no block is given, this method returns an empty array.
+func (conn *DaxConn) OptBlocks(delimiter string) []map[string][]string {
+	conn.ds.parseLazily()
+	conn.ds.mutex.RLock()
+	defer conn.ds.mutex.RUnlock()
+
+	blocks := conn.ds.blocks[delimiter]
+	copied := make([]map[string][]string, len(blocks))
+	for i, b := range blocks {
+		copied[i] = make(map[string][]string, len(b))
+		for name, a := range b {
+			copied[i][name] = append([]string{}, a...)
+		}
+	}
+	return copied
+}
+
+// isBlockOpt reports whether the option is the delimiter or a sub-option of
+// an option block.
+func (r *parseResult) isBlockOpt(name string) bool {
+	for _, cfg := range r.optBlocks {
+		if cfg.Delimiter == name {
+			return true
+		}
+		for _, sub := range cfg.SubOpts {
+			if sub == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// makeOptBlocks divides the options in the tokens into the blocks of the
+// option blocks, and checks the required sub-options of each block.
+// This function is called before the duplicated options are resolved, so
+// that every occurrence of the sub-options is divided.
+func (r *parseResult) makeOptBlocks(toks []argToken) errs.Err {
+	if len(r.optBlocks) == 0 {
+		return errs.Ok()
+	}
+	r.blocks = make(map[string][]map[string][]string, len(r.optBlocks))
+
+	for _, cfg := range r.optBlocks {
+		subs := make(map[string]bool, len(cfg.SubOpts))
+		for _, sub := range cfg.SubOpts {
+			subs[sub] = true
+		}
+
+		var blocks []map[string][]string
+		for _, tok := range toks {
+			switch {
+			case !tok.isOpt():
+				continue
+			case tok.name == cfg.Delimiter:
+				blocks = append(blocks, make(map[string][]string))
+			case !subs[tok.name]:
+				continue
+			case len(blocks) == 0 && !cfg.DefaultBlock:
+				return errs.New(OptionOutsideBlock{
+					Option: tok.name, Block: cfg.Delimiter,
+				})
+			case len(blocks) == 0:
+				blocks = append(blocks, make(map[string][]string))
+			}
+			b := blocks[len(blocks)-1]
+			a, exists := b[tok.name]
+			if !exists {
+				a = []string{}
+			}
+			if tok.hasValue {
+				a = append(append(a, tok.value), tok.extraValues...)
+			}
+			b[tok.name] = a
+		}
+
+		for i, b := range blocks {
+			for _, req := range cfg.Required {
+				if _, exists := b[req]; !exists {
+					return errs.New(BlockOptionMissing{
+						Option: req, Block: cfg.Delimiter, Index: i,
+					})
+				}
+			}
+		}
+		r.blocks[cfg.Delimiter] = blocks
+	}
+	return errs.Ok()
+}
@@ -0,0 +1,120 @@
+package cliargdax_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+)
+
+var blockOptCfgs = []cliargs.OptCfg{
+	cliargs.OptCfg{Name: "server"},
+	cliargs.OptCfg{Name: "host", HasArg: true},
+	cliargs.OptCfg{Name: "port", HasArg: true},
+	cliargs.OptCfg{Name: "tls"},
+	cliargs.OptCfg{Name: "verbose"},
+}
+
+func TestOptBlock(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "--verbose", "--server", "--host", "a", "--port=1", "--tls",
+		"--server", "--host=b", "--port", "2",
+	}, blockOptCfgs).OptBlock(cliargdax.OptBlockCfg{
+		Delimiter: "server",
+		SubOpts:   []string{"host", "port", "tls"},
+		Required:  []string{"host"},
+	})
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.OptBlocks("server"), []map[string][]string{
+		map[string][]string{
+			"server": []string{}, "host": []string{"a"}, "port": []string{"1"},
+			"tls": []string{},
+		},
+		map[string][]string{
+			"server": []string{}, "host": []string{"b"}, "port": []string{"2"},
+		},
+	})
+	assert.Equal(t, conn.Cmd().OptArg("host"), "b")
+	assert.Equal(t, conn.Cmd().OptArg("port"), "2")
+	assert.True(t, conn.Cmd().HasOpt("verbose"))
+	assert.Equal(t, conn.OptBlocks("verbose"), []map[string][]string{})
+}
+
+func TestOptBlock_noBlock(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "--verbose",
+	}, blockOptCfgs).OptBlock(cliargdax.OptBlockCfg{
+		Delimiter: "server",
+		SubOpts:   []string{"host", "port"},
+		Required:  []string{"host"},
+	})
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.OptBlocks("server"), []map[string][]string{})
+}
+
+func TestOptBlock_optionOutsideBlock(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "--port=0", "--server", "--host=a",
+	}, blockOptCfgs).OptBlock(cliargdax.OptBlockCfg{
+		Delimiter: "server",
+		SubOpts:   []string{"host", "port"},
+	})
+	_, err := setupWithOptCfgs(t, ds)
+	assert.Equal(t, err.Reason(), cliargdax.OptionOutsideBlock{
+		Option: "port", Block: "server",
+	})
+	assert.Equal(t, cliargdax.FormatError(err, nil),
+		"option --port must be given after option --server\n"+
+			"Try '--help' for more information.")
+}
+
+func TestOptBlock_defaultBlock(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "--port=0", "--server", "--host=a",
+	}, blockOptCfgs).OptBlock(cliargdax.OptBlockCfg{
+		Delimiter:    "server",
+		SubOpts:      []string{"host", "port"},
+		DefaultBlock: true,
+	})
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.OptBlocks("server"), []map[string][]string{
+		map[string][]string{"port": []string{"0"}},
+		map[string][]string{"server": []string{}, "host": []string{"a"}},
+	})
+}
+
+func TestOptBlock_requiredInEachBlock(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "--server", "--host=a", "--server", "--port=2",
+	}, blockOptCfgs).OptBlock(cliargdax.OptBlockCfg{
+		Delimiter: "server",
+		SubOpts:   []string{"host", "port"},
+		Required:  []string{"host"},
+	})
+	_, err := setupWithOptCfgs(t, ds)
+	assert.Equal(t, err.Reason(), cliargdax.BlockOptionMissing{
+		Option: "host", Block: "server", Index: 1,
+	})
+	assert.Equal(t, cliargdax.FormatError(err, nil),
+		"option --host is required in block #2 of option --server\n"+
+			"Try '--help' for more information.")
+}
+
+func TestOptBlock_duplicatedInBlock(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "--server", "--host=a", "--host=b",
+	}, blockOptCfgs).OptBlock(cliargdax.OptBlockCfg{
+		Delimiter: "server",
+		SubOpts:   []string{"host", "port"},
+	}).OnDuplicate(cliargdax.FirstWins, "host")
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.OptBlocks("server"), []map[string][]string{
+		map[string][]string{"server": []string{}, "host": []string{"a", "b"}},
+	})
+	assert.Equal(t, conn.Cmd().OptArg("host"), "a")
+}
@@ -0,0 +1,179 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/sttk/cliargs"
+)
+
+type /* error reasons */ (
+	// FailToParseBool is the error reason which indicates that an option
+	// argument attached to a boolean option, like --color=maybe, cannot be
+	// converted to a bool value.
+	// The fields Option, Field, and Input are the option name, the field name,
+	// and the option argument.
+	FailToParseBool struct {
+		Option string
+		Field  string
+		Input  string
+	}
+)
+
+// parseBoolValue converts the option argument of a boolean option to a bool
+// value.
+// In addition to the values accepted by strconv.ParseBool, yes and no are
+// accepted case-insensitively.
+func parseBoolValue(s string) (bool, error) {
+	b, e := strconv.ParseBool(s)
+	if e == nil {
+		return b, nil
+	}
+	switch strings.ToLower(s) {
+	case "yes":
+		return true, nil
+	case "no":
+		return false, nil
+	}
+	return false, e
+}
+
+func hasBoolValue(metas map[string]optMeta) bool {
+	for _, m := range metas {
+		if m.boolValue {
+			return true
+		}
+	}
+	return false
+}
+
+// hasBoolValueArg reports whether any boolean option accepting attached values
+// is given with an attached value in the tokens.
+func hasBoolValueArg(toks []argToken, metas map[string]optMeta) bool {
+	for _, tok := range toks {
+		if tok.isOpt() && tok.hasValue && metas[tok.name].boolValue {
+			return true
+		}
+	}
+	return false
+}
+
+// makeBoolValue wraps the event handler of the bool field to set the resolved
+// option argument: "true" or "false", to the field.
+func makeBoolValue(cfg *cliargs.OptCfg, m *optMeta, fld reflect.Value) {
+	m.boolValue = true
+
+	if cfg.OnParsed != nil {
+		setter := *cfg.OnParsed
+		onParsed := func(a []string) error {
+			if len(a) > 0 {
+				b, _ := strconv.ParseBool(a[len(a)-1])
+				fld.SetBool(b)
+				return nil
+			}
+			return setter(a)
+		}
+		cfg.OnParsed = &onParsed
+	}
+}
+
+// boolValueCfgs returns a copy of the option configurations in which boolean
+// options accepting attached values take an option argument, to receive the
+// resolved option arguments.
+// The event handlers of those options are wrapped to fail with the reason:
+// FailToParseBool if the option argument is not a bool value.
+func boolValueCfgs(
+	optCfgs []cliargs.OptCfg, metas map[string]optMeta,
+) []cliargs.OptCfg {
+	if !hasBoolValue(metas) {
+		return optCfgs
+	}
+	cfgs := append([]cliargs.OptCfg{}, optCfgs...)
+	for i, cfg := range cfgs {
+		m := metas[cfg.Name]
+		if !m.boolValue {
+			continue
+		}
+		name, setter := cfg.Name, cfg.OnParsed
+		onParsed := func(a []string) error {
+			if len(a) > 0 {
+				s := a[len(a)-1]
+				b, e := parseBoolValue(s)
+				if e != nil {
+					return reasonErr{
+						option: name,
+						reason: FailToParseBool{
+							Option: name, Field: m.field, Input: s,
+						},
+						cause: e,
+					}
+				}
+				a = []string{strconv.FormatBool(b)}
+			}
+			if setter != nil {
+				return (*setter)(a)
+			}
+			return nil
+		}
+		cfgs[i].HasArg = true
+		cfgs[i].OnParsed = &onParsed
+	}
+	return cfgs
+}
+
+// boolOptArgs makes the command line arguments of the boolean option accepting
+// attached values from the value taken from an environment variable or the
+// config file.
+// The option is not given if the value is false, and the option argument is
+// attached only if the option takes it in the option configurations for the
+// parsing.
+func boolOptArgs(cfg cliargs.OptCfg, b bool) []string {
+	switch {
+	case !b:
+		return nil
+	case cfg.HasArg:
+		return []string{optArg(cfg.Name, "true")}
+	default:
+		return []string{optArg(cfg.Name)}
+	}
+}
+
+// trimBoolValues makes a cliargs.Cmd in which the boolean options accepting
+// attached values and given as true have no option argument, like the other
+// boolean options, and the options given as false have the option argument:
+// "false".
+// Negatable options are left as they are.
+// If no option is trimmed, this function returns the cliargs.Cmd as it is.
+func trimBoolValues(
+	cmd cliargs.Cmd,
+	optCfgs []cliargs.OptCfg,
+	metas map[string]optMeta,
+	counts map[string]int,
+) cliargs.Cmd {
+	if !hasBoolValue(metas) {
+		return cmd
+	}
+	trimmed := false
+	opts := make(map[string][]string)
+	for _, name := range cmdOptNames(cmd, optCfgs, counts) {
+		a := cmd.OptArgs(name)
+		m := metas[name]
+		if m.boolValue && !m.negatable && len(a) > 0 && a[len(a)-1] == "true" {
+			a = nil
+			trimmed = true
+		}
+		opts[name] = a
+	}
+	if !trimmed {
+		return cmd
+	}
+	if trimmedCmd, e := makeCmd(cmd.Name, cmd.Args(), opts); e == nil {
+		return trimmedCmd
+	}
+	return cmd
+}
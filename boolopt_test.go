@@ -0,0 +1,122 @@
+package cliargdax_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+)
+
+type boolValueOptions struct {
+	Color        bool   `optcfg:"color,c" optdefault:"true"`
+	ColorChanged bool   `optchanged:"color"`
+	Verbose      bool   `optcfg:"verbose,v"`
+	Name         string `optcfg:"name"`
+}
+
+func TestBoolValue_attachedValues(t *testing.T) {
+	cases := []struct {
+		arg   string
+		color bool
+	}{
+		{arg: "--color", color: true},
+		{arg: "--color=true", color: true},
+		{arg: "--color=false", color: false},
+		{arg: "--color=1", color: true},
+		{arg: "--color=0", color: false},
+		{arg: "--color=yes", color: true},
+		{arg: "--color=NO", color: false},
+		{arg: "-c=false", color: false},
+	}
+	for _, c := range cases {
+		options := boolValueOptions{}
+		conn, err := setupForOptions(t, []string{"app", c.arg, "x"}, &options)
+		assert.True(t, err.IsOk(), c.arg)
+		assert.Equal(t, options.Color, c.color, c.arg)
+		assert.True(t, options.ColorChanged, c.arg)
+		assert.Equal(t, conn.Cmd().Args(), []string{"x"}, c.arg)
+	}
+}
+
+func TestBoolValue_followingArgIsNotTaken(t *testing.T) {
+	options := boolValueOptions{}
+	conn, err := setupForOptions(t, []string{
+		"app", "--verbose=false", "--color", "false", "--name", "n",
+	}, &options)
+	assert.True(t, err.IsOk())
+	assert.True(t, options.Color)
+	assert.False(t, options.Verbose)
+	assert.Equal(t, options.Name, "n")
+	assert.Equal(t, conn.Cmd().Args(), []string{"false"})
+}
+
+func TestBoolValue_lastWins(t *testing.T) {
+	options := boolValueOptions{}
+	_, err := setupForOptions(t, []string{
+		"app", "--verbose", "-v=no", "--color=false", "-c",
+	}, &options)
+	assert.True(t, err.IsOk())
+	assert.False(t, options.Verbose)
+	assert.True(t, options.Color)
+}
+
+func TestBoolValue_explicitFalseIsRecorded(t *testing.T) {
+	options := boolValueOptions{}
+	conn, err := setupForOptions(t, []string{"app", "--verbose=false"}, &options)
+	assert.True(t, err.IsOk())
+	assert.True(t, conn.Cmd().HasOpt("verbose"))
+	assert.Equal(t, conn.Cmd().OptArgs("verbose"), []string{"false"})
+	assert.Equal(t, conn.OptSource("verbose"), cliargdax.SourceCLI)
+	assert.Equal(t, conn.OptSource("color"), cliargdax.SourceUnset)
+	assert.False(t, options.ColorChanged)
+
+	options = boolValueOptions{}
+	conn, err = setupForOptions(t, []string{"app", "--verbose=true"}, &options)
+	assert.True(t, err.IsOk())
+	assert.True(t, conn.Cmd().HasOpt("verbose"))
+	assert.Equal(t, conn.Cmd().OptArgs("verbose"), []string{})
+	assert.True(t, options.Verbose)
+}
+
+func TestBoolValue_invalidValue(t *testing.T) {
+	options := boolValueOptions{}
+	_, err := setupForOptions(t, []string{"app", "--color=maybe"}, &options)
+	assert.Equal(t, err.Reason(), cliargdax.FailToParseBool{
+		Option: "color", Field: "Color", Input: "maybe",
+	})
+	assert.Equal(t, cliargdax.FormatError(err, nil),
+		"invalid value for option --color: \"maybe\" (must be true or false)\n"+
+			"Try '--help' for more information.")
+}
+
+func TestBoolValue_OptMeta(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "color"},
+		cliargs.OptCfg{Name: "verbose"},
+	}
+
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "--color=no", "x"}, optCfgs,
+	).SetMeta("color", cliargdax.OptMeta{BoolValue: true})
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.Cmd().OptArg("color"), "false")
+	assert.Equal(t, conn.Cmd().Args(), []string{"x"})
+	meta, _ := conn.Meta("color")
+	assert.True(t, meta.BoolValue)
+
+	ds = cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "--color=x"}, optCfgs,
+	).SetMeta("color", cliargdax.OptMeta{BoolValue: true})
+	_, err = setupWithOptCfgs(t, ds)
+	assert.Equal(t, err.Reason(), cliargdax.FailToParseBool{
+		Option: "color", Input: "x",
+	})
+
+	ds = cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "--verbose=true"}, optCfgs,
+	).SetMeta("color", cliargdax.OptMeta{BoolValue: true})
+	_, err = setupWithOptCfgs(t, ds)
+	assert.Equal(t, err.Reason(), cliargs.OptionTakesNoArg{Option: "verbose"})
+}
@@ -0,0 +1,165 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"strings"
+
+	"github.com/sttk/cliargs"
+)
+
+// BuildArgs is the method to reconstruct command line arguments from the
+// results of parsing, for example to pass a part of options to a child
+// process.
+// The options are put in the order of their names with the option names, not
+// aliases, like --name=value, and an array option is put repeatedly for each
+// option argument.
+// An option given with a deprecated alias is put with the name of the option
+// which replaces the alias.
+// The options which got values from environment variables, a config file, or
+// default values are also put, so that the child process gets the same
+// values.
+// If include is not empty, only the options of which names are in it are
+// put, and the options of which names are in exclude are not put.
+// The command arguments are put after "--", and if a sub command is
+// specified, its name, options, and command arguments follow the options of
+// the top command.
+// The returned array does not include the program path.
+func (conn *DaxConn) BuildArgs(include []string, exclude []string) []string {
+	return conn.buildArgs(include, exclude, nil)
+}
+
+func (conn *DaxConn) buildArgs(
+	include []string, exclude []string, secrets map[string]bool,
+) []string {
+	conn.ds.parseLazily()
+	conn.ds.mutex.RLock()
+	defer conn.ds.mutex.RUnlock()
+
+	ds := conn.ds
+	filter := makeOptFilter(include, exclude)
+
+	args := appendOptArgs(nil, conn.overriddenCmd(), ds.optCfgs, ds.metas,
+		ds.counts, filter, secrets)
+	cmdArgs := ds.cmd.Args()
+	if len(ds.subCmdName) > 0 {
+		args = append(args, ds.subCmdName)
+		args = appendOptArgs(args, ds.subCmd, ds.subOptCfgs, nil, nil, filter,
+			secrets)
+		cmdArgs = ds.subCmd.Args()
+	}
+	if len(cmdArgs) > 0 {
+		args = append(args, "--")
+		args = append(args, cmdArgs...)
+	}
+	return args
+}
+
+// BuildCommandLineString is the method to reconstruct a command line string
+// from the results of parsing in the same way as BuildArgs method, for
+// example to write it to a log.
+// The arguments which contain spaces or other special characters of shells
+// are quoted with single quotes, and the option arguments of secret options
+// are replaced with "****".
+func (conn *DaxConn) BuildCommandLineString(
+	include []string, exclude []string,
+) string {
+	conn.ds.parseLazily()
+	conn.ds.mutex.RLock()
+	secrets := conn.ds.secretOpts()
+	conn.ds.mutex.RUnlock()
+
+	args := conn.buildArgs(include, exclude, secrets)
+	quoted := make([]string, len(args))
+	for i, a := range args {
+		quoted[i] = shQuoteIfNeeded(a)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// makeOptFilter returns the function to check whether an option of the name
+// is put by BuildArgs method.
+func makeOptFilter(include []string, exclude []string) func(string) bool {
+	included := make(map[string]bool, len(include))
+	for _, name := range include {
+		included[name] = true
+	}
+	excluded := make(map[string]bool, len(exclude))
+	for _, name := range exclude {
+		excluded[name] = true
+	}
+	return func(name string) bool {
+		if len(included) > 0 && !included[name] {
+			return false
+		}
+		return !excluded[name]
+	}
+}
+
+// appendOptArgs appends the options of the cliargs.Cmd to the arguments.
+func appendOptArgs(
+	args []string,
+	cmd cliargs.Cmd,
+	optCfgs []cliargs.OptCfg,
+	metas map[string]optMeta,
+	counts map[string]int,
+	filter func(string) bool,
+	secrets map[string]bool,
+) []string {
+	hasArg := make(map[string]bool, len(optCfgs))
+	for _, cfg := range optCfgs {
+		hasArg[cfg.Name] = cfg.HasArg
+	}
+
+	for _, name := range cmdOptNames(cmd, optCfgs, counts) {
+		if !filter(name) {
+			continue
+		}
+		m := metas[name]
+		values := cmd.OptArgs(name)
+		if secrets[name] && !m.negatable {
+			values = redactValues(values)
+		}
+
+		switch {
+		case m.negatable:
+			if len(values) > 0 && values[len(values)-1] == "false" {
+				args = append(args, optArg("no-"+name))
+			} else {
+				args = append(args, optArg(name))
+			}
+		case !hasArg[name] && len(values) == 0:
+			n := 1
+			if m.setCount != nil && counts[name] > 1 {
+				n = counts[name]
+			}
+			for ; n > 0; n-- {
+				args = append(args, optArg(name))
+			}
+		default:
+			for _, v := range values {
+				args = append(args, optArg(name, v))
+			}
+		}
+	}
+	return args
+}
+
+// shQuoteIfNeeded quotes the string with single quotes if it is empty or
+// contains characters other than ones which are safe in shells.
+func shQuoteIfNeeded(s string) string {
+	if len(s) == 0 {
+		return "''"
+	}
+	for _, r := range s {
+		switch {
+		case 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z', '0' <= r && r <= '9':
+		case strings.ContainsRune("-_=+.,/:@%", r):
+		default:
+			return shQuote(s)
+		}
+	}
+	return s
+}
@@ -0,0 +1,120 @@
+package cliargdax_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+)
+
+func TestBuildArgs_optCfgs(t *testing.T) {
+	cfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "name", Aliases: []string{"n"}, HasArg: true},
+		cliargs.OptCfg{Name: "tag", HasArg: true, IsArray: true},
+		cliargs.OptCfg{Name: "verbose", Aliases: []string{"v"}},
+		cliargs.OptCfg{Name: "log", HasArg: true, Default: []string{"info"}},
+		cliargs.OptCfg{Name: "dry-run"},
+	}
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"/path/to/app", "-n", "my name", "--tag=a", "-v", "--tag", "b",
+		"arg 1", "--", "--arg2",
+	}, cfgs)
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	assert.Equal(t, conn.BuildArgs(nil, nil), []string{
+		"--log=info", "--name=my name", "--tag=a", "--tag=b", "--verbose",
+		"--", "arg 1", "--arg2",
+	})
+	assert.Equal(t, conn.BuildArgs([]string{"name", "verbose"}, nil), []string{
+		"--name=my name", "--verbose", "--", "arg 1", "--arg2",
+	})
+	assert.Equal(t, conn.BuildArgs(nil, []string{"log", "tag"}), []string{
+		"--name=my name", "--verbose", "--", "arg 1", "--arg2",
+	})
+	assert.Equal(t, conn.BuildArgs([]string{"name", "tag"}, []string{"tag"}),
+		[]string{"--name=my name", "--", "arg 1", "--arg2"})
+
+	assert.Equal(t, conn.BuildCommandLineString(nil, []string{"tag"}),
+		"--log=info '--name=my name' --verbose -- 'arg 1' --arg2")
+}
+
+func TestBuildArgs_reparse(t *testing.T) {
+	cfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "name", HasArg: true},
+		cliargs.OptCfg{Name: "tag", HasArg: true, IsArray: true},
+	}
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"/path/to/app", "--name", "it's", "--tag=", "x",
+	}, cfgs)
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	args := conn.BuildArgs(nil, nil)
+	assert.Equal(t, args, []string{"--name=it's", "--tag=", "--", "x"})
+	assert.Equal(t, conn.BuildCommandLineString(nil, nil),
+		`'--name=it'\''s' --tag= -- x`)
+
+	cmd, err := cliargdax.ParseWith(append([]string{"app"}, args...), cfgs)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, cmd.OptArg("name"), "it's")
+	assert.Equal(t, cmd.OptArgs("tag"), []string{""})
+	assert.Equal(t, cmd.Args(), []string{"x"})
+}
+
+func TestBuildArgs_optionStore(t *testing.T) {
+	options := struct {
+		Verbose int  `optcfg:"verbose,v" optcount:"true"`
+		Color   bool `optcfg:"color" optnegatable:"true"`
+		Cache   bool `optcfg:"cache" optnegatable:"true"`
+		Port    int  `optcfg:"port"`
+	}{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions([]string{
+		"/path/to/app", "-vvv", "--no-color", "--cache", "--port=80",
+	}, &options)
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	assert.Equal(t, conn.BuildArgs(nil, nil), []string{
+		"--cache", "--no-color", "--port=80",
+		"--verbose", "--verbose", "--verbose",
+	})
+}
+
+func TestBuildArgs_deprecatedAlias(t *testing.T) {
+	cfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "output", HasArg: true},
+	}
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"/path/to/app", "--out=file.txt"}, cfgs,
+	).
+		DeprecatedAlias("out", "output", "")
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	assert.Equal(t, conn.BuildArgs(nil, nil), []string{"--output=file.txt"})
+}
+
+func TestBuildArgs_subCmd(t *testing.T) {
+	defer resetOsArgs()
+	os.Args = []string{"/path/to/app", "-v", "build", "--target=x", "src"}
+
+	ds := cliargdax.NewDaxSrcWithSubCmds(map[string][]cliargs.OptCfg{
+		"build": []cliargs.OptCfg{
+			cliargs.OptCfg{Name: "target", HasArg: true},
+		},
+	})
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	assert.Equal(t, conn.BuildArgs(nil, nil), []string{
+		"-v", "build", "--target=x", "--", "src",
+	})
+}
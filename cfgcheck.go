@@ -0,0 +1,143 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"github.com/sttk/cliargs"
+	"github.com/sttk/sabi/errs"
+)
+
+type /* error reasons */ (
+	// DuplicateOptName is the error reason which indicates that multiple
+	// option configurations have the same name.
+	// The fields Name and Indexes are the duplicated name and the indexes of
+	// the option configurations which have it.
+	// The wildcard configuration of which name is "*" is also reported with
+	// this reason if it is specified more than once.
+	DuplicateOptName struct {
+		Name    string
+		Indexes []int
+	}
+
+	// DuplicateOptAlias is the error reason which indicates that multiple
+	// option configurations have the same alias.
+	// The fields Alias and Indexes are the duplicated alias and the indexes of
+	// the option configurations which have it.
+	DuplicateOptAlias struct {
+		Alias   string
+		Indexes []int
+	}
+
+	// AliasCollidesWithName is the error reason which indicates that an alias
+	// of an option configuration is same as the name of another option
+	// configuration.
+	// The fields Alias, Name, and Index are the alias, the name of the option
+	// configuration which has the alias, and the index of it.
+	AliasCollidesWithName struct {
+		Alias string
+		Name  string
+		Index int
+	}
+)
+
+// ValidateOptCfgs is the function to check the consistency of the option
+// configurations before parsing.
+// This function returns an errs.Err with the reason: DuplicateOptName,
+// DuplicateOptAlias, or AliasCollidesWithName if names and aliases conflict,
+// and with the reason: cliargs.ConfigIsArrayButHasNoArg or
+// cliargs.ConfigHasDefaultButHasNoArg if an option configuration is
+// contradictory.
+// The constructors of DaxSrc call this function, and the error is returned by
+// DaxSrc#Setup method.
+func ValidateOptCfgs(cfgs []cliargs.OptCfg) errs.Err {
+	for _, cfg := range cfgs {
+		if cfg.IsArray && !cfg.HasArg {
+			return errs.New(cliargs.ConfigIsArrayButHasNoArg{Option: cfg.Name})
+		}
+		if cfg.Default != nil && !cfg.HasArg {
+			return errs.New(cliargs.ConfigHasDefaultButHasNoArg{Option: cfg.Name})
+		}
+	}
+
+	if !hasConflictingNames(cfgs) {
+		return errs.Ok()
+	}
+
+	names := make(map[string][]int)
+	aliases := make(map[string][]int)
+	var nameOrder, aliasOrder []string
+
+	for i, cfg := range cfgs {
+		if _, exists := names[cfg.Name]; !exists {
+			nameOrder = append(nameOrder, cfg.Name)
+		}
+		names[cfg.Name] = append(names[cfg.Name], i)
+
+		for _, a := range cfg.Aliases {
+			if a == cfg.Name {
+				continue
+			}
+			if _, exists := aliases[a]; !exists {
+				aliasOrder = append(aliasOrder, a)
+			}
+			aliases[a] = append(aliases[a], i)
+		}
+	}
+
+	for _, name := range nameOrder {
+		if indexes := names[name]; len(indexes) > 1 {
+			return errs.New(DuplicateOptName{Name: name, Indexes: indexes})
+		}
+	}
+
+	for _, a := range aliasOrder {
+		indexes := aliases[a]
+		if _, exists := names[a]; exists {
+			i := indexes[0]
+			return errs.New(AliasCollidesWithName{
+				Alias: a, Name: cfgs[i].Name, Index: i,
+			})
+		}
+		if indexes = uniqueIndexes(indexes); len(indexes) > 1 {
+			return errs.New(DuplicateOptAlias{Alias: a, Indexes: indexes})
+		}
+	}
+
+	return errs.Ok()
+}
+
+// hasConflictingNames reports whether any name or alias of the option
+// configurations is used by multiple configurations or more than once.
+func hasConflictingNames(cfgs []cliargs.OptCfg) bool {
+	used := make(map[string]struct{}, len(cfgs)*2)
+	for _, cfg := range cfgs {
+		if _, exists := used[cfg.Name]; exists {
+			return true
+		}
+		used[cfg.Name] = struct{}{}
+	}
+	for _, cfg := range cfgs {
+		for _, a := range cfg.Aliases {
+			if a == cfg.Name {
+				continue
+			}
+			if _, exists := used[a]; exists {
+				return true
+			}
+			used[a] = struct{}{}
+		}
+	}
+	return false
+}
+
+func uniqueIndexes(indexes []int) []int {
+	var a []int
+	for i, n := range indexes {
+		if i == 0 || indexes[i-1] != n {
+			a = append(a, n)
+		}
+	}
+	return a
+}
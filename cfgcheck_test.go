@@ -0,0 +1,148 @@
+package cliargdax_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+)
+
+func TestValidateOptCfgs_ok(t *testing.T) {
+	err := cliargdax.ValidateOptCfgs([]cliargs.OptCfg{
+		cliargs.OptCfg{Name: "foo", Aliases: []string{"f"}},
+		cliargs.OptCfg{Name: "bar", Aliases: []string{"b", "bar"}, HasArg: true},
+		cliargs.OptCfg{Name: "*"},
+	})
+	assert.True(t, err.IsOk())
+
+	err = cliargdax.ValidateOptCfgs(nil)
+	assert.True(t, err.IsOk())
+}
+
+func TestValidateOptCfgs_duplicateName(t *testing.T) {
+	err := cliargdax.ValidateOptCfgs([]cliargs.OptCfg{
+		cliargs.OptCfg{Name: "foo"},
+		cliargs.OptCfg{Name: "bar"},
+		cliargs.OptCfg{Name: "foo", HasArg: true},
+	})
+	switch r := err.Reason().(type) {
+	case cliargdax.DuplicateOptName:
+		assert.Equal(t, r.Name, "foo")
+		assert.Equal(t, r.Indexes, []int{0, 2})
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestValidateOptCfgs_duplicateWildcard(t *testing.T) {
+	err := cliargdax.ValidateOptCfgs([]cliargs.OptCfg{
+		cliargs.OptCfg{Name: "*"},
+		cliargs.OptCfg{Name: "foo"},
+		cliargs.OptCfg{Name: "*"},
+	})
+	switch r := err.Reason().(type) {
+	case cliargdax.DuplicateOptName:
+		assert.Equal(t, r.Name, "*")
+		assert.Equal(t, r.Indexes, []int{0, 2})
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestValidateOptCfgs_duplicateAlias(t *testing.T) {
+	err := cliargdax.ValidateOptCfgs([]cliargs.OptCfg{
+		cliargs.OptCfg{Name: "foo", Aliases: []string{"x"}},
+		cliargs.OptCfg{Name: "bar", Aliases: []string{"x"}},
+	})
+	switch r := err.Reason().(type) {
+	case cliargdax.DuplicateOptAlias:
+		assert.Equal(t, r.Alias, "x")
+		assert.Equal(t, r.Indexes, []int{0, 1})
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestValidateOptCfgs_aliasCollidesWithName(t *testing.T) {
+	err := cliargdax.ValidateOptCfgs([]cliargs.OptCfg{
+		cliargs.OptCfg{Name: "foo", Aliases: []string{"f"}},
+		cliargs.OptCfg{Name: "bar", Aliases: []string{"foo"}},
+	})
+	switch r := err.Reason().(type) {
+	case cliargdax.AliasCollidesWithName:
+		assert.Equal(t, r.Alias, "foo")
+		assert.Equal(t, r.Name, "bar")
+		assert.Equal(t, r.Index, 1)
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestValidateOptCfgs_contradiction(t *testing.T) {
+	err := cliargdax.ValidateOptCfgs([]cliargs.OptCfg{
+		cliargs.OptCfg{Name: "foo", IsArray: true},
+	})
+	switch r := err.Reason().(type) {
+	case cliargs.ConfigIsArrayButHasNoArg:
+		assert.Equal(t, r.Option, "foo")
+	default:
+		assert.Fail(t, err.Error())
+	}
+
+	err = cliargdax.ValidateOptCfgs([]cliargs.OptCfg{
+		cliargs.OptCfg{Name: "bar", Default: []string{"1"}},
+	})
+	switch r := err.Reason().(type) {
+	case cliargs.ConfigHasDefaultButHasNoArg:
+		assert.Equal(t, r.Option, "bar")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestValidateOptCfgs_atSetup(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{"app"},
+		[]cliargs.OptCfg{
+			cliargs.OptCfg{Name: "foo"},
+			cliargs.OptCfg{Name: "foo"},
+		},
+	).Lazy()
+	_, err := setupWithOptCfgs(t, ds)
+	switch r := err.Reason().(type) {
+	case cliargdax.DuplicateOptName:
+		assert.Equal(t, r.Name, "foo")
+	default:
+		assert.Fail(t, err.Error())
+	}
+
+	ds = cliargdax.NewDaxSrcWithSubCmds(map[string][]cliargs.OptCfg{
+		"add": []cliargs.OptCfg{
+			cliargs.OptCfg{Name: "all", Aliases: []string{"a"}},
+			cliargs.OptCfg{Name: "amend", Aliases: []string{"a"}},
+		},
+	})
+	_, err = setupWithOptCfgs(t, ds)
+	switch r := err.Reason().(type) {
+	case cliargdax.DuplicateOptAlias:
+		assert.Equal(t, r.Alias, "a")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestValidateOptCfgs_forOptions(t *testing.T) {
+	type Options struct {
+		Foo bool `optcfg:"foo,f"`
+		Bar bool `optcfg:"bar,f"`
+	}
+	opts := Options{}
+	_, err := setupForOptions(t, []string{"app"}, &opts)
+	switch r := err.Reason().(type) {
+	case cliargdax.DuplicateOptAlias:
+		assert.Equal(t, r.Alias, "f")
+		assert.Equal(t, r.Indexes, []int{0, 1})
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"reflect"
+
+	"github.com/sttk/cliargs"
+	"github.com/sttk/sabi/errs"
+)
+
+type /* error reasons */ (
+	// IllegalChangedField is the error reason which indicates that a field of
+	// an option store which has the struct tag: optchanged is not a bool field
+	// or that the option specified by the tag is not configured.
+	// The fields Field and Option are the field name and the value of the tag.
+	IllegalChangedField struct {
+		Field  string
+		Option string
+	}
+)
+
+// changedField is the struct type for a bool field of an option store which
+// receives whether the option is explicitly given in command line arguments.
+type changedField struct {
+	field  string
+	option string
+	fld    reflect.Value
+}
+
+// OptChanged is the method to check whether the specified option is
+// explicitly given in command line arguments by its name or its alias.
+// If the value of the option comes from an environment variable, the config
+// file, or the default value, this method returns false.
+func (conn *DaxConn) OptChanged(name string) bool {
+	conn.ds.parseLazily()
+	conn.ds.mutex.RLock()
+	defer conn.ds.mutex.RUnlock()
+	src := conn.ds.sources[name]
+	return src == SourceCLI || src == SourceAlias
+}
+
+// splitChangedFields takes out the fields which have the struct tag:
+// optchanged from the fields of an option store.
+func splitChangedFields(
+	fields []storeField,
+) ([]storeField, []changedField, errs.Err) {
+	optFields := make([]storeField, 0, len(fields))
+	var changed []changedField
+
+	for _, f := range fields {
+		name, exists := f.sf.Tag.Lookup("optchanged")
+		if !exists {
+			optFields = append(optFields, f)
+			continue
+		}
+		if f.sf.Type.Kind() != reflect.Bool || len(name) == 0 {
+			return nil, nil, errs.New(IllegalChangedField{
+				Field: f.sf.Name, Option: name,
+			})
+		}
+		if len(name) > 1 {
+			name = f.prefix + name
+		}
+		changed = append(changed, changedField{
+			field: f.sf.Name, option: name, fld: f.fld,
+		})
+	}
+
+	return optFields, changed, errs.Ok()
+}
+
+// bindChangedFields sets the functions to set the changed fields to the
+// metadata of the options specified by their tags, which are option names or
+// aliases.
+func bindChangedFields(
+	changed []changedField, optCfgs []cliargs.OptCfg, metas map[string]optMeta,
+) errs.Err {
+	indexes := cfgIndexes(optCfgs)
+	for _, c := range changed {
+		i, exists := indexes[c.option]
+		if !exists {
+			return errs.New(IllegalChangedField{Field: c.field, Option: c.option})
+		}
+		name := optCfgs[i].Name
+		m := metas[name]
+		setChanged, fld := m.setChanged, c.fld
+		m.setChanged = func(b bool) {
+			if setChanged != nil {
+				setChanged(b)
+			}
+			fld.SetBool(b)
+		}
+		metas[name] = m
+	}
+	return errs.Ok()
+}
@@ -0,0 +1,100 @@
+package cliargdax_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+)
+
+func TestChanged_OptChanged(t *testing.T) {
+	os.Setenv("MY_APP_HOST", "example.com")
+	defer os.Unsetenv("MY_APP_HOST")
+
+	type Options struct {
+		Port    int    `optcfg:"port,p=8080"`
+		Host    string `optcfg:"host" optenv:"MY_APP_HOST"`
+		Verbose bool   `optcfg:"verbose"`
+		Name    string `optcfg:"name"`
+	}
+
+	opts := Options{}
+	conn, err := setupForOptions(t, []string{
+		"app", "-p", "8080", "--verbose",
+	}, &opts)
+	assert.True(t, err.IsOk())
+
+	assert.True(t, conn.OptChanged("port"))
+	assert.True(t, conn.OptChanged("verbose"))
+	assert.False(t, conn.OptChanged("host"))
+	assert.False(t, conn.OptChanged("name"))
+	assert.False(t, conn.OptChanged("unknown"))
+
+	opts = Options{}
+	conn, err = setupForOptions(t, []string{"app"}, &opts)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, opts.Port, 8080)
+	assert.False(t, conn.OptChanged("port"))
+}
+
+func TestChanged_optchanged(t *testing.T) {
+	type Server struct {
+		Port        int  `optcfg:"port=80"`
+		PortChanged bool `optchanged:"port"`
+	}
+	type Options struct {
+		Port        int    `optcfg:"port,p=8080"`
+		PortChanged bool   `optchanged:"port"`
+		Name        string `optcfg:"name,n"`
+		NameChanged bool   `optchanged:"n"`
+		Server      Server
+	}
+
+	opts := Options{}
+	_, err := setupForOptions(t, []string{"app", "-p", "8080"}, &opts)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, opts.Port, 8080)
+	assert.True(t, opts.PortChanged)
+	assert.False(t, opts.NameChanged)
+	assert.Equal(t, opts.Server.Port, 80)
+	assert.False(t, opts.Server.PortChanged)
+
+	opts = Options{}
+	conn, err := setupForOptions(t, []string{
+		"app", "--name=foo", "--server-port=80",
+	}, &opts)
+	assert.True(t, err.IsOk())
+	assert.False(t, opts.PortChanged)
+	assert.True(t, opts.NameChanged)
+	assert.True(t, opts.Server.PortChanged)
+	assert.Equal(t, len(conn.OptCfgs()), 3)
+}
+
+func TestChanged_optchanged_illegal(t *testing.T) {
+	type Options struct {
+		Port        int `optcfg:"port"`
+		PortChanged int `optchanged:"port"`
+	}
+	_, err := setupForOptions(t, []string{"app"}, &Options{})
+	switch r := err.Reason().(type) {
+	case cliargdax.IllegalChangedField:
+		assert.Equal(t, r.Field, "PortChanged")
+		assert.Equal(t, r.Option, "port")
+	default:
+		assert.Fail(t, err.Error())
+	}
+
+	type Options2 struct {
+		Port        int  `optcfg:"port"`
+		HostChanged bool `optchanged:"host"`
+	}
+	_, err = setupForOptions(t, []string{"app"}, &Options2{})
+	switch r := err.Reason().(type) {
+	case cliargdax.IllegalChangedField:
+		assert.Equal(t, r.Field, "HostChanged")
+		assert.Equal(t, r.Option, "host")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
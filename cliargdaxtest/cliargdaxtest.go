@@ -0,0 +1,128 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdaxtest
+
+import (
+	"os"
+	"reflect"
+	"testing"
+
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+)
+
+// ParseOrFail is the function to parse command line arguments without a
+// sabi.DaxBase, and returns a DaxConn to retrieve the results of the parsing.
+// The first element of args is treated as the program path, like os.Args.
+// If opts is nil, all options in args are accepted, if opts is an array of
+// cliargs.OptCfg, args are parsed with it, and otherwise opts is treated as
+// an option store into which the results are stored.
+// If the parsing fails, this function stops the test with t.Fatalf.
+// The DaxSrc is closed when the test finishes.
+func ParseOrFail(t testing.TB, args []string, opts any) *cliargdax.DaxConn {
+	t.Helper()
+
+	ds := newDaxSrc(args, opts)
+	t.Cleanup(ds.Close)
+	if err := ds.Setup(nil); err.IsNotOk() {
+		t.Fatalf("failed to parse %q: %s", args, err.Error())
+		return nil
+	}
+	dc, err := ds.CreateDaxConn()
+	if err.IsNotOk() {
+		t.Fatalf("failed to create DaxConn: %s", err.Error())
+		return nil
+	}
+	return dc.(*cliargdax.DaxConn)
+}
+
+// AssertOpt is the function to check the option of the parsing result held by
+// the DaxConn.
+// If want is a bool, this function checks whether the option is given or has
+// a default value, if want is a string, this function checks the first option
+// argument, and if want is an array of strings, this function checks all
+// option arguments.
+// If the check fails, this function reports the failure with t.Errorf and
+// returns false.
+func AssertOpt(
+	t testing.TB, conn *cliargdax.DaxConn, name string, want any,
+) bool {
+	t.Helper()
+
+	cmd := conn.Cmd()
+	var got any
+	switch want.(type) {
+	case bool:
+		got = cmd.HasOpt(name)
+	case string:
+		if !cmd.HasOpt(name) {
+			t.Errorf("option %q is not given, want %q", name, want)
+			return false
+		}
+		got = cmd.OptArg(name)
+	case []string:
+		got = cmd.OptArgs(name)
+	default:
+		t.Errorf("unsupported type of expected value of option %q: %T",
+			name, want)
+		return false
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("option %q is %#v, want %#v", name, got, want)
+		return false
+	}
+	return true
+}
+
+// AssertParseError is the function to check that parsing command line
+// arguments fails with an error reason of the type T, and returns the reason.
+// The args and cfgs are treated like ParseOrFail function.
+// If the parsing succeeds or fails with another reason, this function stops
+// the test with t.Fatalf.
+func AssertParseError[T any](t testing.TB, args []string, cfgs any) T {
+	t.Helper()
+
+	var zero T
+	ds := newDaxSrc(args, cfgs)
+	t.Cleanup(ds.Close)
+	err := ds.Setup(nil)
+	if err.IsOk() {
+		t.Fatalf("parsing %q succeeded, want an error of %T", args, zero)
+		return zero
+	}
+	r, ok := err.Reason().(T)
+	if !ok {
+		t.Fatalf("parsing %q failed with %T, want %T: %s",
+			args, err.Reason(), zero, err.Error())
+		return zero
+	}
+	return r
+}
+
+// WithArgs is the function to replace os.Args with args and to invoke fn, for
+// testing code which parses os.Args directly.
+// The original os.Args is restored when the test finishes.
+func WithArgs(t testing.TB, args []string, fn func()) {
+	t.Helper()
+
+	orig := os.Args
+	t.Cleanup(func() { os.Args = orig })
+	os.Args = args
+	fn()
+}
+
+// newDaxSrc creates a DaxSrc for args with the option configurations or the
+// option store.
+func newDaxSrc(args []string, opts any) *cliargdax.DaxSrc {
+	switch v := opts.(type) {
+	case nil:
+		return cliargdax.NewDaxSrcWithArgs(args)
+	case []cliargs.OptCfg:
+		return cliargdax.NewDaxSrcWithArgsAndOptCfgs(args, v)
+	default:
+		return cliargdax.NewDaxSrcWithArgsForOptions(args, opts)
+	}
+}
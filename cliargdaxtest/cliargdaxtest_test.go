@@ -0,0 +1,139 @@
+package cliargdaxtest_test
+
+import (
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax/cliargdaxtest"
+	"github.com/sttk/cliargs"
+)
+
+// recorder is the testing.TB which records failures instead of reporting
+// them, to test the failure cases of the helpers.
+type recorder struct {
+	testing.TB
+	errors []string
+	fatals []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...any) {
+	r.fatals = append(r.fatals, fmt.Sprintf(format, args...))
+}
+
+type options struct {
+	Port    int      `optcfg:"port=80"`
+	Verbose bool     `optcfg:"verbose,v"`
+	Tags    []string `optcfg:"tag"`
+}
+
+func TestParseOrFail_options(t *testing.T) {
+	opts := options{}
+	conn := cliargdaxtest.ParseOrFail(
+		t, []string{"app", "-v", "--tag=a", "--tag=b", "file"}, &opts,
+	)
+	assert.NotNil(t, conn)
+	assert.Equal(t, opts.Port, 80)
+	assert.True(t, opts.Verbose)
+	assert.Equal(t, conn.Cmd().Args(), []string{"file"})
+
+	assert.True(t, cliargdaxtest.AssertOpt(t, conn, "verbose", true))
+	assert.True(t, cliargdaxtest.AssertOpt(t, conn, "port", "80"))
+	assert.True(t, cliargdaxtest.AssertOpt(t, conn, "tag", []string{"a", "b"}))
+}
+
+func TestParseOrFail_optCfgs(t *testing.T) {
+	cfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "level", HasArg: true},
+	}
+	conn := cliargdaxtest.ParseOrFail(t, []string{"app", "--level=3"}, cfgs)
+	assert.True(t, cliargdaxtest.AssertOpt(t, conn, "level", "3"))
+	assert.True(t, cliargdaxtest.AssertOpt(t, conn, "verbose", false))
+}
+
+func TestParseOrFail_noCfgs(t *testing.T) {
+	conn := cliargdaxtest.ParseOrFail(t, []string{"app", "--foo=1"}, nil)
+	assert.True(t, cliargdaxtest.AssertOpt(t, conn, "foo", "1"))
+}
+
+func TestParseOrFail_fail(t *testing.T) {
+	r := &recorder{TB: t}
+	cfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "level", HasArg: true},
+	}
+	conn := cliargdaxtest.ParseOrFail(r, []string{"app", "--level"}, cfgs)
+	assert.Nil(t, conn)
+	assert.Equal(t, len(r.fatals), 1)
+	assert.Contains(t, r.fatals[0], `failed to parse ["app" "--level"]`)
+}
+
+func TestAssertOpt_fail(t *testing.T) {
+	conn := cliargdaxtest.ParseOrFail(
+		t, []string{"app", "--foo=1", "--bar"}, nil,
+	)
+
+	r := &recorder{TB: t}
+	assert.False(t, cliargdaxtest.AssertOpt(r, conn, "foo", "2"))
+	assert.False(t, cliargdaxtest.AssertOpt(r, conn, "bar", false))
+	assert.False(t, cliargdaxtest.AssertOpt(r, conn, "baz", "1"))
+	assert.False(t, cliargdaxtest.AssertOpt(r, conn, "foo", []string{}))
+	assert.False(t, cliargdaxtest.AssertOpt(r, conn, "foo", 1))
+	assert.Equal(t, r.errors, []string{
+		`option "foo" is "1", want "2"`,
+		`option "bar" is true, want false`,
+		`option "baz" is not given, want "1"`,
+		`option "foo" is []string{"1"}, want []string{}`,
+		`unsupported type of expected value of option "foo": int`,
+	})
+}
+
+func TestAssertParseError(t *testing.T) {
+	cfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "level", HasArg: true},
+	}
+	reason := cliargdaxtest.AssertParseError[cliargs.UnconfiguredOption](
+		t, []string{"app", "--foo"}, cfgs,
+	)
+	assert.Equal(t, reason.Option, "foo")
+}
+
+func TestAssertParseError_fail(t *testing.T) {
+	cfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "level", HasArg: true},
+	}
+
+	r := &recorder{TB: t}
+	reason := cliargdaxtest.AssertParseError[cliargs.UnconfiguredOption](
+		r, []string{"app", "--level=1"}, cfgs,
+	)
+	assert.Equal(t, reason, cliargs.UnconfiguredOption{})
+	reason = cliargdaxtest.AssertParseError[cliargs.UnconfiguredOption](
+		r, []string{"app", "--level"}, cfgs,
+	)
+	assert.Equal(t, reason, cliargs.UnconfiguredOption{})
+	assert.Equal(t, len(r.fatals), 2)
+	assert.Equal(t, r.fatals[0], `parsing ["app" "--level=1"] succeeded, `+
+		`want an error of cliargs.UnconfiguredOption`)
+	assert.Contains(t, r.fatals[1], `parsing ["app" "--level"] failed with `+
+		`cliargs.OptionNeedsArg, want cliargs.UnconfiguredOption`)
+}
+
+func TestWithArgs(t *testing.T) {
+	orig := os.Args
+
+	t.Run("swap", func(t *testing.T) {
+		cliargdaxtest.WithArgs(t, []string{"app", "--foo"}, func() {
+			assert.Equal(t, os.Args, []string{"app", "--foo"})
+		})
+		assert.Equal(t, os.Args, []string{"app", "--foo"})
+	})
+
+	assert.Equal(t, os.Args, orig)
+}
@@ -0,0 +1,48 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+/*
+Package cliargdaxtest provides helper functions to write tests of command line
+argument parsing with cliargdax package.
+
+The helpers parse command line arguments with the standalone pipeline of
+cliargdax, so neither a sabi.DaxBase nor a transaction is needed, and report
+failures to testing.TB.
+
+A test which parses arguments into an option store and checks the options is
+written as follows:
+
+	func TestServe(t *testing.T) {
+		opts := struct {
+			Port    int  `optcfg:"port=80"`
+			Verbose bool `optcfg:"verbose,v"`
+		}{}
+		conn := cliargdaxtest.ParseOrFail(t, []string{"app", "-v"}, &opts)
+		cliargdaxtest.AssertOpt(t, conn, "verbose", true)
+		cliargdaxtest.AssertOpt(t, conn, "port", "80")
+	}
+
+A test which checks the reason of a parse error is written as follows:
+
+	func TestServe_unknownOption(t *testing.T) {
+		cfgs := []cliargs.OptCfg{
+			cliargs.OptCfg{Name: "port", HasArg: true},
+		}
+		r := cliargdaxtest.AssertParseError[cliargs.UnconfiguredOption](
+			t, []string{"app", "--host=x"}, cfgs,
+		)
+		if r.Option != "host" {
+			t.Errorf("unexpected option: %s", r.Option)
+		}
+	}
+
+A test of code which reads os.Args directly is written as follows:
+
+	func TestMain_help(t *testing.T) {
+		cliargdaxtest.WithArgs(t, []string{"app", "--help"}, func() {
+			...
+		})
+	}
+*/
+package cliargdaxtest
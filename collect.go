@@ -0,0 +1,128 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"github.com/sttk/cliargs"
+	"github.com/sttk/sabi/errs"
+)
+
+type /* error reasons */ (
+	// MultipleParseErrors is the error reason which indicates that one or more
+	// errors occurred in parsing command line arguments in the mode enabled by
+	// DaxSrc#CollectAllErrors method.
+	// The field Errors is the array of the errors, in which the errors of
+	// option formats, like unknown options and missing option arguments, are
+	// in the order of command line arguments, followed by the errors of option
+	// arguments, of command arguments, and of validations.
+	MultipleParseErrors struct {
+		Errors []errs.Err
+	}
+)
+
+// CollectAllErrors is the method to enable or disable the mode to collect all
+// errors in parsing command line arguments instead of stopping at the first
+// one.
+// If enabled, the parsing continues past unknown options, missing option
+// arguments, and invalid option arguments, and Setup method returns an
+// errs.Err with the reason: MultipleParseErrors, which holds every error.
+// The results of the parsing which succeeded, like DaxConn#Cmd, are set even
+// if the parsing fails in this mode, so a usage can be shown with them.
+// Errors which make the parsing impossible, like an error of reading a config
+// file, are returned as they are.
+// This mode is disabled by default.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) CollectAllErrors(collect bool) *DaxSrc {
+	ds.mode.collectAll = collect
+	return ds
+}
+
+// parseAllWith parses command line arguments with the option configurations
+// in the same way as cliargs.ParseWith function, but returns all the errors
+// which occurred.
+func parseAllWith(
+	osArgs []string, optCfgs []cliargs.OptCfg,
+) (cliargs.Cmd, []error) {
+	var errList []error
+
+	plainCfgs := make([]cliargs.OptCfg, len(optCfgs))
+	for i, cfg := range optCfgs {
+		plainCfgs[i] = cliargs.OptCfg{
+			Name: cfg.Name, Aliases: cfg.Aliases,
+			HasArg: cfg.HasArg, IsArray: cfg.IsArray,
+		}
+	}
+	indexes := cfgIndexes(optCfgs)
+	given := make(map[string]bool)
+
+	for _, tok := range scanArgsWith(osArgs, optCfgs) {
+		if !tok.isOpt() {
+			continue
+		}
+		arg := optArg(tok.name)
+		if tok.hasValue {
+			arg = optArg(tok.name, tok.value)
+		}
+		if _, e := cliargs.ParseWith([]string{"", arg}, plainCfgs); e != nil {
+			errList = append(errList, e)
+			continue
+		}
+		if i, exists := indexes[tok.name]; exists {
+			cfg := optCfgs[i]
+			if given[cfg.Name] && cfg.HasArg && !cfg.IsArray {
+				errList = append(errList, cliargs.OptionIsNotArray{Option: cfg.Name})
+			}
+			given[cfg.Name] = true
+		}
+	}
+
+	cfgs := make([]cliargs.OptCfg, len(optCfgs))
+	copy(cfgs, optCfgs)
+	for i, cfg := range cfgs {
+		if cfg.OnParsed == nil {
+			continue
+		}
+		onParsed := *cfg.OnParsed
+		collect := func(a []string) error {
+			if e := onParsed(a); e != nil {
+				errList = append(errList, e)
+			}
+			return nil
+		}
+		cfgs[i].OnParsed = &collect
+	}
+
+	cmd, _ := cliargs.ParseWith(osArgs, cfgs)
+	return cmd, errList
+}
+
+// validateAll binds command arguments and validates the results of parsing
+// like parseArgs method, and joins their errors to the errors of parsing.
+// If the error of parsing does not have the reason: MultipleParseErrors, it
+// is returned as it is.
+func (ds *DaxSrc) validateAll(r *parseResult, err errs.Err) errs.Err {
+	var errList []errs.Err
+	if err.IsNotOk() {
+		m, ok := err.Reason().(MultipleParseErrors)
+		if !ok {
+			return err
+		}
+		errList = m.Errors
+	}
+	if e := r.bindArgs(); e.IsNotOk() {
+		errList = append(errList, e)
+	}
+	if e := ds.validate(*r); e.IsNotOk() {
+		errList = append(errList, e)
+	}
+	return joinParseErrs(errList)
+}
+
+func joinParseErrs(errList []errs.Err) errs.Err {
+	if len(errList) == 0 {
+		return errs.Ok()
+	}
+	return errs.New(MultipleParseErrors{Errors: errList})
+}
@@ -0,0 +1,113 @@
+package cliargdax_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+)
+
+func TestCollectAllErrors(t *testing.T) {
+	type Options struct {
+		Verbose bool   `optcfg:"verbose,v"`
+		Port    int    `optcfg:"port"`
+		Name    string `optcfg:"name"`
+		Level   string `optcfg:"level"`
+		Format  string `optcfg:"format" optchoices:"json,yaml"`
+	}
+
+	opts := Options{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions([]string{
+		"app", "--qux", "-v", "--port=x", "--name=foo", "--level", "file",
+		"--format=xml", "--verbose=maybe",
+	}, &opts).CollectAllErrors(true).Lazy()
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	err = conn.ParseErr()
+
+	switch r := err.Reason().(type) {
+	case cliargdax.MultipleParseErrors:
+		assert.Equal(t, len(r.Errors), 4)
+		assert.Equal(t, r.Errors[0].Reason(),
+			cliargs.UnconfiguredOption{Option: "qux"})
+		switch r1 := r.Errors[1].Reason().(type) {
+		case cliargdax.FailToParseBool:
+			assert.Equal(t, r1.Option, "verbose")
+			assert.Equal(t, r1.Input, "maybe")
+		default:
+			assert.Fail(t, r.Errors[1].Error())
+		}
+		switch r1 := r.Errors[2].Reason().(type) {
+		case cliargs.FailToParseInt:
+			assert.Equal(t, r1.Option, "port")
+			assert.Equal(t, r1.Input, "x")
+		default:
+			assert.Fail(t, r.Errors[2].Error())
+		}
+		switch r1 := r.Errors[3].Reason().(type) {
+		case cliargdax.OptionValueNotInChoices:
+			assert.Equal(t, r1.Option, "format")
+			assert.Equal(t, r1.Value, "xml")
+		default:
+			assert.Fail(t, r.Errors[3].Error())
+		}
+	default:
+		assert.Fail(t, err.Error())
+	}
+
+	cmd := conn.Cmd()
+	assert.Equal(t, cmd.Name, "app")
+	assert.True(t, cmd.HasOpt("verbose"))
+	assert.Equal(t, cmd.OptArg("name"), "foo")
+	assert.Equal(t, cmd.OptArg("level"), "file")
+	assert.Equal(t, opts.Name, "foo")
+}
+
+func TestCollectAllErrors_missingArgAndNotArray(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "--name=a", "--name=b", "--level",
+	}, []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "name", HasArg: true},
+		cliargs.OptCfg{Name: "level", HasArg: true},
+	}).CollectAllErrors(true)
+	_, err := setupWithOptCfgs(t, ds)
+
+	switch r := err.Reason().(type) {
+	case cliargdax.MultipleParseErrors:
+		assert.Equal(t, len(r.Errors), 2)
+		assert.Equal(t, r.Errors[0].Reason(), cliargdax.OptionGivenTwice{
+			Option: "name", FirstIndex: 1, SecondIndex: 2,
+			FirstValue: "a", SecondValue: "b",
+		})
+		assert.Equal(t, r.Errors[1].Reason(),
+			cliargs.OptionNeedsArg{Option: "level"})
+	default:
+		assert.Fail(t, err.Error())
+	}
+
+	assert.Equal(t, cliargdax.FormatError(err, nil),
+		"option --name cannot be given more than once: "+
+			"\"a\" at argument 1 and \"b\" at argument 2\n"+
+			"option --level requires an argument\n"+
+			"Try '--help' for more information.")
+}
+
+func TestCollectAllErrors_ok(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgs([]string{"app", "--foo"}).
+		CollectAllErrors(true)
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.True(t, conn.Cmd().HasOpt("foo"))
+}
+
+func TestCollectAllErrors_disabled(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "--qux", "--level",
+	}, []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "level", HasArg: true},
+	})
+	_, err := setupWithOptCfgs(t, ds)
+	assert.Equal(t, err.Reason(), cliargs.UnconfiguredOption{Option: "qux"})
+}
@@ -0,0 +1,65 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"github.com/sttk/cliargs"
+)
+
+type /* error reasons */ (
+	// CombinedOptionNeedsArg is the error reason which indicates that a short
+	// option which takes an option argument is combined with other short
+	// options but is not the last of them, like -c in -acb.
+	// The fields Option, Letter, and Arg are the option name, the character of
+	// the short option, and the command line argument.
+	// The error of cliargs.OptionNeedsArg is set as the cause.
+	CombinedOptionNeedsArg struct {
+		Option string
+		Letter string
+		Arg    string
+	}
+)
+
+// findCombinedOptsNeedingArg finds the short options which take option
+// arguments but are combined with other short options not at the last, like
+// -c in -acb, and returns a map of which keys are their option names.
+func findCombinedOptsNeedingArg(
+	osArgs []string, optCfgs []cliargs.OptCfg, mode parseMode,
+) map[string]CombinedOptionNeedsArg {
+	indexes := cfgIndexes(optCfgs)
+	sc := argScanner{
+		stopAtFirstArg:  mode.stopAtFirstArg,
+		allowSingleDash: mode.allowSingleDash,
+	}
+	sc.takesArg = func(name string) bool {
+		i, exists := indexes[name]
+		return exists && optCfgs[i].HasArg
+	}
+	sc.isConfigured = func(name string) bool {
+		_, exists := indexes[name]
+		return exists
+	}
+
+	var found map[string]CombinedOptionNeedsArg
+	toks := sc.scan(osArgs)
+	for i, tok := range toks {
+		if !tok.isShort || tok.hasValue || !sc.takesArg(tok.name) {
+			continue
+		}
+		if i+1 >= len(toks) || toks[i+1].index != tok.index {
+			continue
+		}
+		if found == nil {
+			found = make(map[string]CombinedOptionNeedsArg)
+		}
+		name := optCfgs[indexes[tok.name]].Name
+		if _, exists := found[name]; !exists {
+			found[name] = CombinedOptionNeedsArg{
+				Option: name, Letter: tok.name, Arg: osArgs[tok.index],
+			}
+		}
+	}
+	return found
+}
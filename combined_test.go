@@ -0,0 +1,69 @@
+package cliargdax_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+)
+
+type combinedOptions struct {
+	All   bool   `optcfg:"all,a"`
+	Brief bool   `optcfg:"brief,b"`
+	Count int    `optcfg:"count,c"`
+	File  string `optcfg:"file,f"`
+}
+
+func TestCombined_abc(t *testing.T) {
+	opts := combinedOptions{}
+	_, err := setupForOptions(t, []string{"app", "-ab"}, &opts)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, opts, combinedOptions{All: true, Brief: true})
+
+	opts = combinedOptions{}
+	_, err = setupForOptions(t, []string{"app", "-abc=3"}, &opts)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, opts, combinedOptions{All: true, Brief: true, Count: 3})
+
+	opts = combinedOptions{}
+	conn, err := setupForOptions(t, []string{"app", "-abc", "3", "x"}, &opts)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, opts, combinedOptions{All: true, Brief: true, Count: 3})
+	assert.Equal(t, conn.Cmd().Args(), []string{"x"})
+
+	opts = combinedOptions{}
+	_, err = setupForOptions(t, []string{"app", "-xvf", "file.tar"}, &opts)
+	assert.Equal(t, err.Reason(), cliargs.UnconfiguredOption{Option: "x"})
+}
+
+func TestCombined_tarStyle(t *testing.T) {
+	opts := combinedOptions{}
+	_, err := setupForOptions(t, []string{"app", "-abf", "file.tar"}, &opts)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, opts, combinedOptions{
+		All: true, Brief: true, File: "file.tar",
+	})
+}
+
+func TestCombined_invalid(t *testing.T) {
+	opts := combinedOptions{}
+	_, err := setupForOptions(t, []string{"app", "-ab3"}, &opts)
+	assert.Equal(t, err.Reason(), cliargs.OptionHasInvalidChar{Option: "3"})
+
+	opts = combinedOptions{}
+	_, err = setupForOptions(t, []string{"app", "-acb", "3"}, &opts)
+	switch r := err.Reason().(type) {
+	case cliargdax.CombinedOptionNeedsArg:
+		assert.Equal(t, r.Option, "count")
+		assert.Equal(t, r.Letter, "c")
+		assert.Equal(t, r.Arg, "-acb")
+		assert.Equal(t, err.Cause(), cliargs.OptionNeedsArg{Option: "count"})
+	default:
+		assert.Fail(t, err.Error())
+	}
+
+	opts = combinedOptions{}
+	_, err = setupForOptions(t, []string{"app", "--count"}, &opts)
+	assert.Equal(t, err.Reason(), cliargs.OptionNeedsArg{Option: "count"})
+}
@@ -0,0 +1,289 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"io"
+	"strings"
+
+	"github.com/sttk/cliargs"
+	"github.com/sttk/sabi/errs"
+)
+
+type /* error reasons */ (
+	// UnsupportedShell is the error reason which indicates that a completion
+	// script cannot be generated for the shell.
+	// The field Shell is the specified shell name.
+	UnsupportedShell struct {
+		Shell string
+	}
+
+	// FailToWriteCompletion is the error reason which indicates that a
+	// completion script cannot be written to the io.Writer.
+	// The field Shell is the shell name of the completion script.
+	FailToWriteCompletion struct {
+		Shell string
+	}
+)
+
+// compOpt is the completion specification of an option.
+// The field choices is the array of option arguments to be completed, and
+// the field file is "file" or "dir" if the option argument is completed as a
+// file path or a directory path.
+type compOpt struct {
+	shorts  []string
+	longs   []string
+	hasArg  bool
+	metavar string
+	choices []string
+	file    string
+	desc    string
+}
+
+// GenCompletion is the function to write a completion script for the shell
+// to the io.Writer.
+// The shell is one of bash, zsh, and fish.
+// The completion script completes the option names and aliases in the
+// OptCfg array, and the option arguments of the options of which ArgHelp
+// fields are the enumeration of choices, like {json|yaml|table}, which is set
+// by DaxSrc#Choices method or the optchoices struct tag.
+// The option arguments of the options of which ArgHelp fields are <path> or
+// <file>, which is set for Path type fields, are completed as file paths,
+// and ones of <dir> are completed as directory paths, ignoring the case.
+// The metavar of an option argument, like FILE for <FILE>, is used as the
+// message of the argument in the zsh completion script.
+// If the shell is not supported, this function returns an errs.Err with the
+// reason: UnsupportedShell, and if failing to write, it returns an errs.Err
+// with the reason: FailToWriteCompletion.
+func GenCompletion(
+	shell string, cmdName string, cfgs []cliargs.OptCfg, w io.Writer,
+) errs.Err {
+	var gen func(string, []compOpt) string
+	switch shell {
+	case "bash":
+		gen = genBashCompletion
+	case "zsh":
+		gen = genZshCompletion
+	case "fish":
+		gen = genFishCompletion
+	default:
+		return errs.New(UnsupportedShell{Shell: shell})
+	}
+
+	_, e := io.WriteString(w, gen(cmdName, makeCompOpts(cfgs)))
+	if e != nil {
+		return errs.New(FailToWriteCompletion{Shell: shell}, e)
+	}
+	return errs.Ok()
+}
+
+// GenCompletion is the method to write a completion script for the shell to
+// the io.Writer, with the command name and the OptCfg array of this
+// DaxConn, excluding hidden options.
+// See GenCompletion function for details.
+func (conn *DaxConn) GenCompletion(shell string, w io.Writer) errs.Err {
+	conn.ds.parseLazily()
+	conn.ds.mutex.RLock()
+	defer conn.ds.mutex.RUnlock()
+	return GenCompletion(shell, conn.ds.cmd.Name, conn.ds.visibleOptCfgs(), w)
+}
+
+func makeCompOpts(cfgs []cliargs.OptCfg) []compOpt {
+	opts := make([]compOpt, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		if cfg.Name == "*" {
+			continue
+		}
+		o := compOpt{hasArg: cfg.HasArg, desc: cfg.Desc}
+		if i := strings.IndexByte(o.desc, '\n'); i >= 0 {
+			o.desc = o.desc[:i]
+		}
+		for _, name := range append([]string{cfg.Name}, cfg.Aliases...) {
+			if len([]rune(name)) == 1 {
+				o.shorts = append(o.shorts, name)
+			} else {
+				o.longs = append(o.longs, name)
+			}
+		}
+		if cfg.HasArg {
+			o.metavar = argMetavar(cfg)
+			a := cfg.ArgHelp
+			switch {
+			case len(a) > 2 && a[0] == '{' && a[len(a)-1] == '}':
+				o.choices = strings.Split(a[1:len(a)-1], "|")
+			case strings.EqualFold(a, "<path>") || strings.EqualFold(a, "<file>"):
+				o.file = "file"
+			case strings.EqualFold(a, "<dir>"):
+				o.file = "dir"
+			}
+		}
+		opts = append(opts, o)
+	}
+	return opts
+}
+
+func (o compOpt) flags() []string {
+	var flags []string
+	for _, s := range o.shorts {
+		flags = append(flags, "-"+s)
+	}
+	for _, l := range o.longs {
+		flags = append(flags, "--"+l)
+	}
+	return flags
+}
+
+// shQuote quotes the string with single quotes for POSIX shells.
+func shQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// funcName makes a shell function name from the command name.
+func funcName(cmdName string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') ||
+			('0' <= r && r <= '9') {
+			return r
+		}
+		return '_'
+	}, cmdName)
+}
+
+func genBashCompletion(cmdName string, opts []compOpt) string {
+	fn := "_" + funcName(cmdName) + "_completion"
+
+	var b strings.Builder
+	b.WriteString("# bash completion for " + cmdName + "\n")
+	b.WriteString(fn + "() {\n")
+	b.WriteString("    local cur prev\n")
+	b.WriteString("    cur=\"${COMP_WORDS[COMP_CWORD]}\"\n")
+	b.WriteString("    prev=\"${COMP_WORDS[COMP_CWORD-1]}\"\n")
+
+	var all []string
+	var cases []string
+	for _, o := range opts {
+		flags := o.flags()
+		all = append(all, flags...)
+		if !o.hasArg {
+			continue
+		}
+		var reply string
+		switch {
+		case len(o.choices) > 0:
+			words := shQuote(strings.Join(o.choices, " "))
+			reply = "COMPREPLY=( $(compgen -W " + words + " -- \"$cur\") )"
+		case o.file == "file":
+			reply = "COMPREPLY=( $(compgen -f -- \"$cur\") )"
+		case o.file == "dir":
+			reply = "COMPREPLY=( $(compgen -d -- \"$cur\") )"
+		default:
+			reply = "COMPREPLY=()"
+		}
+		cases = append(cases, "        "+strings.Join(flags, "|")+")\n"+
+			"            "+reply+"\n"+
+			"            return 0\n"+
+			"            ;;\n")
+	}
+
+	if len(cases) > 0 {
+		b.WriteString("    case \"$prev\" in\n")
+		for _, c := range cases {
+			b.WriteString(c)
+		}
+		b.WriteString("    esac\n")
+	}
+
+	b.WriteString("    if [[ \"$cur\" == -* ]]; then\n")
+	b.WriteString("        COMPREPLY=( $(compgen -W " +
+		shQuote(strings.Join(all, " ")) + " -- \"$cur\") )\n")
+	b.WriteString("        return 0\n")
+	b.WriteString("    fi\n")
+	b.WriteString("    COMPREPLY=( $(compgen -f -- \"$cur\") )\n")
+	b.WriteString("}\n")
+	b.WriteString("complete -F " + fn + " " + cmdName + "\n")
+	return b.String()
+}
+
+// zshEscape escapes the characters which are special in specifications of
+// _arguments function of zsh.
+func zshEscape(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`, "[", `\[`, "]", `\]`, ":", `\:`,
+	).Replace(s)
+}
+
+func genZshCompletion(cmdName string, opts []compOpt) string {
+	var b strings.Builder
+	b.WriteString("#compdef " + cmdName + "\n\n")
+	b.WriteString("_arguments \\\n")
+
+	for _, o := range opts {
+		flags := o.flags()
+		s := "[" + zshEscape(o.desc) + "]"
+		if o.hasArg {
+			name := zshEscape(o.metavar)
+			switch {
+			case len(o.choices) > 0:
+				choices := make([]string, len(o.choices))
+				for i, c := range o.choices {
+					choices[i] = strings.NewReplacer(
+						" ", `\ `, "(", `\(`, ")", `\)`,
+					).Replace(zshEscape(c))
+				}
+				s += ":" + name + ":(" + strings.Join(choices, " ") + ")"
+			case o.file == "file":
+				s += ":" + name + ":_files"
+			case o.file == "dir":
+				s += ":" + name + ":_files -/"
+			default:
+				s += ":" + name + ":"
+			}
+		}
+		if len(flags) == 1 {
+			b.WriteString("  " + shQuote(flags[0]+s) + " \\\n")
+		} else {
+			excl := shQuote("(" + strings.Join(flags, " ") + ")")
+			b.WriteString("  " + excl + "{" + strings.Join(flags, ",") + "}" +
+				shQuote(s) + " \\\n")
+		}
+	}
+
+	b.WriteString("  '*:file:_files'\n")
+	return b.String()
+}
+
+func genFishCompletion(cmdName string, opts []compOpt) string {
+	var b strings.Builder
+	b.WriteString("# fish completion for " + cmdName + "\n")
+
+	for _, o := range opts {
+		line := "complete -c " + cmdName
+		for _, s := range o.shorts {
+			line += " -s " + s
+		}
+		for _, l := range o.longs {
+			line += " -l " + l
+		}
+		if len(o.desc) > 0 {
+			line += " -d " + shQuote(o.desc)
+		}
+		if o.hasArg {
+			line += " -r"
+			switch {
+			case len(o.choices) > 0:
+				line += " -f -a " + shQuote(strings.Join(o.choices, " "))
+			case o.file == "file":
+				line += " -F"
+			case o.file == "dir":
+				line += " -f -a '(__fish_complete_directories)'"
+			default:
+				line += " -f"
+			}
+		}
+		b.WriteString(line + "\n")
+	}
+
+	return b.String()
+}
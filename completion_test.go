@@ -0,0 +1,130 @@
+package cliargdax_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+)
+
+var completionOptCfgs = []cliargs.OptCfg{
+	cliargs.OptCfg{Name: "verbose", Aliases: []string{"v"}, Desc: "Verbose mode."},
+	cliargs.OptCfg{
+		Name: "format", Aliases: []string{"f"}, HasArg: true,
+		ArgHelp: "{json|yaml}", Desc: "Output [format]: it's json or yaml.",
+	},
+	cliargs.OptCfg{Name: "config", HasArg: true, ArgHelp: "<path>"},
+	cliargs.OptCfg{Name: "out-dir", HasArg: true, ArgHelp: "<dir>"},
+	cliargs.OptCfg{Name: "name", HasArg: true},
+	cliargs.OptCfg{Name: "*"},
+}
+
+func TestCompletion_GenCompletion_bash(t *testing.T) {
+	var b strings.Builder
+	err := cliargdax.GenCompletion("bash", "my-app", completionOptCfgs, &b)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, b.String(), `# bash completion for my-app
+_my_app_completion() {
+    local cur prev
+    cur="${COMP_WORDS[COMP_CWORD]}"
+    prev="${COMP_WORDS[COMP_CWORD-1]}"
+    case "$prev" in
+        -f|--format)
+            COMPREPLY=( $(compgen -W 'json yaml' -- "$cur") )
+            return 0
+            ;;
+        --config)
+            COMPREPLY=( $(compgen -f -- "$cur") )
+            return 0
+            ;;
+        --out-dir)
+            COMPREPLY=( $(compgen -d -- "$cur") )
+            return 0
+            ;;
+        --name)
+            COMPREPLY=()
+            return 0
+            ;;
+    esac
+    if [[ "$cur" == -* ]]; then
+        COMPREPLY=( $(compgen -W '-v --verbose -f --format --config --out-dir --name' -- "$cur") )
+        return 0
+    fi
+    COMPREPLY=( $(compgen -f -- "$cur") )
+}
+complete -F _my_app_completion my-app
+`)
+}
+
+func TestCompletion_GenCompletion_zsh(t *testing.T) {
+	var b strings.Builder
+	err := cliargdax.GenCompletion("zsh", "app", completionOptCfgs, &b)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, b.String(), `#compdef app
+
+_arguments \
+  '(-v --verbose)'{-v,--verbose}'[Verbose mode.]' \
+  '(-f --format)'{-f,--format}'[Output \[format\]\: it'\''s json or yaml.]:FORMAT:(json yaml)' \
+  '--config[]:path:_files' \
+  '--out-dir[]:dir:_files -/' \
+  '--name[]:NAME:' \
+  '*:file:_files'
+`)
+}
+
+func TestCompletion_GenCompletion_fish(t *testing.T) {
+	var b strings.Builder
+	err := cliargdax.GenCompletion("fish", "app", completionOptCfgs, &b)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, b.String(), `# fish completion for app
+complete -c app -s v -l verbose -d 'Verbose mode.'
+complete -c app -s f -l format -d 'Output [format]: it'\''s json or yaml.' -r -f -a 'json yaml'
+complete -c app -l config -r -F
+complete -c app -l out-dir -r -f -a '(__fish_complete_directories)'
+complete -c app -l name -r -f
+`)
+}
+
+func TestCompletion_GenCompletion_unsupportedShell(t *testing.T) {
+	var b strings.Builder
+	err := cliargdax.GenCompletion("tcsh", "app", completionOptCfgs, &b)
+	switch r := err.Reason().(type) {
+	case cliargdax.UnsupportedShell:
+		assert.Equal(t, r.Shell, "tcsh")
+	default:
+		assert.Fail(t, err.Error())
+	}
+	assert.Equal(t, b.String(), "")
+}
+
+func TestCompletion_GenCompletion_failToWrite(t *testing.T) {
+	err := cliargdax.GenCompletion("bash", "app", completionOptCfgs,
+		failWriter{})
+	switch r := err.Reason().(type) {
+	case cliargdax.FailToWriteCompletion:
+		assert.Equal(t, r.Shell, "bash")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestCompletion_DaxConn_GenCompletion(t *testing.T) {
+	type Options struct {
+		Format string         `optcfg:"format" optchoices:"json,yaml"`
+		Config cliargdax.Path `optcfg:"config"`
+	}
+
+	options := Options{}
+	conn, err := setupForOptions(t, []string{"/path/to/app"}, &options)
+	assert.True(t, err.IsOk())
+
+	var b strings.Builder
+	err = conn.GenCompletion("fish", &b)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, b.String(), `# fish completion for app
+complete -c app -l format -r -f -a 'json yaml'
+complete -c app -l config -r -F
+`)
+}
@@ -0,0 +1,417 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sttk/cliargs"
+	"github.com/sttk/sabi/errs"
+	"gopkg.in/yaml.v3"
+)
+
+type /* error reasons */ (
+	// UnsupportedConfigFormat is the error reason which indicates that the
+	// format of a config file is neither "json" nor "yaml", or that it cannot
+	// be determined from the file extension.
+	// The fields Path and Format are the path of the config file and the format.
+	UnsupportedConfigFormat struct {
+		Path   string
+		Format string
+	}
+
+	// FailToReadConfigFile is the error reason which indicates that a config
+	// file cannot be read.
+	// The field Path is the path of the config file.
+	FailToReadConfigFile struct {
+		Path string
+	}
+
+	// FailToDecodeConfigFile is the error reason which indicates that the
+	// content of a config file is not a valid document of its format or its top
+	// level is not a mapping.
+	// The fields Path and Format are the path of the config file and the format.
+	FailToDecodeConfigFile struct {
+		Path   string
+		Format string
+	}
+
+	// ConfigValueTypeMismatch is the error reason which indicates that the type
+	// of a value in a config file does not match the option, for example, a
+	// list is given to an option which takes a single argument.
+	// The fields Key, Option, and Input are the key path of the value, like
+	// "server.ports[1]", the option name, and the text of the value.
+	ConfigValueTypeMismatch struct {
+		Key    string
+		Option string
+		Input  string
+	}
+
+	// FailToParseConfigValue is the error reason which indicates that a value
+	// in a config file cannot be converted to the field type of an option store.
+	// The fields Option, Field, Key, and Input are the option name, the field
+	// name, the key path of the value, and the text of the value.
+	FailToParseConfigValue struct {
+		Option string
+		Field  string
+		Key    string
+		Input  string
+	}
+)
+
+type configFile struct {
+	path   string
+	format string
+}
+
+type configValue struct {
+	key   string
+	input string
+}
+
+// WithConfigFile is the method to add the path of a config file, of which
+// values are used for options which are given neither in command line
+// arguments nor by environment variables.
+// So the precedence is command line arguments, environment variables, the
+// config file, and default values, in descending order.
+// The supported formats are "json" and "yaml" (or "yml"), and if the format is
+// empty, it is determined from the file extension.
+// The top-level keys of the config file are option names, and a nested mapping
+// which is not for a map option is flattened by joining keys with "-", which
+// matches the default prefix of options of a nested struct.
+// If this method is called multiple times, the paths are searched in the
+// order and only the first existing file is loaded.
+// These paths are default locations, so it is not an error that none of them
+// exists.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) WithConfigFile(path string, format string) *DaxSrc {
+	ds.configFiles = append(ds.configFiles, configFile{
+		path: path, format: format,
+	})
+	return ds
+}
+
+// EnableConfigOpt is the method to enable the option to specify the path of a
+// config file, of which name and aliases are specified as the arguments.
+// If no argument is specified, the name is "config".
+// Since the config file is explicitly requested with this option, it is an
+// error that the file does not exist.
+// The format of the file is determined from its extension, or is the format of
+// the first path specified with WithConfigFile method if the extension is
+// unknown.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) EnableConfigOpt(nameAndAliases ...string) *DaxSrc {
+	if len(nameAndAliases) == 0 {
+		nameAndAliases = []string{"config"}
+	}
+	ds.configOpt = &cliargs.OptCfg{
+		Name:    nameAndAliases[0],
+		Aliases: nameAndAliases[1:],
+		HasArg:  true,
+		Desc:    msg(MsgConfigOptDesc),
+		ArgHelp: "<path>",
+	}
+	return ds
+}
+
+// ConfigFile is the method to retrieve the path of the config file which is
+// loaded. If no config file is loaded, this method returns an empty string.
+func (conn *DaxConn) ConfigFile() string {
+	conn.ds.parseLazily()
+	conn.ds.mutex.RLock()
+	defer conn.ds.mutex.RUnlock()
+	return conn.ds.configPath
+}
+
+// loadConfig finds the config file with the option enabled by
+// EnableConfigOpt method before the full parsing, or from the paths specified
+// with WithConfigFile method, and stores its content into the parse result.
+func (ds *DaxSrc) loadConfig(r *parseResult) errs.Err {
+	files := ds.configFiles
+	tok, explicit := ds.addOpt(r, ds.configOpt)
+	if explicit {
+		format := ""
+		if len(ds.configFiles) > 0 {
+			format = ds.configFiles[0].format
+		}
+		if len(configFormatOf(tok.value)) > 0 {
+			format = ""
+		}
+		files = []configFile{configFile{path: tok.value, format: format}}
+	}
+
+	for _, f := range files {
+		if err := checkContext(ds.ctx); err.IsNotOk() {
+			return err
+		}
+		data, e := readFile(ds.fsys, f.path)
+		if e != nil {
+			if !explicit && errors.Is(e, fs.ErrNotExist) {
+				continue
+			}
+			return errs.New(FailToReadConfigFile{Path: f.path}, e)
+		}
+
+		format := strings.ToLower(f.format)
+		if len(format) == 0 {
+			format = configFormatOf(f.path)
+		}
+		m, err := decodeConfig(f.path, format, data)
+		if err.IsNotOk() {
+			return err
+		}
+		r.config = m
+		r.configPath = f.path
+		break
+	}
+	return errs.Ok()
+}
+
+func configFormatOf(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		return "json"
+	case ".yaml", ".yml":
+		return "yaml"
+	default:
+		return ""
+	}
+}
+
+func decodeConfig(path, format string, data []byte) (map[string]any, errs.Err) {
+	var m map[string]any
+	var e error
+
+	switch format {
+	case "json":
+		dec := json.NewDecoder(bytes.NewReader(data))
+		dec.UseNumber()
+		e = dec.Decode(&m)
+	case "yaml", "yml":
+		format = "yaml"
+		e = yaml.Unmarshal(data, &m)
+	default:
+		return nil, errs.New(UnsupportedConfigFormat{Path: path, Format: format})
+	}
+
+	if e != nil {
+		return nil, errs.New(FailToDecodeConfigFile{Path: path, Format: format}, e)
+	}
+	return m, errs.Ok()
+}
+
+// makeConfigArgs makes command line arguments from the values of the config
+// file for the options which are not given in the command line arguments,
+// which are already supplemented with environment variables.
+// This function also returns a map of which keys are the names of these
+// options and of which values are the key paths and the texts in the config
+// file, to report conversion errors with them.
+func makeConfigArgs(
+	osArgs []string, optCfgs []cliargs.OptCfg, metas map[string]optMeta,
+	config map[string]any,
+) ([]string, map[string]configValue, errs.Err) {
+	if len(config) == 0 {
+		return nil, nil, errs.Ok()
+	}
+
+	cfgs := make([]cliargs.OptCfg, len(optCfgs))
+	for i, cfg := range optCfgs {
+		cfg.Default = nil
+		cfg.OnParsed = nil
+		cfgs[i] = cfg
+	}
+
+	cmd, e := cliargs.ParseWith(osArgs, cfgs)
+	if e != nil {
+		return nil, nil, errs.New(e)
+	}
+
+	byName := make(map[string]cliargs.OptCfg, len(optCfgs))
+	for _, cfg := range optCfgs {
+		if cfg.Name != "*" {
+			byName[cfg.Name] = cfg
+		}
+	}
+
+	cb := configArgsBuilder{
+		cmd: cmd, cfgs: byName, metas: metas, opts: make(map[string]configValue),
+	}
+	if err := cb.add(config, "", ""); err.IsNotOk() {
+		return nil, nil, err
+	}
+	return cb.args, cb.opts, errs.Ok()
+}
+
+type configArgsBuilder struct {
+	cmd   cliargs.Cmd
+	cfgs  map[string]cliargs.OptCfg
+	metas map[string]optMeta
+	args  []string
+	opts  map[string]configValue
+}
+
+func (cb *configArgsBuilder) add(m map[string]any, key, prefix string) errs.Err {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		v := m[k]
+		path := k
+		if len(key) > 0 {
+			path = key + "." + k
+		}
+		name := prefix + k
+
+		cfg, exists := cb.cfgs[name]
+		if !exists {
+			if sub, ok := configMapOf(v); ok {
+				if err := cb.add(sub, path, name+"-"); err.IsNotOk() {
+					return err
+				}
+			}
+			continue
+		}
+		if cb.cmd.HasOpt(name) || v == nil {
+			continue
+		}
+		if err := cb.addOpt(cfg, v, path); err.IsNotOk() {
+			return err
+		}
+	}
+	return errs.Ok()
+}
+
+func (cb *configArgsBuilder) addOpt(
+	cfg cliargs.OptCfg, v any, key string,
+) errs.Err {
+	name := cfg.Name
+
+	mismatch := func(key string, v any) errs.Err {
+		return errs.New(ConfigValueTypeMismatch{
+			Key: key, Option: name, Input: fmt.Sprint(v),
+		})
+	}
+
+	if m := cb.metas[name]; m.boolValue && !m.negatable {
+		if b, ok := v.(bool); ok {
+			cb.args = append(cb.args, boolOptArgs(cfg, b)...)
+			return errs.Ok()
+		}
+	}
+
+	if !cfg.HasArg {
+		if b, ok := v.(bool); ok {
+			if b {
+				cb.args = append(cb.args, optArg(name))
+			}
+			return errs.Ok()
+		}
+		if cb.metas[name].setCount == nil {
+			return mismatch(key, v)
+		}
+		s, _ := configScalarOf(v)
+		n, e := strconv.ParseUint(s, 10, 8)
+		if e != nil {
+			return mismatch(key, v)
+		}
+		for ; n > 0; n-- {
+			cb.args = append(cb.args, optArg(name))
+		}
+		return errs.Ok()
+	}
+
+	var values []string
+
+	if sub, ok := configMapOf(v); ok {
+		if !cfg.IsArray {
+			return mismatch(key, v)
+		}
+		keys := make([]string, 0, len(sub))
+		for k := range sub {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			s, ok := configScalarOf(sub[k])
+			if !ok {
+				return mismatch(key+"."+k, sub[k])
+			}
+			values = append(values, k+"="+s)
+		}
+	} else if list, ok := v.([]any); ok {
+		if !cfg.IsArray {
+			return mismatch(key, v)
+		}
+		for i, elem := range list {
+			s, ok := configScalarOf(elem)
+			if !ok {
+				return mismatch(fmt.Sprintf("%s[%d]", key, i), elem)
+			}
+			values = append(values, s)
+		}
+	} else {
+		s, ok := configScalarOf(v)
+		if !ok {
+			return mismatch(key, v)
+		}
+		values = append(values, s)
+	}
+
+	for _, s := range values {
+		cb.args = append(cb.args, optArg(name, s))
+	}
+	cb.opts[name] = configValue{key: key, input: strings.Join(values, ",")}
+	return errs.Ok()
+}
+
+func configMapOf(v any) (map[string]any, bool) {
+	switch m := v.(type) {
+	case map[string]any:
+		return m, true
+	case map[any]any:
+		sm := make(map[string]any, len(m))
+		for k, e := range m {
+			sm[fmt.Sprint(k)] = e
+		}
+		return sm, true
+	default:
+		return nil, false
+	}
+}
+
+func configScalarOf(v any) (string, bool) {
+	switch s := v.(type) {
+	case string:
+		return s, true
+	case bool:
+		return strconv.FormatBool(s), true
+	case json.Number:
+		return s.String(), true
+	case int:
+		return strconv.Itoa(s), true
+	case int64:
+		return strconv.FormatInt(s, 10), true
+	case uint64:
+		return strconv.FormatUint(s, 10), true
+	case float64:
+		return strconv.FormatFloat(s, 'f', -1, 64), true
+	case time.Time:
+		return s.Format(time.RFC3339Nano), true
+	default:
+		return "", false
+	}
+}
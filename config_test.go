@@ -0,0 +1,241 @@
+package cliargdax_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+)
+
+type configOptions struct {
+	Verbose bool              `optcfg:"verbose,v"`
+	Port    int               `optcfg:"port=80"`
+	Host    string            `optcfg:"host=localhost" optenv:"MY_APP_HOST"`
+	Tags    []string          `optcfg:"tag"`
+	Labels  map[string]string `optcfg:"label"`
+	Server  struct {
+		Timeout int `optcfg:"timeout"`
+	}
+}
+
+func TestConfig_WithConfigFile_json(t *testing.T) {
+	path := writeTempFile(t, "app.json", `{
+  "verbose": true,
+  "port": 8080,
+  "tag": ["a", "b"],
+  "label": {"env": "prod"},
+  "server": {"timeout": 30},
+  "unknown": 1
+}`)
+
+	opts := configOptions{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions(
+		[]string{"app", "--port=9000"}, &opts,
+	).WithConfigFile(path, "")
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.ConfigFile(), path)
+
+	assert.True(t, opts.Verbose)
+	assert.Equal(t, opts.Port, 9000)
+	assert.Equal(t, opts.Host, "localhost")
+	assert.Equal(t, opts.Tags, []string{"a", "b"})
+	assert.Equal(t, opts.Labels, map[string]string{"env": "prod"})
+	assert.Equal(t, opts.Server.Timeout, 30)
+}
+
+func TestConfig_WithConfigFile_yaml(t *testing.T) {
+	path := writeTempFile(t, "app.conf", `
+port: 8080
+host: example.com
+tag:
+  - x
+server:
+  timeout: 5
+`)
+
+	opts := configOptions{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions(
+		[]string{"app", "--tag=y"}, &opts,
+	).WithConfigFile(path, "yaml")
+
+	_, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	assert.False(t, opts.Verbose)
+	assert.Equal(t, opts.Port, 8080)
+	assert.Equal(t, opts.Host, "example.com")
+	assert.Equal(t, opts.Tags, []string{"y"})
+	assert.Equal(t, opts.Server.Timeout, 5)
+}
+
+func TestConfig_WithConfigFile_envVarPrecedes(t *testing.T) {
+	os.Setenv("MY_APP_HOST", "env.example.com")
+	defer os.Unsetenv("MY_APP_HOST")
+
+	path := writeTempFile(t, "app.json", `{"host": "file.example.com"}`)
+
+	opts := configOptions{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions(
+		[]string{"app"}, &opts,
+	).WithConfigFile(path, "")
+
+	_, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, opts.Host, "env.example.com")
+}
+
+func TestConfig_WithConfigFile_searchPaths(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.json")
+	path := writeTempFile(t, "app.yml", "port: 8080\n")
+
+	opts := configOptions{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions([]string{"app"}, &opts).
+		WithConfigFile(missing, "").
+		WithConfigFile(path, "")
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.ConfigFile(), path)
+	assert.Equal(t, opts.Port, 8080)
+}
+
+func TestConfig_WithConfigFile_noFile(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.json")
+
+	opts := configOptions{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions([]string{"app"}, &opts).
+		WithConfigFile(missing, "")
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.ConfigFile(), "")
+	assert.Equal(t, opts.Port, 80)
+}
+
+func TestConfig_EnableConfigOpt(t *testing.T) {
+	def := writeTempFile(t, "default.json", `{"port": 1}`)
+	path := writeTempFile(t, "app.json", `{"port": 2, "verbose": true}`)
+
+	opts := configOptions{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions(
+		[]string{"app", "--config", path, "arg"}, &opts,
+	).WithConfigFile(def, "").EnableConfigOpt()
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.ConfigFile(), path)
+	assert.Equal(t, conn.Cmd().Args(), []string{"arg"})
+	assert.Equal(t, conn.Cmd().OptArg("config"), path)
+	assert.Equal(t, opts.Port, 2)
+	assert.True(t, opts.Verbose)
+}
+
+func TestConfig_EnableConfigOpt_noFile(t *testing.T) {
+	missing := filepath.Join(t.TempDir(), "missing.json")
+
+	opts := configOptions{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions(
+		[]string{"app", "-c", missing}, &opts,
+	).EnableConfigOpt("config", "c")
+
+	_, err := setupWithOptCfgs(t, ds)
+	switch r := err.Reason().(type) {
+	case cliargdax.FailToReadConfigFile:
+		assert.Equal(t, r.Path, missing)
+		assert.True(t, os.IsNotExist(err.Cause()))
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestConfig_typeMismatch(t *testing.T) {
+	path := writeTempFile(t, "app.json", `{"server": {"timeout": [1, 2]}}`)
+
+	opts := configOptions{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions([]string{"app"}, &opts).
+		WithConfigFile(path, "")
+
+	_, err := setupWithOptCfgs(t, ds)
+	switch r := err.Reason().(type) {
+	case cliargdax.ConfigValueTypeMismatch:
+		assert.Equal(t, r.Key, "server.timeout")
+		assert.Equal(t, r.Option, "server-timeout")
+		assert.Equal(t, r.Input, "[1 2]")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestConfig_typeMismatch_listElement(t *testing.T) {
+	path := writeTempFile(t, "app.yaml", "tag:\n  - a\n  - [b]\n")
+
+	opts := configOptions{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions([]string{"app"}, &opts).
+		WithConfigFile(path, "")
+
+	_, err := setupWithOptCfgs(t, ds)
+	switch r := err.Reason().(type) {
+	case cliargdax.ConfigValueTypeMismatch:
+		assert.Equal(t, r.Key, "tag[1]")
+		assert.Equal(t, r.Option, "tag")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestConfig_failToParseValue(t *testing.T) {
+	path := writeTempFile(t, "app.json", `{"server": {"timeout": "abc"}}`)
+
+	opts := configOptions{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions([]string{"app"}, &opts).
+		WithConfigFile(path, "")
+
+	_, err := setupWithOptCfgs(t, ds)
+	switch r := err.Reason().(type) {
+	case cliargdax.FailToParseConfigValue:
+		assert.Equal(t, r.Option, "server-timeout")
+		assert.Equal(t, r.Field, "Timeout")
+		assert.Equal(t, r.Key, "server.timeout")
+		assert.Equal(t, r.Input, "abc")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestConfig_failToDecode(t *testing.T) {
+	path := writeTempFile(t, "app.json", `{"port": `)
+
+	opts := configOptions{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions([]string{"app"}, &opts).
+		WithConfigFile(path, "")
+
+	_, err := setupWithOptCfgs(t, ds)
+	switch r := err.Reason().(type) {
+	case cliargdax.FailToDecodeConfigFile:
+		assert.Equal(t, r.Path, path)
+		assert.Equal(t, r.Format, "json")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestConfig_unsupportedFormat(t *testing.T) {
+	path := writeTempFile(t, "app.toml", `port = 1`)
+
+	opts := configOptions{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions([]string{"app"}, &opts).
+		WithConfigFile(path, "")
+
+	_, err := setupWithOptCfgs(t, ds)
+	switch r := err.Reason().(type) {
+	case cliargdax.UnsupportedConfigFormat:
+		assert.Equal(t, r.Path, path)
+		assert.Equal(t, r.Format, "")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
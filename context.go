@@ -0,0 +1,66 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"context"
+
+	"github.com/sttk/sabi"
+	"github.com/sttk/sabi/errs"
+)
+
+type /* error reasons */ (
+	// SetupCanceled is the error reason which indicates that the context passed
+	// to SetupContext method is done before the setup is completed, while
+	// reading response files, option files, the config file, or arguments from
+	// the standard input, or before invoking a function registered with
+	// AfterSetup method.
+	// The cause of the error is the error returned by context.Context#Err.
+	SetupCanceled struct{}
+)
+
+// SetupContext is the method to set up this DaxSrc in the same way as Setup
+// method, with the context which can cancel the setup.
+// The context is checked before each file is read and before each function
+// registered with AfterSetup method is invoked, and reading arguments from
+// the standard input stops waiting for the input when the context is done.
+// In these cases, this method returns an errs.Err with the reason:
+// SetupCanceled.
+// When the setup is canceled while reading arguments from the standard input,
+// the goroutine reading the input is left blocked until the reader returns.
+// See DaxSrc#ReadArgsFromStdin method for details.
+// In lazy mode, the context is used at the first parsing.
+// The functions registered with AfterSetup method can get the context with
+// DaxConn#Context method.
+func (ds *DaxSrc) SetupContext(ctx context.Context, ag sabi.AsyncGroup) errs.Err {
+	ds.ctx = ctx
+	return ds.setup(ag)
+}
+
+// Context is the method to retrieve the context passed to
+// DaxSrc#SetupContext method, or context.Background() if this DaxSrc is set
+// up with DaxSrc#Setup method.
+func (conn *DaxConn) Context() context.Context {
+	return conn.ds.context()
+}
+
+func (ds *DaxSrc) context() context.Context {
+	if ds.ctx == nil {
+		return context.Background()
+	}
+	return ds.ctx
+}
+
+// checkContext returns an errs.Err with the reason: SetupCanceled if the
+// context is done.
+func checkContext(ctx context.Context) errs.Err {
+	if ctx == nil {
+		return errs.Ok()
+	}
+	if e := ctx.Err(); e != nil {
+		return errs.New(SetupCanceled{}, e)
+	}
+	return errs.Ok()
+}
@@ -0,0 +1,151 @@
+package cliargdax_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+	"github.com/sttk/sabi/errs"
+)
+
+type ctxKey struct{}
+
+func TestContext_SetupContext(t *testing.T) {
+	ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+
+	var v any
+	ds := cliargdax.NewDaxSrcWithArgs([]string{"app", "--foo", "a"}).
+		AfterSetup(func(conn *cliargdax.DaxConn) errs.Err {
+			v = conn.Context().Value(ctxKey{})
+			return errs.Ok()
+		})
+	err := ds.SetupContext(ctx, &noopAsyncGroup{})
+	assert.True(t, err.IsOk())
+	assert.Equal(t, v, "value")
+	defer ds.Close()
+
+	conn, err := ds.CreateDaxConn()
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.(*cliargdax.DaxConn).Cmd().Args(), []string{"a"})
+
+	err = ds.Setup(&noopAsyncGroup{})
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.(*cliargdax.DaxConn).Context(), context.Background())
+}
+
+func TestContext_cancelWhileReadingStdin(t *testing.T) {
+	pr, pw := io.Pipe()
+	defer pw.Close()
+
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "--args-from-stdin",
+	}, stdinArgsOptCfgs).ReadArgsFromStdin(cliargdax.StdinArgsCfg{
+		Reader: pr,
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		pw.Write([]byte("a.txt\n"))
+		cancel()
+	}()
+
+	err := ds.SetupContext(ctx, &noopAsyncGroup{})
+	assert.Equal(t, err.Reason(), cliargdax.SetupCanceled{})
+	assert.True(t, errors.Is(err.Cause(), context.Canceled))
+	assert.Equal(t, cliargdax.FormatError(err, nil),
+		"parsing command line arguments was canceled\n"+
+			"Try '--help' for more information.")
+}
+
+func TestContext_deadlineWhileReadingStdin(t *testing.T) {
+	pr, pw := io.Pipe()
+	defer pw.Close()
+
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "--args-from-stdin",
+	}, stdinArgsOptCfgs).ReadArgsFromStdin(cliargdax.StdinArgsCfg{
+		Reader: pr,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := ds.SetupContext(ctx, &noopAsyncGroup{})
+	assert.Equal(t, err.Reason(), cliargdax.SetupCanceled{})
+	assert.True(t, errors.Is(err.Cause(), context.DeadlineExceeded))
+}
+
+func TestContext_canceledBeforeReadingFiles(t *testing.T) {
+	dir := t.TempDir()
+	rsp := filepath.Join(dir, "args.rsp")
+	assert.Nil(t, os.WriteFile(rsp, []byte("--verbose\n"), 0644))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "@" + rsp,
+	}, stdinArgsOptCfgs).ExpandResponseFiles(true)
+	err := ds.SetupContext(ctx, &noopAsyncGroup{})
+	assert.Equal(t, err.Reason(), cliargdax.SetupCanceled{})
+
+	cfgFile := filepath.Join(dir, "app.json")
+	assert.Nil(t, os.WriteFile(cfgFile, []byte(`{"name":"x"}`), 0644))
+	ds = cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{"app"}, stdinArgsOptCfgs).
+		WithConfigFile(cfgFile, "")
+	err = ds.SetupContext(ctx, &noopAsyncGroup{})
+	assert.Equal(t, err.Reason(), cliargdax.SetupCanceled{})
+
+	err = ds.Setup(&noopAsyncGroup{})
+	assert.True(t, err.IsOk())
+}
+
+func TestContext_canceledInAfterSetup(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var logs []string
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{"app"}, []cliargs.OptCfg{}).
+		AfterSetup(func(conn *cliargdax.DaxConn) errs.Err {
+			logs = append(logs, "first")
+			cancel()
+			return errs.Ok()
+		}).
+		AfterSetup(func(conn *cliargdax.DaxConn) errs.Err {
+			logs = append(logs, "second")
+			return errs.Ok()
+		})
+
+	err := ds.SetupContext(ctx, &noopAsyncGroup{})
+	assert.Equal(t, err.Reason(), cliargdax.SetupCanceled{})
+	assert.Equal(t, logs, []string{"first"})
+}
+
+func TestContext_lazy(t *testing.T) {
+	pr, pw := io.Pipe()
+	defer pw.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "--args-from-stdin",
+	}, stdinArgsOptCfgs).ReadArgsFromStdin(cliargdax.StdinArgsCfg{
+		Reader: pr,
+	}).Lazy()
+
+	err := ds.SetupContext(ctx, &noopAsyncGroup{})
+	assert.True(t, err.IsOk())
+	defer ds.Close()
+
+	cancel()
+	conn, err := ds.CreateDaxConn()
+	assert.True(t, err.IsOk())
+	err = conn.(*cliargdax.DaxConn).ParseErr()
+	assert.Equal(t, err.Reason(), cliargdax.SetupCanceled{})
+}
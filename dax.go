@@ -53,6 +53,236 @@ cliargs.OptCfg that is created from the option store instance.
 These configuration array and store instance can be retrieve by using
 DaxConn#OptCfgs and DaxConn#Options methods.
 
+An option argument can be given in the forms: --name=value and -n=value, of
+which option names and option arguments are split only at the first "=".
+All the characters after it are kept verbatim, so an option argument can
+contain "=" and can also start with it, like --opt==x and -o==x, of which
+option arguments are =x.
+The forms --name= and -n= give an explicit empty option argument, which is
+distinguished from the absence of the option by cliargs.Cmd#HasOpt method,
+or by DaxConn#OptChanged method for an option with a default value.
+These rules are same with or without option configurations, and in all the
+modes of a DaxSrc, like DaxSrc#AllowAbbrev and DaxSrc#CollectAllErrors.
+
+A negative number, like -5 or -0.25, is a command argument or an option
+argument instead of short options, like --threshold -5, unless an option of
+which name or alias is a single digit is configured.
+Other forms of numbers starting with "-", like -1e5, are still parsed as
+short options except as option arguments.
+
+Option stores owned by different packages can be added to a DaxSrc instance
+with keys by DaxSrc#AddOptions method, and they are parsed at once.
+Each option store can be retrieved by DaxConn#OptionsByKey method.
+
+	sabi.Uses("cliopts", cliargdax.NewDaxSrc().
+	    AddOptions("logging", &LoggingOpts{}).
+	    AddOptions("server", &ServerOpts{}))
+
+For options which are contributed at runtime, NewDaxSrcDynamic function
+creates a DaxSrc instance which stores the results of parsing into a
+map[string]any, which can be retrieved by DaxConn#Options method.
+
+In addition to the types supported by cliargs package, a field of an option
+store can be map[string]string, which takes option arguments in the format:
+key=value multiple times, and time.Duration or time.Time, which takes an
+option argument in the format of time.ParseDuration function or of the
+layout.
+A field can also be url.URL, net.IP, or Path, of which option argument is
+converted by url.Parse function, by net.ParseIP function, or to a cleaned
+absolute path.
+Furthermore, a field of a type which implements encoding.TextUnmarshaler
+interface is set by its UnmarshalText method, even if the underlying type is
+supported by cliargs package.
+These types, time.Duration, and time.Time are also available as the pointer
+types and the slice types.
+A field of any other type can be supported by a decoder registered with
+DaxSrc#WithDecoder method for a DaxSrc instance, or with RegisterDecoder
+function, which are consulted in this order before UnmarshalText method and
+the built-in conversions.
+
+If the optcfg struct tag of a field has no option name, the option name is
+derived from the field name in kebab-case, like log-level for LogLevel and
+server-url for ServerURL, and the convention can be changed by
+DaxSrc#NameMapping method.
+
+A field of a struct type, or of a pointer type to a struct, which is not
+supported as an option type is treated as a nested struct, and its fields
+become options.
+The option names and long aliases of the fields in a nested struct are
+prefixed with the value of the optprefix struct tag, or with the field name
+derived in the same way and a hyphen by default, like --server-port.
+The fields in an anonymous embedded struct are not prefixed by default.
+The path of the nested struct, like Server.TLS, is the group of the options,
+which can be retrieved by DaxConn#OptGroup method, and the options are listed
+in the sections of their groups in the help text and in the documents.
+
+A field of which optcfg struct tag is "-" does not become an option, and if it
+is a nested struct, none of its fields become options either, so bookkeeping
+fields can be put in an option store.
+Unexported fields are always skipped, except that the exported fields of an
+anonymous embedded struct of an unexported type become options.
+An exported field of a type which is neither supported as an option type nor
+a nested struct makes the Setup method fail with the reason:
+cliargs.IllegalOptionType, instead of being ignored.
+
+In addition to the struct tags supported by cliargs package (optcfg, optdesc,
+and optarg), the following struct tags are available for fields of an option
+store:
+
+	optenv:"ENV_VAR"   The environment variable of which value is used when
+	                   the option is not given in command line arguments.
+	                   (Precedence: command line > environment variable >
+	                   default value)
+	optdefault:"VALUE" The default value used when the option is given neither
+	                   in command line arguments nor by the environment
+	                   variable.
+	                   The values of an array field are separated by commas,
+	                   and the value of a bool field is true or false.
+	optrequired:"true" The option must be given in command line arguments, by
+	                   the environment variable, or by the default value.
+	optchoices:"a,b,c" The choices of the option argument.
+	optchoicesfold:"true"
+	                   The choices are matched case-insensitively.
+	optmetavar:"FILE"  The placeholder of the option argument in the help
+	                   text, the usage line, completion scripts, and
+	                   documents, like --output <FILE>, which is used when
+	                   neither optarg nor optchoices is specified.
+	                   (Default: the upper-cased option name, like <NAME>)
+	optnegatable:"true"
+	                   The bool option can be given also in the negated form:
+	                   --no-name, and the last occurrence wins.
+	optsep:","         Each option argument of the array field is split with
+	                   the separator, which can be escaped with a backslash.
+	optnargs:"N"       Each occurrence of the option of the slice field takes N
+	                   option arguments: the option argument and the following
+	                   N-1 command line arguments, like --map SRC DST, which
+	                   can be retrieved per occurrence by DaxConn#OptTuples.
+	                   If N is * or +, the option takes all the following
+	                   command line arguments until the next option or "--",
+	                   like --files a b c, and + requires at least one.
+	                   Negative numbers, like --offsets -1 -2, are taken if
+	                   the elements of the field are numbers, and otherwise
+	                   the first one can be attached, like --offsets=-1.
+	optnargsend:";"    The sentinel argument which ends the option arguments
+	                   of the option of optnargs:"*" or optnargs:"+" instead
+	                   of the next option, like --exec cmd -x ";", which
+	                   takes also the arguments starting with "-".
+	optdupkey:"error"  A duplicated key given to the map[string]string field
+	                   causes an error instead of overwriting the value.
+	optlayout:"LAYOUT" The layout of the option argument of the time.Time
+	                   field. (Default: time.RFC3339)
+	optfromfile:"true" An option argument starting with @, like @/path/to/file,
+	                   is replaced with the contents of the file, and @@ is
+	                   for a literal @.
+	opthidden:"true"   The option is accepted but is not shown in the help
+	                   text, completion scripts, and documents.
+	optdeprecated:"MESSAGE"
+	                   The option is accepted but a warning with the message
+	                   is recorded when it is given.
+	optsince:"2.1"     The option is available from the version, and is
+	                   rejected if the application version set by
+	                   DaxSrc#WithAppVersion is older. The help text shows it
+	                   like (since v2.1).
+	optliteral:"true"  The option arguments are not substituted even if
+	                   DaxSrc#InterpolateOptArgs is enabled.
+	optprefix:"PREFIX" The prefix of the option names and long aliases of the
+	                   fields in the nested struct field.
+	optpos:"N"         The field is bound to the command argument at the
+	                   position N, or to the rest command arguments not bound
+	                   to other fields if the value is rest, instead of an
+	                   option. The command argument is converted in the same
+	                   way as an option argument, and it must be given unless
+	                   the field is a pointer or an array.
+	                   An io.Reader field is set to os.Stdin if the command
+	                   argument is "-", or to the file opened with the path.
+	optcount:"true"    The int or uint field is set to the number of
+	                   occurrences of the option, like -vvv, instead of an
+	                   option argument.
+	optmin:"N"         The inclusive lower bound of a numeric option argument.
+	optmax:"N"         The inclusive upper bound of a numeric option argument.
+	                   Default values are also checked against the bounds.
+	optchanged:"NAME"  The bool field is set to whether the option of the name
+	                   or alias is explicitly given in command line arguments,
+	                   instead of being an option.
+	optsecret:"true"   The option argument is replaced with "****" in error
+	                   messages, DaxConn#RedactedArgs, DaxConn#ResultJSON, and
+	                   DaxConn#BuildCommandLineString.
+	optgroup:"GROUP"   The option is listed in the section of the group in the
+	                   help text and in the documents, instead of the group of
+	                   the nested struct. The order of the sections can be
+	                   declared with DaxSrc#GroupOrder method.
+
+A bool field of an option store accepts also an attached value, like
+--color=false, which is converted with strconv.ParseBool or is yes or no.
+An option argument in the following argument is not taken, and the last
+occurrence wins.
+The option given as false is recorded in cliargs.Cmd with the option argument
+"false" and as a change in command line arguments, while the option given
+without a value or as true is recorded without an option argument as before.
+
+The choices, the environment variable, the default value, the group, and the
+hidden and secret flags can also be set for an option configured by a cliargs.OptCfg with
+DaxSrc#SetMeta method, and so can the acceptance of an attached bool value
+with the field BoolValue.
+
+	ds.SetMeta("format", cliargdax.OptMeta{
+	    Choices: []string{"json", "yaml"}, EnvVar: "FMT", Default: "json",
+	})
+
+Option values can also be read from a config file in JSON or YAML, of which
+path is specified with DaxSrc#WithConfigFile method or with the option enabled
+by DaxSrc#EnableConfigOpt method, like --config=app.yaml.
+The keys of the config file are option names, and the values are used for
+options given neither in command line arguments nor by environment variables.
+(Precedence: command line > environment variable > config file > default
+value)
+
+The above constructors parse os.Args.
+To parse another array of command line arguments, for example in tests or
+embedded invocations, use NewDaxSrcWithArgs, NewDaxSrcWithArgsAndOptCfgs, or
+NewDaxSrcWithArgsForOptions function instead.
+
+	args := []string{"/path/to/app", "--foo", "bar"}
+	sabi.Uses("cliopts", cliargdax.NewDaxSrcWithArgs(args))
+
+NewDaxSrcWithSubCmds and NewDaxSrcWithSubCmdsForOptions functions create a
+DaxSrc instance for command line arguments including a sub command, like
+git-style commands.
+These functions take a map of which keys are sub command names and of which
+values are cliargs.OptCfg arrays or option stores for the sub commands.
+The first non option-format argument is treated as a sub command name, and the
+results of parsing the sub command can be retrieved by using
+DaxConn#SubCmdName, DaxConn#SubCmd, DaxConn#SubOptCfgs, and
+DaxConn#SubOptions methods.
+
+	sabi.Uses("cliopts", cliargdax.NewDaxSrcWithSubCmds(
+	    map[string][]cliargs.OptCfg{
+	        "add":    []cliargs.OptCfg{ ... },
+	        "commit": []cliargs.OptCfg{ ... },
+	    },
+	).DefaultSubCmd("add"))
+
+For simpler tools of which sub commands share the options, handlers can be
+registered for the first command arguments with DaxSrc#Route method, which
+can also be nested routes, like "remote add", and DaxConn#Dispatch method
+invokes the handler of the matching route with a DaxConn instance of which
+command arguments are the ones after the route.
+
+	sabi.Uses("cliopts", cliargdax.NewDaxSrc().
+	    Route("serve", serve).
+	    Route("remote add", addRemote).
+	    DispatchOnSetup(true))
+
+By default, Setup method of DaxSrc parses command line arguments and fails if
+they are invalid.
+If DaxSrc#Lazy method is called before Setup, the parsing is postponed until
+the results are requested through a DaxConn, and the parsing error can be
+retrieved by DaxConn#ParseErr method.
+This is useful to print a usage message within a transaction when a user
+mistypes an option.
+
+	sabi.Uses("cliopts", cliargdax.NewDaxSrc().Lazy())
+
 # Usage of dax connection
 
 This package provides a dax connection named DaxConn.
@@ -64,7 +294,7 @@ And by this dac connection, the results of command-line argument parsing can be
 obtained.
 
 	func (dax MyDax) doSomeDataAccess() errs.Err {
-	    conn, err := sabi.GetDaxConn[cliargdax.DaxConn](dax, "cliopts")
+	    conn, err := sabi.GetDaxConn[*cliargdax.DaxConn](dax, "cliopts")
 	    if err.IsNotOk() {
 	        return err
 	    }
@@ -72,14 +302,55 @@ obtained.
 	    var cmd cliargs.Cmd = conn.Cmd()
 	    var optCfgs []cliargs.OptCfg = conn.OptCfgs()
 	    var options *MyOptions = conn.Options().(*MyOptions)
+	    var rawArgs []string = conn.RawArgs()
 
 	    return errs.Ok()
 	}
+
+The option store can also be retrieved without the type assertion by
+GetOptions function, which returns an error instead of panicking if the type
+is wrong.
+
+	options, err := cliargdax.GetOptions[MyOptions](conn)
+
+The help text made from the OptCfg array can be retrieved by DaxConn#HelpText
+method or written by DaxConn#PrintHelp method.
+Its heading, trailer, and width can be set with DaxSrc#HelpHeading,
+DaxSrc#HelpTrailer, and DaxSrc#HelpWidth methods.
+
+	conn.PrintHelp(os.Stdout)
+
+The messages of errors made by FormatError function, the help text, and
+documents are made from a message catalog, which can be replaced with
+SetMessages function. JapaneseMessages function returns a Japanese catalog,
+and the messages missing in a catalog are made in English.
+
+	cliargdax.SetMessages(cliargdax.JapaneseMessages())
+
+The progress, timings, failures, and warnings of the parsing can be received
+by a Logger set with DaxSrc#WithLogger method, in which the values of secret
+options are not written.
+
+	ds := cliargdax.NewDaxSrc().WithLogger(myLogger)
+
+# Usage without sabi
+
+Parse, ParseWith, and ParseInto functions parse command line arguments with
+the same pipeline as DaxSrc#Setup method, and return the results directly,
+for small programs which do not use sabi.DaxBase.
+
+	options := MyOptions{}
+	cmd, err := cliargdax.ParseInto(os.Args, &options)
 */
 package cliargdax
 
 import (
+	"context"
+	"io"
+	"io/fs"
 	"os"
+	"reflect"
+	"sync"
 
 	"github.com/sttk/cliargs"
 	"github.com/sttk/sabi"
@@ -93,14 +364,47 @@ import (
 // an array of cliargs.OptCfg struct for storing command line argument
 // configurations, and methods to set and retrieve any type struct instance
 // generated from the results of command line argument parsing.
+//
+// DaxConn is used as a pointer, and the buffered state of each connection, like
+// the instance set by SetOptions method and the overrides set by
+// WithOptOverride method, is held in the DaxConn instance.
+// DaxConn instances created by the same DaxSrc instance share the results of
+// parsing, and Src method returns the DaxSrc instance.
 type DaxConn struct {
-	ds *DaxSrc
+	ds           *DaxSrc
+	writeThrough bool
+	txn          connTxn
+	overlay      connOverlay
+}
+
+// connTxn is the struct type which holds the instance set by
+// DaxConn#SetOptions method until the transaction is committed.
+// The fields prevOptions and newOptions are the instances which the DaxSrc
+// had before and after the commit, which are used by DaxConn#ForceBack
+// method.
+type connTxn struct {
+	options     any
+	hasOptions  bool
+	prevOptions any
+	newOptions  any
+	hasPrev     bool
+	committed   bool
+	mutex       sync.Mutex
+}
+
+// Src is the method to retrieve the DaxSrc instance which created this DaxConn
+// instance.
+func (conn *DaxConn) Src() *DaxSrc {
+	return conn.ds
 }
 
 // Cmd is the method to retrieve a cliargs.Cmd struct instance that stores the
 // results of command line argument parsing.
-func (conn DaxConn) Cmd() cliargs.Cmd {
-	return conn.ds.cmd
+func (conn *DaxConn) Cmd() cliargs.Cmd {
+	conn.ds.parseLazily()
+	conn.ds.mutex.RLock()
+	defer conn.ds.mutex.RUnlock()
+	return conn.overriddenCmd()
 }
 
 // OptCfgs is the method to retrieve an array of cliargs.OptCfg struct
@@ -108,114 +412,596 @@ func (conn DaxConn) Cmd() cliargs.Cmd {
 // This array is either passed as an argument to NewDaxSrcWithOptCfgs function
 // or parsed from the struct instance passed as an argument to
 // NewDaxSrcForOptions function.
-func (conn DaxConn) OptCfgs() []cliargs.OptCfg {
-	return conn.ds.optCfgs
+// The returned array is a copy, including the aliases and the default values
+// of its elements, so modifying it does not affect the internal state of the
+// DaxSrc.
+func (conn *DaxConn) OptCfgs() []cliargs.OptCfg {
+	conn.ds.parseLazily()
+	conn.ds.mutex.RLock()
+	defer conn.ds.mutex.RUnlock()
+	return copyOptCfgs(conn.ds.optCfgs)
+}
+
+// copyOptCfgs returns a copy of the array of cliargs.OptCfg in which the
+// aliases and the default values are also copied.
+// The event handlers are shared with the original ones.
+func copyOptCfgs(optCfgs []cliargs.OptCfg) []cliargs.OptCfg {
+	if optCfgs == nil {
+		return nil
+	}
+	cfgs := make([]cliargs.OptCfg, len(optCfgs))
+	for i, cfg := range optCfgs {
+		if cfg.Aliases != nil {
+			cfg.Aliases = append([]string{}, cfg.Aliases...)
+		}
+		if cfg.Default != nil {
+			cfg.Default = append([]string{}, cfg.Default...)
+		}
+		cfgs[i] = cfg
+	}
+	return cfgs
+}
+
+// OptCount is the method to retrieve the number of occurrences of the
+// specified option in command line arguments.
+// Occurrences by aliases are counted as ones by the option name, and each
+// character of combined short options, like -vvv, is counted as an
+// occurrence.
+// If the option is not given, this method returns zero.
+func (conn *DaxConn) OptCount(name string) int {
+	conn.ds.parseLazily()
+	conn.ds.mutex.RLock()
+	defer conn.ds.mutex.RUnlock()
+	return conn.ds.counts[name]
+}
+
+// Warnings is the method to retrieve the warnings which occurred in command
+// line argument parsing but did not make the parsing fail, for example
+// unknown options ignored by DaxSrc#IgnoreUnknownOpts.
+// If there is no warning, this method returns an empty array.
+func (conn *DaxConn) Warnings() []errs.Err {
+	conn.ds.parseLazily()
+	conn.ds.mutex.RLock()
+	defer conn.ds.mutex.RUnlock()
+	return append([]errs.Err{}, conn.ds.warnings...)
+}
+
+// Reparse is the method to parse the command line arguments captured at
+// Setup again with the specified array of cliargs.OptCfg.
+// If an index is specified as the optional second argument, the parsing
+// starts from the element at the index, which is treated as the command name
+// like the first element of os.Args.
+// This index is typically obtained by cliargs.FindFirstArg function to parse
+// the arguments of a sub command.
+// If the parsing succeeds, the results are set to the DaxSrc and can be
+// retrieved by Cmd and OptCfgs methods, otherwise the DaxSrc is not updated.
+// The option store of the DaxSrc is not changed by this method.
+func (conn *DaxConn) Reparse(
+	cfgs []cliargs.OptCfg, index ...int,
+) (cliargs.Cmd, errs.Err) {
+	cmd, _, err := conn.ds.reparse(index, cfgs, nil, false)
+	return cmd, err
+}
+
+// ReparseFor is the method to parse the command line arguments captured at
+// Setup again for the specified option store, which is a pointer of a struct
+// instance of any type.
+// If an index is specified as the optional second argument, the parsing
+// starts from the element at the index, which is treated as the command name
+// like the first element of os.Args.
+// If the parsing succeeds, the results are set to the DaxSrc and can be
+// retrieved by Cmd, OptCfgs, and Options methods, otherwise the DaxSrc is not
+// updated.
+func (conn *DaxConn) ReparseFor(
+	opts any, index ...int,
+) (cliargs.Cmd, []cliargs.OptCfg, errs.Err) {
+	return conn.ds.reparse(index, nil, opts, true)
+}
+
+// ParseErr is the method to retrieve the error of command line argument
+// parsing.
+// If the parsing succeeded, this method returns errs.Ok().
+// This method is mainly used in lazy mode, in which Setup method does not
+// return the parsing error.
+func (conn *DaxConn) ParseErr() errs.Err {
+	conn.ds.parseLazily()
+	conn.ds.mutex.RLock()
+	defer conn.ds.mutex.RUnlock()
+	return conn.ds.parseErr
+}
+
+// RawArgs is the method to retrieve the command line arguments which were
+// captured at Setup, excluding the program path.
+// The returned array is a copy, so modifying it does not affect the internal
+// state of the DaxSrc.
+// This array is captured even if the parsing at Setup failed, and response
+// files and the sentinel argument to read arguments from the standard input
+// in it are not expanded.
+func (conn *DaxConn) RawArgs() []string {
+	conn.ds.mutex.RLock()
+	defer conn.ds.mutex.RUnlock()
+	if len(conn.ds.rawArgs) <= 1 {
+		return []string{}
+	}
+	return append([]string{}, conn.ds.rawArgs[1:]...)
+}
+
+// ProgramPath is the method to retrieve the program path, which is the first
+// element of the command line arguments captured at Setup.
+// If no command line argument was captured, this method returns an empty
+// string.
+func (conn *DaxConn) ProgramPath() string {
+	conn.ds.mutex.RLock()
+	defer conn.ds.mutex.RUnlock()
+	if len(conn.ds.osArgs) == 0 {
+		return ""
+	}
+	return conn.ds.osArgs[0]
+}
+
+// InvokedAs is the method to retrieve the base name of the program path,
+// which is the name the program was invoked as, even if the command name is
+// overridden by DaxSrc#WithCmdName method.
+// This is useful for a multiplexer program which branches on how it was
+// called through symbolic links.
+// Symbolic links are not resolved.
+// If no command line argument was captured, this method returns an empty
+// string.
+func (conn *DaxConn) InvokedAs() string {
+	conn.ds.mutex.RLock()
+	defer conn.ds.mutex.RUnlock()
+	return cmdName(conn.ds.osArgs)
 }
 
 // Options is the method to retrieve a struct instance of any type, which
 // is either passed as an argument to NewDaxSrcForOptions or set by
 // DaxConn#SetOptions method.
-func (conn DaxConn) Options() any {
+// If DaxConn#WithOptionsOverride method has been called, this method returns
+// the instance set by it.
+// Otherwise, if DaxConn#SetOptions method has been called with this DaxConn
+// instance in the current transaction, this method returns the instance set
+// by it even before committed.
+// If no option store is passed to the constructor nor set, this method returns
+// the first option store added by DaxSrc#AddOptions method.
+func (conn *DaxConn) Options() any {
+	if opts, ok := conn.overriddenOptions(); ok {
+		return opts
+	}
+	if !conn.writeThrough {
+		conn.txn.mutex.Lock()
+		defer conn.txn.mutex.Unlock()
+		if conn.txn.hasOptions {
+			return conn.txn.options
+		}
+	}
+	conn.ds.parseLazily()
+	conn.ds.mutex.RLock()
+	defer conn.ds.mutex.RUnlock()
+	if conn.ds.options == nil && len(conn.ds.addedStores) > 0 {
+		return conn.ds.addedStores[0].options
+	}
 	return conn.ds.options
 }
 
 // SetOptions is the method to set a struct instance of any type to a DaxSrc
 // instance through this DaxConn instance..
-// Because this argument is set to a DaxSrc instance, it is persists even
-// after the transaction has ended.
+// The instance is held in this DaxConn instance until the transaction is
+// committed, and is discarded if the transaction fails.
+// Because this argument is set to a DaxSrc instance at the commit, it is
+// persists even after the transaction has ended.
 // If the DaxSrc instance is global, the argument instance will persist until
 // the application is terminated (until the sabi.Close function is called).
-func (conn DaxConn) SetOptions(opts any) {
-	conn.ds.options = opts
+// If DaxSrc#WriteOptionsThrough method is called, the instance is set to the
+// DaxSrc instance immediately, regardless of the transaction.
+// This method and the methods to retrieve the results of parsing, like
+// Options, Cmd, and OptCfgs, are safe for concurrent use by multiple
+// goroutines, but the struct instance itself is shared and is not guarded.
+func (conn *DaxConn) SetOptions(opts any) {
+	conn.ds.parseLazily()
+
+	if conn.writeThrough {
+		conn.ds.mutex.Lock()
+		defer conn.ds.mutex.Unlock()
+		conn.ds.options = opts
+		return
+	}
+
+	conn.txn.mutex.Lock()
+	defer conn.txn.mutex.Unlock()
+	conn.txn.options = opts
+	conn.txn.hasOptions = true
 }
 
 // Commit is the one of the required methods for a struct that inherits
 // sabi.DaxConn.
 // It is called by sabi.Txn function.
-// This method is empty and only returns a result of errs.Ok().
-func (conn DaxConn) Commit(ag sabi.AsyncGroup) errs.Err {
+// This method sets the instance held by DaxConn#SetOptions method to the
+// DaxSrc instance.
+func (conn *DaxConn) Commit(ag sabi.AsyncGroup) errs.Err {
+	if conn.writeThrough {
+		return errs.Ok()
+	}
+
+	conn.txn.mutex.Lock()
+	defer conn.txn.mutex.Unlock()
+
+	if conn.txn.hasOptions {
+		conn.ds.mutex.Lock()
+		conn.txn.prevOptions = conn.ds.options
+		conn.txn.newOptions = conn.txn.options
+		conn.ds.options = conn.txn.options
+		conn.ds.mutex.Unlock()
+
+		conn.txn.options = nil
+		conn.txn.hasOptions = false
+		conn.txn.hasPrev = true
+	}
+	conn.txn.committed = true
 	return errs.Ok()
 }
 
 // IsCommitted is the one of the required methods for a struct that inherits
 // sabi.DaxConn.
 // It is called by sabi.Txn function.
-// This method always returns true.
-func (conn DaxConn) IsCommitted() bool {
-	return true
+// This method returns true if Commit method of this DaxConn instance has been
+// called, or if DaxSrc#WriteOptionsThrough method is called.
+func (conn *DaxConn) IsCommitted() bool {
+	if conn.writeThrough {
+		return true
+	}
+	conn.txn.mutex.Lock()
+	defer conn.txn.mutex.Unlock()
+	return conn.txn.committed
 }
 
 // Rollback is the one of the required methods for a struct that inherits
 // sabi.DaxConn.
-// This method never be called because IsCommitted always returns true.
-func (conn DaxConn) Rollback(ag sabi.AsyncGroup) {
-	// never be run because IsCommitted always returns true.
+// It is called by sabi.Txn function when the transaction fails before this
+// DaxConn instance is committed.
+// This method discards the instance held by DaxConn#SetOptions method.
+func (conn *DaxConn) Rollback(ag sabi.AsyncGroup) {
+	if conn.writeThrough {
+		return
+	}
+	conn.txn.mutex.Lock()
+	defer conn.txn.mutex.Unlock()
+	conn.txn.options = nil
+	conn.txn.hasOptions = false
 }
 
 // ForceBack is the one of the required methods for a struct that inherits
 // sabi.DaxConn.
-// This method is empty and does nothing.
-func (conn DaxConn) ForceBack(ag sabi.AsyncGroup) {
+// It is called by sabi.Txn function when the transaction fails after this
+// DaxConn instance is committed.
+// This method restores the instance which the DaxSrc instance had before the
+// commit.
+// If the instance of the DaxSrc has been replaced after the commit, for
+// example by another DaxConn instance, this method leaves it as it is not to
+// undo the later change, and writes a warning to the Logger set with
+// DaxSrc#WithLogger method.
+func (conn *DaxConn) ForceBack(ag sabi.AsyncGroup) {
+	if conn.writeThrough {
+		return
+	}
+	conn.txn.mutex.Lock()
+	defer conn.txn.mutex.Unlock()
+
+	if conn.txn.hasPrev {
+		conn.ds.mutex.Lock()
+		if sameOptions(conn.ds.options, conn.txn.newOptions) {
+			conn.ds.options = conn.txn.prevOptions
+		} else if conn.ds.logger != nil {
+			conn.ds.logger.Warnf("cliargdax: force back skipped: " +
+				"the option store has been replaced after the commit")
+		}
+		conn.ds.mutex.Unlock()
+
+		conn.txn.prevOptions = nil
+		conn.txn.newOptions = nil
+		conn.txn.hasPrev = false
+	}
+	conn.txn.options = nil
+	conn.txn.hasOptions = false
+	conn.txn.committed = false
+}
+
+// sameOptions reports whether the two option stores are the same instance.
+// Option stores of types which are not comparable, like maps, are never the
+// same.
+func sameOptions(a, b any) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	t := reflect.TypeOf(a)
+	if t != reflect.TypeOf(b) || !t.Comparable() {
+		return false
+	}
+	return a == b
 }
 
 // Close is the one of the required methods for a struct that inherits
 // sabi.DaxConn.
-// This method is empty and does nothing.
-func (conn DaxConn) Close() {
+// This method discards the overrides set by DaxConn#WithOptOverride and
+// DaxConn#WithOptionsOverride methods.
+func (conn *DaxConn) Close() {
+	conn.overlay.clear()
 }
 
 // DaxSrc is the dax source struct for command line argument operations.
 // This struct stores the results of command line argument parsing, and
 // provides them via a DaxConn instance.
 type DaxSrc struct {
+	args    []string
+	osArgs  []string
+	rawArgs []string
+	cmdName string
 	cmd     cliargs.Cmd
 	optCfgs []cliargs.OptCfg
+	cfgErr  errs.Err
 	options any
+	metas   map[string]optMeta
+
+	addedStores  []optStore
+	extraOptCfgs []cliargs.OptCfg
+	mergeOpts    []MergeOption
+	decoders     decoderMap
+	nameMapping  NameMapping
+	counts       map[string]int
+
+	termIndex int
+	hasTerm   bool
+
+	unknownOpts  []string
+	wildcardOpts []string
+	warnings     []errs.Err
+
+	requiredOpts map[string]bool
+	dependencies []optDependency
+	choices      map[string]optChoices
+	validators   []optValidator
+	negatables   map[string]bool
+	argCfgs      []ArgCfg
+	optMetas     map[string]OptMeta
+
+	helpHeading string
+	helpTrailer string
+	helpWidth   int
+	helpColor   bool
+	termSize    TerminalSizeFunc
+	groupOrder  []string
+
+	usageOptLimit int
+
+	helpOpt       *cliargs.OptCfg
+	onHelp        func(help string)
+	helpRequested bool
+
+	versionOpt       *cliargs.OptCfg
+	versionInfo      VersionInfo
+	versionRequested bool
+	appVersion       string
+
+	hiddenOpts           map[string]bool
+	deprecatedOpts       map[string]string
+	deprecatedAliases    map[string]deprecatedAlias
+	deprecatedOptAliases map[string]string
+	onDeprecation        func(warning string)
+	deprecations         []OptionDeprecated
+
+	fromFileOpts   map[string]bool
+	expandRspFiles bool
+	argFiles       []string
+	stdinArgs      *StdinArgsCfg
+	rawOptArgs     map[string][]string
+	spellings      map[string][]string
+	occurrences    []OptOccurrence
+	strMapSep      *string
+	fsys           fs.FS
+	optBlocks      []OptBlockCfg
+	blocks         map[string][]map[string][]string
+	tupleSizes     map[string][]int
+
+	recordTokens bool
+	tokenInfos   []TokenInfo
+
+	configFiles []configFile
+	configOpt   *cliargs.OptCfg
+	configPath  string
+	sources     map[string]ValueSource
+	prevResult  *prevResult
+
+	subCmds       map[string]subCmdCfg
+	defaultSubCmd string
+	subCmdName    string
+	subCmd        cliargs.Cmd
+	subOptCfgs    []cliargs.OptCfg
+	subOptions    any
+
+	optCallbacks     []optCallback
+	dupPolicy        DuplicatePolicy
+	dupPolicies      map[string]DuplicatePolicy
+	afterSetupHooks  []func(conn *DaxConn) errs.Err
+	beforeCloseHooks []func(conn *DaxConn)
+
+	routes                map[string]RouteHandler
+	defaultRoute          RouteHandler
+	routeUnknownToDefault bool
+	dispatchOnSetup       bool
+
+	ctx           context.Context
+	logger        Logger
+	stats         ParseStats
+	statsInResult bool
+
+	exitWriter io.Writer
+	exitCode   int
+	exitFunc   func(code int)
+
+	initial      initState
+	mode         parseMode
+	isDynamic    bool
+	isLazy       bool
+	writeThrough bool
+	isParsed     bool
+	parseErr     errs.Err
+	isSetUp      bool
+	mutex        sync.RWMutex
 }
 
 // Setup is the one of the required methods for a struct that inherits
 // sabi.DaxSrc.
 // This method parses command line arguments and sets the results of the
 // parsing to this DaxSrc instance.
+// The command line arguments are the array passed to the constructor if it
+// is given, otherwise os.Args.
+// If the command line arguments are empty, they are parsed like the ones with
+// only the program path, and the name of the parsed cliargs.Cmd is an empty
+// string, as it is when the program path is empty.
 // If failing to parse, this method returns errs.Err instnace that holds an
 // error instance from cliargs.Parse/ParseWith/ParseFor function as the error
 // reason.
+// If this DaxSrc has already been set up, the results of the previous parsing
+// are cleared and the option stores are set to their zero values before
+// parsing, in the same way as Reset method.
+// To cancel the setup with a context, use SetupContext method instead.
 func (ds *DaxSrc) Setup(ag sabi.AsyncGroup) errs.Err {
-	if ds.options != nil {
-		cmd, optCfgs, e := cliargs.ParseFor(os.Args, ds.options)
-		if e != nil {
-			return errs.New(e)
-		}
-		ds.cmd = cmd
-		ds.optCfgs = optCfgs
-	} else if len(ds.optCfgs) > 0 {
-		cmd, e := cliargs.ParseWith(os.Args, ds.optCfgs)
-		if e != nil {
-			return errs.New(e)
-		}
-		ds.cmd = cmd
+	return ds.SetupContext(context.Background(), ag)
+}
+
+func (ds *DaxSrc) setup(ag sabi.AsyncGroup) errs.Err {
+	ds.prepareToParse()
+
+	if ds.args != nil {
+		ds.osArgs = append([]string{}, ds.args...)
 	} else {
-		cmd, e := cliargs.Parse()
-		if e != nil {
-			return errs.New(e)
-		}
-		ds.cmd = cmd
+		ds.osArgs = append([]string{}, os.Args...)
 	}
+	ds.rawArgs = ds.osArgs
 
-	return errs.Ok()
+	start := ds.now()
+	ds.logSetupStart()
+
+	if ds.cfgErr.IsNotOk() {
+		ds.logSetupEnd(ds.cfgErr, start)
+		return ds.cfgErr
+	}
+
+	if ds.isLazy {
+		ds.isSetUp = true
+		return errs.Ok()
+	}
+
+	ds.parseErr = ds.parse()
+	ds.isParsed = true
+	if ds.parseErr.IsOk() && ds.hasAfterSetup() && !ds.isExitRequested() {
+		t := ds.now()
+		ds.parseErr = ds.runAfterSetup()
+		ds.logPhase("after-setup hooks", t)
+	}
+	ds.handleExit(ds.parseErr)
+	ds.isSetUp = ds.parseErr.IsOk()
+	ds.logSetupEnd(ds.parseErr, start)
+	return ds.parseErr
+}
+
+func (ds *DaxSrc) parseLazily() {
+	if !ds.isLazy {
+		return
+	}
+
+	ds.mutex.Lock()
+	if ds.isParsed {
+		ds.mutex.Unlock()
+		return
+	}
+	start := ds.now()
+	ds.logLazyParse()
+	ds.parseErr = ds.parse()
+	ds.isParsed = true
+	ok := ds.parseErr.IsOk() && !ds.isExitRequested()
+	ds.mutex.Unlock()
+
+	if ok && ds.hasAfterSetup() {
+		t := ds.now()
+		err := ds.runAfterSetup()
+		ds.logPhase("after-setup hooks", t)
+		ds.mutex.Lock()
+		ds.parseErr = err
+		ds.mutex.Unlock()
+	}
+
+	ds.handleExit(ds.parseErr)
+
+	ds.mutex.RLock()
+	ds.logSetupEnd(ds.parseErr, start)
+	ds.mutex.RUnlock()
+}
+
+// Lazy is the method to make this DaxSrc parse command line arguments lazily.
+// In lazy mode, Setup method only captures command line arguments, and the
+// parsing is done when a method of DaxConn which needs the parsing results,
+// like Cmd, OptCfgs, and Options, is called at first.
+// Even if the parsing failed, Setup does not fail, and the error can be
+// retrieved by DaxConn#ParseErr method.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) Lazy() *DaxSrc {
+	ds.isLazy = true
+	return ds
+}
+
+// WriteOptionsThrough is the method to make DaxConn#SetOptions method set the
+// instance to this DaxSrc immediately, regardless of the transaction, which
+// is the behavior of the earlier versions.
+// In this mode, DaxConn#IsCommitted method always returns true, and
+// DaxConn#Rollback and DaxConn#ForceBack methods do nothing.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) WriteOptionsThrough() *DaxSrc {
+	ds.writeThrough = true
+	return ds
+}
+
+// WithCmdName is the method to override the command name, which is derived
+// from the base name of the program path by default.
+// The overridden name is set to the Name field of cliargs.Cmd retrieved by
+// DaxConn#Cmd method, and is used in the help text, completion scripts, and
+// documents.
+// The name the program was invoked as can still be retrieved by
+// DaxConn#InvokedAs method.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) WithCmdName(name string) *DaxSrc {
+	ds.cmdName = name
+	return ds
 }
 
 // Close is the one of the required methods for a struct that inherits
 // sabi.DaxSrc.
-// This method is empty and does nothing.
+// This method invokes the functions registered with BeforeClose method, and
+// releases the results of the parsing.
+// The option store keeps the values set by the parsing, and this DaxSrc can
+// be set up again.
 func (ds *DaxSrc) Close() {
+	ds.runBeforeClose()
+	ds.mutex.Lock()
+	ds.releaseResults()
+	ds.mutex.Unlock()
 }
 
 // CreateDaxConn is the one of the required methods for a struct that inherits
 // sabi.DaxSrc.
-// This method creates a new instance of cliargdax.DaxConn struct.
+// This method creates a new instance of cliargdax.DaxConn struct, which is
+// cheap because the results of parsing are not copied but shared with this
+// DaxSrc instance.
+// If this DaxSrc is in lazy mode and the parsing has already failed, this
+// method returns the error of the parsing.
 func (ds *DaxSrc) CreateDaxConn() (sabi.DaxConn, errs.Err) {
-	return DaxConn{ds: ds}, errs.Ok()
+	if ds.isLazy {
+		ds.mutex.Lock()
+		defer ds.mutex.Unlock()
+		if ds.isParsed && ds.parseErr.IsNotOk() {
+			return nil, ds.parseErr
+		}
+	}
+	return &DaxConn{ds: ds, writeThrough: ds.writeThrough}, errs.Ok()
 }
 
 // NewDaxSrc is the constructor function of cliargdax.DaxSrc struct.
@@ -225,13 +1011,83 @@ func NewDaxSrc() *DaxSrc {
 
 // NewDaxSrcWithOptCfgs is the constructor function for cliargdax.DaxSrc struct
 // that takes an array of instances of the cliargs.OptCfg struct.
+// The array is checked by ValidateOptCfgs function, and if it is invalid, the
+// Setup method of the created DaxSrc returns the error.
 func NewDaxSrcWithOptCfgs(cfgs []cliargs.OptCfg) *DaxSrc {
-	return &DaxSrc{optCfgs: cfgs}
+	return &DaxSrc{optCfgs: cfgs, cfgErr: ValidateOptCfgs(cfgs)}
 }
 
 // NewDaxSrcForOptions is the constructor function for cliargdax.DaxSrc struct
 // that takes an instnace of a struct of any type, which stores the results of
 // command line argument parsing.
+// The option store is checked to be a pointer to a struct, and if it is not,
+// the Setup method of the created DaxSrc returns an errs.Err with the reason:
+// OptionStoreIsNotPointer, OptionStoreIsNil, OptionStoreIsNotStruct, or
+// OptionStoreHasNoExportedField.
+// A pointer to a pointer to a struct is accepted and dereferenced by one
+// level.
 func NewDaxSrcForOptions(opts any) *DaxSrc {
-	return &DaxSrc{options: opts}
+	opts, err := checkOptionStore(opts)
+	return &DaxSrc{options: opts, cfgErr: err}
+}
+
+// NewDaxSrcForOptionsWithExtra is the constructor function for
+// cliargdax.DaxSrc struct that takes an instance of a struct of any type,
+// which stores the results of command line argument parsing, and an array of
+// instances of the cliargs.OptCfg struct, which are merged to the option
+// configurations made from the struct with MergeOptCfgs function.
+// The merge options are passed to MergeOptCfgs function, and if the merging
+// fails, the Setup method of the created DaxSrc returns the error.
+// Like NewDaxSrcForOptions, the option store is checked before the array.
+func NewDaxSrcForOptionsWithExtra(
+	opts any, extra []cliargs.OptCfg, mergeOpts ...MergeOption,
+) *DaxSrc {
+	opts, err := checkOptionStore(opts)
+	if err.IsOk() {
+		err = ValidateOptCfgs(extra)
+	}
+	return &DaxSrc{
+		options:      opts,
+		extraOptCfgs: extra,
+		mergeOpts:    mergeOpts,
+		cfgErr:       err,
+	}
+}
+
+// NewDaxSrcWithArgs is the constructor function of cliargdax.DaxSrc struct
+// that takes an array of command line arguments to be parsed instead of
+// os.Args.
+// The first element of the array is treated as the program path, like
+// os.Args.
+func NewDaxSrcWithArgs(args []string) *DaxSrc {
+	return &DaxSrc{args: append([]string{}, args...)}
+}
+
+// NewDaxSrcWithArgsAndOptCfgs is the constructor function for
+// cliargdax.DaxSrc struct that takes an array of command line arguments to be
+// parsed instead of os.Args, and an array of instances of the cliargs.OptCfg
+// struct.
+// Like NewDaxSrcWithOptCfgs, the array is checked by ValidateOptCfgs function.
+func NewDaxSrcWithArgsAndOptCfgs(
+	args []string, cfgs []cliargs.OptCfg,
+) *DaxSrc {
+	return &DaxSrc{
+		args:    append([]string{}, args...),
+		optCfgs: cfgs,
+		cfgErr:  ValidateOptCfgs(cfgs),
+	}
+}
+
+// NewDaxSrcWithArgsForOptions is the constructor function for
+// cliargdax.DaxSrc struct that takes an array of command line arguments to be
+// parsed instead of os.Args, and an instance of a struct of any type, which
+// stores the results of command line argument parsing.
+// Like NewDaxSrcForOptions, the option store is checked.
+func NewDaxSrcWithArgsForOptions(args []string, opts any) *DaxSrc {
+	opts, err := checkOptionStore(opts)
+	return &DaxSrc{
+		args:    append([]string{}, args...),
+		options: opts,
+		cfgErr:  err,
+	}
 }
@@ -2,6 +2,8 @@ package cliargdax_test
 
 import (
 	"os"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -35,7 +37,7 @@ func TestCliArgDax_NewDaxSrc_ok(t *testing.T) {
 	dc, err := ds.CreateDaxConn()
 	assert.True(t, err.IsOk())
 
-	conn, ok := dc.(cliargdax.DaxConn)
+	conn, ok := dc.(*cliargdax.DaxConn)
 	assert.True(t, ok)
 
 	cmd := conn.Cmd()
@@ -100,7 +102,7 @@ func TestCliArgDax_NewDaxSrcWithOptCfgs_ok(t *testing.T) {
 	dc, err := ds.CreateDaxConn()
 	assert.True(t, err.IsOk())
 
-	conn, ok := dc.(cliargdax.DaxConn)
+	conn, ok := dc.(*cliargdax.DaxConn)
 	assert.True(t, ok)
 
 	cmd := conn.Cmd()
@@ -178,7 +180,7 @@ func TestCliArgDax_NewDaxSrcForOptions_ok(t *testing.T) {
 	dc, err := ds.CreateDaxConn()
 	assert.True(t, err.IsOk())
 
-	conn, ok := dc.(cliargdax.DaxConn)
+	conn, ok := dc.(*cliargdax.DaxConn)
 	assert.True(t, ok)
 
 	cmd := conn.Cmd()
@@ -243,7 +245,7 @@ func TestCliArgDax_txn_commit(t *testing.T) {
 	base.Uses("cliarg", cliargdax.NewDaxSrc())
 
 	err := sabi.Txn(base, func(dax sabi.Dax) errs.Err {
-		conn, err := sabi.GetDaxConn[cliargdax.DaxConn](base, "cliarg")
+		conn, err := sabi.GetDaxConn[*cliargdax.DaxConn](base, "cliarg")
 		assert.True(t, err.IsOk())
 
 		cmd := conn.Cmd()
@@ -274,7 +276,7 @@ func TestCliArgDax_txn_rollback(t *testing.T) {
 	type FailToDoSomething struct{}
 
 	err := sabi.Txn(base, func(dax sabi.Dax) errs.Err {
-		_, err := sabi.GetDaxConn[cliargdax.DaxConn](base, "cliarg")
+		_, err := sabi.GetDaxConn[*cliargdax.DaxConn](base, "cliarg")
 		assert.True(t, err.IsOk())
 		return errs.New(FailToDoSomething{})
 	})
@@ -300,7 +302,7 @@ func TestCliArgDax_DaxConn_SetOption(t *testing.T) {
 	}
 
 	err := sabi.Txn(base, func(dax sabi.Dax) errs.Err {
-		conn, err := sabi.GetDaxConn[cliargdax.DaxConn](base, "cliarg")
+		conn, err := sabi.GetDaxConn[*cliargdax.DaxConn](base, "cliarg")
 		assert.True(t, err.IsOk())
 		conn.SetOptions(MyOption{Flag: 111})
 		return errs.Ok()
@@ -308,7 +310,7 @@ func TestCliArgDax_DaxConn_SetOption(t *testing.T) {
 	assert.True(t, err.IsOk())
 
 	err = sabi.Txn(base, func(dax sabi.Dax) errs.Err {
-		conn, err := sabi.GetDaxConn[cliargdax.DaxConn](base, "cliarg")
+		conn, err := sabi.GetDaxConn[*cliargdax.DaxConn](base, "cliarg")
 		assert.True(t, err.IsOk())
 		assert.Equal(t, conn.Options().(MyOption).Flag, 111)
 		return errs.Ok()
@@ -316,6 +318,203 @@ func TestCliArgDax_DaxConn_SetOption(t *testing.T) {
 	assert.True(t, err.IsOk())
 }
 
+func TestCliArgDax_DaxConn_SetOptions_rollback(t *testing.T) {
+	base := sabi.NewDaxBase()
+	defer base.Close()
+
+	base.Uses("cliarg", cliargdax.NewDaxSrcWithArgs([]string{"/path/to/app"}))
+
+	type MyOption struct {
+		Flag int
+	}
+	type FailToDoSomething struct{}
+
+	err := sabi.Txn(base, func(dax sabi.Dax) errs.Err {
+		conn, err := sabi.GetDaxConn[*cliargdax.DaxConn](base, "cliarg")
+		assert.True(t, err.IsOk())
+		conn.SetOptions(MyOption{Flag: 111})
+		return errs.Ok()
+	})
+	assert.True(t, err.IsOk())
+
+	err = sabi.Txn(base, func(dax sabi.Dax) errs.Err {
+		conn, err := sabi.GetDaxConn[*cliargdax.DaxConn](base, "cliarg")
+		assert.True(t, err.IsOk())
+		conn.SetOptions(MyOption{Flag: 222})
+		assert.Equal(t, conn.Options().(MyOption).Flag, 222)
+		return errs.New(FailToDoSomething{})
+	})
+	switch err.Reason().(type) {
+	case FailToDoSomething:
+	default:
+		assert.Fail(t, err.Error())
+	}
+
+	err = sabi.Txn(base, func(dax sabi.Dax) errs.Err {
+		conn, err := sabi.GetDaxConn[*cliargdax.DaxConn](base, "cliarg")
+		assert.True(t, err.IsOk())
+		assert.Equal(t, conn.Options().(MyOption).Flag, 111)
+		return errs.Ok()
+	})
+	assert.True(t, err.IsOk())
+}
+
+func TestCliArgDax_DaxConn_SetOptions_commitAndForceBack(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgs([]string{"/path/to/app"})
+
+	ag := &noopAsyncGroup{}
+	err := ds.Setup(ag)
+	defer ds.Close()
+	assert.True(t, err.IsOk())
+
+	dc1, err := ds.CreateDaxConn()
+	assert.True(t, err.IsOk())
+	conn1 := dc1.(*cliargdax.DaxConn)
+
+	dc2, err := ds.CreateDaxConn()
+	assert.True(t, err.IsOk())
+	conn2 := dc2.(*cliargdax.DaxConn)
+
+	conn1.SetOptions("a")
+	assert.Equal(t, conn1.Options(), "a")
+	assert.Nil(t, conn2.Options())
+	assert.False(t, conn1.IsCommitted())
+
+	assert.True(t, conn1.Commit(ag).IsOk())
+	assert.True(t, conn1.IsCommitted())
+	assert.Equal(t, conn2.Options(), "a")
+
+	conn1.ForceBack(ag)
+	assert.False(t, conn1.IsCommitted())
+	assert.Nil(t, conn1.Options())
+	assert.Nil(t, conn2.Options())
+
+	conn2.SetOptions("b")
+	conn2.Rollback(ag)
+	assert.Nil(t, conn2.Options())
+	assert.True(t, conn2.Commit(ag).IsOk())
+	assert.Nil(t, conn1.Options())
+}
+
+func TestCliArgDax_DaxConn_ForceBack_afterOtherCommit(t *testing.T) {
+	logger := &recordingLogger{}
+	ds := cliargdax.NewDaxSrcWithArgs([]string{"/path/to/app"}).
+		WithLogger(logger)
+
+	ag := &noopAsyncGroup{}
+	err := ds.Setup(ag)
+	defer ds.Close()
+	assert.True(t, err.IsOk())
+
+	dc1, err := ds.CreateDaxConn()
+	assert.True(t, err.IsOk())
+	conn1 := dc1.(*cliargdax.DaxConn)
+
+	dc2, err := ds.CreateDaxConn()
+	assert.True(t, err.IsOk())
+	conn2 := dc2.(*cliargdax.DaxConn)
+
+	conn1.SetOptions("a")
+	assert.True(t, conn1.Commit(ag).IsOk())
+	conn2.SetOptions("b")
+	assert.True(t, conn2.Commit(ag).IsOk())
+
+	conn1.ForceBack(ag)
+	assert.False(t, conn1.IsCommitted())
+	assert.Equal(t, conn1.Options(), "b")
+	assert.Equal(t, logger.warns, []string{
+		"cliargdax: force back skipped: " +
+			"the option store has been replaced after the commit",
+	})
+
+	conn2.ForceBack(ag)
+	assert.Equal(t, conn1.Options(), "a")
+}
+
+func TestCliArgDax_DaxConn_Src(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgs([]string{"/path/to/app", "--foo=1"})
+
+	ag := &noopAsyncGroup{}
+	err := ds.Setup(ag)
+	defer ds.Close()
+	assert.True(t, err.IsOk())
+
+	dc1, err := ds.CreateDaxConn()
+	assert.True(t, err.IsOk())
+	conn1 := dc1.(*cliargdax.DaxConn)
+
+	dc2, err := ds.CreateDaxConn()
+	assert.True(t, err.IsOk())
+	conn2 := dc2.(*cliargdax.DaxConn)
+
+	assert.Same(t, conn1.Src(), ds)
+	assert.Same(t, conn2.Src(), ds)
+	assert.NotSame(t, conn1, conn2)
+
+	var _ sabi.DaxConn = conn1
+
+	conn1.SetOptions("a")
+	conn1.WithOptOverride("foo", "2")
+	assert.Equal(t, conn1.Options(), "a")
+	assert.Equal(t, conn1.Cmd().OptArg("foo"), "2")
+	assert.Nil(t, conn2.Options())
+	assert.Equal(t, conn2.Cmd().OptArg("foo"), "1")
+
+	assert.True(t, conn1.Commit(ag).IsOk())
+	assert.Equal(t, conn2.Options(), "a")
+	assert.False(t, conn2.IsCommitted())
+
+	conn1.Close()
+	assert.Equal(t, conn1.Cmd().OptArg("foo"), "1")
+}
+
+func TestCliArgDax_DaxConn_Src_viaGetDaxConn(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgs([]string{"/path/to/app"})
+
+	base := sabi.NewDaxBase()
+	defer base.Close()
+
+	base.Uses("cliarg", ds)
+
+	err := sabi.Txn(base, func(dax sabi.Dax) errs.Err {
+		conn1, err := sabi.GetDaxConn[*cliargdax.DaxConn](base, "cliarg")
+		assert.True(t, err.IsOk())
+		conn2, err := sabi.GetDaxConn[*cliargdax.DaxConn](base, "cliarg")
+		assert.True(t, err.IsOk())
+
+		assert.Same(t, conn1, conn2)
+		assert.Same(t, conn1.Src(), ds)
+		return errs.Ok()
+	})
+	assert.True(t, err.IsOk())
+}
+
+func TestCliArgDax_WriteOptionsThrough(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgs([]string{"/path/to/app"}).
+		WriteOptionsThrough()
+
+	ag := &noopAsyncGroup{}
+	err := ds.Setup(ag)
+	defer ds.Close()
+	assert.True(t, err.IsOk())
+
+	dc1, err := ds.CreateDaxConn()
+	assert.True(t, err.IsOk())
+	conn1 := dc1.(*cliargdax.DaxConn)
+
+	dc2, err := ds.CreateDaxConn()
+	assert.True(t, err.IsOk())
+	conn2 := dc2.(*cliargdax.DaxConn)
+
+	assert.True(t, conn1.IsCommitted())
+	conn1.SetOptions("a")
+	assert.Equal(t, conn2.Options(), "a")
+
+	conn1.Rollback(ag)
+	conn1.ForceBack(ag)
+	assert.Equal(t, conn2.Options(), "a")
+}
+
 func TestCliArgDax_forCoverage(t *testing.T) {
 	defer resetOsArgs()
 
@@ -330,8 +529,677 @@ func TestCliArgDax_forCoverage(t *testing.T) {
 	dc, err := ds.CreateDaxConn()
 	assert.True(t, err.IsOk())
 
-	conn, ok := dc.(cliargdax.DaxConn)
+	conn, ok := dc.(*cliargdax.DaxConn)
 	assert.True(t, ok)
 
 	conn.Rollback(ag)
 }
+
+func TestCliArgDax_DaxConn_SetOptions_concurrently(t *testing.T) {
+	type MyOptions struct {
+		N int `optcfg:"n"`
+	}
+
+	for _, lazy := range []bool{false, true} {
+		ds := cliargdax.NewDaxSrcWithArgsForOptions(
+			[]string{"/path/to/app", "--n=1"}, &MyOptions{},
+		)
+		if lazy {
+			ds.Lazy()
+		}
+
+		ag := &noopAsyncGroup{}
+		err := ds.Setup(ag)
+		assert.True(t, err.IsOk())
+
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				dc, err := ds.CreateDaxConn()
+				assert.True(t, err.IsOk())
+				conn := dc.(*cliargdax.DaxConn)
+				for j := 0; j < 100; j++ {
+					conn.SetOptions(&MyOptions{N: i})
+					assert.Equal(t, conn.Options().(*MyOptions).N, i)
+					assert.True(t, conn.Commit(ag).IsOk())
+					_, ok := conn.Options().(*MyOptions)
+					assert.True(t, ok)
+					assert.True(t, conn.Cmd().HasOpt("n"))
+					cfgs := conn.OptCfgs()
+					cfgs[0].Name = "x"
+					assert.Equal(t, conn.ProgramPath(), "/path/to/app")
+				}
+			}(i)
+		}
+		wg.Wait()
+
+		dc, err := ds.CreateDaxConn()
+		assert.True(t, err.IsOk())
+		conn := dc.(*cliargdax.DaxConn)
+		assert.Equal(t, conn.OptCfgs()[0].Name, "n")
+		ds.Close()
+	}
+}
+
+func TestCliArgDax_DaxConn_RawArgs(t *testing.T) {
+	defer resetOsArgs()
+
+	os.Args = []string{"/path/to/app", "--foo", "bar", "--baz=123"}
+
+	ds := cliargdax.NewDaxSrc()
+
+	ag := &noopAsyncGroup{}
+	err := ds.Setup(ag)
+	defer ds.Close()
+	assert.True(t, err.IsOk())
+
+	dc, err := ds.CreateDaxConn()
+	assert.True(t, err.IsOk())
+
+	conn := dc.(*cliargdax.DaxConn)
+	assert.Equal(t, conn.ProgramPath(), "/path/to/app")
+	assert.Equal(t, conn.RawArgs(), []string{"--foo", "bar", "--baz=123"})
+
+	rawArgs := conn.RawArgs()
+	rawArgs[0] = "--qux"
+	assert.Equal(t, conn.RawArgs(), []string{"--foo", "bar", "--baz=123"})
+
+	os.Args[1] = "--qux"
+	assert.Equal(t, conn.RawArgs(), []string{"--foo", "bar", "--baz=123"})
+}
+
+func TestCliArgDax_DaxConn_RawArgs_error(t *testing.T) {
+	defer resetOsArgs()
+
+	os.Args = []string{"/path/to/app", "--foo", "bar", "--123"}
+
+	ds := cliargdax.NewDaxSrc()
+
+	ag := &noopAsyncGroup{}
+	err := ds.Setup(ag)
+	defer ds.Close()
+	assert.True(t, err.IsNotOk())
+
+	dc, err := ds.CreateDaxConn()
+	assert.True(t, err.IsOk())
+
+	conn := dc.(*cliargdax.DaxConn)
+	assert.Equal(t, conn.ProgramPath(), "/path/to/app")
+	assert.Equal(t, conn.RawArgs(), []string{"--foo", "bar", "--123"})
+}
+
+func TestCliArgDax_DaxConn_RawArgs_noArgs(t *testing.T) {
+	defer resetOsArgs()
+
+	os.Args = []string{}
+
+	ds := cliargdax.NewDaxSrc()
+
+	ag := &noopAsyncGroup{}
+	err := ds.Setup(ag)
+	defer ds.Close()
+	assert.True(t, err.IsOk())
+
+	dc, err := ds.CreateDaxConn()
+	assert.True(t, err.IsOk())
+
+	conn := dc.(*cliargdax.DaxConn)
+	assert.Equal(t, conn.ProgramPath(), "")
+	assert.Equal(t, conn.RawArgs(), []string{})
+}
+
+func TestCliArgDax_emptyOsArgs(t *testing.T) {
+	defer resetOsArgs()
+
+	type Options struct {
+		Foo string `optcfg:"foo" optdefault:"x"`
+	}
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "foo", HasArg: true, Default: []string{"x"}},
+	}
+
+	for _, osArgs := range [][]string{
+		[]string{}, []string{""}, []string{"/path/to/app"},
+	} {
+		name := ""
+		if len(osArgs) > 0 && len(osArgs[0]) > 0 {
+			name = "app"
+		}
+
+		for _, ds := range []*cliargdax.DaxSrc{
+			cliargdax.NewDaxSrc(),
+			cliargdax.NewDaxSrcWithOptCfgs(optCfgs),
+			cliargdax.NewDaxSrcForOptions(&Options{}),
+		} {
+			os.Args = osArgs
+
+			ag := &noopAsyncGroup{}
+			err := ds.Setup(ag)
+			assert.True(t, err.IsOk())
+
+			dc, err := ds.CreateDaxConn()
+			assert.True(t, err.IsOk())
+
+			conn := dc.(*cliargdax.DaxConn)
+			cmd := conn.Cmd()
+			assert.Equal(t, cmd.Name, name)
+			assert.Equal(t, cmd.Args(), []string{})
+			assert.Equal(t, conn.RawArgs(), []string{})
+			assert.Equal(t, conn.Occurrences(), []cliargdax.OptOccurrence{})
+			if len(conn.OptCfgs()) > 0 {
+				assert.Equal(t, cmd.OptArg("foo"), "x")
+			} else {
+				assert.False(t, cmd.HasOpt("foo"))
+			}
+
+			cmd, err = conn.Reparse(optCfgs, 1)
+			assert.True(t, err.IsOk())
+			assert.Equal(t, cmd.Args(), []string{})
+
+			ds.Close()
+		}
+	}
+}
+
+func TestCliArgDax_DaxConn_OptCfgs_copy(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "--foo=y"},
+		[]cliargs.OptCfg{cliargs.OptCfg{
+			Name: "foo", Aliases: []string{"f"}, HasArg: true,
+			Default: []string{"x"},
+		}},
+	)
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	cfgs := conn.OptCfgs()
+	cfgs[0].Aliases[0] = "g"
+	cfgs[0].Default[0] = "z"
+
+	cfgs = conn.OptCfgs()
+	assert.Equal(t, cfgs[0].Aliases, []string{"f"})
+	assert.Equal(t, cfgs[0].Default, []string{"x"})
+}
+
+func TestCliArgDax_WithCmdName(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"/usr/bin/ls", "-a"},
+		[]cliargs.OptCfg{cliargs.OptCfg{Name: "all", Aliases: []string{"a"}}},
+	).WithCmdName("mybox")
+
+	ag := &noopAsyncGroup{}
+	err := ds.Setup(ag)
+	defer ds.Close()
+	assert.True(t, err.IsOk())
+
+	dc, err := ds.CreateDaxConn()
+	assert.True(t, err.IsOk())
+
+	conn := dc.(*cliargdax.DaxConn)
+	assert.Equal(t, conn.Cmd().Name, "mybox")
+	assert.True(t, conn.Cmd().HasOpt("all"))
+	assert.Equal(t, conn.InvokedAs(), "ls")
+	assert.Equal(t, conn.ProgramPath(), "/usr/bin/ls")
+	assert.True(t, strings.HasPrefix(conn.HelpText(), "Usage: mybox [OPTIONS]"))
+}
+
+func TestCliArgDax_InvokedAs(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgs([]string{"/path/to/app", "--foo"})
+
+	ag := &noopAsyncGroup{}
+	err := ds.Setup(ag)
+	defer ds.Close()
+	assert.True(t, err.IsOk())
+
+	dc, err := ds.CreateDaxConn()
+	assert.True(t, err.IsOk())
+
+	conn := dc.(*cliargdax.DaxConn)
+	assert.Equal(t, conn.Cmd().Name, "app")
+	assert.Equal(t, conn.InvokedAs(), "app")
+}
+
+func TestCliArgDax_NewDaxSrcWithArgs_ok(t *testing.T) {
+	defer resetOsArgs()
+
+	os.Args = []string{"/path/to/app", "--qux"}
+
+	ds1 := cliargdax.NewDaxSrcWithArgs(
+		[]string{"/path/to/app1", "--foo", "bar", "--baz=123"})
+	ds2 := cliargdax.NewDaxSrcWithArgs(
+		[]string{"/path/to/app2", "-x", "qux"})
+
+	ag := &noopAsyncGroup{}
+	err := ds1.Setup(ag)
+	defer ds1.Close()
+	assert.True(t, err.IsOk())
+	err = ds2.Setup(ag)
+	defer ds2.Close()
+	assert.True(t, err.IsOk())
+
+	dc, err := ds1.CreateDaxConn()
+	assert.True(t, err.IsOk())
+	conn1 := dc.(*cliargdax.DaxConn)
+
+	dc, err = ds2.CreateDaxConn()
+	assert.True(t, err.IsOk())
+	conn2 := dc.(*cliargdax.DaxConn)
+
+	cmd := conn1.Cmd()
+	assert.Equal(t, cmd.Name, "app1")
+	assert.Equal(t, cmd.Args(), []string{"bar"})
+	assert.True(t, cmd.HasOpt("foo"))
+	assert.True(t, cmd.HasOpt("baz"))
+	assert.Equal(t, cmd.OptArg("baz"), "123")
+	assert.False(t, cmd.HasOpt("x"))
+	assert.False(t, cmd.HasOpt("qux"))
+
+	cmd = conn2.Cmd()
+	assert.Equal(t, cmd.Name, "app2")
+	assert.Equal(t, cmd.Args(), []string{"qux"})
+	assert.True(t, cmd.HasOpt("x"))
+	assert.False(t, cmd.HasOpt("foo"))
+	assert.False(t, cmd.HasOpt("qux"))
+}
+
+func TestCliArgDax_NewDaxSrcWithArgs_error(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgs(
+		[]string{"/path/to/app", "--foo", "bar", "--123"})
+
+	ag := &noopAsyncGroup{}
+	err := ds.Setup(ag)
+	defer ds.Close()
+
+	switch r := err.Reason().(type) {
+	case cliargs.OptionHasInvalidChar:
+		assert.Equal(t, r.Option, "123")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestCliArgDax_NewDaxSrcWithArgsAndOptCfgs_ok(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "foo"},
+		cliargs.OptCfg{Name: "baz", HasArg: true},
+	}
+
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"/path/to/app", "--foo", "bar", "--baz=123"}, optCfgs)
+
+	ag := &noopAsyncGroup{}
+	err := ds.Setup(ag)
+	defer ds.Close()
+	assert.True(t, err.IsOk())
+
+	dc, err := ds.CreateDaxConn()
+	assert.True(t, err.IsOk())
+	conn := dc.(*cliargdax.DaxConn)
+
+	cmd := conn.Cmd()
+	assert.Equal(t, cmd.Name, "app")
+	assert.Equal(t, cmd.Args(), []string{"bar"})
+	assert.True(t, cmd.HasOpt("foo"))
+	assert.Equal(t, cmd.OptArg("baz"), "123")
+	assert.Equal(t, len(conn.OptCfgs()), 2)
+}
+
+func TestCliArgDax_NewDaxSrcWithArgsAndOptCfgs_error(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "foo"},
+	}
+
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"/path/to/app", "--foo", "--qux"}, optCfgs)
+
+	ag := &noopAsyncGroup{}
+	err := ds.Setup(ag)
+	defer ds.Close()
+
+	switch r := err.Reason().(type) {
+	case cliargs.UnconfiguredOption:
+		assert.Equal(t, r.Option, "qux")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestCliArgDax_NewDaxSrcWithArgsForOptions_ok(t *testing.T) {
+	type Options struct {
+		Foo bool `optcfg:"foo"`
+		Baz int  `optcfg:"baz"`
+	}
+	options := Options{}
+
+	ds := cliargdax.NewDaxSrcWithArgsForOptions(
+		[]string{"/path/to/app", "--foo", "bar", "--baz=123"}, &options)
+
+	ag := &noopAsyncGroup{}
+	err := ds.Setup(ag)
+	defer ds.Close()
+	assert.True(t, err.IsOk())
+
+	dc, err := ds.CreateDaxConn()
+	assert.True(t, err.IsOk())
+	conn := dc.(*cliargdax.DaxConn)
+
+	assert.Equal(t, conn.Cmd().Args(), []string{"bar"})
+	assert.Equal(t, len(conn.OptCfgs()), 2)
+
+	opts := conn.Options().(*Options)
+	assert.True(t, opts.Foo)
+	assert.Equal(t, opts.Baz, 123)
+}
+
+func TestCliArgDax_NewDaxSrcWithArgsForOptions_error(t *testing.T) {
+	type Options struct {
+		Foo bool `optcfg:"foo"`
+		Baz int  `optcfg:"baz"`
+	}
+	options := Options{}
+
+	ds := cliargdax.NewDaxSrcWithArgsForOptions(
+		[]string{"/path/to/app", "--baz=abc"}, &options)
+
+	ag := &noopAsyncGroup{}
+	err := ds.Setup(ag)
+	defer ds.Close()
+
+	switch r := err.Reason().(type) {
+	case cliargs.FailToParseInt:
+		assert.Equal(t, r.Option, "baz")
+		assert.Equal(t, r.Input, "abc")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestCliArgDax_Lazy_ok(t *testing.T) {
+	count := 0
+	onParsed := func(a []string) error {
+		count++
+		return nil
+	}
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "foo", OnParsed: &onParsed},
+	}
+
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"/path/to/app", "--foo", "bar"}, optCfgs).Lazy()
+
+	ag := &noopAsyncGroup{}
+	err := ds.Setup(ag)
+	defer ds.Close()
+	assert.True(t, err.IsOk())
+	assert.Equal(t, count, 0)
+
+	dc, err := ds.CreateDaxConn()
+	assert.True(t, err.IsOk())
+	conn := dc.(*cliargdax.DaxConn)
+	assert.Equal(t, count, 0)
+
+	cmd := conn.Cmd()
+	assert.Equal(t, count, 1)
+	assert.Equal(t, cmd.Args(), []string{"bar"})
+	assert.True(t, cmd.HasOpt("foo"))
+
+	cmd = conn.Cmd()
+	assert.Equal(t, count, 1)
+	assert.True(t, conn.ParseErr().IsOk())
+
+	dc, err = ds.CreateDaxConn()
+	assert.True(t, err.IsOk())
+	assert.True(t, dc.(*cliargdax.DaxConn).Cmd().HasOpt("foo"))
+	assert.Equal(t, count, 1)
+}
+
+func TestCliArgDax_Lazy_error(t *testing.T) {
+	type Options struct {
+		Foo bool `optcfg:"foo"`
+	}
+	options := Options{}
+
+	ds := cliargdax.NewDaxSrcWithArgsForOptions(
+		[]string{"/path/to/app", "--foo", "--qux"}, &options).Lazy()
+
+	ag := &noopAsyncGroup{}
+	err := ds.Setup(ag)
+	defer ds.Close()
+	assert.True(t, err.IsOk())
+
+	dc, err := ds.CreateDaxConn()
+	assert.True(t, err.IsOk())
+	conn := dc.(*cliargdax.DaxConn)
+
+	switch r := conn.ParseErr().Reason().(type) {
+	case cliargs.UnconfiguredOption:
+		assert.Equal(t, r.Option, "qux")
+	default:
+		assert.Fail(t, conn.ParseErr().Error())
+	}
+	assert.Equal(t, conn.RawArgs(), []string{"--foo", "--qux"})
+
+	dc, err = ds.CreateDaxConn()
+	assert.Nil(t, dc)
+	switch r := err.Reason().(type) {
+	case cliargs.UnconfiguredOption:
+		assert.Equal(t, r.Option, "qux")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestCliArgDax_Lazy_txn(t *testing.T) {
+	base := sabi.NewDaxBase()
+	defer base.Close()
+
+	err := base.Uses("cliarg", cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"/path/to/app", "--qux"},
+		[]cliargs.OptCfg{cliargs.OptCfg{Name: "foo"}},
+	).Lazy())
+	assert.True(t, err.IsOk())
+
+	err = sabi.Txn(base, func(dax sabi.Dax) errs.Err {
+		conn, err := sabi.GetDaxConn[*cliargdax.DaxConn](base, "cliarg")
+		assert.True(t, err.IsOk())
+		return conn.ParseErr()
+	})
+	switch r := err.Reason().(type) {
+	case cliargs.UnconfiguredOption:
+		assert.Equal(t, r.Option, "qux")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestCliArgDax_DaxConn_Reparse(t *testing.T) {
+	osArgs := []string{"/path/to/app", "--foo", "sub", "--bar=1", "baz"}
+	ds := cliargdax.NewDaxSrcWithArgs(osArgs)
+
+	ag := &noopAsyncGroup{}
+	err := ds.Setup(ag)
+	defer ds.Close()
+	assert.True(t, err.IsOk())
+
+	dc, err := ds.CreateDaxConn()
+	assert.True(t, err.IsOk())
+	conn := dc.(*cliargdax.DaxConn)
+
+	index, arg, exists := cliargs.FindFirstArg(osArgs)
+	assert.True(t, exists)
+	assert.Equal(t, index, 2)
+	assert.Equal(t, arg, "sub")
+
+	subCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "bar", HasArg: true},
+	}
+	cmd, err := conn.Reparse(subCfgs, index)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, cmd.Name, "sub")
+	assert.Equal(t, cmd.Args(), []string{"baz"})
+	assert.False(t, cmd.HasOpt("foo"))
+	assert.Equal(t, cmd.OptArg("bar"), "1")
+
+	cmd = conn.Cmd()
+	assert.Equal(t, cmd.Name, "sub")
+	assert.Equal(t, cmd.OptArg("bar"), "1")
+	assert.Equal(t, conn.OptCfgs()[0].Name, "bar")
+
+	cmd, err = conn.Reparse(subCfgs)
+	switch r := err.Reason().(type) {
+	case cliargs.UnconfiguredOption:
+		assert.Equal(t, r.Option, "foo")
+	default:
+		assert.Fail(t, err.Error())
+	}
+
+	cmd = conn.Cmd()
+	assert.Equal(t, cmd.Name, "sub")
+	assert.Equal(t, cmd.OptArg("bar"), "1")
+}
+
+func TestCliArgDax_DaxConn_Reparse_keepsOptions(t *testing.T) {
+	type MyOptions struct {
+		Foo bool `optcfg:"foo"`
+	}
+	options := MyOptions{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions(
+		[]string{"/path/to/app", "--foo", "sub", "--bar=1"}, &options,
+	).StopAtFirstArg(true)
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.True(t, options.Foo)
+
+	cmd, err := conn.Reparse([]cliargs.OptCfg{
+		cliargs.OptCfg{Name: "bar", HasArg: true},
+	}, 2)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, cmd.OptArg("bar"), "1")
+	assert.Equal(t, conn.Options(), &options)
+
+	other := MyOptions{}
+	conn.SetOptions(&other)
+	assert.True(t, conn.Commit(&noopAsyncGroup{}).IsOk())
+
+	_, err = conn.Reparse([]cliargs.OptCfg{
+		cliargs.OptCfg{Name: "bar", HasArg: true},
+	}, 2)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.Options(), &other)
+}
+
+func TestCliArgDax_DaxConn_ReparseFor(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgs(
+		[]string{"/path/to/app", "sub", "--bar=1", "baz"})
+
+	ag := &noopAsyncGroup{}
+	err := ds.Setup(ag)
+	defer ds.Close()
+	assert.True(t, err.IsOk())
+
+	dc, err := ds.CreateDaxConn()
+	assert.True(t, err.IsOk())
+	conn := dc.(*cliargdax.DaxConn)
+
+	type SubOptions struct {
+		Bar int `optcfg:"bar"`
+	}
+	subOptions := SubOptions{}
+
+	cmd, cfgs, err := conn.ReparseFor(&subOptions, 1)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, cmd.Name, "sub")
+	assert.Equal(t, cmd.Args(), []string{"baz"})
+	assert.Equal(t, len(cfgs), 1)
+	assert.Equal(t, cfgs[0].Name, "bar")
+	assert.Equal(t, subOptions.Bar, 1)
+	assert.Equal(t, conn.Options(), &subOptions)
+	assert.Equal(t, conn.OptCfgs()[0].Name, "bar")
+
+	cmd, _, err = conn.ReparseFor(&subOptions, 10)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, cmd.Name, "")
+	assert.Equal(t, cmd.Args(), []string{})
+}
+
+func TestCliArgDax_DaxConn_Reparse_concurrent(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgs(
+		[]string{"/path/to/app", "sub", "--bar=1"})
+
+	ag := &noopAsyncGroup{}
+	err := ds.Setup(ag)
+	defer ds.Close()
+	assert.True(t, err.IsOk())
+
+	dc, err := ds.CreateDaxConn()
+	assert.True(t, err.IsOk())
+	conn := dc.(*cliargdax.DaxConn)
+
+	subCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "bar", HasArg: true},
+	}
+
+	done := make(chan bool)
+	for i := 0; i < 10; i++ {
+		go func() {
+			for j := 0; j < 100; j++ {
+				cmd := conn.Cmd()
+				if cmd.Name == "sub" {
+					assert.Equal(t, cmd.OptArg("bar"), "1")
+				} else {
+					assert.Equal(t, cmd.Name, "app")
+					assert.Equal(t, cmd.OptArg("bar"), "1")
+				}
+			}
+			done <- true
+		}()
+	}
+	for i := 0; i < 100; i++ {
+		_, err := conn.Reparse(subCfgs, 1)
+		assert.True(t, err.IsOk())
+	}
+	for i := 0; i < 10; i++ {
+		<-done
+	}
+}
+
+func TestCliArgDax_DaxConn_OptCount(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgs(
+		[]string{"app", "-vvv", "--debug", "-v", "--debug=x", "--", "-v"},
+	)
+	err := ds.Setup(&noopAsyncGroup{})
+	assert.True(t, err.IsOk())
+	defer ds.Close()
+
+	dc, err := ds.CreateDaxConn()
+	assert.True(t, err.IsOk())
+	conn := dc.(*cliargdax.DaxConn)
+
+	assert.Equal(t, conn.OptCount("v"), 4)
+	assert.Equal(t, conn.OptCount("debug"), 2)
+	assert.Equal(t, conn.OptCount("x"), 0)
+}
+
+func TestCliArgDax_DaxConn_OptCount_withOptCfgs(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "verbose", Aliases: []string{"v"}},
+		cliargs.OptCfg{Name: "file", Aliases: []string{"f"}, HasArg: true},
+	}
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "-vv", "--verbose", "-f", "-v"}, optCfgs,
+	)
+	err := ds.Setup(&noopAsyncGroup{})
+	assert.True(t, err.IsOk())
+	defer ds.Close()
+
+	dc, err := ds.CreateDaxConn()
+	assert.True(t, err.IsOk())
+	conn := dc.(*cliargdax.DaxConn)
+
+	assert.Equal(t, conn.OptCount("verbose"), 3)
+	assert.Equal(t, conn.OptCount("v"), 0)
+	assert.Equal(t, conn.OptCount("file"), 1)
+	assert.Equal(t, conn.Cmd().OptArg("file"), "-v")
+}
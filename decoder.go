@@ -0,0 +1,117 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// DecodeFunc is the function type to convert an option argument to a value of
+// a field type of an option store, which is registered with RegisterDecoder
+// function or DaxSrc#WithDecoder method.
+// The returned value must be assignable or convertible to the field type.
+type DecodeFunc func(s string) (any, error)
+
+// decoderMap is the map type for the decoders of field types.
+type decoderMap map[reflect.Type]DecodeFunc
+
+var globalDecoders = struct {
+	mutex sync.RWMutex
+	m     decoderMap
+}{m: make(decoderMap)}
+
+// RegisterDecoder is the function to register the decoder which converts an
+// option argument to a value of the type, for fields of option stores of all
+// DaxSrc instances.
+// A field of the type T, *T, or []T is supported with the decoder, and a
+// value of an environment variable or a default value is also converted by
+// it.
+// The decoders are consulted in the following precedence order: the decoder
+// registered to a DaxSrc with DaxSrc#WithDecoder method, the decoder
+// registered with this function, the UnmarshalText method of
+// encoding.TextUnmarshaler, and the built-in conversions.
+// Though time.Time and net.IP implement encoding.TextUnmarshaler, they are
+// converted by the built-in conversions for them, which support the optlayout
+// struct tag and report the reasons: FailToParseTime and FailToParseIP.
+// If the decoder returns an error, the parsing fails with the reason:
+// OptionArgUnmarshalFailed, of which Cause is the error.
+// Registering a decoder for the same type replaces the previous one, and nil
+// removes it.
+// This function is safe to be called concurrently, for example in init
+// functions, but a decoder registered after a DaxSrc is set up is used from
+// the next setup.
+func RegisterDecoder(t reflect.Type, dec DecodeFunc) {
+	globalDecoders.mutex.Lock()
+	defer globalDecoders.mutex.Unlock()
+	if dec == nil {
+		delete(globalDecoders.m, t)
+		return
+	}
+	globalDecoders.m[t] = dec
+}
+
+// WithDecoder is the method to register the decoder which converts an option
+// argument to a value of the type, only for the fields of the option stores
+// of this DaxSrc, for example to isolate tests from the decoders registered
+// with RegisterDecoder function.
+// The decoder registered with this method takes precedence over the one
+// registered with RegisterDecoder function for the same type, and nil means
+// that no decoder is used for the type in this DaxSrc, even if one is
+// registered with RegisterDecoder function.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) WithDecoder(t reflect.Type, dec DecodeFunc) *DaxSrc {
+	if ds.decoders == nil {
+		ds.decoders = make(decoderMap)
+	}
+	ds.decoders[t] = dec
+	return ds
+}
+
+// lookup returns the decoder for the type from this map, or from the global
+// registry if this map does not have it.
+// If this map has nil for the type, this function returns false without
+// looking up the global registry.
+func (decs decoderMap) lookup(t reflect.Type) (DecodeFunc, bool) {
+	if dec, ok := decs[t]; ok {
+		return dec, dec != nil
+	}
+	globalDecoders.mutex.RLock()
+	defer globalDecoders.mutex.RUnlock()
+	dec, ok := globalDecoders.m[t]
+	return dec, ok
+}
+
+// parserFor returns the valueParser for the type with the decoder registered
+// for it.
+func (decs decoderMap) parserFor(t reflect.Type) (valueParser, bool) {
+	dec, ok := decs.lookup(t)
+	if !ok {
+		return nil, false
+	}
+
+	p := func(name, s string) (reflect.Value, error) {
+		x, e := dec(s)
+		if e == nil {
+			v := reflect.ValueOf(x)
+			switch {
+			case !v.IsValid():
+				return reflect.Zero(t), nil
+			case v.Type().AssignableTo(t):
+				return v, nil
+			case v.Type().ConvertibleTo(t):
+				return v.Convert(t), nil
+			}
+			e = fmt.Errorf("decoder returned %T for %v", x, t)
+		}
+		return reflect.Value{}, reasonErr{
+			option: name,
+			reason: OptionArgUnmarshalFailed{Option: name, Value: s, Cause: e},
+			cause:  e,
+		}
+	}
+	return p, true
+}
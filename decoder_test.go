@@ -0,0 +1,193 @@
+package cliargdax_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+)
+
+type color int
+
+const (
+	colorRed color = iota + 1
+	colorGreen
+	colorBlue
+)
+
+func decodeColor(s string) (any, error) {
+	switch strings.ToLower(s) {
+	case "red":
+		return colorRed, nil
+	case "green":
+		return colorGreen, nil
+	case "blue":
+		return colorBlue, nil
+	}
+	return nil, fmt.Errorf("unknown color: %s", s)
+}
+
+type shade int
+
+func registerColorDecoder(t *testing.T) {
+	cliargdax.RegisterDecoder(reflect.TypeOf(color(0)), decodeColor)
+	t.Cleanup(func() {
+		cliargdax.RegisterDecoder(reflect.TypeOf(color(0)), nil)
+	})
+}
+
+func TestDecoder_RegisterDecoder(t *testing.T) {
+	registerColorDecoder(t)
+
+	type Options struct {
+		Color  color   `optcfg:"color"`
+		Accent *color  `optcfg:"accent"`
+		Marks  []color `optcfg:"marks=[red,blue]"`
+	}
+
+	options := Options{}
+	conn, err := setupForOptions(t, []string{
+		"app", "--color=Green", "--accent", "red",
+	}, &options)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, options.Color, colorGreen)
+	assert.Equal(t, *options.Accent, colorRed)
+	assert.Equal(t, options.Marks, []color{colorRed, colorBlue})
+	assert.Equal(t, conn.Cmd().OptArg("color"), "Green")
+
+	options = Options{}
+	_, err = setupForOptions(t, []string{"app", "--color=pink"}, &options)
+	switch r := err.Reason().(type) {
+	case cliargdax.OptionArgUnmarshalFailed:
+		assert.Equal(t, r.Option, "color")
+		assert.Equal(t, r.Value, "pink")
+		assert.Equal(t, r.Cause.Error(), "unknown color: pink")
+		assert.True(t, errors.Is(err, r.Cause))
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestDecoder_WithDecoder(t *testing.T) {
+	registerColorDecoder(t)
+
+	type Options struct {
+		Color color    `optcfg:"color"`
+		Level logLevel `optcfg:"level"`
+		Shade shade    `optcfg:"shade"`
+	}
+
+	options := Options{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions([]string{
+		"app", "--color=red", "--level=loud", "--shade=3",
+	}, &options).
+		WithDecoder(reflect.TypeOf(color(0)), func(s string) (any, error) {
+			return colorBlue, nil
+		}).
+		WithDecoder(reflect.TypeOf(logLevel(0)), func(s string) (any, error) {
+			return levelDebug, nil
+		}).
+		WithDecoder(reflect.TypeOf(shade(0)), func(s string) (any, error) {
+			return len(s) + 1, nil
+		})
+	err := ds.Setup(&noopAsyncGroup{})
+	assert.True(t, err.IsOk())
+	defer ds.Close()
+	assert.Equal(t, options.Color, colorBlue)
+	assert.Equal(t, options.Level, levelDebug)
+	assert.Equal(t, options.Shade, shade(2))
+
+	options = Options{}
+	ds = cliargdax.NewDaxSrcWithArgsForOptions([]string{
+		"app", "--color=red", "--level=info",
+	}, &options)
+	err = ds.Setup(&noopAsyncGroup{})
+	assert.True(t, err.IsOk())
+	defer ds.Close()
+	assert.Equal(t, options.Color, colorRed)
+	assert.Equal(t, options.Level, levelInfo)
+}
+
+func TestDecoder_wrongType(t *testing.T) {
+	type Options struct {
+		Shade shade `optcfg:"shade"`
+	}
+
+	options := Options{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions([]string{
+		"app", "--shade=3",
+	}, &options).
+		WithDecoder(reflect.TypeOf(shade(0)), func(s string) (any, error) {
+			return "dark", nil
+		})
+	err := ds.Setup(&noopAsyncGroup{})
+	switch r := err.Reason().(type) {
+	case cliargdax.OptionArgUnmarshalFailed:
+		assert.Equal(t, r.Option, "shade")
+		assert.Equal(t, r.Cause.Error(),
+			"decoder returned string for cliargdax_test.shade")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestDecoder_envAndDefault(t *testing.T) {
+	registerColorDecoder(t)
+
+	type Options struct {
+		Color color `optcfg:"color" optenv:"DECODER_TEST_COLOR"`
+		Back  color `optcfg:"back=green"`
+	}
+
+	t.Setenv("DECODER_TEST_COLOR", "blue")
+	options := Options{}
+	_, err := setupForOptions(t, []string{"app"}, &options)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, options.Color, colorBlue)
+	assert.Equal(t, options.Back, colorGreen)
+
+	type Options2 struct {
+		Back color `optcfg:"back=pink"`
+	}
+	options2 := Options2{}
+	_, err = setupForOptions(t, []string{"app"}, &options2)
+	switch r := err.Reason().(type) {
+	case cliargdax.FailToParseDefault:
+		assert.Equal(t, r.Option, "back")
+		assert.Equal(t, r.Input, "pink")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestDecoder_WithDecoder_nil(t *testing.T) {
+	registerColorDecoder(t)
+
+	type Options struct {
+		Color color `optcfg:"color"`
+	}
+
+	options := Options{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions([]string{
+		"app", "--color=2",
+	}, &options).WithDecoder(reflect.TypeOf(color(0)), nil)
+	err := ds.Setup(&noopAsyncGroup{})
+	assert.True(t, err.IsOk())
+	defer ds.Close()
+	assert.Equal(t, options.Color, colorGreen)
+}
+
+func TestDecoder_unregistered(t *testing.T) {
+	type Options struct {
+		Color color `optcfg:"color"`
+	}
+
+	options := Options{}
+	_, err := setupForOptions(t, []string{"app", "--color=3"}, &options)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, options.Color, colorBlue)
+}
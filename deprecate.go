@@ -0,0 +1,216 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"github.com/sttk/cliargs"
+	"github.com/sttk/sabi/errs"
+)
+
+type /* error reasons */ (
+	// OptionDeprecated is the reason of a warning which indicates that a
+	// deprecated option is given in command line arguments.
+	// The fields Option, Replacement, and Message are the given option name,
+	// the name of the option which replaces it, and the message of the
+	// deprecation.
+	// Replacement and Message can be empty.
+	OptionDeprecated struct {
+		Option      string
+		Replacement string
+		Message     string
+	}
+)
+
+// deprecatedAlias is a deprecated long option name which is replaced with
+// another option.
+type deprecatedAlias struct {
+	replacement string
+	message     string
+}
+
+// Hidden is the method to set names of options which are accepted but are
+// excluded from the help text, completion scripts, and documents made by
+// DaxConn methods.
+// For option stores, the struct tag: opthidden:"true" is also available.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) Hidden(names ...string) *DaxSrc {
+	if ds.hiddenOpts == nil {
+		ds.hiddenOpts = make(map[string]bool, len(names))
+	}
+	for _, name := range names {
+		ds.hiddenOpts[name] = true
+	}
+	return ds
+}
+
+// Deprecated is the method to mark the option as deprecated with the message.
+// A deprecated option is accepted, but when it is given in command line
+// arguments, a warning with the reason: OptionDeprecated is recorded.
+// For option stores, the struct tag: optdeprecated:"message" is also
+// available.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) Deprecated(name, message string) *DaxSrc {
+	if ds.deprecatedOpts == nil {
+		ds.deprecatedOpts = make(map[string]string)
+	}
+	ds.deprecatedOpts[name] = message
+	return ds
+}
+
+// DeprecatedAlias is the method to declare a deprecated long option name
+// which is replaced with the option specified as the second argument.
+// The deprecated name is not configured as an option, is not shown in help
+// texts, and when it is given in command line arguments, it is parsed as the
+// replacement option with a warning with the reason: OptionDeprecated.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) DeprecatedAlias(
+	name, replacement, message string,
+) *DaxSrc {
+	if ds.deprecatedAliases == nil {
+		ds.deprecatedAliases = make(map[string]deprecatedAlias)
+	}
+	ds.deprecatedAliases[name] = deprecatedAlias{
+		replacement: replacement, message: message,
+	}
+	return ds
+}
+
+// DeprecatedOptAlias is the method to mark an alias of a configured option as
+// deprecated with the message, while the option name and the other aliases
+// stay available without warnings.
+// When the alias is given in command line arguments, a warning with the
+// reason: OptionDeprecated is recorded, of which Option is the alias and
+// Replacement is the option name.
+// A deprecated alias is tracked separately from its option, so both are
+// reported if the option is also deprecated.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) DeprecatedOptAlias(alias, message string) *DaxSrc {
+	if ds.deprecatedOptAliases == nil {
+		ds.deprecatedOptAliases = make(map[string]string)
+	}
+	ds.deprecatedOptAliases[alias] = message
+	return ds
+}
+
+// OnDeprecation is the method to set the function which is invoked after
+// parsing with the message of each deprecated option given in command line
+// arguments, for example to print it to stderr.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) OnDeprecation(handler func(warning string)) *DaxSrc {
+	ds.onDeprecation = handler
+	return ds
+}
+
+// DeprecationWarnings is the method to retrieve the messages of deprecated
+// options given in command line arguments, like:
+// "--old-name is deprecated: use --new-name".
+// Each deprecated option is reported once, in the order of the occurrences
+// for deprecated aliases followed by the order of the OptCfg array.
+// If there is no deprecated option given, this method returns an empty
+// array.
+func (conn *DaxConn) DeprecationWarnings() []string {
+	conn.ds.parseLazily()
+	conn.ds.mutex.RLock()
+	defer conn.ds.mutex.RUnlock()
+	msgs := make([]string, len(conn.ds.deprecations))
+	for i, d := range conn.ds.deprecations {
+		msgs[i] = deprecationMessage(d)
+	}
+	return msgs
+}
+
+func deprecationMessage(d OptionDeprecated) string {
+	if len(d.Message) > 0 {
+		return msg(MsgWithDetail, "message",
+			msg(MsgDeprecationWarning, "option", optArg(d.Option)),
+			"detail", d.Message)
+	}
+	if len(d.Replacement) > 0 {
+		return msg(MsgDeprecationWarningUse, "option", optArg(d.Option),
+			"other", optArg(d.Replacement))
+	}
+	return msg(MsgDeprecationWarning, "option", optArg(d.Option))
+}
+
+// addDeprecation records the deprecated option as a deprecation and a warning
+// unless it is already recorded.
+func (r *parseResult) addDeprecation(d OptionDeprecated) {
+	for _, x := range r.deprecations {
+		if x.Option == d.Option {
+			return
+		}
+	}
+	r.deprecations = append(r.deprecations, d)
+	r.warnings = append(r.warnings, errs.New(d))
+}
+
+// replaceDeprecatedAlias returns the replacement option name if the long
+// option name is a deprecated alias, and records the deprecation.
+func (r *parseResult) replaceDeprecatedAlias(name string) string {
+	a, exists := r.aliases[name]
+	if !exists {
+		return name
+	}
+	if _, configured := cfgIndexes(r.optCfgs)[name]; configured {
+		return name
+	}
+	r.addDeprecation(OptionDeprecated{
+		Option: name, Replacement: a.replacement, Message: a.message,
+	})
+	return a.replacement
+}
+
+// checkDeprecatedOptAliases records the deprecations of deprecated aliases of
+// options given in command line arguments.
+func (r *parseResult) checkDeprecatedOptAliases(toks []argToken) {
+	if len(r.optAliases) == 0 {
+		return
+	}
+	for _, tok := range toks {
+		if !tok.isAlias {
+			continue
+		}
+		if message, exists := r.optAliases[tok.alias]; exists {
+			r.addDeprecation(OptionDeprecated{
+				Option: tok.alias, Replacement: tok.name, Message: message,
+			})
+		}
+	}
+}
+
+// checkDeprecatedOpts records the deprecations of deprecated options given in
+// command line arguments.
+func (r *parseResult) checkDeprecatedOpts() {
+	for _, cfg := range r.optCfgs {
+		m := r.metas[cfg.Name]
+		if m.deprecated && r.counts[cfg.Name] > 0 {
+			r.addDeprecation(OptionDeprecated{
+				Option: cfg.Name, Message: m.deprecation,
+			})
+		}
+	}
+}
+
+// handleDeprecations invokes the function set by OnDeprecation method with
+// the message of each deprecation.
+func (ds *DaxSrc) handleDeprecations() {
+	if ds.onDeprecation == nil {
+		return
+	}
+	for _, d := range ds.deprecations {
+		ds.onDeprecation(deprecationMessage(d))
+	}
+}
+
+// visibleOptCfgs returns the OptCfg array excluding hidden options.
+func (ds *DaxSrc) visibleOptCfgs() []cliargs.OptCfg {
+	cfgs := make([]cliargs.OptCfg, 0, len(ds.optCfgs))
+	for _, cfg := range ds.optCfgs {
+		if !ds.metas[cfg.Name].hidden {
+			cfgs = append(cfgs, cfg)
+		}
+	}
+	return cfgs
+}
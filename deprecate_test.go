@@ -0,0 +1,232 @@
+package cliargdax_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+)
+
+func TestDeprecate_Hidden(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "foo", Desc: "Foo."},
+		cliargs.OptCfg{Name: "secret", HasArg: true, Desc: "Secret."},
+	}
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "--secret=x"}, optCfgs,
+	).Hidden("secret")
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.Cmd().OptArg("secret"), "x")
+	assert.Equal(t, len(conn.OptCfgs()), 2)
+	assert.Equal(t, conn.HelpText(), `Usage: app [OPTIONS]
+
+Options:
+      --foo   Foo.
+`)
+
+	var b strings.Builder
+	err = conn.GenCompletion("fish", &b)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, b.String(), "# fish completion for app\n"+
+		"complete -c app -l foo -d 'Foo.'\n")
+
+	b.Reset()
+	err = conn.GenMarkdownDoc(&b)
+	assert.True(t, err.IsOk())
+	assert.False(t, strings.Contains(b.String(), "secret"))
+}
+
+func TestDeprecate_opthidden(t *testing.T) {
+	type Options struct {
+		Foo    bool `optcfg:"foo"`
+		Secret bool `optcfg:"secret" opthidden:"true"`
+	}
+
+	options := Options{}
+	conn, err := setupForOptions(t, []string{"app", "--secret"}, &options)
+	assert.True(t, err.IsOk())
+	assert.True(t, options.Secret)
+	assert.Equal(t, conn.HelpText(), "Usage: app [OPTIONS]\n\nOptions:\n      --foo\n")
+}
+
+func TestDeprecate_Deprecated(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "old", Aliases: []string{"o"}},
+		cliargs.OptCfg{Name: "new"},
+	}
+	var msgs []string
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "-o", "--old"}, optCfgs,
+	).Deprecated("old", "use --new").
+		OnDeprecation(func(msg string) { msgs = append(msgs, msg) })
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.True(t, conn.Cmd().HasOpt("old"))
+	assert.Equal(t, conn.DeprecationWarnings(),
+		[]string{"--old is deprecated: use --new"})
+	assert.Equal(t, msgs, []string{"--old is deprecated: use --new"})
+
+	warnings := conn.Warnings()
+	assert.Equal(t, len(warnings), 1)
+	switch r := warnings[0].Reason().(type) {
+	case cliargdax.OptionDeprecated:
+		assert.Equal(t, r.Option, "old")
+		assert.Equal(t, r.Replacement, "")
+		assert.Equal(t, r.Message, "use --new")
+	default:
+		assert.Fail(t, warnings[0].Error())
+	}
+}
+
+func TestDeprecate_Deprecated_notGiven(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "old"},
+		cliargs.OptCfg{Name: "new"},
+	}
+	called := false
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "--new"}, optCfgs,
+	).Deprecated("old", "").OnDeprecation(func(string) { called = true })
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.DeprecationWarnings(), []string{})
+	assert.Equal(t, len(conn.Warnings()), 0)
+	assert.False(t, called)
+}
+
+func TestDeprecate_optdeprecated(t *testing.T) {
+	type Options struct {
+		Old string `optcfg:"old" optdeprecated:"use --new instead"`
+		New string `optcfg:"new"`
+	}
+
+	options := Options{}
+	conn, err := setupForOptions(t, []string{"app", "--old", "x"}, &options)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, options.Old, "x")
+	assert.Equal(t, conn.DeprecationWarnings(),
+		[]string{"--old is deprecated: use --new instead"})
+}
+
+func TestDeprecate_DeprecatedAlias(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "new-name", HasArg: true, IsArray: true},
+		cliargs.OptCfg{Name: "v"},
+	}
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "--old-name", "foo", "-v", "--old-name=bar"}, optCfgs,
+	).DeprecatedAlias("old-name", "new-name", "")
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.Cmd().OptArgs("new-name"), []string{"foo", "bar"})
+	assert.False(t, conn.Cmd().HasOpt("old-name"))
+	assert.Equal(t, conn.Cmd().Args(), []string{})
+	assert.Equal(t, conn.OptCount("new-name"), 2)
+	assert.Equal(t, conn.DeprecationWarnings(),
+		[]string{"--old-name is deprecated: use --new-name instead"})
+
+	switch r := conn.Warnings()[0].Reason().(type) {
+	case cliargdax.OptionDeprecated:
+		assert.Equal(t, r.Option, "old-name")
+		assert.Equal(t, r.Replacement, "new-name")
+	default:
+		assert.Fail(t, conn.Warnings()[0].Error())
+	}
+}
+
+func TestDeprecate_DeprecatedAlias_optionStore(t *testing.T) {
+	type Options struct {
+		Output string `optcfg:"output"`
+	}
+
+	options := Options{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions(
+		[]string{"app", "--out=a.txt"}, &options,
+	).DeprecatedAlias("out", "output", "renamed")
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, options.Output, "a.txt")
+	assert.Equal(t, conn.DeprecationWarnings(),
+		[]string{"--out is deprecated: renamed"})
+}
+
+func TestDeprecate_DeprecatedOptAlias(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{
+			Name: "output", Aliases: []string{"o", "out"}, HasArg: true, IsArray: true,
+		},
+		cliargs.OptCfg{Name: "organization", HasArg: true},
+	}
+
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "--output", "a", "--out=b"}, optCfgs,
+	).DeprecatedOptAlias("o", "use --organization for -o")
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.DeprecationWarnings(), []string{})
+	assert.Equal(t, len(conn.Warnings()), 0)
+
+	ds = cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "-o", "a", "-o=b", "--output=c"}, optCfgs,
+	).DeprecatedOptAlias("o", "use --organization for -o").
+		DeprecatedOptAlias("out", "").
+		Deprecated("output", "")
+	conn, err = setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.Cmd().OptArgs("output"), []string{"a", "b", "c"})
+	assert.Equal(t, conn.DeprecationWarnings(), []string{
+		"-o is deprecated: use --organization for -o",
+		"--output is deprecated",
+	})
+
+	warnings := conn.Warnings()
+	assert.Equal(t, len(warnings), 2)
+	assert.Equal(t, warnings[0].Reason(), cliargdax.OptionDeprecated{
+		Option: "o", Replacement: "output",
+		Message: "use --organization for -o",
+	})
+
+	ds = cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "--out", "a"}, optCfgs,
+	).DeprecatedOptAlias("out", "")
+	conn, err = setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.DeprecationWarnings(), []string{
+		"--out is deprecated: use --output instead",
+	})
+}
+
+func TestDeprecate_OptSpellings(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "output", Aliases: []string{"o"}, HasArg: true, IsArray: true},
+		cliargs.OptCfg{Name: "verbose", Aliases: []string{"v"}},
+		cliargs.OptCfg{Name: "level", HasArg: true},
+	}
+	t.Setenv("TEST_SPELLING_LEVEL", "3")
+
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "-o", "a", "--output=b", "-vv", "--verbose", "--", "-o",
+	}, optCfgs).SetMeta("level", cliargdax.OptMeta{EnvVar: "TEST_SPELLING_LEVEL"})
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.Cmd().OptArg("level"), "3")
+	assert.Equal(t, conn.OptSpellings("output"), []string{"-o", "--output"})
+	assert.Equal(t, conn.OptSpellings("verbose"),
+		[]string{"-v", "-v", "--verbose"})
+	assert.Equal(t, conn.OptSpellings("level"), []string{})
+
+	ds = cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "--out", "a", "/o:b",
+	}, optCfgs).AllowAbbrev(true).AllowSlashOpts(true)
+	conn, err = setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.OptSpellings("output"), []string{"--out", "/o"})
+}
@@ -0,0 +1,225 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"sort"
+
+	"github.com/sttk/cliargs"
+)
+
+// ResultDiff is the struct type which holds the differences between two
+// results of parsing, which is made by DiffResults function and
+// DaxConn#DiffFromPrevious method.
+// The fields Added, Removed, and Changed are the options which are only in
+// the new result, only in the old result, and in both with different option
+// arguments, in the order of their names.
+// The field Args is the difference of the command arguments.
+type ResultDiff struct {
+	Added   []OptDiff
+	Removed []OptDiff
+	Changed []OptDiff
+	Args    ArgsDiff
+}
+
+// OptDiff is the struct type which holds the difference of an option between
+// two results of parsing.
+// The fields Before and After are the option arguments in the old and the new
+// results, which are nil if the option is not in the result.
+// The fields AddedValues and RemovedValues are the option arguments which are
+// only in the new result and only in the old result when compared as sets.
+// The field OrderChanged is true if the option arguments differ as ordered
+// lists, and the field SetChanged is true if they differ as sets, so only
+// OrderChanged is true if the same option arguments are given in another
+// order.
+// If the field Redacted is true, the option is secret and its option
+// arguments are not held, but the other fields are still set.
+type OptDiff struct {
+	Name          string
+	Before        []string
+	After         []string
+	AddedValues   []string
+	RemovedValues []string
+	OrderChanged  bool
+	SetChanged    bool
+	Redacted      bool
+}
+
+// ArgsDiff is the struct type which holds the difference of the command
+// arguments between two results of parsing.
+// The field Changed is true if the command arguments of Before and After
+// differ.
+type ArgsDiff struct {
+	Before  []string
+	After   []string
+	Changed bool
+}
+
+// IsEmpty is the method to check whether the two results of parsing have no
+// difference in options and command arguments.
+func (d ResultDiff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0 &&
+		!d.Args.Changed
+}
+
+// Has is the method to check whether the specified option is added, removed,
+// or changed.
+func (d ResultDiff) Has(name string) bool {
+	for _, a := range [][]OptDiff{d.Added, d.Removed, d.Changed} {
+		for _, o := range a {
+			if o.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// DiffResults is the function to compare two results of parsing, for example
+// the ones before and after reloading options from a control file, and
+// returns the added, removed, and changed options and the change of the
+// command arguments.
+// The options compared are the ones configured by the option configurations,
+// so the options given for a wildcard configuration "*" are not compared.
+// An option is changed if its option arguments differ as ordered lists, and
+// OptDiff tells whether they also differ as sets.
+// Since cliargs.Cmd does not tell which options are secret, the option
+// arguments in the result of this function are not redacted, so use
+// DaxConn#DiffFromPrevious method to redact them.
+func DiffResults(prev, next cliargs.Cmd, optCfgs []cliargs.OptCfg) ResultDiff {
+	return diffResults(
+		prev, cmdOpts(prev, optCfgs, nil), next, cmdOpts(next, optCfgs, nil), nil,
+	)
+}
+
+// DiffFromPrevious is the method to compare the result of the previous
+// parsing of this DaxSrc with the current one, which is made when Setup
+// method is called again, for example after DaxSrc#WithArgs method, or when
+// DaxConn#Reparse or DaxConn#ReparseFor method succeeds.
+// The option arguments of secret options in the result are redacted, so
+// they appear only by their names.
+// If this DaxSrc has not parsed twice since it is created or reset, this
+// method returns false as the second result.
+// See DiffResults function for details.
+func (conn *DaxConn) DiffFromPrevious() (ResultDiff, bool) {
+	conn.ds.parseLazily()
+	conn.ds.mutex.RLock()
+	defer conn.ds.mutex.RUnlock()
+
+	ds := conn.ds
+	if ds.prevResult == nil || !ds.isParsed || ds.parseErr.IsNotOk() {
+		return ResultDiff{}, false
+	}
+	secrets := ds.secretOpts()
+	for name := range ds.prevResult.secrets {
+		secrets[name] = true
+	}
+	prev := ds.prevResult
+	return diffResults(
+		prev.cmd, cmdOpts(prev.cmd, prev.optCfgs, prev.counts),
+		ds.cmd, cmdOpts(ds.cmd, ds.optCfgs, ds.counts), secrets,
+	), true
+}
+
+// prevResult is the result of the previous parsing which is kept for
+// DaxConn#DiffFromPrevious method.
+type prevResult struct {
+	cmd     cliargs.Cmd
+	optCfgs []cliargs.OptCfg
+	counts  map[string]int
+	secrets map[string]bool
+}
+
+// savePrevResult keeps the current result of parsing as the previous one if
+// the parsing succeeded.
+func (ds *DaxSrc) savePrevResult() {
+	if !ds.isParsed || ds.parseErr.IsNotOk() {
+		return
+	}
+	ds.prevResult = &prevResult{
+		cmd: ds.cmd, optCfgs: ds.optCfgs, counts: ds.counts,
+		secrets: ds.secretOpts(),
+	}
+}
+
+func diffResults(
+	prev cliargs.Cmd, prevOpts map[string][]string,
+	next cliargs.Cmd, nextOpts map[string][]string,
+	secrets map[string]bool,
+) ResultDiff {
+	names := make([]string, 0, len(prevOpts)+len(nextOpts))
+	for name := range prevOpts {
+		names = append(names, name)
+	}
+	for name := range nextOpts {
+		if _, exists := prevOpts[name]; !exists {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var d ResultDiff
+	for _, name := range names {
+		before, inPrev := prevOpts[name]
+		after, inNext := nextOpts[name]
+		o := OptDiff{Name: name, Before: before, After: after}
+		o.AddedValues = subtractValues(after, before)
+		o.RemovedValues = subtractValues(before, after)
+		o.SetChanged = len(o.AddedValues) > 0 || len(o.RemovedValues) > 0
+		o.OrderChanged = !equalStrings(before, after)
+		if secrets[name] {
+			o = OptDiff{
+				Name: name, OrderChanged: o.OrderChanged, SetChanged: o.SetChanged,
+				Redacted: true,
+			}
+		}
+
+		switch {
+		case !inPrev:
+			d.Added = append(d.Added, o)
+		case !inNext:
+			d.Removed = append(d.Removed, o)
+		case o.OrderChanged:
+			d.Changed = append(d.Changed, o)
+		}
+	}
+
+	d.Args = ArgsDiff{
+		Before:  prev.Args(),
+		After:   next.Args(),
+		Changed: !equalStrings(prev.Args(), next.Args()),
+	}
+	return d
+}
+
+// cmdOpts returns the options of the cliargs.Cmd with their option arguments,
+// of which names are listed by cmdOptNames function.
+func cmdOpts(
+	cmd cliargs.Cmd, optCfgs []cliargs.OptCfg, counts map[string]int,
+) map[string][]string {
+	names := cmdOptNames(cmd, optCfgs, counts)
+	opts := make(map[string][]string, len(names))
+	for _, name := range names {
+		opts[name] = cmd.OptArgs(name)
+	}
+	return opts
+}
+
+// subtractValues returns the values in a which are not in b, without
+// duplicates, in the order of a.
+func subtractValues(a, b []string) []string {
+	set := make(map[string]bool, len(b))
+	for _, s := range b {
+		set[s] = true
+	}
+	var diff []string
+	for _, s := range a {
+		if !set[s] {
+			diff = append(diff, s)
+			set[s] = true
+		}
+	}
+	return diff
+}
@@ -0,0 +1,139 @@
+package cliargdax_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+)
+
+func TestDiffResults(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "verbose"},
+		cliargs.OptCfg{Name: "debug"},
+		cliargs.OptCfg{Name: "level", HasArg: true},
+		cliargs.OptCfg{Name: "tag", HasArg: true, IsArray: true},
+		cliargs.OptCfg{Name: "host", HasArg: true, IsArray: true},
+		cliargs.OptCfg{Name: "port", HasArg: true},
+	}
+	prev, err := cliargs.ParseWith([]string{
+		"app", "--verbose", "--level=1", "--tag=a", "--tag=b",
+		"--host=x", "--host=y", "--port=80", "file",
+	}, optCfgs)
+	assert.Nil(t, err)
+	next, err := cliargs.ParseWith([]string{
+		"app", "--debug", "--level=2", "--tag=b", "--tag=a",
+		"--host=x", "--host=z", "--host=x", "--port=80", "file", "more",
+	}, optCfgs)
+	assert.Nil(t, err)
+
+	d := cliargdax.DiffResults(prev, next, optCfgs)
+	assert.Equal(t, d.Added, []cliargdax.OptDiff{
+		cliargdax.OptDiff{Name: "debug", After: []string{}},
+	})
+	assert.Equal(t, d.Removed, []cliargdax.OptDiff{
+		cliargdax.OptDiff{Name: "verbose", Before: []string{}},
+	})
+	assert.Equal(t, d.Changed, []cliargdax.OptDiff{
+		cliargdax.OptDiff{
+			Name:          "host",
+			Before:        []string{"x", "y"},
+			After:         []string{"x", "z", "x"},
+			AddedValues:   []string{"z"},
+			RemovedValues: []string{"y"},
+			OrderChanged:  true,
+			SetChanged:    true,
+		},
+		cliargdax.OptDiff{
+			Name:          "level",
+			Before:        []string{"1"},
+			After:         []string{"2"},
+			AddedValues:   []string{"2"},
+			RemovedValues: []string{"1"},
+			OrderChanged:  true,
+			SetChanged:    true,
+		},
+		cliargdax.OptDiff{
+			Name:         "tag",
+			Before:       []string{"a", "b"},
+			After:        []string{"b", "a"},
+			OrderChanged: true,
+		},
+	})
+	assert.Equal(t, d.Args, cliargdax.ArgsDiff{
+		Before: []string{"file"}, After: []string{"file", "more"}, Changed: true,
+	})
+	assert.True(t, d.Has("level"))
+	assert.True(t, d.Has("verbose"))
+	assert.False(t, d.Has("port"))
+	assert.False(t, d.IsEmpty())
+
+	assert.True(t, cliargdax.DiffResults(prev, prev, optCfgs).IsEmpty())
+}
+
+type diffOptions struct {
+	Level    int    `optcfg:"level"`
+	Password string `optcfg:"password" optsecret:"true"`
+}
+
+func TestDaxConn_DiffFromPrevious(t *testing.T) {
+	options := diffOptions{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions([]string{
+		"app", "--level=1", "--password=foo",
+	}, &options)
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	_, ok := conn.DiffFromPrevious()
+	assert.False(t, ok)
+
+	ds.WithArgs([]string{"app", "--level=2", "--password=bar"})
+	conn, err = setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, options.Level, 2)
+
+	d, ok := conn.DiffFromPrevious()
+	assert.True(t, ok)
+	assert.Equal(t, d.Changed, []cliargdax.OptDiff{
+		cliargdax.OptDiff{
+			Name:          "level",
+			Before:        []string{"1"},
+			After:         []string{"2"},
+			AddedValues:   []string{"2"},
+			RemovedValues: []string{"1"},
+			OrderChanged:  true,
+			SetChanged:    true,
+		},
+		cliargdax.OptDiff{
+			Name: "password", OrderChanged: true, SetChanged: true, Redacted: true,
+		},
+	})
+
+	ds.Reset()
+	conn, err = setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	_, ok = conn.DiffFromPrevious()
+	assert.False(t, ok)
+}
+
+func TestDaxConn_DiffFromPrevious_reparse(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "foo"},
+		cliargs.OptCfg{Name: "bar"},
+	}
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "--foo", "--bar",
+	}, optCfgs)
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	_, err = conn.Reparse([]cliargs.OptCfg{
+		cliargs.OptCfg{Name: "foo"},
+		cliargs.OptCfg{Name: "*"},
+	})
+	assert.True(t, err.IsOk())
+
+	d, ok := conn.DiffFromPrevious()
+	assert.True(t, ok)
+	assert.True(t, d.IsEmpty())
+}
@@ -0,0 +1,160 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"github.com/sttk/cliargs"
+)
+
+type /* error reasons */ (
+	// OptionGivenTwice is the error reason which indicates that an option
+	// which takes an option argument but is not an array is given more than
+	// once in command line arguments.
+	// The fields FirstIndex and SecondIndex are the indexes of the first two
+	// occurrences in command line arguments, of which the program path is at
+	// index 0, and the fields FirstValue and SecondValue are their option
+	// arguments.
+	// The occurrences given by aliases are also counted as the option.
+	OptionGivenTwice struct {
+		Option      string
+		FirstIndex  int
+		SecondIndex int
+		FirstValue  string
+		SecondValue string
+	}
+)
+
+// DuplicatePolicy is the enum type which indicates how an option which takes
+// an option argument but is not an array is resolved when it is given more
+// than once in command line arguments.
+type DuplicatePolicy int
+
+const (
+	// ErrorOnDuplicate indicates that the parsing fails with the reason:
+	// OptionGivenTwice.
+	ErrorOnDuplicate DuplicatePolicy = iota
+
+	// FirstWins indicates that the option argument of the first occurrence is
+	// used and the others are ignored.
+	FirstWins
+
+	// LastWins indicates that the option argument of the last occurrence is
+	// used and the others are ignored.
+	LastWins
+)
+
+// OnDuplicate is the method to set the policy to resolve an option which
+// takes an option argument but is not an array and is given more than once in
+// command line arguments.
+// If option names are specified, the policy is applied to only those options,
+// otherwise it is applied to all options which have no policy of their own.
+// The policy is ErrorOnDuplicate by default.
+// The functions registered with OnOpt method are invoked for all the
+// occurrences of the option even if some of them are ignored by this policy.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) OnDuplicate(policy DuplicatePolicy, names ...string) *DaxSrc {
+	if len(names) == 0 {
+		ds.dupPolicy = policy
+		return ds
+	}
+	if ds.dupPolicies == nil {
+		ds.dupPolicies = make(map[string]DuplicatePolicy)
+	}
+	for _, name := range names {
+		ds.dupPolicies[name] = policy
+	}
+	return ds
+}
+
+// duplicatePolicy returns the policy to resolve the option of the specified
+// name when it is given more than once.
+func (r *parseResult) duplicatePolicy(name string) DuplicatePolicy {
+	if p, exists := r.dupPolicies[name]; exists {
+		return p
+	}
+	if r.isBlockOpt(name) {
+		return LastWins
+	}
+	return r.dupPolicy
+}
+
+// hasDuplicateResolution reports whether any option is resolved by a policy
+// other than ErrorOnDuplicate, including the options of option blocks.
+func (r *parseResult) hasDuplicateResolution() bool {
+	if r.dupPolicy != ErrorOnDuplicate || len(r.optBlocks) > 0 {
+		return true
+	}
+	for _, p := range r.dupPolicies {
+		if p != ErrorOnDuplicate {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveDuplicates finds the options which take option arguments but are not
+// arrays and are given more than once in the tokens, and records them to
+// report with the reason: OptionGivenTwice.
+// The occurrences ignored by the policies FirstWins and LastWins are removed
+// from the tokens.
+func (r *parseResult) resolveDuplicates(
+	toks []argToken, optCfgs []cliargs.OptCfg,
+) []argToken {
+	indexes := cfgIndexes(optCfgs)
+	occurrences := make(map[string][]int)
+	for i, tok := range toks {
+		if !tok.isOpt() {
+			continue
+		}
+		j, exists := indexes[tok.name]
+		if !exists || !optCfgs[j].HasArg || optCfgs[j].IsArray {
+			continue
+		}
+		occurrences[tok.name] = append(occurrences[tok.name], i)
+	}
+
+	var removed map[int]bool
+	for name, occ := range occurrences {
+		if len(occ) < 2 {
+			continue
+		}
+		switch r.duplicatePolicy(name) {
+		case FirstWins:
+			occ = occ[1:]
+		case LastWins:
+			occ = occ[:len(occ)-1]
+		default:
+			if r.duplicates == nil {
+				r.duplicates = make(map[string]OptionGivenTwice)
+			}
+			first, second := toks[occ[0]], toks[occ[1]]
+			r.duplicates[name] = OptionGivenTwice{
+				Option:      name,
+				FirstIndex:  first.index,
+				SecondIndex: second.index,
+				FirstValue:  first.value,
+				SecondValue: second.value,
+			}
+			continue
+		}
+		if removed == nil {
+			removed = make(map[int]bool)
+		}
+		for _, i := range occ {
+			removed[i] = true
+		}
+	}
+
+	if len(removed) == 0 {
+		return toks
+	}
+	resolved := make([]argToken, 0, len(toks)-len(removed))
+	for i, tok := range toks {
+		if !removed[i] {
+			resolved = append(resolved, tok)
+		}
+	}
+	return resolved
+}
@@ -0,0 +1,129 @@
+package cliargdax_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+	"github.com/sttk/sabi/errs"
+)
+
+var dupOptCfgs = []cliargs.OptCfg{
+	cliargs.OptCfg{Name: "name", Aliases: []string{"n"}, HasArg: true},
+	cliargs.OptCfg{Name: "level", HasArg: true},
+	cliargs.OptCfg{Name: "verbose", Aliases: []string{"v"}},
+}
+
+func TestOnDuplicate_errorByDefault(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "--name=a", "x", "-n", "b", "--name=c",
+	}, dupOptCfgs)
+	_, err := setupWithOptCfgs(t, ds)
+	assert.Equal(t, err.Reason(), cliargdax.OptionGivenTwice{
+		Option: "name", FirstIndex: 1, SecondIndex: 3,
+		FirstValue: "a", SecondValue: "b",
+	})
+	assert.Equal(t, cliargdax.FormatError(err, dupOptCfgs),
+		"option --name cannot be given more than once: "+
+			"\"a\" at argument 1 and \"b\" at argument 3\n"+
+			"Try '--help' for more information.")
+}
+
+func TestOnDuplicate_flagsAreNotDuplicates(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "-v", "--verbose",
+	}, dupOptCfgs)
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.OptCount("verbose"), 2)
+}
+
+func TestOnDuplicate_FirstWins(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "--name=a", "-n", "b", "--level=1", "--level=2",
+	}, dupOptCfgs).OnDuplicate(cliargdax.FirstWins)
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.Cmd().OptArg("name"), "a")
+	assert.Equal(t, conn.Cmd().OptArg("level"), "1")
+}
+
+func TestOnDuplicate_LastWins(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "--name=a", "-n", "b", "x", "--name", "c",
+	}, dupOptCfgs).OnDuplicate(cliargdax.LastWins)
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.Cmd().OptArg("name"), "c")
+	assert.Equal(t, conn.Cmd().Args(), []string{"x"})
+}
+
+func TestOnDuplicate_perOption(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "--name=a", "--name=b", "--level=1", "--level=2",
+	}, dupOptCfgs).OnDuplicate(cliargdax.LastWins, "name")
+	_, err := setupWithOptCfgs(t, ds)
+	assert.Equal(t, err.Reason(), cliargdax.OptionGivenTwice{
+		Option: "level", FirstIndex: 3, SecondIndex: 4,
+		FirstValue: "1", SecondValue: "2",
+	})
+
+	ds = cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "--name=a", "--name=b", "--level=1", "--level=2",
+	}, dupOptCfgs).
+		OnDuplicate(cliargdax.FirstWins).
+		OnDuplicate(cliargdax.LastWins, "name")
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.Cmd().OptArg("name"), "b")
+	assert.Equal(t, conn.Cmd().OptArg("level"), "1")
+}
+
+func TestOnDuplicate_withStore(t *testing.T) {
+	type Options struct {
+		Name  string `optcfg:"name,n"`
+		Token string `optcfg:"token" optsecret:"true"`
+	}
+
+	options := Options{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions([]string{
+		"app", "-n", "a", "--name=b",
+	}, &options).OnDuplicate(cliargdax.LastWins)
+	_, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, options.Name, "b")
+
+	options = Options{}
+	ds = cliargdax.NewDaxSrcWithArgsForOptions([]string{
+		"app", "--token=abc", "--token=def",
+	}, &options).Lazy()
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	err = conn.ParseErr()
+	assert.Equal(t, err.Reason(), cliargdax.OptionGivenTwice{
+		Option: "token", FirstIndex: 1, SecondIndex: 2,
+		FirstValue: "abc", SecondValue: "def",
+	})
+	assert.Equal(t, conn.FormatError(err),
+		"option --token cannot be given more than once: "+
+			"\"****\" at argument 1 and \"****\" at argument 2\n"+
+			"Try 'app --help' for more information.")
+}
+
+func TestOnDuplicate_callbacksSeeAllOccurrences(t *testing.T) {
+	var values []string
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "--name=a", "--name=b",
+	}, dupOptCfgs).
+		OnDuplicate(cliargdax.FirstWins).
+		OnOpt("name", func(value string) errs.Err {
+			values = append(values, value)
+			return errs.Ok()
+		})
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, values, []string{"a", "b"})
+	assert.Equal(t, conn.Cmd().OptArg("name"), "a")
+}
@@ -0,0 +1,159 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"reflect"
+	"strconv"
+
+	"github.com/sttk/cliargs"
+	"github.com/sttk/sabi/errs"
+)
+
+// NewDaxSrcDynamic is the constructor function for cliargdax.DaxSrc struct
+// that takes an array of instances of the cliargs.OptCfg struct, and stores
+// the results of parsing into a map[string]any instead of a struct instance,
+// for options which are contributed at runtime, for example by plugins.
+// After parsing, DaxConn#Options method returns the map, of which keys are
+// the names of the given options, including ones given by environment
+// variables or default values, and of which values are true for options
+// which take no option argument, strings for options which take an option
+// argument, and []string for array options.
+// If OptMeta.Kind of an option is set by DaxSrc#SetMeta method, the option
+// arguments are converted to the kind, like int or []int.
+// The options which are not given are absent in the map.
+// The map is made once by the parsing and is not modified after it, so it
+// can be read concurrently, but must not be modified.
+func NewDaxSrcDynamic(cfgs []cliargs.OptCfg) *DaxSrc {
+	ds := NewDaxSrcWithOptCfgs(cfgs)
+	ds.isDynamic = true
+	return ds
+}
+
+// NewDaxSrcWithArgsDynamic is the constructor function for cliargdax.DaxSrc
+// struct that takes an array of command line arguments to be parsed instead
+// of os.Args, and an array of instances of the cliargs.OptCfg struct.
+// Except for the command line arguments, this function is same as
+// NewDaxSrcDynamic function.
+func NewDaxSrcWithArgsDynamic(args []string, cfgs []cliargs.OptCfg) *DaxSrc {
+	ds := NewDaxSrcWithArgsAndOptCfgs(args, cfgs)
+	ds.isDynamic = true
+	return ds
+}
+
+// makeDynamicOptions makes the map of the dynamic option store from the
+// results of parsing.
+func makeDynamicOptions(
+	cmd cliargs.Cmd, optCfgs []cliargs.OptCfg, counts map[string]int,
+	metas map[string]optMeta,
+) (map[string]any, errs.Err) {
+	hasArg := make(map[string]bool, len(optCfgs))
+	isArray := make(map[string]bool, len(optCfgs))
+	for _, cfg := range optCfgs {
+		hasArg[cfg.Name] = cfg.HasArg
+		isArray[cfg.Name] = cfg.IsArray
+	}
+
+	opts := make(map[string]any)
+	for _, name := range cmdOptNames(cmd, optCfgs, counts) {
+		a := cmd.OptArgs(name)
+		if !hasArg[name] && len(a) == 0 {
+			opts[name] = true
+			continue
+		}
+		if !hasArg[name] && metas[name].boolValue {
+			opts[name] = (a[len(a)-1] != "false")
+			continue
+		}
+
+		kind := metas[name].kind
+		if !isArray[name] {
+			var s string
+			if len(a) > 0 {
+				s = a[len(a)-1]
+			}
+			v, err := convertDynamic(name, s, kind)
+			if err.IsNotOk() {
+				return nil, err
+			}
+			opts[name] = v
+			continue
+		}
+
+		if kind == reflect.Invalid || kind == reflect.String {
+			opts[name] = append([]string{}, a...)
+			continue
+		}
+		var arr reflect.Value
+		for _, s := range a {
+			v, err := convertDynamic(name, s, kind)
+			if err.IsNotOk() {
+				return nil, err
+			}
+			rv := reflect.ValueOf(v)
+			if !arr.IsValid() {
+				arr = reflect.MakeSlice(reflect.SliceOf(rv.Type()), 0, len(a))
+			}
+			arr = reflect.Append(arr, rv)
+		}
+		if arr.IsValid() {
+			opts[name] = arr.Interface()
+		}
+	}
+	return opts, errs.Ok()
+}
+
+// convertDynamic converts the option argument to the value of the kind.
+func convertDynamic(name, s string, kind reflect.Kind) (any, errs.Err) {
+	switch kind {
+	case reflect.Bool:
+		b, e := strconv.ParseBool(s)
+		if e != nil {
+			return nil, errs.New(OptionValidationFailed{
+				Option: name, Value: s, Cause: e,
+			}, e)
+		}
+		return b, errs.Ok()
+	case reflect.Int, reflect.Int64:
+		bitSize := 64
+		if kind == reflect.Int {
+			bitSize = strconv.IntSize
+		}
+		n, e := strconv.ParseInt(s, 0, bitSize)
+		if e != nil {
+			return nil, errs.New(cliargs.FailToParseInt{
+				Option: name, Input: s, BitSize: bitSize,
+			}, e)
+		}
+		if kind == reflect.Int {
+			return int(n), errs.Ok()
+		}
+		return n, errs.Ok()
+	case reflect.Uint, reflect.Uint64:
+		bitSize := 64
+		if kind == reflect.Uint {
+			bitSize = strconv.IntSize
+		}
+		n, e := strconv.ParseUint(s, 0, bitSize)
+		if e != nil {
+			return nil, errs.New(cliargs.FailToParseUint{
+				Option: name, Input: s, BitSize: bitSize,
+			}, e)
+		}
+		if kind == reflect.Uint {
+			return uint(n), errs.Ok()
+		}
+		return n, errs.Ok()
+	case reflect.Float64:
+		f, e := strconv.ParseFloat(s, 64)
+		if e != nil {
+			return nil, errs.New(cliargs.FailToParseFloat{
+				Option: name, Input: s, BitSize: 64,
+			}, e)
+		}
+		return f, errs.Ok()
+	}
+	return s, errs.Ok()
+}
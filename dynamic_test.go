@@ -0,0 +1,112 @@
+package cliargdax_test
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+)
+
+func TestDynamic_ok(t *testing.T) {
+	t.Setenv("MY_APP_RATE", "0.5")
+
+	cfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "verbose", Aliases: []string{"v"}},
+		cliargs.OptCfg{Name: "name", HasArg: true},
+		cliargs.OptCfg{Name: "tag", HasArg: true, IsArray: true},
+		cliargs.OptCfg{Name: "port", HasArg: true, Default: []string{"8080"}},
+		cliargs.OptCfg{Name: "id", HasArg: true, IsArray: true},
+		cliargs.OptCfg{Name: "rate", HasArg: true},
+		cliargs.OptCfg{Name: "quiet"},
+		cliargs.OptCfg{Name: "level", HasArg: true},
+	}
+	ds := cliargdax.NewDaxSrcWithArgsDynamic([]string{
+		"/path/to/app", "-v", "--name=foo", "--tag=a", "--tag=b",
+		"--id=1", "--id=2", "arg",
+	}, cfgs).
+		SetMeta("port", cliargdax.OptMeta{Kind: reflect.Int}).
+		SetMeta("id", cliargdax.OptMeta{Kind: reflect.Uint64}).
+		SetMeta("rate", cliargdax.OptMeta{
+			Kind: reflect.Float64, EnvVar: "MY_APP_RATE",
+		})
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	opts, ok := conn.Options().(map[string]any)
+	assert.True(t, ok)
+	assert.Equal(t, opts, map[string]any{
+		"verbose": true,
+		"name":    "foo",
+		"tag":     []string{"a", "b"},
+		"port":    8080,
+		"id":      []uint64{1, 2},
+		"rate":    0.5,
+	})
+	_, exists := opts["quiet"]
+	assert.False(t, exists)
+	_, exists = opts["level"]
+	assert.False(t, exists)
+
+	assert.Equal(t, conn.Cmd().Args(), []string{"arg"})
+}
+
+func TestDynamic_noOptCfgs(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsDynamic(
+		[]string{"/path/to/app", "--foo", "--bar=1", "--bar=2"}, nil,
+	)
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	assert.Equal(t, conn.Options(), map[string]any{
+		"foo": true,
+		"bar": "2",
+	})
+}
+
+func TestDynamic_failToConvert(t *testing.T) {
+	cfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "port", HasArg: true},
+	}
+	ds := cliargdax.NewDaxSrcWithArgsDynamic(
+		[]string{"/path/to/app", "--port=abc"}, cfgs,
+	).
+		SetMeta("port", cliargdax.OptMeta{Kind: reflect.Int})
+
+	_, err := setupWithOptCfgs(t, ds)
+	switch r := err.Reason().(type) {
+	case cliargs.FailToParseInt:
+		assert.Equal(t, r.Option, "port")
+		assert.Equal(t, r.Input, "abc")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestDynamic_concurrentRead(t *testing.T) {
+	cfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "name", HasArg: true},
+	}
+	ds := cliargdax.NewDaxSrcWithArgsDynamic(
+		[]string{"/path/to/app", "--name=foo"}, cfgs,
+	).
+		Lazy()
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			opts := conn.Options().(map[string]any)
+			assert.Equal(t, opts["name"], "foo")
+		}()
+	}
+	wg.Wait()
+}
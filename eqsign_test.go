@@ -0,0 +1,90 @@
+package cliargdax_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+)
+
+var eqSignCases = []struct {
+	arg    string
+	opt    string
+	values []string
+}{
+	{arg: "--opt=app=nginx", opt: "opt", values: []string{"app=nginx"}},
+	{arg: "--opt==value", opt: "opt", values: []string{"=value"}},
+	{arg: "--opt===", opt: "opt", values: []string{"=="}},
+	{arg: "--opt=", opt: "opt", values: []string{""}},
+	{arg: "-o=app=nginx", opt: "o", values: []string{"app=nginx"}},
+	{arg: "-o==x", opt: "o", values: []string{"=x"}},
+	{arg: "-o=", opt: "o", values: []string{""}},
+	{arg: "-ao==x", opt: "o", values: []string{"=x"}},
+	{arg: "-ao=", opt: "o", values: []string{""}},
+}
+
+func TestEqSign_withoutOptCfgs(t *testing.T) {
+	for _, c := range eqSignCases {
+		ds := cliargdax.NewDaxSrcWithArgs([]string{"app", c.arg, "x"})
+		conn, err := setupWithOptCfgs(t, ds)
+		assert.True(t, err.IsOk(), c.arg)
+		cmd := conn.Cmd()
+		assert.True(t, cmd.HasOpt(c.opt), c.arg)
+		assert.Equal(t, cmd.OptArgs(c.opt), c.values, c.arg)
+		assert.Equal(t, cmd.Args(), []string{"x"}, c.arg)
+	}
+}
+
+func TestEqSign_withOptCfgs(t *testing.T) {
+	modes := map[string]func(ds *cliargdax.DaxSrc){
+		"default":          func(ds *cliargdax.DaxSrc) {},
+		"AllowAbbrev":      func(ds *cliargdax.DaxSrc) { ds.AllowAbbrev(true) },
+		"IgnoreCase":       func(ds *cliargdax.DaxSrc) { ds.IgnoreCase(true) },
+		"CollectAllErrors": func(ds *cliargdax.DaxSrc) { ds.CollectAllErrors(true) },
+		"StopAtFirstArg":   func(ds *cliargdax.DaxSrc) { ds.StopAtFirstArg(true) },
+		"IgnoreUnknownOpts": func(ds *cliargdax.DaxSrc) {
+			ds.IgnoreUnknownOpts(true)
+		},
+	}
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "opt", Aliases: []string{"o"}, HasArg: true},
+		cliargs.OptCfg{Name: "a"},
+	}
+
+	for name, setMode := range modes {
+		for _, c := range eqSignCases {
+			ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+				[]string{"app", c.arg, "x"}, optCfgs,
+			)
+			setMode(ds)
+			conn, err := setupWithOptCfgs(t, ds)
+			assert.True(t, err.IsOk(), name+" "+c.arg)
+			cmd := conn.Cmd()
+			assert.True(t, cmd.HasOpt("opt"), name+" "+c.arg)
+			assert.Equal(t, cmd.OptArgs("opt"), c.values, name+" "+c.arg)
+			assert.Equal(t, cmd.Args(), []string{"x"}, name+" "+c.arg)
+		}
+	}
+}
+
+func TestEqSign_optionStore(t *testing.T) {
+	type Options struct {
+		Opt string `optcfg:"opt,o" optdefault:"def"`
+		A   bool   `optcfg:"a"`
+	}
+
+	for _, c := range eqSignCases {
+		options := Options{}
+		conn, err := setupForOptions(t, []string{"app", c.arg}, &options)
+		assert.True(t, err.IsOk(), c.arg)
+		assert.Equal(t, options.Opt, c.values[0], c.arg)
+		assert.True(t, conn.OptChanged("opt"), c.arg)
+	}
+
+	options := Options{}
+	conn, err := setupForOptions(t, []string{"app"}, &options)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, options.Opt, "def")
+	assert.False(t, conn.OptChanged("opt"))
+}
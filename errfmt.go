@@ -0,0 +1,415 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/sttk/cliargs"
+	"github.com/sttk/sabi/errs"
+)
+
+// FormatError is the function to make a user-facing message from an errs.Err
+// returned by this package, like:
+//
+//	unknown option: --qux (did you mean --quux?)
+//	Try '--help' for more information.
+//
+// The first line is the message for the reason of the error, and an option
+// name or alias in the option configurations which is close to an unknown
+// option is suggested.
+// The second line is the hint to run with the --help option.
+// If the reason is not known, the first line is the result of err.Error().
+// If err is ok, this function returns an empty string.
+func FormatError(err errs.Err, cfgs []cliargs.OptCfg) string {
+	return formatError(err, cfgs, nil, "help", "")
+}
+
+// FormatError is the method to make a user-facing message from an errs.Err in
+// the same way as FormatError function, with the option configurations of
+// this DaxConn excluding hidden options.
+// If the help option is enabled by DaxSrc#EnableHelpOpt method, the hint uses
+// its name, and the command name is also included in the hint.
+// The option arguments of secret options in the message are replaced with
+// "****".
+func (conn *DaxConn) FormatError(err errs.Err) string {
+	conn.ds.parseLazily()
+	conn.ds.mutex.RLock()
+	defer conn.ds.mutex.RUnlock()
+
+	ds := conn.ds
+	return formatError(err, ds.visibleOptCfgs(), ds.secretOpts(),
+		ds.helpOptName(), ds.hintCmdName())
+}
+
+// helpOptName returns the name of the help option, or "help" if the option is
+// not enabled.
+func (ds *DaxSrc) helpOptName() string {
+	if ds.helpOpt != nil {
+		return ds.helpOpt.Name
+	}
+	return "help"
+}
+
+// hintCmdName returns the command name which is used in messages, which is
+// available even if the parsing failed.
+func (ds *DaxSrc) hintCmdName() string {
+	if len(ds.cmdName) > 0 {
+		return ds.cmdName
+	}
+	return cmdName(ds.osArgs)
+}
+
+func formatError(
+	err errs.Err, cfgs []cliargs.OptCfg, secrets map[string]bool,
+	help string, cmdName string,
+) string {
+	if err.IsOk() {
+		return ""
+	}
+	return errorText(err, cfgs, secrets) + "\n" + helpHint(help, cmdName)
+}
+
+// errorText returns the message for the reason of the error, or the result of
+// err.Error() if the reason is not known.
+func errorText(
+	err errs.Err, cfgs []cliargs.OptCfg, secrets map[string]bool,
+) string {
+	msg, ok := errorMessage(err.Reason(), cfgs, secrets)
+	if !ok {
+		msg = err.Error()
+	}
+	return msg
+}
+
+// helpHint returns the line to hint to run with the help option.
+func helpHint(help string, cmdName string) string {
+	hint := optArg(help)
+	if len(cmdName) > 0 {
+		hint = cmdName + " " + hint
+	}
+	return msg(MsgHelpHint, "command", hint)
+}
+
+// errorMessage returns the one-line message for the error reason, and whether
+// the reason is known.
+// The option arguments of the options in secrets are replaced with "****".
+// The message is made with the message catalog set by SetMessages function.
+func errorMessage(
+	reason any, cfgs []cliargs.OptCfg, secrets map[string]bool,
+) (string, bool) {
+	switch r := redactReason(reason, secrets).(type) {
+	case cliargs.UnconfiguredOption:
+		return suggested(msg(MsgUnknownOption, "option", optArg(r.Option)),
+			suggestOptNames(r.Option, cfgs)), true
+	case UnconfiguredOptionWithSuggestion:
+		return suggested(msg(MsgUnknownOption, "option", optArg(r.Option)),
+			r.Suggestions), true
+	case cliargs.OptionNeedsArg:
+		return optMsg(MsgOptionNeedsArg, r.Option), true
+	case CombinedOptionNeedsArg:
+		return msg(MsgCombinedOptionNeedsArg,
+			"letter", r.Letter, "arg", r.Arg), true
+	case cliargs.OptionTakesNoArg:
+		return optMsg(MsgOptionTakesNoArg, r.Option), true
+	case cliargs.OptionIsNotArray:
+		return optMsg(MsgOptionIsNotArray, r.Option), true
+	case OptionGivenTwice:
+		return msg(MsgOptionGivenTwice, "option", optArg(r.Option),
+			"first", strconv.Quote(r.FirstValue),
+			"firstIndex", strconv.Itoa(r.FirstIndex),
+			"second", strconv.Quote(r.SecondValue),
+			"secondIndex", strconv.Itoa(r.SecondIndex)), true
+	case NotEnoughOptionArgs:
+		return msg(MsgNotEnoughOptionArgs, "option", optArg(r.Option),
+			"expected", strconv.Itoa(r.Expected),
+			"found", strconv.Itoa(r.Found)), true
+	case OptionNotAvailableInVersion:
+		return msg(MsgOptionNotAvailableInVersion, "option", optArg(r.Option),
+			"current", versionText(r.Current), "since", versionText(r.Since)), true
+	case OptionInterpolationCycle:
+		chain := make([]string, len(r.Chain))
+		for i, name := range r.Chain {
+			chain[i] = optArg(name)
+		}
+		return msg(MsgOptionInterpolationCycle,
+			"chain", strings.Join(chain, " -> ")), true
+	case UnknownOptionReference:
+		return msg(MsgUnknownOptionReference, "option", optArg(r.Option),
+			"placeholder", r.Placeholder), true
+	case cliargs.OptionHasInvalidChar:
+		return msg(MsgInvalidOption, "option", strconv.Quote(r.Option)), true
+	case cliargs.FailToParseInt:
+		return invalidValue(r.Option, r.Input, msg(MsgAnInteger)), true
+	case cliargs.FailToParseUint:
+		return invalidValue(r.Option, r.Input, msg(MsgANonNegativeInteger)), true
+	case cliargs.FailToParseFloat:
+		return invalidValue(r.Option, r.Input, msg(MsgANumber)), true
+	case FailToParseBool:
+		return invalidValue(r.Option, r.Input, msg(MsgABool)), true
+	case cliargs.IllegalOptionType:
+		return optMsg(MsgIllegalOptionType, r.Option), true
+	case cliargs.OptionStoreIsNotChangeable:
+		return msg(MsgOptionStoreIsNotChangeable), true
+	case OptionStoreIsNotPointer:
+		return msg(MsgOptionStoreIsNotPointer, "type", r.Type), true
+	case OptionStoreIsNil:
+		if len(r.Type) == 0 {
+			return msg(MsgOptionStoreIsNil, "type", "<nil>"), true
+		}
+		return msg(MsgOptionStoreIsNil, "type", r.Type), true
+	case OptionStoreIsNotStruct:
+		return msg(MsgOptionStoreIsNotStruct, "type", r.Type), true
+	case OptionStoreHasNoExportedField:
+		return msg(MsgOptionStoreHasNoExported, "type", r.Type), true
+	case cliargs.ConfigIsArrayButHasNoArg:
+		return optMsg(MsgConfigIsArrayButHasNoArg, r.Option), true
+	case cliargs.ConfigHasDefaultButHasNoArg:
+		return optMsg(MsgConfigHasDefaultButHasNoArg, r.Option), true
+
+	case AmbiguousOption:
+		return msg(MsgAmbiguousOption, "option", optArg(r.Option),
+			"options", joinOpts(r.Candidates, msg(MsgListSeparator))), true
+	case OptionNameCollidesIgnoringCase:
+		return msg(MsgOptionsCollideIgnoreCase,
+			"option", optArg(r.Option), "other", optArg(r.Other)), true
+	case RequiredOptionMissing:
+		if len(r.Options) > 1 {
+			return msg(MsgRequiredOptionsMissing,
+				"options", joinOpts(r.Options, msg(MsgListSeparator))), true
+		}
+		return optMsg(MsgRequiredOptionMissing, r.Option), true
+	case OptionRequiresOther:
+		return msg(MsgOptionRequiresOther, "option", optArg(r.Option),
+			"other", optArg(r.RequiredOption)), true
+	case OptionOutsideBlock:
+		return msg(MsgOptionOutsideBlock, "option", optArg(r.Option),
+			"block", optArg(r.Block)), true
+	case BlockOptionMissing:
+		return msg(MsgBlockOptionMissing, "option", optArg(r.Option),
+			"block", optArg(r.Block), "index", strconv.Itoa(r.Index+1)), true
+	case OptionValueNotInChoices:
+		return msg(MsgValueNotInChoices, "option", optArg(r.Option),
+			"value", strconv.Quote(r.Value),
+			"choices", strings.Join(r.Choices, msg(MsgListSeparator))), true
+	case OptionValueOutOfRange:
+		rng := rangeText(r.Min, r.Max)
+		return msg(MsgValueOutOfRange, "option", optArg(r.Option),
+			"value", strconv.Quote(r.Value), "range", rng), true
+	case IllegalOptionRange:
+		return optMsg(MsgIllegalOptionRange, r.Option), true
+	case IllegalOptionNArgs:
+		return optMsg(MsgIllegalOptionNArgs, r.Option), true
+	case OptionValidationFailed:
+		if len(r.Failures) > 0 {
+			msgs := make([]string, len(r.Failures))
+			for i, f := range r.Failures {
+				msgs[i], _ = errorMessage(f, cfgs, secrets)
+			}
+			return strings.Join(msgs, msg(MsgErrorSeparator)), true
+		}
+		return withCause(msg(MsgInvalidValue, "option", optArg(r.Option),
+			"value", strconv.Quote(r.Value)), r.Cause), true
+	case OptCallbackFailed:
+		m := optMsg(MsgOptCallbackFailed, r.Option)
+		if len(r.Value) > 0 {
+			m = msg(MsgOptCallbackFailedArg, "option", optArg(r.Option),
+				"value", strconv.Quote(r.Value))
+		}
+		return withCause(m, r.Cause), true
+	case OptionArgIsNotKeyValue:
+		return invalidValue(r.Option, r.Value, msg(MsgKeyValue)), true
+	case OptionArgHasDuplicateKey:
+		return msg(MsgDuplicateKey, "option", optArg(r.Option),
+			"key", strconv.Quote(r.Key)), true
+	case OptionArgUnmarshalFailed:
+		return withCause(msg(MsgInvalidValue, "option", optArg(r.Option),
+			"value", strconv.Quote(r.Value)), r.Cause), true
+	case OptionFileReadFailed:
+		return msg(MsgFailToReadOptionFile, "option", optArg(r.Option),
+			"path", r.Path), true
+	case OptionDeprecated:
+		m := optMsg(MsgOptionDeprecated, r.Option)
+		if len(r.Replacement) > 0 {
+			m = msg(MsgOptionDeprecatedUse, "option", optArg(r.Option),
+				"other", optArg(r.Replacement))
+		}
+		if len(r.Message) > 0 {
+			m = msg(MsgWithDetail, "message", m, "detail", r.Message)
+		}
+		return m, true
+	case FailToParseEnvVar:
+		return msg(MsgFailToParseEnvVar, "envvar", r.EnvVar,
+			"option", optArg(r.Option), "value", strconv.Quote(r.Input)), true
+	case FailToParseDefault:
+		return msg(MsgFailToParseDefault, "option", optArg(r.Option),
+			"value", strconv.Quote(r.Input)), true
+	case FailToParseElement:
+		return msg(MsgInvalidValue, "option", optArg(r.Option),
+			"value", strconv.Quote(r.Input)), true
+	case FailToParseDuration:
+		return invalidValue(r.Option, r.Input, msg(MsgADuration)), true
+	case FailToParseTime:
+		return invalidValue(r.Option, r.Input,
+			msg(MsgATime, "layout", r.Layout)), true
+	case FailToParseURL:
+		return invalidValue(r.Option, r.Input, msg(MsgAURL)), true
+	case FailToParseIP:
+		return invalidValue(r.Option, r.Input, msg(MsgAnIPAddress)), true
+	case FailToResolvePath:
+		return invalidValue(r.Option, r.Input, msg(MsgAPath)), true
+	case FailToOpenArgFile:
+		return msg(MsgFailToOpenArgFile, "path", r.Path), true
+	case MissingPositionalArg:
+		return msg(MsgMissingArg, "name", r.Name), true
+	case TooManyPositionalArgs:
+		return msg(MsgTooManyArgs, "got", strconv.Itoa(r.Got),
+			"max", strconv.Itoa(r.Max)), true
+	case UnknownSubCommand:
+		return msg(MsgUnknownSubCommand, "name", r.Name), true
+	case UnknownRoute:
+		m := msg(MsgUnknownSubCommand, "name", r.Name)
+		if len(r.Suggestions) == 0 {
+			return m, true
+		}
+		return msg(MsgDidYouMean, "message", m,
+			"options", strings.Join(r.Suggestions, msg(MsgOr))), true
+	case RouteIsMissing:
+		return msg(MsgRouteIsMissing,
+			"routes", strings.Join(r.Routes, msg(MsgListSeparator))), true
+	case FailToReadResponseFile:
+		return msg(MsgFailToReadResponseFile, "path", r.Path), true
+	case ResponseFileHasUnclosedQuote:
+		return msg(MsgResponseFileHasUnclosedQuote, "path", r.Path), true
+	case ResponseFileHasCycle:
+		return msg(MsgResponseFileHasCycle, "path", r.Path), true
+	case ResponseFileIsTooDeep:
+		return msg(MsgResponseFileIsTooDeep, "path", r.Path), true
+	case FailToReadStdinArgs:
+		return msg(MsgFailToReadStdinArgs), true
+	case TooManyStdinArgs:
+		return msg(MsgTooManyStdinArgs, "limit", strconv.Itoa(r.Limit)), true
+	case StdinArgsTooLarge:
+		return msg(MsgStdinArgsTooLarge, "limit", strconv.Itoa(r.Limit)), true
+	case SetupCanceled:
+		return msg(MsgSetupCanceled), true
+	case UnsupportedConfigFormat:
+		return msg(MsgUnsupportedConfigFormat,
+			"format", strconv.Quote(r.Format), "path", r.Path), true
+	case FailToReadConfigFile:
+		return msg(MsgFailToReadConfigFile, "path", r.Path), true
+	case FailToDecodeConfigFile:
+		return msg(MsgFailToDecodeConfigFile, "path", r.Path), true
+	case ConfigValueTypeMismatch:
+		return msg(MsgConfigValueTypeMismatch,
+			"key", r.Key, "value", r.Input), true
+	case FailToParseConfigValue:
+		return msg(MsgFailToParseConfigValue,
+			"key", r.Key, "value", strconv.Quote(r.Input)), true
+
+	case MultipleParseErrors:
+		msgs := make([]string, len(r.Errors))
+		for i, e := range r.Errors {
+			m, ok := errorMessage(e.Reason(), cfgs, secrets)
+			if !ok {
+				m = e.Error()
+			}
+			msgs[i] = m
+		}
+		return strings.Join(msgs, "\n"), true
+
+	case DuplicateOptName:
+		return optMsg(MsgDuplicateOptName, r.Name), true
+	case DuplicateOptAlias:
+		return msg(MsgDuplicateOptAlias, "alias", optArg(r.Alias)), true
+	case AliasCollidesWithName:
+		return msg(MsgAliasCollidesWithName,
+			"alias", optArg(r.Alias), "option", optArg(r.Name)), true
+	case OptionStoresCollide:
+		return optMsg(MsgOptionStoresCollide, r.Option), true
+	case WildcardWithIgnoreUnknownOpts:
+		return msg(MsgWildcardWithIgnoreUnknown), true
+	case OptionStoreHasCycle:
+		return msg(MsgOptionStoreHasCycle, "field", r.Field), true
+	case OptionStoreIsTooDeep:
+		return msg(MsgOptionStoreIsTooDeep, "field", r.Field), true
+	case DerivedOptNameCollides:
+		return msg(MsgDerivedOptNameCollides, "option", optArg(r.Option),
+			"field", r.Field, "other", r.OtherField), true
+	case IllegalArgPosition:
+		return msg(MsgIllegalArgPosition, "field", r.Field,
+			"position", strconv.Quote(r.Position)), true
+	case IllegalPositionalCfg:
+		return msg(MsgIllegalPositionalCfg, "name", r.Name), true
+	case IllegalChangedField:
+		return msg(MsgIllegalChangedField, "field", r.Field,
+			"option", strconv.Quote(r.Option)), true
+	case MetaForUnconfiguredOption:
+		return optMsg(MsgMetaForUnconfiguredOption, r.Option), true
+	case OptionsTypeMismatch:
+		return msg(MsgOptionsTypeMismatch), true
+	case UnsupportedShell:
+		return msg(MsgUnsupportedShell, "shell", strconv.Quote(r.Shell)), true
+	case FailToWriteCompletion:
+		return msg(MsgFailToWriteCompletion, "shell", r.Shell), true
+	case FailToWriteDoc:
+		return msg(MsgFailToWriteDoc, "format", r.Format), true
+	case FailToMarshalResult:
+		return msg(MsgFailToMarshalResult), true
+	case FailToMarshalSchema:
+		return msg(MsgFailToMarshalSchema), true
+	case FailToUnmarshalResult:
+		return msg(MsgFailToUnmarshalResult), true
+	}
+	return "", false
+}
+
+// optMsg makes the message of which only placeholder is {option}.
+func optMsg(id MsgID, option string) string {
+	return msg(id, "option", optArg(option))
+}
+
+// suggested appends the suggestions of option names to the message.
+func suggested(m string, suggestions []string) string {
+	if len(suggestions) == 0 {
+		return m
+	}
+	return msg(MsgDidYouMean,
+		"message", m, "options", joinOpts(suggestions, msg(MsgOr)))
+}
+
+// withCause appends the message of the cause to the message if it is not
+// nil.
+func withCause(m string, cause error) string {
+	if cause == nil {
+		return m
+	}
+	return msg(MsgWithCause, "message", m, "cause", cause.Error())
+}
+
+func invalidValue(option, input, want string) string {
+	return msg(MsgInvalidValueMustBe, "option", optArg(option),
+		"value", strconv.Quote(input), "want", want)
+}
+
+func rangeText(min, max string) string {
+	switch {
+	case len(min) > 0 && len(max) > 0:
+		return msg(MsgRangeBetween, "min", min, "max", max)
+	case len(min) > 0:
+		return msg(MsgRangeAtLeast, "min", min)
+	default:
+		return msg(MsgRangeAtMost, "max", max)
+	}
+}
+
+func joinOpts(names []string, sep string) string {
+	a := make([]string, len(names))
+	for i, n := range names {
+		a[i] = optArg(n)
+	}
+	return strings.Join(a, sep)
+}
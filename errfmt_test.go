@@ -0,0 +1,104 @@
+package cliargdax_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+	"github.com/sttk/sabi/errs"
+)
+
+func TestFormatError_unknownOption(t *testing.T) {
+	cfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "quux"},
+		cliargs.OptCfg{Name: "verbose", Aliases: []string{"v"}},
+	}
+
+	err := errs.New(cliargs.UnconfiguredOption{Option: "qux"})
+	assert.Equal(t, cliargdax.FormatError(err, cfgs),
+		"unknown option: --qux (did you mean --quux?)\n"+
+			"Try '--help' for more information.")
+
+	err = errs.New(cliargs.UnconfiguredOption{Option: "foo"})
+	assert.Equal(t, cliargdax.FormatError(err, cfgs),
+		"unknown option: --foo\nTry '--help' for more information.")
+
+	err = errs.New(cliargs.UnconfiguredOption{Option: "x"})
+	assert.Equal(t, cliargdax.FormatError(err, cfgs),
+		"unknown option: -x\nTry '--help' for more information.")
+}
+
+func TestFormatError_reasons(t *testing.T) {
+	const hint = "\nTry '--help' for more information."
+
+	testData := []struct {
+		reason any
+		msg    string
+	}{
+		{cliargs.OptionNeedsArg{Option: "n"}, "option -n requires an argument"},
+		{
+			cliargs.FailToParseInt{Option: "port", Input: "x"},
+			`invalid value for option --port: "x" (must be an integer)`,
+		},
+		{
+			cliargdax.RequiredOptionMissing{Option: "a", Options: []string{"a"}},
+			"option -a is required",
+		},
+		{
+			cliargdax.RequiredOptionMissing{
+				Option: "a", Options: []string{"a", "bb"},
+			},
+			"missing required options: -a, --bb",
+		},
+		{
+			cliargdax.OptionValueNotInChoices{
+				Option: "format", Value: "xml", Choices: []string{"json", "yaml"},
+			},
+			`invalid value for option --format: "xml" (choose from json, yaml)`,
+		},
+		{
+			cliargdax.OptionValueOutOfRange{
+				Option: "port", Value: "0", Min: "1", Max: "65535",
+			},
+			`value for option --port is out of range: "0" ` +
+				`(must be between 1 and 65535)`,
+		},
+		{
+			cliargdax.OptionValidationFailed{
+				Option: "a", Value: "1", Cause: errors.New("bad"),
+			},
+			`invalid value for option -a: "1" (bad)`,
+		},
+		{cliargdax.UnknownSubCommand{Name: "foo"}, "unknown command: foo"},
+	}
+
+	for _, d := range testData {
+		assert.Equal(t, cliargdax.FormatError(errs.New(d.reason), nil), d.msg+hint)
+	}
+}
+
+func TestFormatError_unknownReason(t *testing.T) {
+	type UnknownReason struct{}
+	err := errs.New(UnknownReason{})
+	assert.Equal(t, cliargdax.FormatError(err, nil),
+		err.Error()+"\nTry '--help' for more information.")
+
+	assert.Equal(t, cliargdax.FormatError(errs.Ok(), nil), "")
+}
+
+func TestFormatError_DaxConn(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"/path/to/app", "--verbos",
+	}, []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "verbose"},
+		cliargs.OptCfg{Name: "verbosx"},
+	}).Lazy().EnableHelpOpt("usage").Hidden("verbosx")
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	assert.Equal(t, conn.FormatError(conn.ParseErr()),
+		"unknown option: --verbos (did you mean --verbose?)\n"+
+			"Try 'app --usage' for more information.")
+}
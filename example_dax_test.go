@@ -17,7 +17,7 @@ func ExampleDaxConn_Cmd() {
 
 	base.Uses("cliarg", cliargdax.NewDaxSrc())
 
-	conn, err := sabi.GetDaxConn[cliargdax.DaxConn](base, "cliarg")
+	conn, err := sabi.GetDaxConn[*cliargdax.DaxConn](base, "cliarg")
 	fmt.Printf("err.IsOk = %t\n", err.IsOk())
 
 	cmd := conn.Cmd()
@@ -45,7 +45,7 @@ func ExampleDaxConn_OptCfgs() {
 	}{}
 	base.Uses("cliarg", cliargdax.NewDaxSrcForOptions(&opts))
 
-	conn, err := sabi.GetDaxConn[cliargdax.DaxConn](base, "cliarg")
+	conn, err := sabi.GetDaxConn[*cliargdax.DaxConn](base, "cliarg")
 	fmt.Printf("err.IsOk = %t\n", err.IsOk())
 
 	optCfgs := conn.OptCfgs()
@@ -71,7 +71,7 @@ func ExampleDaxConn_Options() {
 	}
 	base.Uses("cliarg", cliargdax.NewDaxSrcForOptions(&MyOptions{}))
 
-	conn, err := sabi.GetDaxConn[cliargdax.DaxConn](base, "cliarg")
+	conn, err := sabi.GetDaxConn[*cliargdax.DaxConn](base, "cliarg")
 	fmt.Printf("err.IsOk = %t\n", err.IsOk())
 
 	options := conn.Options().(*MyOptions)
@@ -92,7 +92,7 @@ func ExampleDaxConn_SetOptions() {
 
 	base.Uses("cliarg", cliargdax.NewDaxSrc())
 
-	conn, err := sabi.GetDaxConn[cliargdax.DaxConn](base, "cliarg")
+	conn, err := sabi.GetDaxConn[*cliargdax.DaxConn](base, "cliarg")
 	fmt.Printf("err.IsOk = %t\n", err.IsOk())
 
 	fmt.Printf("options = %v\n", conn.Options())
@@ -119,7 +119,7 @@ func ExampleNewDaxSrc() {
 
 	base.Uses("cliarg", cliargdax.NewDaxSrc())
 
-	conn, err := sabi.GetDaxConn[cliargdax.DaxConn](base, "cliarg")
+	conn, err := sabi.GetDaxConn[*cliargdax.DaxConn](base, "cliarg")
 	fmt.Printf("err.IsOk = %t\n", err.IsOk())
 
 	cmd := conn.Cmd()
@@ -153,7 +153,7 @@ func ExampleNewDaxSrcForOptions() {
 	}
 	base.Uses("cliarg", cliargdax.NewDaxSrcWithOptCfgs(optCfgs))
 
-	conn, err := sabi.GetDaxConn[cliargdax.DaxConn](base, "cliarg")
+	conn, err := sabi.GetDaxConn[*cliargdax.DaxConn](base, "cliarg")
 	fmt.Printf("err.IsOk = %t\n", err.IsOk())
 
 	cmd := conn.Cmd()
@@ -185,7 +185,7 @@ func ExampleNewDaxSrcWithOptCfgs() {
 	}
 	base.Uses("cliarg", cliargdax.NewDaxSrcForOptions(&MyOptions{}))
 
-	conn, err := sabi.GetDaxConn[cliargdax.DaxConn](base, "cliarg")
+	conn, err := sabi.GetDaxConn[*cliargdax.DaxConn](base, "cliarg")
 	fmt.Printf("err.IsOk = %t\n", err.IsOk())
 
 	cmd := conn.Cmd()
@@ -208,3 +208,77 @@ func ExampleNewDaxSrcWithOptCfgs() {
 
 	resetOsArgs()
 }
+
+func ExampleNewDaxSrcWithArgs() {
+	base := sabi.NewDaxBase()
+	defer base.Close()
+
+	args := []string{"path/to/app", "--foo", "bar"}
+	base.Uses("cliarg", cliargdax.NewDaxSrcWithArgs(args))
+
+	conn, err := sabi.GetDaxConn[*cliargdax.DaxConn](base, "cliarg")
+	fmt.Printf("err.IsOk = %t\n", err.IsOk())
+
+	cmd := conn.Cmd()
+	fmt.Printf("cmd.Name = %s\n", cmd.Name)
+	fmt.Printf("cmd.Args = %v\n", cmd.Args())
+	fmt.Printf("cmd.HasOpts: foo = %t\n", cmd.HasOpt("foo"))
+
+	// Output:
+	// err.IsOk = true
+	// cmd.Name = app
+	// cmd.Args = [bar]
+	// cmd.HasOpts: foo = true
+}
+
+func ExampleParse() {
+	cmd, err := cliargdax.Parse([]string{"path/to/app", "--foo", "bar"})
+	fmt.Printf("err.IsOk = %t\n", err.IsOk())
+
+	fmt.Printf("cmd.Name = %s\n", cmd.Name)
+	fmt.Printf("cmd.Args = %v\n", cmd.Args())
+	fmt.Printf("cmd.HasOpts: foo = %t\n", cmd.HasOpt("foo"))
+
+	// Output:
+	// err.IsOk = true
+	// cmd.Name = app
+	// cmd.Args = [bar]
+	// cmd.HasOpts: foo = true
+}
+
+func ExampleParseWith() {
+	cfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "foo", HasArg: true, Default: []string{"baz"}},
+	}
+	cmd, err := cliargdax.ParseWith([]string{"path/to/app", "bar"}, cfgs)
+	fmt.Printf("err.IsOk = %t\n", err.IsOk())
+
+	fmt.Printf("cmd.Args = %v\n", cmd.Args())
+	fmt.Printf("cmd.OptArg: foo = %s\n", cmd.OptArg("foo"))
+
+	// Output:
+	// err.IsOk = true
+	// cmd.Args = [bar]
+	// cmd.OptArg: foo = baz
+}
+
+func ExampleParseInto() {
+	type MyOptions struct {
+		Foo  bool `optcfg:"foo"`
+		Port int  `optcfg:"port=8080"`
+	}
+	options := MyOptions{}
+
+	cmd, err := cliargdax.ParseInto([]string{"path/to/app", "--foo"}, &options)
+	fmt.Printf("err.IsOk = %t\n", err.IsOk())
+
+	fmt.Printf("cmd.HasOpts: foo = %t\n", cmd.HasOpt("foo"))
+	fmt.Printf("options.Foo = %v\n", options.Foo)
+	fmt.Printf("options.Port = %v\n", options.Port)
+
+	// Output:
+	// err.IsOk = true
+	// cmd.HasOpts: foo = true
+	// options.Foo = true
+	// options.Port = 8080
+}
@@ -0,0 +1,98 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/sttk/sabi/errs"
+)
+
+// ExitOnError is the method to enable the mode to exit the process when
+// parsing command line arguments fails, which most command line programs
+// want.
+// In this mode, if the parsing, the validation, or a function registered with
+// AfterSetup method fails, the error message made in the same way as
+// DaxConn#FormatError method and the usage line are written to the writer
+// specified as the first argument, and the process exits with the code
+// specified as the second argument, which is conventionally 2.
+// And if the option enabled by EnableHelpOpt or EnableVersionOpt method is
+// given, the help text or the version text is written to os.Stdout, and the
+// process exits with the code 0 without invoking the functions registered
+// with AfterSetup method.
+// If the function is set by OnHelp method, it is invoked instead of writing
+// the help text.
+// The function to exit the process is os.Exit by default, and can be changed
+// by ExitFunc method.
+// This mode is disabled by default, and Setup method returns the error of
+// the parsing.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) ExitOnError(w io.Writer, code int) *DaxSrc {
+	ds.exitWriter = w
+	ds.exitCode = code
+	return ds
+}
+
+// ExitFunc is the method to set the function which is invoked to exit the
+// process in the mode enabled by ExitOnError method, instead of os.Exit.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) ExitFunc(exit func(code int)) *DaxSrc {
+	ds.exitFunc = exit
+	return ds
+}
+
+// isExitRequested reports whether the process is to exit for the help text or
+// the version text in the mode enabled by ExitOnError method, in which case
+// the functions registered with AfterSetup method are not invoked.
+func (ds *DaxSrc) isExitRequested() bool {
+	return ds.exitWriter != nil && (ds.helpRequested || ds.versionRequested)
+}
+
+// handleExit writes the error or the requested text and exits the process if
+// the mode enabled by ExitOnError method is on.
+// This method is called after the functions registered with AfterSetup method
+// are invoked, so the errors returned by them are also handled.
+func (ds *DaxSrc) handleExit(err errs.Err) {
+	if ds.exitWriter == nil {
+		return
+	}
+
+	exit := ds.exitFunc
+	if exit == nil {
+		exit = os.Exit
+	}
+
+	if err.IsNotOk() {
+		cfgs, _ := ds.usageOptCfgs()
+		var b strings.Builder
+		b.WriteString(errorText(err, cfgs, ds.secretOpts()))
+		b.WriteString("\n")
+		b.WriteString(helpUsage(ds.hintCmdName(), cfgs, ds.argCfgs))
+		b.WriteString("\n")
+		b.WriteString(helpHint(ds.helpOptName(), ds.hintCmdName()))
+		b.WriteString("\n")
+		io.WriteString(ds.exitWriter, b.String())
+		exit(ds.exitCode)
+		return
+	}
+
+	if ds.helpRequested {
+		if ds.onHelp == nil {
+			bw := bufio.NewWriter(os.Stdout)
+			ds.writeHelp(bw, ds.helpStyleFor(os.Stdout))
+			bw.Flush()
+		}
+		exit(0)
+		return
+	}
+
+	if ds.versionRequested {
+		io.WriteString(os.Stdout, ds.versionInfo.String()+"\n")
+		exit(0)
+	}
+}
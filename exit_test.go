@@ -0,0 +1,197 @@
+package cliargdax_test
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+	"github.com/sttk/sabi/errs"
+)
+
+func TestExitOnError_parseError(t *testing.T) {
+	cfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "color", HasArg: true},
+	}
+	var buf bytes.Buffer
+	code := -1
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"/path/to/app", "--colr=red"}, cfgs,
+	).
+		EnableHelpOpt("help", "h").
+		ExitOnError(&buf, 2).
+		ExitFunc(func(c int) { code = c })
+
+	err := ds.Setup(&noopAsyncGroup{})
+	defer ds.Close()
+
+	switch err.Reason().(type) {
+	case cliargdax.UnconfiguredOptionWithSuggestion:
+	default:
+		assert.Fail(t, err.Error())
+	}
+	assert.Equal(t, code, 2)
+	assert.Equal(t, buf.String(),
+		"unknown option: --colr (did you mean --color?)\n"+
+			"Usage: app [OPTIONS]\n"+
+			"Try 'app --help' for more information.\n")
+}
+
+func TestExitOnError_validationError(t *testing.T) {
+	options := struct {
+		Port int `optcfg:"port" optrequired:"true"`
+	}{}
+	var buf bytes.Buffer
+	code := -1
+	ds := cliargdax.NewDaxSrcWithArgsForOptions([]string{"/path/to/app"}, &options).
+		ExitOnError(&buf, 64).
+		ExitFunc(func(c int) { code = c })
+
+	err := ds.Setup(&noopAsyncGroup{})
+	defer ds.Close()
+
+	switch err.Reason().(type) {
+	case cliargdax.RequiredOptionMissing:
+	default:
+		assert.Fail(t, err.Error())
+	}
+	assert.Equal(t, code, 64)
+	assert.Equal(t, buf.String(),
+		"option --port is required\n"+
+			"Usage: app [OPTIONS]\n"+
+			"Try 'app --help' for more information.\n")
+}
+
+func TestExitOnError_afterSetupError(t *testing.T) {
+	type FailToOpen struct{}
+
+	var buf bytes.Buffer
+	code := -1
+	ds := cliargdax.NewDaxSrcWithArgs([]string{"/path/to/app", "--foo"}).
+		AfterSetup(func(conn *cliargdax.DaxConn) errs.Err {
+			return errs.New(FailToOpen{})
+		}).
+		ExitOnError(&buf, 2).
+		ExitFunc(func(c int) { code = c })
+
+	err := ds.Setup(&noopAsyncGroup{})
+	defer ds.Close()
+
+	switch err.Reason().(type) {
+	case FailToOpen:
+	default:
+		assert.Fail(t, err.Error())
+	}
+	assert.Equal(t, code, 2)
+	assert.True(t, strings.HasPrefix(buf.String(), err.Error()+"\n"), buf.String())
+	assert.Contains(t, buf.String(), "Usage: app")
+}
+
+func TestExitOnError_ok(t *testing.T) {
+	var buf bytes.Buffer
+	code := -1
+	ds := cliargdax.NewDaxSrcWithArgs([]string{"/path/to/app", "--foo"}).
+		ExitOnError(&buf, 2).
+		ExitFunc(func(c int) { code = c })
+
+	err := ds.Setup(&noopAsyncGroup{})
+	defer ds.Close()
+
+	assert.True(t, err.IsOk())
+	assert.Equal(t, code, -1)
+	assert.Equal(t, buf.String(), "")
+}
+
+func TestExitOnError_help(t *testing.T) {
+	var buf bytes.Buffer
+	var help string
+	code := -1
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"/path/to/app", "--help", "--unknown"}, []cliargs.OptCfg{},
+	).
+		EnableHelpOpt("help").
+		OnHelp(func(h string) { help = h }).
+		ExitOnError(&buf, 2).
+		ExitFunc(func(c int) { code = c })
+
+	err := ds.Setup(&noopAsyncGroup{})
+	defer ds.Close()
+
+	assert.True(t, err.IsOk())
+	assert.Equal(t, code, 0)
+	assert.Equal(t, buf.String(), "")
+	assert.Contains(t, help, "Usage: app")
+}
+
+func TestExitOnError_version(t *testing.T) {
+	f, e := os.CreateTemp(t.TempDir(), "stdout")
+	assert.Nil(t, e)
+	origStdout := os.Stdout
+	os.Stdout = f
+	defer func() { os.Stdout = origStdout }()
+
+	var buf bytes.Buffer
+	code := -1
+	ds := cliargdax.NewDaxSrcWithArgs([]string{"/path/to/app", "--version"}).
+		EnableVersionOpt("1.2.3").
+		ExitOnError(&buf, 2).
+		ExitFunc(func(c int) { code = c })
+
+	err := ds.Setup(&noopAsyncGroup{})
+	defer ds.Close()
+
+	assert.True(t, err.IsOk())
+	assert.Equal(t, code, 0)
+	assert.Equal(t, buf.String(), "")
+
+	out, e := os.ReadFile(f.Name())
+	assert.Nil(t, e)
+	assert.Equal(t, string(out), "1.2.3\n")
+}
+
+func TestExitOnError_lazy(t *testing.T) {
+	cfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "foo"},
+	}
+	var buf bytes.Buffer
+	code := -1
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"/path/to/app", "--bar"}, cfgs,
+	).
+		Lazy().
+		ExitOnError(&buf, 2).
+		ExitFunc(func(c int) { code = c })
+
+	err := ds.Setup(&noopAsyncGroup{})
+	defer ds.Close()
+	assert.True(t, err.IsOk())
+	assert.Equal(t, code, -1)
+
+	dc, err := ds.CreateDaxConn()
+	assert.True(t, err.IsOk())
+	conn := dc.(*cliargdax.DaxConn)
+
+	assert.True(t, conn.ParseErr().IsNotOk())
+	assert.Equal(t, code, 2)
+	assert.Contains(t, buf.String(), "unknown option: --bar\n")
+}
+
+func TestExitOnError_disabled(t *testing.T) {
+	cfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "foo"},
+	}
+	code := -1
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"/path/to/app", "--bar"}, cfgs,
+	).
+		ExitFunc(func(c int) { code = c })
+
+	err := ds.Setup(&noopAsyncGroup{})
+	defer ds.Close()
+
+	assert.True(t, err.IsNotOk())
+	assert.Equal(t, code, -1)
+}
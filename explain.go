@@ -0,0 +1,315 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/sttk/cliargs"
+	"github.com/sttk/sabi/errs"
+)
+
+// TokenKind is the type for the classifications of command line arguments.
+type TokenKind int
+
+const (
+	// LongOptionToken is the classification of an argument which is a long
+	// option, like --foo or --foo=bar, or an option in the form /name.
+	LongOptionToken TokenKind = iota
+
+	// ShortOptionsToken is the classification of an argument which is a
+	// short option or a group of short options, like -a or -abc.
+	ShortOptionsToken
+
+	// OptionArgToken is the classification of an argument which is the option
+	// argument of the option in the previous argument.
+	OptionArgToken
+
+	// PositionalToken is the classification of an argument which is a command
+	// argument, including a sub command name.
+	PositionalToken
+
+	// TerminatorToken is the classification of the option terminator "--".
+	TerminatorToken
+)
+
+// String is the method to retrieve the name of this classification.
+func (k TokenKind) String() string {
+	switch k {
+	case LongOptionToken:
+		return "long option"
+	case ShortOptionsToken:
+		return "short options"
+	case OptionArgToken:
+		return "option argument"
+	case PositionalToken:
+		return "positional"
+	case TerminatorToken:
+		return "terminator"
+	default:
+		return "unknown"
+	}
+}
+
+// TokenInfo is the struct type which holds how a command line argument was
+// interpreted in the parsing.
+// The field Index is the index of the argument in the command line arguments
+// after the expansion of response files, excluding the program path.
+// The field Token is the argument, of which option argument is replaced with
+// "****" if the option is secret.
+// The field Options is the array of the names of the options to which this
+// argument is attributed, and has multiple names for a group of short
+// options.
+// The field ResponseFile is the path of the response file from which this
+// argument is expanded, or an empty string if it is given directly.
+// The field Err is the error of the parsing at this argument, or errs.Ok()
+// if there is no error.
+type TokenInfo struct {
+	Index        int
+	Token        string
+	Kind         TokenKind
+	Options      []string
+	ResponseFile string
+	Err          errs.Err
+}
+
+// RecordTokens is the method to enable or disable the recording of how each
+// command line argument is interpreted in the parsing.
+// The recorded information can be retrieved by DaxConn#Explain and
+// DaxConn#ExplainText methods, even if the parsing failed.
+// This mode is disabled by default, and the parsing does not record anything
+// unless enabled.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) RecordTokens(record bool) *DaxSrc {
+	ds.recordTokens = record
+	return ds
+}
+
+// Explain is the method to retrieve the information of how each command line
+// argument was interpreted in the parsing.
+// The information is recorded only if it is enabled with DaxSrc#RecordTokens
+// method, otherwise this method returns an empty array.
+// Errors without options, like a missing command argument, are not
+// attributed to any argument.
+func (conn *DaxConn) Explain() []TokenInfo {
+	conn.ds.parseLazily()
+	conn.ds.mutex.RLock()
+	defer conn.ds.mutex.RUnlock()
+	return append([]TokenInfo{}, conn.ds.tokenInfos...)
+}
+
+// ExplainText is the method to retrieve the information by Explain method as
+// a table of which columns are aligned, for example to show it in debug
+// output.
+// The table has the columns: INDEX, TOKEN, KIND, OPTION, FILE, and ERROR,
+// and the empty columns at the end of each row are omitted.
+func (conn *DaxConn) ExplainText() string {
+	infos := conn.Explain()
+
+	conn.ds.mutex.RLock()
+	cfgs := conn.ds.explainCfgs()
+	secrets := conn.ds.secretOpts()
+	conn.ds.mutex.RUnlock()
+
+	rows := [][]string{
+		[]string{"INDEX", "TOKEN", "KIND", "OPTION", "FILE", "ERROR"},
+	}
+	for _, info := range infos {
+		var e string
+		if info.Err.IsNotOk() {
+			e = errorText(info.Err, cfgs, secrets)
+		}
+		rows = append(rows, []string{
+			strconv.Itoa(info.Index), info.Token, info.Kind.String(),
+			strings.Join(info.Options, ","), info.ResponseFile, e,
+		})
+	}
+
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for i, col := range row {
+			if n := len([]rune(col)); n > widths[i] {
+				widths[i] = n
+			}
+		}
+	}
+
+	var b strings.Builder
+	for _, row := range rows {
+		last := len(row) - 1
+		for last > 0 && len(row[last]) == 0 {
+			last--
+		}
+		for i := 0; i <= last; i++ {
+			b.WriteString(row[i])
+			if i < last {
+				pad := widths[i] - len([]rune(row[i])) + 2
+				b.WriteString(strings.Repeat(" ", pad))
+			}
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// explainCfgs returns the option configurations of this DaxSrc and its sub
+// command, which are made again from the option stores if the parsing
+// failed.
+func (ds *DaxSrc) explainCfgs() []cliargs.OptCfg {
+	cfgs := ds.optCfgs
+	if len(cfgs) == 0 {
+		if stores := ds.optionStores(); len(stores) > 0 {
+			cfgs, _, _, _ = ds.buildOptCfgsWithExtra(stores, ds.extraOptCfgs)
+		}
+	}
+	cfgs = append(append([]cliargs.OptCfg{}, cfgs...), ds.subOptCfgs...)
+	if len(ds.subOptCfgs) == 0 {
+		for _, sub := range ds.subCmds {
+			if sub.options != nil {
+				subCfgs, _, _, _ := buildOptCfgs(
+					sub.options, ds.decoders, ds.nameMapping,
+				)
+				cfgs = append(cfgs, subCfgs...)
+			} else {
+				cfgs = append(cfgs, sub.optCfgs...)
+			}
+		}
+	}
+	return cfgs
+}
+
+// explainTokens makes the information of how each command line argument is
+// interpreted, and attributes the errors of the parsing to the arguments.
+func (ds *DaxSrc) explainTokens(err errs.Err) []TokenInfo {
+	osArgs := ds.osArgs
+	if len(osArgs) <= 1 {
+		return nil
+	}
+
+	cfgs := ds.explainCfgs()
+	secrets := ds.secretOpts()
+	negated := make(map[string]string)
+	for _, cfg := range cfgs {
+		if ds.metas[cfg.Name].negatable {
+			negated["no-"+cfg.Name] = cfg.Name
+		}
+	}
+
+	infos := make([]TokenInfo, len(osArgs)-1)
+	for i := range infos {
+		infos[i] = TokenInfo{
+			Index: i, Token: osArgs[i+1], Kind: PositionalToken, Err: errs.Ok(),
+		}
+		if i+1 < len(ds.argFiles) {
+			infos[i].ResponseFile = ds.argFiles[i+1]
+		}
+	}
+
+	for _, tok := range ds.scanArgsAsParsed(osArgs, cfgs) {
+		info := &infos[tok.index-1]
+		switch {
+		case tok.isTerm:
+			if !tok.isImplicit {
+				info.Kind = TerminatorToken
+			}
+			continue
+		case !tok.isOpt():
+			continue
+		case tok.isShort:
+			info.Kind = ShortOptionsToken
+		default:
+			info.Kind = LongOptionToken
+		}
+
+		name := tok.name
+		if n, exists := negated[name]; exists {
+			name = n
+		}
+		info.Options = append(info.Options, name)
+
+		if !tok.hasValue {
+			continue
+		}
+		if tok.isNextArg {
+			next := &infos[tok.index]
+			next.Kind = OptionArgToken
+			next.Options = []string{name}
+			if secrets[name] {
+				next.Token = redacted
+			}
+		} else if secrets[name] {
+			info.Token = info.Token[:len(info.Token)-len(tok.value)] + redacted
+		}
+	}
+
+	if err.IsNotOk() {
+		errList := []errs.Err{err}
+		if m, ok := err.Reason().(MultipleParseErrors); ok {
+			errList = m.Errors
+		}
+		for _, e := range errList {
+			attributeErr(infos, e)
+		}
+	}
+
+	return infos
+}
+
+// attributeErr sets the error to the first argument which is attributed to
+// the option of the error, or to the last one if the error is that an option
+// which is not an array is given multiple times.
+// The error with the reason: OptionGivenTwice is set to the argument of its
+// second occurrence.
+func attributeErr(infos []TokenInfo, err errs.Err) {
+	if r, ok := err.Reason().(OptionGivenTwice); ok {
+		if i := r.SecondIndex - 1; i >= 0 && i < len(infos) {
+			if infos[i].Err.IsOk() {
+				infos[i].Err = err
+			}
+		}
+		return
+	}
+
+	name := reasonOption(err.Reason())
+	if len(name) == 0 {
+		return
+	}
+	_, isLast := err.Reason().(cliargs.OptionIsNotArray)
+
+	found := -1
+	for i, info := range infos {
+		for _, opt := range info.Options {
+			if opt == name {
+				found = i
+				break
+			}
+		}
+		if found >= 0 && !isLast {
+			break
+		}
+	}
+	if found >= 0 && infos[found].Err.IsOk() {
+		infos[found].Err = err
+	}
+}
+
+// reasonOption returns the value of the field Option of the error reason, or
+// an empty string if the reason has no such field.
+func reasonOption(reason any) string {
+	if r, ok := reason.(cliargs.InvalidOption); ok {
+		return r.GetOpt()
+	}
+	v := reflect.ValueOf(reason)
+	if v.Kind() != reflect.Struct {
+		return ""
+	}
+	f := v.FieldByName("Option")
+	if !f.IsValid() || f.Kind() != reflect.String {
+		return ""
+	}
+	return f.String()
+}
@@ -0,0 +1,157 @@
+package cliargdax_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+)
+
+var explainOptCfgs = []cliargs.OptCfg{
+	cliargs.OptCfg{Name: "name", Aliases: []string{"n"}, HasArg: true},
+	cliargs.OptCfg{Name: "all", Aliases: []string{"a"}},
+	cliargs.OptCfg{Name: "b"},
+	cliargs.OptCfg{Name: "level", HasArg: true},
+}
+
+func TestExplain_disabled(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "--all", "x"}, explainOptCfgs,
+	)
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.Explain(), []cliargdax.TokenInfo{})
+}
+
+func TestExplain_tokens(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "--name", "foo", "-ab", "x", "--level=3", "--", "--all",
+	}, explainOptCfgs).RecordTokens(true)
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	infos := conn.Explain()
+	assert.Equal(t, len(infos), 7)
+
+	assert.Equal(t, infos[0].Index, 0)
+	assert.Equal(t, infos[0].Token, "--name")
+	assert.Equal(t, infos[0].Kind, cliargdax.LongOptionToken)
+	assert.Equal(t, infos[0].Options, []string{"name"})
+	assert.True(t, infos[0].Err.IsOk())
+
+	assert.Equal(t, infos[1].Token, "foo")
+	assert.Equal(t, infos[1].Kind, cliargdax.OptionArgToken)
+	assert.Equal(t, infos[1].Options, []string{"name"})
+
+	assert.Equal(t, infos[2].Token, "-ab")
+	assert.Equal(t, infos[2].Kind, cliargdax.ShortOptionsToken)
+	assert.Equal(t, infos[2].Options, []string{"all", "b"})
+
+	assert.Equal(t, infos[3].Token, "x")
+	assert.Equal(t, infos[3].Kind, cliargdax.PositionalToken)
+	assert.Nil(t, infos[3].Options)
+
+	assert.Equal(t, infos[4].Kind, cliargdax.LongOptionToken)
+	assert.Equal(t, infos[4].Options, []string{"level"})
+
+	assert.Equal(t, infos[5].Kind, cliargdax.TerminatorToken)
+
+	assert.Equal(t, infos[6].Index, 6)
+	assert.Equal(t, infos[6].Token, "--all")
+	assert.Equal(t, infos[6].Kind, cliargdax.PositionalToken)
+}
+
+func TestExplain_error(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "--all", "--foo", "x"}, explainOptCfgs,
+	).RecordTokens(true)
+	err := ds.Setup(&noopAsyncGroup{})
+	t.Cleanup(ds.Close)
+	assert.True(t, err.IsNotOk())
+
+	dc, _ := ds.CreateDaxConn()
+	conn := dc.(*cliargdax.DaxConn)
+	infos := conn.Explain()
+	assert.Equal(t, len(infos), 3)
+	assert.True(t, infos[0].Err.IsOk())
+	assert.Equal(t, infos[1].Options, []string{"foo"})
+	switch infos[1].Err.Reason().(type) {
+	case cliargs.UnconfiguredOption:
+	default:
+		assert.Fail(t, infos[1].Err.Error())
+	}
+	assert.True(t, infos[2].Err.IsOk())
+}
+
+func TestExplain_optionIsNotArray(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "--level=1", "--level=2"}, explainOptCfgs,
+	).RecordTokens(true).CollectAllErrors(true)
+	err := ds.Setup(&noopAsyncGroup{})
+	t.Cleanup(ds.Close)
+	assert.True(t, err.IsNotOk())
+
+	dc, _ := ds.CreateDaxConn()
+	infos := dc.(*cliargdax.DaxConn).Explain()
+	assert.True(t, infos[0].Err.IsOk())
+	switch infos[1].Err.Reason().(type) {
+	case cliargdax.OptionGivenTwice:
+	default:
+		assert.Fail(t, infos[1].Err.Error())
+	}
+}
+
+func TestExplain_responseFile(t *testing.T) {
+	rsp := filepath.Join(t.TempDir(), "args.rsp")
+	assert.Nil(t, os.WriteFile(rsp, []byte("--name foo\n"), 0600))
+
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "@" + rsp, "x"}, explainOptCfgs,
+	).ExpandResponseFiles(true).RecordTokens(true)
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	infos := conn.Explain()
+	assert.Equal(t, len(infos), 3)
+	assert.Equal(t, infos[0].Token, "--name")
+	assert.Equal(t, infos[0].ResponseFile, rsp)
+	assert.Equal(t, infos[1].Kind, cliargdax.OptionArgToken)
+	assert.Equal(t, infos[1].ResponseFile, rsp)
+	assert.Equal(t, infos[2].Token, "x")
+	assert.Equal(t, infos[2].ResponseFile, "")
+}
+
+func TestExplain_secret(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "--name", "foo", "--level=3"}, explainOptCfgs,
+	).RecordTokens(true).
+		SetMeta("name", cliargdax.OptMeta{Secret: true}).
+		SetMeta("level", cliargdax.OptMeta{Secret: true})
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	infos := conn.Explain()
+	assert.Equal(t, infos[1].Token, "****")
+	assert.Equal(t, infos[2].Token, "--level=****")
+}
+
+func TestExplain_ExplainText(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "-n", "foo", "x", "--bar"}, explainOptCfgs,
+	).RecordTokens(true).Lazy()
+	err := ds.Setup(&noopAsyncGroup{})
+	t.Cleanup(ds.Close)
+	assert.True(t, err.IsOk())
+
+	dc, _ := ds.CreateDaxConn()
+	conn := dc.(*cliargdax.DaxConn)
+	assert.Equal(t, conn.ExplainText(), ""+
+		"INDEX  TOKEN  KIND             OPTION  FILE  ERROR\n"+
+		"0      -n     short options    name\n"+
+		"1      foo    option argument  name\n"+
+		"2      x      positional\n"+
+		"3      --bar  long option      bar           unknown option: --bar\n")
+}
@@ -0,0 +1,108 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"strings"
+
+	"github.com/sttk/sabi/errs"
+)
+
+type /* error reasons */ (
+	// OptionFileReadFailed is the error reason which indicates that the file
+	// specified with @path as an option argument cannot be read.
+	// The fields Option, Path, and Cause are the option name, the file path,
+	// and the error of reading the file.
+	OptionFileReadFailed struct {
+		Option string
+		Path   string
+		Cause  error
+	}
+)
+
+// FromFile is the method to set names of options of which option arguments
+// can be read from files.
+// If an option argument of these options starts with @, like @/path/to/file,
+// the contents of the file, of which a single trailing newline is trimmed,
+// are used as the option argument.
+// An option argument starting with a literal @ can be given by @@.
+// If failing to read the file, Setup method returns an errs.Err with the
+// reason: OptionFileReadFailed.
+// The option arguments before the substitution can be retrieved by
+// DaxConn#RawOptArgs method.
+// For option stores, the struct tag: optfromfile:"true" is also available.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) FromFile(names ...string) *DaxSrc {
+	if ds.fromFileOpts == nil {
+		ds.fromFileOpts = make(map[string]bool, len(names))
+	}
+	for _, name := range names {
+		ds.fromFileOpts[name] = true
+	}
+	return ds
+}
+
+// RawOptArgs is the method to retrieve the option arguments of the specified
+// option as given in command line arguments, before they are substituted with
+// the contents of files by the @path syntax.
+// For options which do not take option arguments from files, this method
+// returns the same array as cliargs.Cmd#OptArgs method.
+func (conn *DaxConn) RawOptArgs(name string) []string {
+	conn.ds.parseLazily()
+	conn.ds.mutex.RLock()
+	defer conn.ds.mutex.RUnlock()
+	if raw, exists := conn.ds.rawOptArgs[name]; exists {
+		return append([]string{}, raw...)
+	}
+	return conn.ds.cmd.OptArgs(name)
+}
+
+func hasFromFile(metas map[string]optMeta) bool {
+	for _, m := range metas {
+		if m.fromFile {
+			return true
+		}
+	}
+	return false
+}
+
+// readOptFiles substitutes option arguments of the tokens starting with @ with
+// the contents of the files, and records the option arguments before the
+// substitution.
+func (r *parseResult) readOptFiles(toks []argToken) ([]argToken, errs.Err) {
+	for i, tok := range toks {
+		if !tok.isOpt() || !tok.hasValue || !r.metas[tok.name].fromFile {
+			continue
+		}
+		if r.rawOptArgs == nil {
+			r.rawOptArgs = make(map[string][]string)
+		}
+		r.rawOptArgs[tok.name] = append(r.rawOptArgs[tok.name], tok.value)
+
+		switch {
+		case strings.HasPrefix(tok.value, "@@"):
+			toks[i].value = tok.value[1:]
+		case strings.HasPrefix(tok.value, "@"):
+			path := tok.value[1:]
+			if err := checkContext(r.ctx); err.IsNotOk() {
+				return nil, err
+			}
+			b, e := readFile(r.fsys, path)
+			if e != nil {
+				return nil, errs.New(OptionFileReadFailed{
+					Option: tok.name, Path: path, Cause: e,
+				}, e)
+			}
+			s := string(b)
+			if strings.HasSuffix(s, "\r\n") {
+				s = s[:len(s)-2]
+			} else if strings.HasSuffix(s, "\n") {
+				s = s[:len(s)-1]
+			}
+			toks[i].value = s
+		}
+	}
+	return toks, errs.Ok()
+}
@@ -0,0 +1,103 @@
+package cliargdax_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+)
+
+func writeTempFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	assert.Nil(t, os.WriteFile(path, []byte(content), 0600))
+	return path
+}
+
+func TestFromFile_FromFile(t *testing.T) {
+	cert := writeTempFile(t, "cert.pem", "-----BEGIN-----\nabc\n-----END-----\n")
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "cert", Aliases: []string{"c"}, HasArg: true},
+		cliargs.OptCfg{Name: "name", HasArg: true},
+	}
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "--cert", "@" + cert, "--name=@foo",
+	}, optCfgs).FromFile("cert")
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.Cmd().OptArg("cert"),
+		"-----BEGIN-----\nabc\n-----END-----")
+	assert.Equal(t, conn.Cmd().OptArg("name"), "@foo")
+	assert.Equal(t, conn.RawOptArgs("cert"), []string{"@" + cert})
+	assert.Equal(t, conn.RawOptArgs("name"), []string{"@foo"})
+	assert.Equal(t, conn.RawArgs(),
+		[]string{"--cert", "@" + cert, "--name=@foo"})
+}
+
+func TestFromFile_FromFile_escaped(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "user", HasArg: true, IsArray: true},
+	}
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "--user=@@alice", "--user", "bob",
+	}, optCfgs).FromFile("user")
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.Cmd().OptArgs("user"), []string{"@alice", "bob"})
+	assert.Equal(t, conn.RawOptArgs("user"), []string{"@@alice", "bob"})
+}
+
+func TestFromFile_FromFile_trimsSingleNewline(t *testing.T) {
+	path := writeTempFile(t, "token", "secret\n\n")
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "token", HasArg: true},
+	}
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "--token=@" + path,
+	}, optCfgs).FromFile("token")
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.Cmd().OptArg("token"), "secret\n")
+}
+
+func TestFromFile_FromFile_readFailed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nothing")
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "token", HasArg: true},
+	}
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "--token", "@" + path,
+	}, optCfgs).FromFile("token")
+
+	_, err := setupWithOptCfgs(t, ds)
+	switch r := err.Reason().(type) {
+	case cliargdax.OptionFileReadFailed:
+		assert.Equal(t, r.Option, "token")
+		assert.Equal(t, r.Path, path)
+		assert.True(t, os.IsNotExist(r.Cause))
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestFromFile_optfromfile(t *testing.T) {
+	path := writeTempFile(t, "port", "8080\n")
+	type Options struct {
+		Port int    `optcfg:"port" optfromfile:"true"`
+		Name string `optcfg:"name"`
+	}
+
+	options := Options{}
+	conn, err := setupForOptions(t, []string{
+		"app", "--port=@" + path, "--name", "@x",
+	}, &options)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, options.Port, 8080)
+	assert.Equal(t, options.Name, "@x")
+	assert.Equal(t, conn.RawOptArgs("port"), []string{"@" + path})
+}
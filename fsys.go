@@ -0,0 +1,67 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// WithFS is the method to set the file system from which response files,
+// files of option arguments starting with @, and config files are read,
+// instead of the file system of the OS.
+// This is useful to read files from fstest.MapFS in tests or to read a
+// default config file embedded with embed.FS.
+// Since the paths of fs.FS are slash-separated and unrooted, a path given to
+// this DaxSrc is mapped to the path of the file system by converting
+// separators to slashes, cleaning it, and stripping the leading slash, like
+// conf/app.json for /conf/app.json or ./conf/app.json.
+// A path which goes above the root, like ../app.json, cannot be read and
+// causes an error of which cause is fs.ErrInvalid.
+// If the file system is nil, which is the default, the files are read from
+// the OS as they are.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) WithFS(fsys fs.FS) *DaxSrc {
+	ds.fsys = fsys
+	return ds
+}
+
+// fsPath maps the path given to DaxSrc to the path of fs.FS.
+func fsPath(p string) string {
+	p = path.Clean(filepath.ToSlash(p))
+	if v := filepath.VolumeName(p); len(v) > 0 {
+		p = p[len(v):]
+	}
+	p = strings.TrimLeft(p, "/")
+	if len(p) == 0 {
+		return "."
+	}
+	return p
+}
+
+// readFile reads the file from the file system, or from the OS if the file
+// system is nil.
+func readFile(fsys fs.FS, p string) ([]byte, error) {
+	if fsys == nil {
+		return os.ReadFile(p)
+	}
+	name := fsPath(p)
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: p, Err: fs.ErrInvalid}
+	}
+	return fs.ReadFile(fsys, name)
+}
+
+// filePathKey returns the key to identify the file, which is the absolute path
+// for the OS, or the mapped path for fs.FS.
+func filePathKey(fsys fs.FS, p string) (string, error) {
+	if fsys == nil {
+		return filepath.Abs(p)
+	}
+	return fsPath(p), nil
+}
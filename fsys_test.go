@@ -0,0 +1,121 @@
+package cliargdax_test
+
+import (
+	"errors"
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+)
+
+func TestFS_WithFS_responseFiles(t *testing.T) {
+	fsys := fstest.MapFS{
+		"args/outer.rsp": &fstest.MapFile{
+			Data: []byte("--name 'John Smith' @/args/inner.rsp file1\n"),
+		},
+		"args/inner.rsp": &fstest.MapFile{Data: []byte("--level=3\n")},
+	}
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "name", HasArg: true},
+		cliargs.OptCfg{Name: "level", HasArg: true},
+	}
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "@./args/outer.rsp", "file2"}, optCfgs,
+	).ExpandResponseFiles(true).WithFS(fsys)
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	cmd := conn.Cmd()
+	assert.Equal(t, cmd.OptArg("name"), "John Smith")
+	assert.Equal(t, cmd.OptArg("level"), "3")
+	assert.Equal(t, cmd.Args(), []string{"file1", "file2"})
+}
+
+func TestFS_WithFS_responseFileCycle(t *testing.T) {
+	fsys := fstest.MapFS{
+		"a.rsp": &fstest.MapFile{Data: []byte("@/b.rsp")},
+		"b.rsp": &fstest.MapFile{Data: []byte("--x @a.rsp")},
+	}
+	ds := cliargdax.NewDaxSrcWithArgs([]string{"app", "@a.rsp"}).
+		ExpandResponseFiles(true).WithFS(fsys)
+	_, err := setupWithOptCfgs(t, ds)
+	switch r := err.Reason().(type) {
+	case cliargdax.ResponseFileHasCycle:
+		assert.Equal(t, r.Path, "a.rsp")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestFS_WithFS_optFiles(t *testing.T) {
+	fsys := fstest.MapFS{
+		"etc/cert.pem": &fstest.MapFile{Data: []byte("abc\n")},
+	}
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "cert", HasArg: true},
+	}
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "--cert", "@/etc/cert.pem"}, optCfgs,
+	).FromFile("cert").WithFS(fsys)
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.Cmd().OptArg("cert"), "abc")
+	assert.Equal(t, conn.RawOptArgs("cert"), []string{"@/etc/cert.pem"})
+}
+
+func TestFS_WithFS_outsideRoot(t *testing.T) {
+	fsys := fstest.MapFS{
+		"cert.pem": &fstest.MapFile{Data: []byte("abc")},
+	}
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "cert", HasArg: true},
+	}
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "--cert", "@../cert.pem"}, optCfgs,
+	).FromFile("cert").WithFS(fsys)
+
+	_, err := setupWithOptCfgs(t, ds)
+	switch r := err.Reason().(type) {
+	case cliargdax.OptionFileReadFailed:
+		assert.Equal(t, r.Path, "../cert.pem")
+		assert.True(t, errors.Is(err.Cause(), fs.ErrInvalid))
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestFS_WithFS_configFiles(t *testing.T) {
+	fsys := fstest.MapFS{
+		"etc/app.yaml": &fstest.MapFile{Data: []byte("port: 8080\n")},
+	}
+
+	opts := configOptions{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions(
+		[]string{"app"}, &opts,
+	).WithConfigFile("/home/app.json", "").
+		WithConfigFile("/etc/app.yaml", "").
+		WithFS(fsys)
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.ConfigFile(), "/etc/app.yaml")
+	assert.Equal(t, opts.Port, 8080)
+}
+
+func TestFS_WithFS_nilIsOS(t *testing.T) {
+	cert := writeTempFile(t, "cert.pem", "abc\n")
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "cert", HasArg: true},
+	}
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "--cert", "@" + cert}, optCfgs,
+	).FromFile("cert").WithFS(fstest.MapFS{}).WithFS(nil)
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.Cmd().OptArg("cert"), "abc")
+}
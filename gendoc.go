@@ -0,0 +1,260 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"io"
+	"strings"
+
+	"github.com/sttk/cliargs"
+	"github.com/sttk/sabi/errs"
+)
+
+type /* error reasons */ (
+	// FailToWriteDoc is the error reason which indicates that a document of
+	// options cannot be written to the io.Writer.
+	// The field Format is the format of the document: markdown or man.
+	FailToWriteDoc struct {
+		Format string
+	}
+)
+
+// docOpt is the option information which is described in a document.
+type docOpt struct {
+	flags    []string
+	argHelp  string
+	defaults []string
+	desc     string
+}
+
+func makeDocOpts(cfgs []cliargs.OptCfg) []docOpt {
+	opts := make([]docOpt, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		if cfg.Name == "*" {
+			continue
+		}
+		o := docOpt{defaults: cfg.Default, desc: cfg.Desc}
+		var longs []string
+		for _, name := range append([]string{cfg.Name}, cfg.Aliases...) {
+			if len([]rune(name)) == 1 {
+				o.flags = append(o.flags, "-"+name)
+			} else {
+				longs = append(longs, "--"+name)
+			}
+		}
+		o.flags = append(o.flags, longs...)
+		if cfg.HasArg {
+			o.argHelp = argLabel(cfg)
+		}
+		opts = append(opts, o)
+	}
+	return opts
+}
+
+// docSection is the section of the options which belong to a group in a
+// document.
+type docSection struct {
+	group string
+	opts  []docOpt
+}
+
+func makeDocSections(
+	groups []string, sections map[string][]cliargs.OptCfg,
+) []docSection {
+	docSections := make([]docSection, 0, len(groups))
+	for _, g := range groups {
+		if opts := makeDocOpts(sections[g]); len(opts) > 0 {
+			docSections = append(docSections, docSection{group: g, opts: opts})
+		}
+	}
+	return docSections
+}
+
+// docSectionTitle returns the title of the section, or an empty string if the
+// document has no group.
+func docSectionTitle(sections []docSection, sec docSection) string {
+	if len(sections) == 1 && len(sec.group) == 0 {
+		return ""
+	}
+	if len(sec.group) == 0 {
+		return msg(MsgDocOtherOptions)
+	}
+	return msg(MsgDocGroup, "group", sec.group)
+}
+
+func writeDoc(w io.Writer, format, doc string) errs.Err {
+	_, e := io.WriteString(w, doc)
+	if e != nil {
+		return errs.New(FailToWriteDoc{Format: format}, e)
+	}
+	return errs.Ok()
+}
+
+// GenMarkdownDoc is the function to write a Markdown document of the command
+// and the options in the OptCfg array to the io.Writer.
+// The document consists of NAME, SYNOPSIS, and OPTIONS sections, and each
+// option is described with its names, aliases, argument help, default
+// values, and description, in the order of the OptCfg array.
+// If failing to write, this function returns an errs.Err with the reason:
+// FailToWriteDoc.
+func GenMarkdownDoc(
+	cmdName string, cfgs []cliargs.OptCfg, w io.Writer,
+) errs.Err {
+	sections := map[string][]cliargs.OptCfg{"": cfgs}
+	return genMarkdownDoc(cmdName, makeDocSections([]string{""}, sections), w)
+}
+
+func genMarkdownDoc(
+	cmdName string, sections []docSection, w io.Writer,
+) errs.Err {
+	var b strings.Builder
+
+	b.WriteString("# " + mdEscape(cmdName) + "\n\n")
+	b.WriteString("## " + msg(MsgDocName) + "\n\n" + mdEscape(cmdName) + "\n\n")
+	b.WriteString("## " + msg(MsgDocSynopsis) + "\n\n")
+	b.WriteString("`" + cmdName)
+	if len(sections) > 0 {
+		b.WriteString(" " + msg(MsgUsageOptions))
+	}
+	b.WriteString("`\n")
+
+	if len(sections) > 0 {
+		b.WriteString("\n## " + msg(MsgDocOptions) + "\n")
+	}
+	for _, sec := range sections {
+		heading := "###"
+		if title := docSectionTitle(sections, sec); len(title) > 0 {
+			b.WriteString("\n### " + mdEscape(title) + "\n")
+			heading = "####"
+		}
+		for _, o := range sec.opts {
+			label := strings.Join(o.flags, ", ")
+			if len(o.argHelp) > 0 {
+				label += " " + o.argHelp
+			}
+			b.WriteString("\n" + heading + " `" + label + "`\n")
+			if len(o.desc) > 0 {
+				b.WriteString("\n")
+				for _, line := range strings.Split(o.desc, "\n") {
+					b.WriteString(mdEscape(line) + "  \n")
+				}
+			}
+			if o.defaults != nil {
+				b.WriteString("\n" + msg(MsgDocDefault,
+					"value", "`"+strings.Join(o.defaults, ",")+"`") + "\n")
+			}
+		}
+	}
+
+	return writeDoc(w, "markdown", b.String())
+}
+
+// mdEscape escapes the characters which have special meanings in Markdown.
+func mdEscape(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`, "`", "\\`", "*", `\*`, "_", `\_`, "[", `\[`, "]", `\]`,
+		"<", `\<`, ">", `\>`, "#", `\#`, "|", `\|`,
+	).Replace(s)
+}
+
+// GenManPage is the function to write a man page in roff format of the
+// command and the options in the OptCfg array to the io.Writer.
+// The man page is in section 1 and consists of NAME, SYNOPSIS, and OPTIONS
+// sections, like the document by GenMarkdownDoc function.
+// If failing to write, this function returns an errs.Err with the reason:
+// FailToWriteDoc.
+func GenManPage(cmdName string, cfgs []cliargs.OptCfg, w io.Writer) errs.Err {
+	sections := map[string][]cliargs.OptCfg{"": cfgs}
+	return genManPage(cmdName, makeDocSections([]string{""}, sections), w)
+}
+
+func genManPage(cmdName string, sections []docSection, w io.Writer) errs.Err {
+	var b strings.Builder
+
+	b.WriteString(".TH " + roffEscape(strings.ToUpper(cmdName)) + " 1\n")
+	b.WriteString(".SH " + msg(MsgDocName) + "\n" + roffLine(cmdName) + "\n")
+	b.WriteString(".SH " + msg(MsgDocSynopsis) + "\n.B " + roffEscape(cmdName) + "\n")
+	if len(sections) > 0 {
+		b.WriteString(msg(MsgUsageOptions) + "\n")
+		b.WriteString(".SH " + msg(MsgDocOptions) + "\n")
+	}
+	for _, sec := range sections {
+		if title := docSectionTitle(sections, sec); len(title) > 0 {
+			b.WriteString(".SS " + roffLine(title) + "\n")
+		}
+		for _, o := range sec.opts {
+			flags := make([]string, len(o.flags))
+			for i, f := range o.flags {
+				flags[i] = `\fB` + roffEscape(f) + `\fR`
+			}
+			label := strings.Join(flags, ", ")
+			if len(o.argHelp) > 0 {
+				label += ` \fI` + roffEscape(o.argHelp) + `\fR`
+			}
+			b.WriteString(".TP\n" + label + "\n")
+			if len(o.desc) > 0 {
+				for i, line := range strings.Split(o.desc, "\n") {
+					if i > 0 {
+						b.WriteString(".br\n")
+					}
+					b.WriteString(roffLine(line) + "\n")
+				}
+			}
+			if o.defaults != nil {
+				if len(o.desc) > 0 {
+					b.WriteString(".br\n")
+				}
+				b.WriteString(msg(MsgDocDefault,
+					"value", roffEscape(strings.Join(o.defaults, ","))) + "\n")
+			}
+		}
+	}
+
+	return writeDoc(w, "man", b.String())
+}
+
+// roffEscape escapes backslashes and hyphens for roff.
+func roffEscape(s string) string {
+	return strings.NewReplacer(`\`, `\e`, "-", `\-`).Replace(s)
+}
+
+// roffLine escapes the text line for roff, including a period or an
+// apostrophe at the head of the line which would be a control character.
+func roffLine(s string) string {
+	s = roffEscape(s)
+	if strings.HasPrefix(s, ".") || strings.HasPrefix(s, "'") {
+		s = `\&` + s
+	}
+	return s
+}
+
+// GenMarkdownDoc is the method to write a Markdown document with the command
+// name and the OptCfg array of this DaxConn, excluding hidden options.
+// The options which belong to groups are described in the subsections of
+// their groups in the order declared with DaxSrc#GroupOrder method.
+// See GenMarkdownDoc function for details.
+func (conn *DaxConn) GenMarkdownDoc(w io.Writer) errs.Err {
+	conn.ds.parseLazily()
+	conn.ds.mutex.RLock()
+	defer conn.ds.mutex.RUnlock()
+	return genMarkdownDoc(conn.ds.cmd.Name, conn.ds.docSections(), w)
+}
+
+// GenManPage is the method to write a man page with the command name and the
+// OptCfg array of this DaxConn, excluding hidden options.
+// The options which belong to groups are described in the subsections of
+// their groups like GenMarkdownDoc method.
+// See GenManPage function for details.
+func (conn *DaxConn) GenManPage(w io.Writer) errs.Err {
+	conn.ds.parseLazily()
+	conn.ds.mutex.RLock()
+	defer conn.ds.mutex.RUnlock()
+	return genManPage(conn.ds.cmd.Name, conn.ds.docSections(), w)
+}
+
+func (ds *DaxSrc) docSections() []docSection {
+	groups, sections := groupOptCfgs(ds.optCfgs, ds.metas, ds.groupOrder)
+	return makeDocSections(groups, sections)
+}
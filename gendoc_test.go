@@ -0,0 +1,128 @@
+package cliargdax_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+)
+
+var docOptCfgs = []cliargs.OptCfg{
+	cliargs.OptCfg{
+		Name: "foo-bar", Aliases: []string{"f"}, HasArg: true,
+		Default: []string{"x"}, Desc: "The *foo* option.\n.Second line.",
+	},
+	cliargs.OptCfg{Name: "verbose", Desc: `Prints C:\path <tmp>.`},
+	cliargs.OptCfg{
+		Name: "tag", HasArg: true, IsArray: true, ArgHelp: "<name>",
+		Default: []string{"a", "b"},
+	},
+}
+
+func TestGenDoc_GenMarkdownDoc(t *testing.T) {
+	var b strings.Builder
+	err := cliargdax.GenMarkdownDoc("my_app", docOptCfgs, &b)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, b.String(), "# my\\_app\n"+
+		"\n"+
+		"## NAME\n"+
+		"\n"+
+		"my\\_app\n"+
+		"\n"+
+		"## SYNOPSIS\n"+
+		"\n"+
+		"`my_app [OPTIONS]`\n"+
+		"\n"+
+		"## OPTIONS\n"+
+		"\n"+
+		"### `-f, --foo-bar <FOO_BAR>`\n"+
+		"\n"+
+		"The \\*foo\\* option.  \n"+
+		".Second line.  \n"+
+		"\n"+
+		"Default: `x`\n"+
+		"\n"+
+		"### `--verbose`\n"+
+		"\n"+
+		"Prints C:\\\\path \\<tmp\\>.  \n"+
+		"\n"+
+		"### `--tag <name>...`\n"+
+		"\n"+
+		"Default: `a,b`\n")
+}
+
+func TestGenDoc_GenManPage(t *testing.T) {
+	var b strings.Builder
+	err := cliargdax.GenManPage("my-app", docOptCfgs, &b)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, b.String(), `.TH MY\-APP 1
+.SH NAME
+my\-app
+.SH SYNOPSIS
+.B my\-app
+[OPTIONS]
+.SH OPTIONS
+.TP
+\fB\-f\fR, \fB\-\-foo\-bar\fR \fI<FOO_BAR>\fR
+The *foo* option.
+.br
+\&.Second line.
+.br
+Default: x
+.TP
+\fB\-\-verbose\fR
+Prints C:\epath <tmp>.
+.TP
+\fB\-\-tag\fR \fI<name>...\fR
+Default: a,b
+`)
+}
+
+func TestGenDoc_noOptions(t *testing.T) {
+	var b strings.Builder
+	err := cliargdax.GenManPage("app", nil, &b)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, b.String(), ".TH APP 1\n.SH NAME\napp\n.SH SYNOPSIS\n.B app\n")
+
+	b.Reset()
+	err = cliargdax.GenMarkdownDoc("app", nil, &b)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, b.String(), "# app\n\n## NAME\n\napp\n\n## SYNOPSIS\n\n`app`\n")
+}
+
+func TestGenDoc_failToWrite(t *testing.T) {
+	err := cliargdax.GenManPage("app", docOptCfgs, failWriter{})
+	switch r := err.Reason().(type) {
+	case cliargdax.FailToWriteDoc:
+		assert.Equal(t, r.Format, "man")
+	default:
+		assert.Fail(t, err.Error())
+	}
+
+	err = cliargdax.GenMarkdownDoc("app", docOptCfgs, failWriter{})
+	switch r := err.Reason().(type) {
+	case cliargdax.FailToWriteDoc:
+		assert.Equal(t, r.Format, "markdown")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestGenDoc_DaxConn(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{"/path/to/app"},
+		[]cliargs.OptCfg{cliargs.OptCfg{Name: "foo"}})
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	var b strings.Builder
+	err = conn.GenManPage(&b)
+	assert.True(t, err.IsOk())
+	assert.True(t, strings.HasPrefix(b.String(), ".TH APP 1\n"))
+
+	b.Reset()
+	err = conn.GenMarkdownDoc(&b)
+	assert.True(t, err.IsOk())
+	assert.True(t, strings.HasPrefix(b.String(), "# app\n"))
+}
@@ -6,6 +6,8 @@ require (
 	github.com/stretchr/testify v1.8.4
 	github.com/sttk/cliargs v0.6.0
 	github.com/sttk/sabi v0.6.0
+	golang.org/x/term v0.13.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -14,7 +16,5 @@ require (
 	github.com/sttk/linebreak v0.3.0 // indirect
 	github.com/sttk/orderedmap v1.0.0 // indirect
 	golang.org/x/sys v0.13.0 // indirect
-	golang.org/x/term v0.13.0 // indirect
 	golang.org/x/text v0.13.0 // indirect
-	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,75 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"github.com/sttk/cliargs"
+)
+
+// GroupOrder is the method to declare the order of the option groups in the
+// help text and in the documents.
+// The options of the declared groups are listed first in the declared order,
+// followed by the options of the other groups in the order of appearance,
+// and the options which belong to no group are listed in the last section.
+// If this method is not called, all the sections are in the order of
+// appearance.
+// The groups which have no visible option are omitted.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) GroupOrder(groups ...string) *DaxSrc {
+	ds.groupOrder = groups
+	return ds
+}
+
+// optGroup returns the group of the option, which is the value of the
+// optgroup struct tag or of OptMeta#Group if set, or the path of the nested
+// struct in the option store.
+func (m optMeta) optGroup() string {
+	if len(m.section) > 0 {
+		return m.section
+	}
+	return m.group
+}
+
+// groupOptCfgs divides the option configurations into the sections of their
+// groups, excluding hidden options, and returns the group names in the order
+// of the sections with the option configurations of each group.
+func groupOptCfgs(
+	cfgs []cliargs.OptCfg, metas map[string]optMeta, order []string,
+) ([]string, map[string][]cliargs.OptCfg) {
+	var appeared []string
+	sections := make(map[string][]cliargs.OptCfg)
+	for _, cfg := range cfgs {
+		if cfg.Name == "*" || metas[cfg.Name].hidden {
+			continue
+		}
+		g := metas[cfg.Name].optGroup()
+		if _, exists := sections[g]; !exists {
+			appeared = append(appeared, g)
+		}
+		sections[g] = append(sections[g], cfg)
+	}
+
+	if len(order) == 0 {
+		return appeared, sections
+	}
+
+	groups := make([]string, 0, len(appeared))
+	declared := make(map[string]bool, len(order))
+	for _, g := range order {
+		if _, exists := sections[g]; exists && len(g) > 0 && !declared[g] {
+			groups = append(groups, g)
+		}
+		declared[g] = true
+	}
+	for _, g := range appeared {
+		if len(g) > 0 && !declared[g] {
+			groups = append(groups, g)
+		}
+	}
+	if _, exists := sections[""]; exists {
+		groups = append(groups, "")
+	}
+	return groups, sections
+}
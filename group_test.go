@@ -0,0 +1,220 @@
+package cliargdax_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+)
+
+type groupOptions struct {
+	Verbose bool   `optcfg:"verbose" optdesc:"Verbose mode."`
+	Host    string `optcfg:"host" optgroup:"Networking" optdesc:"Host name."`
+	Debug   bool   `optcfg:"debug" optgroup:"Advanced" optdesc:"Debug mode."`
+	Port    int    `optcfg:"port" optgroup:"Networking" optdesc:"Port number."`
+	Name    string `optcfg:"name" optgroup:"General" optdesc:"User name."`
+	Secret  string `optcfg:"secret" optgroup:"Hidden" opthidden:"true"`
+}
+
+func TestGroup_HelpText_orderOfAppearance(t *testing.T) {
+	options := groupOptions{}
+	conn, err := setupForOptions(t, []string{"app"}, &options)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.HelpText(), `Usage: app [OPTIONS]
+
+Options:
+      --verbose       Verbose mode.
+
+Networking options:
+      --host <HOST>   Host name.
+      --port <PORT>   Port number.
+
+Advanced options:
+      --debug         Debug mode.
+
+General options:
+      --name <NAME>   User name.
+`)
+	assert.Equal(t, conn.OptGroup("host"), "Networking")
+	assert.Equal(t, conn.OptGroup("verbose"), "")
+}
+
+func TestGroup_HelpText_GroupOrder(t *testing.T) {
+	options := groupOptions{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions([]string{"app"}, &options)
+	ds.GroupOrder("General", "Networking", "Empty", "Advanced")
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.HelpText(), `Usage: app [OPTIONS]
+
+General options:
+      --name <NAME>   User name.
+
+Networking options:
+      --host <HOST>   Host name.
+      --port <PORT>   Port number.
+
+Advanced options:
+      --debug         Debug mode.
+
+Options:
+      --verbose       Verbose mode.
+`)
+}
+
+func TestGroup_HelpText_undeclaredGroup(t *testing.T) {
+	options := groupOptions{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions([]string{"app"}, &options)
+	ds.GroupOrder("Advanced")
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.HelpText(), `Usage: app [OPTIONS]
+
+Advanced options:
+      --debug         Debug mode.
+
+Networking options:
+      --host <HOST>   Host name.
+      --port <PORT>   Port number.
+
+General options:
+      --name <NAME>   User name.
+
+Options:
+      --verbose       Verbose mode.
+`)
+}
+
+func TestGroup_optgroupOverridesNestedStruct(t *testing.T) {
+	type Server struct {
+		Port int    `optcfg:"port" optdesc:"Port number."`
+		Host string `optcfg:"host" optgroup:"Networking" optdesc:"Host name."`
+	}
+	type Options struct {
+		Server Server
+	}
+
+	options := Options{}
+	conn, err := setupForOptions(t, []string{"app"}, &options)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.OptGroup("server-port"), "Server")
+	assert.Equal(t, conn.OptGroup("server-host"), "Networking")
+}
+
+func TestGroup_OptMeta(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{"app"},
+		[]cliargs.OptCfg{
+			cliargs.OptCfg{Name: "foo", Desc: "Foo."},
+			cliargs.OptCfg{Name: "bar", Desc: "Bar."},
+		})
+	ds.SetMeta("bar", cliargdax.OptMeta{Group: "Advanced"})
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	meta, ok := conn.Meta("bar")
+	assert.True(t, ok)
+	assert.Equal(t, meta.Group, "Advanced")
+	meta, ok = conn.Meta("foo")
+	assert.True(t, ok)
+	assert.Equal(t, meta.Group, "")
+
+	assert.Equal(t, conn.HelpText(), `Usage: app [OPTIONS]
+
+Options:
+      --foo   Foo.
+
+Advanced options:
+      --bar   Bar.
+`)
+}
+
+func TestGroup_GenMarkdownDoc(t *testing.T) {
+	options := groupOptions{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions([]string{"app"}, &options)
+	ds.GroupOrder("Networking")
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	var b strings.Builder
+	err = conn.GenMarkdownDoc(&b)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, b.String(), "# app\n"+
+		"\n"+
+		"## NAME\n"+
+		"\n"+
+		"app\n"+
+		"\n"+
+		"## SYNOPSIS\n"+
+		"\n"+
+		"`app [OPTIONS]`\n"+
+		"\n"+
+		"## OPTIONS\n"+
+		"\n"+
+		"### Networking options\n"+
+		"\n"+
+		"#### `--host <HOST>`\n"+
+		"\n"+
+		"Host name.  \n"+
+		"\n"+
+		"#### `--port <PORT>`\n"+
+		"\n"+
+		"Port number.  \n"+
+		"\n"+
+		"### Advanced options\n"+
+		"\n"+
+		"#### `--debug`\n"+
+		"\n"+
+		"Debug mode.  \n"+
+		"\n"+
+		"### General options\n"+
+		"\n"+
+		"#### `--name <NAME>`\n"+
+		"\n"+
+		"User name.  \n"+
+		"\n"+
+		"### Other options\n"+
+		"\n"+
+		"#### `--verbose`\n"+
+		"\n"+
+		"Verbose mode.  \n")
+}
+
+func TestGroup_GenManPage(t *testing.T) {
+	options := groupOptions{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions([]string{"app"}, &options)
+	ds.GroupOrder("Advanced", "General", "Networking")
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	var b strings.Builder
+	err = conn.GenManPage(&b)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, b.String(), ".TH APP 1\n"+
+		".SH NAME\n"+
+		"app\n"+
+		".SH SYNOPSIS\n"+
+		".B app\n"+
+		"[OPTIONS]\n"+
+		".SH OPTIONS\n"+
+		".SS Advanced options\n"+
+		".TP\n"+
+		`\fB\-\-debug\fR`+"\n"+
+		"Debug mode.\n"+
+		".SS General options\n"+
+		".TP\n"+
+		`\fB\-\-name\fR \fI<NAME>\fR`+"\n"+
+		"User name.\n"+
+		".SS Networking options\n"+
+		".TP\n"+
+		`\fB\-\-host\fR \fI<HOST>\fR`+"\n"+
+		"Host name.\n"+
+		".TP\n"+
+		`\fB\-\-port\fR \fI<PORT>\fR`+"\n"+
+		"Port number.\n"+
+		".SS Other options\n"+
+		".TP\n"+
+		`\fB\-\-verbose\fR`+"\n"+
+		"Verbose mode.\n")
+}
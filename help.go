@@ -0,0 +1,339 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/sttk/cliargs"
+	"github.com/sttk/sabi/errs"
+)
+
+type /* error reasons */ (
+	// FailToPrintHelp is the error reason which indicates that the help text
+	// cannot be written to the io.Writer.
+	FailToPrintHelp struct{}
+)
+
+const (
+	defaultHelpWidth = 80
+	helpIndent       = 2
+	helpGap          = 3
+	maxHelpLabel     = 30
+)
+
+// HelpHeading is the method to set the text which is printed at the head of
+// the help text, before the usage line.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) HelpHeading(heading string) *DaxSrc {
+	ds.helpHeading = heading
+	return ds
+}
+
+// HelpTrailer is the method to set the text which is printed at the tail of
+// the help text, after the option listing.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) HelpTrailer(trailer string) *DaxSrc {
+	ds.helpTrailer = trailer
+	return ds
+}
+
+// HelpWidth is the method to set the width to which descriptions in the help
+// text are wrapped.
+// If this width is not set or is not positive, the width of the terminal is
+// used when the help text is written to a terminal, and otherwise the value of
+// the environment variable COLUMNS is used, or 80 if it is not available.
+// The terminal is detected with the function set by DaxSrc#TerminalSize
+// method.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) HelpWidth(width int) *DaxSrc {
+	ds.helpWidth = width
+	return ds
+}
+
+// PrintHelp is the method to write the help text, which is retrieved by
+// HelpText method, to the io.Writer.
+// If failing to write, this method returns an errs.Err with the reason:
+// FailToPrintHelp.
+func (conn *DaxConn) PrintHelp(w io.Writer) errs.Err {
+	return conn.HelpTo(w)
+}
+
+// HelpTo is the method to write the help text, which is retrieved by HelpText
+// method, to the io.Writer through a buffer without making the whole help
+// text as a string, for example to write it to a socket.
+// If the io.Writer is a terminal, the help text is wrapped to its width and
+// is colorized if enabled with DaxSrc#HelpColor method.
+// If failing to write, this method returns an errs.Err with the reason:
+// FailToPrintHelp.
+func (conn *DaxConn) HelpTo(w io.Writer) errs.Err {
+	conn.ds.parseLazily()
+	conn.ds.mutex.RLock()
+	defer conn.ds.mutex.RUnlock()
+
+	bw := bufio.NewWriter(w)
+	conn.ds.writeHelp(bw, conn.ds.helpStyleFor(w))
+	if e := bw.Flush(); e != nil {
+		return errs.New(FailToPrintHelp{}, e)
+	}
+	return errs.Ok()
+}
+
+// HelpText is the method to retrieve the help text, which consists of the
+// heading, the usage line, the listing of options, and the trailer.
+// The usage line is made from the command name and the positional argument
+// configurations.
+// The options are listed in the order of the OptCfg array with their names,
+// aliases, placeholders of option arguments, and descriptions, and the
+// options which belong to groups are listed in sections of their groups in
+// the order declared with DaxSrc#GroupOrder method.
+// The placeholder of an option argument is its ArgHelp, which is set with the
+// optmetavar struct tag or OptMeta.Metavar, or its upper-cased name, like
+// <LOG_LEVEL>, and the placeholder of an array option is followed by "...".
+// Descriptions are wrapped to the width specified with DaxSrc#HelpWidth
+// method with hanging indentation, in which East Asian wide characters are
+// counted as two cells and lines can be broken between them.
+func (conn *DaxConn) HelpText() string {
+	conn.ds.parseLazily()
+	conn.ds.mutex.RLock()
+	defer conn.ds.mutex.RUnlock()
+	return conn.ds.helpText()
+}
+
+func (ds *DaxSrc) helpText() string {
+	var b strings.Builder
+	ds.writeHelp(&b, ds.helpStyleFor(nil))
+	return b.String()
+}
+
+// helpLayout is the set of the column widths and the indentations of the
+// option listing in the help text, which are computed once for all entries.
+type helpLayout struct {
+	labelWidth int
+	descWidth  int
+	indent     string
+	hanging    string
+}
+
+func newHelpLayout(labelWidth, width int) helpLayout {
+	hanging := strings.Repeat(" ", helpIndent+labelWidth+helpGap)
+	return helpLayout{
+		labelWidth: labelWidth,
+		descWidth:  width - len(hanging),
+		indent:     hanging[:helpIndent],
+		hanging:    hanging,
+	}
+}
+
+// writeHelp writes the help text to the writer.
+func (ds *DaxSrc) writeHelp(b io.StringWriter, s helpStyle) {
+	if len(ds.helpHeading) > 0 {
+		b.WriteString(strings.TrimRight(ds.helpHeading, "\n"))
+		b.WriteString("\n\n")
+	}
+
+	groups, sections := groupOptCfgs(ds.optCfgs, ds.metas, ds.groupOrder)
+
+	visible := ds.visibleOptCfgs()
+	labels := make(map[string]string, len(visible))
+	labelWidth := 0
+	for _, cfg := range visible {
+		label := helpLabel(cfg, helpStyle{})
+		labels[cfg.Name] = label
+		if n := textWidth(label); n > labelWidth && n <= maxHelpLabel {
+			labelWidth = n
+		}
+	}
+	layout := newHelpLayout(labelWidth, s.width)
+
+	b.WriteString(helpUsage(ds.cmd.Name, visible, ds.argCfgs))
+	b.WriteString("\n")
+
+	for _, g := range groups {
+		b.WriteString("\n")
+		if len(g) == 0 {
+			b.WriteString(s.heading(msg(MsgOptionsHeading)) + "\n")
+		} else {
+			b.WriteString(s.heading(msg(MsgGroupHeading, "group", g)) + "\n")
+		}
+		for _, cfg := range sections[g] {
+			desc := cfg.Desc
+			if since := ds.metas[cfg.Name].since; len(since) > 0 {
+				desc = strings.TrimSpace(msg(MsgHelpSince,
+					"desc", desc, "since", versionText(since)))
+			}
+			label, display := labels[cfg.Name], labels[cfg.Name]
+			if s.color {
+				display = helpLabel(cfg, s)
+			}
+			writeHelpEntry(b, label, display, desc, layout)
+		}
+	}
+
+	if len(ds.helpTrailer) > 0 {
+		b.WriteString("\n")
+		b.WriteString(strings.TrimRight(ds.helpTrailer, "\n"))
+		b.WriteString("\n")
+	}
+
+}
+
+// terminalWidth returns the value of the environment variable COLUMNS, or the
+// default width if it is not available.
+func terminalWidth() int {
+	if n, e := strconv.Atoi(os.Getenv("COLUMNS")); e == nil && n > 0 {
+		return n
+	}
+	return defaultHelpWidth
+}
+
+// helpUsage makes the usage line from the command name, the option
+// configurations, and the positional argument configurations.
+func helpUsage(
+	cmdName string, optCfgs []cliargs.OptCfg, argCfgs []ArgCfg,
+) string {
+	words := []string{cmdName}
+	if len(optCfgs) > 0 {
+		words = append(words, msg(MsgUsageOptions))
+	}
+	words = append(words, usageArgs(argCfgs)...)
+	return msg(MsgUsage, "command", strings.Join(words, " "))
+}
+
+// helpLabel makes the label of the option, in which short aliases are put
+// before long names, like -f, --foo-bar <FOO_BAR>, and the option names are
+// colorized if coloring of the help style is enabled.
+func helpLabel(cfg cliargs.OptCfg, s helpStyle) string {
+	var shorts, longs []string
+	for _, name := range append([]string{cfg.Name}, cfg.Aliases...) {
+		if len([]rune(name)) == 1 {
+			shorts = append(shorts, s.flag("-"+name))
+		} else {
+			longs = append(longs, s.flag("--"+name))
+		}
+	}
+
+	label := strings.Join(append(shorts, longs...), ", ")
+	if len(shorts) == 0 {
+		label = "    " + label
+	}
+
+	if cfg.HasArg {
+		label += " " + argLabel(cfg)
+	}
+
+	return label
+}
+
+// writeHelpEntry writes the label and the description of an option, wrapping
+// the description with hanging indentation.
+// The label is written as display, which may be colorized, and its width is
+// measured with label.
+// If the label is wider than the label column, the description starts from
+// the next line.
+func writeHelpEntry(
+	b io.StringWriter, label, display, desc string, l helpLayout,
+) {
+	b.WriteString(l.indent)
+	b.WriteString(display)
+
+	lines := wrapText(desc, l.descWidth)
+	if len(lines) == 0 {
+		b.WriteString("\n")
+		return
+	}
+
+	n := textWidth(label)
+	if n > l.labelWidth {
+		b.WriteString("\n")
+		b.WriteString(l.hanging)
+	} else {
+		b.WriteString(l.hanging[:l.labelWidth-n+helpGap])
+	}
+
+	for i, line := range lines {
+		if i > 0 {
+			b.WriteString(l.hanging)
+		}
+		b.WriteString(line)
+		b.WriteString("\n")
+	}
+}
+
+// wrapText divides the text into lines of which widths are not greater than
+// the specified width, at white spaces and between East Asian wide
+// characters, which are counted as two cells.
+// Line breaks in the text are preserved, and a word longer than the width is
+// put in a line by itself.
+func wrapText(text string, width int) []string {
+	if width < 1 {
+		width = 1
+	}
+
+	var lines []string
+	var line strings.Builder
+	for _, para := range strings.Split(text, "\n") {
+		words := strings.Fields(para)
+		if len(words) == 0 {
+			if len(lines) > 0 {
+				lines = append(lines, "")
+			}
+			continue
+		}
+
+		line.Reset()
+		lineWidth := 0
+		for _, w := range words {
+			for start, end := 0, 0; start < len(w); start = end {
+				end = segmentEnd(w, start)
+				seg := w[start:end]
+				gap := 0
+				if start == 0 && lineWidth > 0 {
+					gap = 1
+				}
+				n := textWidth(seg)
+				if lineWidth > 0 && lineWidth+gap+n > width {
+					lines = append(lines, line.String())
+					line.Reset()
+					lineWidth, gap = 0, 0
+				}
+				if gap > 0 {
+					line.WriteByte(' ')
+				}
+				line.WriteString(seg)
+				lineWidth += gap + n
+			}
+		}
+		lines = append(lines, line.String())
+	}
+
+	for len(lines) > 0 && len(lines[len(lines)-1]) == 0 {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// noLineHead is the set of the punctuations which should not be put at the
+// head of a line, so they are not separated from the preceding character.
+const noLineHead = "、。，．,.)）」』】〉》！？!?ー"
+
+// segmentEnd returns the end index of the segment of the word which starts at
+// the index, where a segment is an East Asian wide character or a run of the
+// other characters, between which a line can be broken.
+func segmentEnd(word string, start int) int {
+	prevWide := false
+	for i, r := range word[start:] {
+		wide := isWideRune(r)
+		if i > 0 && (wide || prevWide) && !strings.ContainsRune(noLineHead, r) {
+			return start + i
+		}
+		prevWide = wide
+	}
+	return len(word)
+}
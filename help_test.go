@@ -0,0 +1,336 @@
+package cliargdax_test
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+)
+
+func TestHelp_HelpText(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{
+			Name:    "foo-bar",
+			Aliases: []string{"f"},
+			HasArg:  true,
+			Desc:    "This is the description of the foo-bar option, which is long enough to be wrapped.",
+		},
+		cliargs.OptCfg{
+			Name: "verbose",
+			Desc: "Print verbose messages.",
+		},
+		cliargs.OptCfg{
+			Name:    "q",
+			Aliases: []string{"quiet"},
+		},
+		cliargs.OptCfg{
+			Name:    "output-format-of-results",
+			HasArg:  true,
+			ArgHelp: "<json|yaml|table>",
+			Desc:    "Output format.",
+		},
+	}
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"/path/to/app", "src"}, optCfgs,
+	).HelpWidth(60).
+		HelpHeading("app - an example application\n").
+		HelpTrailer("See the manual for details.").
+		PositionalCfgs([]cliargdax.ArgCfg{
+			{Name: "source", Required: true},
+			{Name: "extras", Variadic: true},
+		})
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.HelpText(), `app - an example application
+
+Usage: app [OPTIONS] <source> [<extras>...]
+
+Options:
+  -f, --foo-bar <FOO_BAR>   This is the description of the
+                            foo-bar option, which is long
+                            enough to be wrapped.
+      --verbose             Print verbose messages.
+  -q, --quiet
+      --output-format-of-results <json|yaml|table>
+                            Output format.
+
+See the manual for details.
+`)
+}
+
+func TestHelp_HelpText_groups(t *testing.T) {
+	type Server struct {
+		Port int `optcfg:"port" optdesc:"Port number."`
+	}
+	type Options struct {
+		Verbose bool `optcfg:"verbose,v" optdesc:"Verbose mode."`
+		Server  Server
+	}
+
+	options := Options{}
+	conn, err := setupForOptions(t, []string{"app"}, &options)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.HelpText(), `Usage: app [OPTIONS]
+
+Options:
+  -v, --verbose   Verbose mode.
+
+Server options:
+      --server-port <SERVER_PORT>
+                  Port number.
+`)
+}
+
+func TestHelp_HelpText_noOptions(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app"}, []cliargs.OptCfg{},
+	)
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.HelpText(), "Usage: app\n")
+}
+
+func TestHelp_PrintHelp(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "foo", Desc: "Foo.\nSecond line."},
+	}
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{"app"}, optCfgs)
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	var b strings.Builder
+	err = conn.PrintHelp(&b)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, b.String(), `Usage: app [OPTIONS]
+
+Options:
+      --foo   Foo.
+              Second line.
+`)
+}
+
+type failWriter struct{}
+
+func (w failWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("fail")
+}
+
+func TestHelp_PrintHelp_fail(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{"app"},
+		[]cliargs.OptCfg{})
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	err = conn.PrintHelp(failWriter{})
+	switch err.Reason().(type) {
+	case cliargdax.FailToPrintHelp:
+		assert.Equal(t, err.Cause().Error(), "fail")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestHelp_HelpTo(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "foo", Aliases: []string{"f"}, Desc: "Foo."},
+	}
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{"app"}, optCfgs)
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	var b strings.Builder
+	err = conn.HelpTo(&b)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, b.String(), conn.HelpText())
+
+	err = conn.HelpTo(failWriter{})
+	switch err.Reason().(type) {
+	case cliargdax.FailToPrintHelp:
+		assert.Equal(t, err.Cause().Error(), "fail")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestHelp_HelpText_wideChars(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{
+			Name: "verbose", Aliases: []string{"v"},
+			Desc: "詳細なメッセージを出力します。ログの量が多くなります。",
+		},
+		cliargs.OptCfg{
+			Name: "mode", HasArg: true, ArgHelp: "<モード>",
+			Desc: "動作モード (fast または safe) を指定します。",
+		},
+	}
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app"}, optCfgs,
+	).HelpWidth(40)
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.HelpText(), `Usage: app [OPTIONS]
+
+Options:
+  -v, --verbose         詳細なメッセージ
+                        を出力します。ロ
+                        グの量が多くなり
+                        ます。
+      --mode <モード>   動作モード (fast
+                        または safe) を
+                        指定します。
+`)
+}
+
+func fakeTerminal(width int) cliargdax.TerminalSizeFunc {
+	return func(w io.Writer) (int, bool) {
+		return width, true
+	}
+}
+
+func TestHelp_HelpTo_terminalWidth(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{
+			Name: "verbose", Aliases: []string{"v"},
+			Desc: "Prints detailed messages while running.",
+		},
+	}
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app"}, optCfgs,
+	).TerminalSize(fakeTerminal(40))
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	var b strings.Builder
+	err = conn.HelpTo(&b)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, b.String(), `Usage: app [OPTIONS]
+
+Options:
+  -v, --verbose   Prints detailed
+                  messages while
+                  running.
+`)
+
+	ds.HelpWidth(60)
+	b.Reset()
+	err = conn.HelpTo(&b)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, b.String(), `Usage: app [OPTIONS]
+
+Options:
+  -v, --verbose   Prints detailed messages while running.
+`)
+}
+
+func TestHelp_HelpTo_notTerminal(t *testing.T) {
+	t.Setenv("COLUMNS", "40")
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{
+			Name: "verbose", Aliases: []string{"v"},
+			Desc: "Prints detailed messages while running.",
+		},
+	}
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app"}, optCfgs,
+	).HelpColor(true).
+		TerminalSize(func(w io.Writer) (int, bool) { return 100, false })
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	var b strings.Builder
+	err = conn.HelpTo(&b)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, b.String(), `Usage: app [OPTIONS]
+
+Options:
+  -v, --verbose   Prints detailed
+                  messages while
+                  running.
+`)
+}
+
+func TestHelp_HelpColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{
+			Name: "verbose", Aliases: []string{"v"},
+			Desc: "Prints detailed messages.",
+		},
+		cliargs.OptCfg{
+			Name: "level", HasArg: true,
+			Desc: "Specifies the log level.",
+		},
+	}
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app"}, optCfgs,
+	).HelpColor(true).TerminalSize(fakeTerminal(80))
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	var b strings.Builder
+	err = conn.HelpTo(&b)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, b.String(), "Usage: app [OPTIONS]\n"+
+		"\n"+
+		"\x1b[1mOptions:\x1b[0m\n"+
+		"  \x1b[36m-v\x1b[0m, \x1b[36m--verbose\x1b[0m         "+
+		"Prints detailed messages.\n"+
+		"      \x1b[36m--level\x1b[0m <LEVEL>   "+
+		"Specifies the log level.\n")
+
+	assert.Equal(t, conn.HelpText(), `Usage: app [OPTIONS]
+
+Options:
+  -v, --verbose         Prints detailed messages.
+      --level <LEVEL>   Specifies the log level.
+`)
+}
+
+func TestHelp_HelpColor_noColor(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{
+			Name: "verbose", Aliases: []string{"v"},
+			Desc: "Prints detailed messages.",
+		},
+	}
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app"}, optCfgs,
+	).HelpColor(true).TerminalSize(fakeTerminal(80))
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	var b strings.Builder
+	err = conn.HelpTo(&b)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, b.String(), `Usage: app [OPTIONS]
+
+Options:
+  -v, --verbose   Prints detailed messages.
+`)
+}
+
+func TestHelp_HelpColor_disabledByDefault(t *testing.T) {
+	t.Setenv("NO_COLOR", "")
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{
+			Name: "verbose", Aliases: []string{"v"},
+			Desc: "Prints detailed messages.",
+		},
+	}
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app"}, optCfgs,
+	).TerminalSize(fakeTerminal(80))
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	var b strings.Builder
+	err = conn.HelpTo(&b)
+	assert.True(t, err.IsOk())
+	assert.NotContains(t, b.String(), "\x1b[")
+}
@@ -0,0 +1,103 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"github.com/sttk/cliargs"
+)
+
+// EnableHelpOpt is the method to enable the option to request the help text,
+// of which name and aliases are specified as the arguments.
+// The option is added to the OptCfg array before parsing unless an option of
+// the same name is already configured, and can be listed in the help text.
+// When the option is given in command line arguments, DaxConn#HelpRequested
+// method returns true, and errors of parsing and validation, like missing
+// required options, are suppressed so that the help can always be shown.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) EnableHelpOpt(name string, aliases ...string) *DaxSrc {
+	ds.helpOpt = &cliargs.OptCfg{
+		Name:    name,
+		Aliases: aliases,
+		Desc:    msg(MsgHelpOptDesc),
+	}
+	return ds
+}
+
+// OnHelp is the method to set the function which is invoked with the help
+// text after parsing when the option enabled by EnableHelpOpt method is given
+// in command line arguments.
+// This function can print the help text and exit, or do anything else.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) OnHelp(handler func(help string)) *DaxSrc {
+	ds.onHelp = handler
+	return ds
+}
+
+// HelpRequested is the method to check whether the option enabled by
+// DaxSrc#EnableHelpOpt method is given in command line arguments.
+func (conn *DaxConn) HelpRequested() bool {
+	conn.ds.parseLazily()
+	conn.ds.mutex.RLock()
+	defer conn.ds.mutex.RUnlock()
+	return conn.ds.helpRequested
+}
+
+// addFlagOpt adds the option configuration to the option configurations of
+// the parse result unless an option of the same name is already configured,
+// and returns whether the option is given in command line arguments.
+// If the option configurations are empty, which means any options are
+// accepted, the configuration of name "*" is also added to keep accepting any
+// options.
+func (ds *DaxSrc) addFlagOpt(r *parseResult, opt *cliargs.OptCfg) bool {
+	_, found := ds.addOpt(r, opt)
+	return found
+}
+
+// addOpt adds the option configuration in the same way as addFlagOpt, and
+// returns the last token of the option in command line arguments and whether
+// the option is given.
+func (ds *DaxSrc) addOpt(r *parseResult, opt *cliargs.OptCfg) (argToken, bool) {
+	if opt == nil {
+		return argToken{}, false
+	}
+
+	cfgs := append([]cliargs.OptCfg{}, r.optCfgs...)
+	exists := false
+	for _, cfg := range cfgs {
+		if cfg.Name == opt.Name {
+			exists = true
+			break
+		}
+	}
+	if !exists {
+		if len(cfgs) == 0 {
+			cfgs = append(cfgs, cliargs.OptCfg{Name: "*"})
+		}
+		cfgs = append(cfgs, *opt)
+	}
+	r.optCfgs = cfgs
+
+	var last argToken
+	found := false
+	sc := argScanner{
+		stopAtFirstArg:  ds.mode.stopAtFirstArg,
+		allowSingleDash: ds.mode.allowSingleDash,
+	}
+	for _, tok := range scanArgsBy(sc, r.osArgs, r.optCfgs) {
+		if tok.name == opt.Name {
+			last = tok
+			found = true
+		}
+	}
+	return last, found
+}
+
+// handleHelp invokes the function set by OnHelp method with the help text if
+// the help is requested.
+func (ds *DaxSrc) handleHelp() {
+	if ds.helpRequested && ds.onHelp != nil {
+		ds.onHelp(ds.helpText())
+	}
+}
@@ -0,0 +1,115 @@
+package cliargdax_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+)
+
+func TestHelpOpt_EnableHelpOpt(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "foo", Desc: "Foo."},
+	}
+	var help string
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{"app", "-h"}, optCfgs).
+		EnableHelpOpt("help", "h").
+		OnHelp(func(s string) { help = s })
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.True(t, conn.HelpRequested())
+	assert.Equal(t, len(conn.OptCfgs()), 2)
+	assert.Equal(t, conn.OptCfgs()[1].Name, "help")
+	assert.Equal(t, conn.OptCfgs()[1].Aliases, []string{"h"})
+	assert.Equal(t, help, `Usage: app [OPTIONS]
+
+Options:
+      --foo    Foo.
+  -h, --help   Print help.
+`)
+	assert.Equal(t, help, conn.HelpText())
+}
+
+func TestHelpOpt_EnableHelpOpt_notGiven(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "foo"},
+	}
+	called := false
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{"app", "--foo"},
+		optCfgs).
+		EnableHelpOpt("help").
+		OnHelp(func(string) { called = true })
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.False(t, conn.HelpRequested())
+	assert.False(t, called)
+	assert.True(t, conn.Cmd().HasOpt("foo"))
+}
+
+func TestHelpOpt_EnableHelpOpt_suppressErrors(t *testing.T) {
+	type Options struct {
+		Name  string `optcfg:"name" optrequired:"true"`
+		Count int    `optcfg:"count"`
+	}
+
+	options := Options{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions(
+		[]string{"app", "--count=x", "--help", "--unknown"}, &options,
+	).EnableHelpOpt("help", "h")
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.True(t, conn.HelpRequested())
+
+	options = Options{}
+	ds = cliargdax.NewDaxSrcWithArgsForOptions(
+		[]string{"app", "--count=1"}, &options,
+	).EnableHelpOpt("help", "h")
+
+	_, err = setupWithOptCfgs(t, ds)
+	switch err.Reason().(type) {
+	case cliargdax.RequiredOptionMissing:
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestHelpOpt_EnableHelpOpt_afterTerminator(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "--", "--help"}, []cliargs.OptCfg{},
+	).EnableHelpOpt("help")
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.False(t, conn.HelpRequested())
+	assert.Equal(t, conn.Cmd().Args(), []string{"--help"})
+}
+
+func TestHelpOpt_EnableHelpOpt_anyOptions(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgs([]string{"app", "--foo", "--help"}).
+		EnableHelpOpt("help")
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.True(t, conn.HelpRequested())
+	assert.True(t, conn.Cmd().HasOpt("foo"))
+}
+
+func TestHelpOpt_EnableHelpOpt_subCmd(t *testing.T) {
+	defer resetOsArgs()
+
+	os.Args = []string{"/path/to/app", "add", "--help"}
+
+	ds := cliargdax.NewDaxSrcWithSubCmds(map[string][]cliargs.OptCfg{
+		"add": []cliargs.OptCfg{cliargs.OptCfg{Name: "all"}},
+	}).EnableHelpOpt("help")
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.True(t, conn.HelpRequested())
+	assert.Equal(t, conn.SubCmdName(), "add")
+}
@@ -0,0 +1,79 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"github.com/sttk/sabi/errs"
+)
+
+// AfterSetup is the method to register the function which is invoked with a
+// DaxConn instance after command line arguments are successfully parsed, for
+// example to open the log file specified by an option.
+// The registered functions are invoked in the order of registration by Setup
+// method, or in lazy mode at the first parsing, and the first error returned
+// by them is returned by Setup method or by DaxConn#ParseErr method.
+// The DaxConn instance passed to the functions is not in any transaction, so
+// DaxConn#SetOptions method sets the option store to this DaxSrc immediately.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) AfterSetup(hook func(conn *DaxConn) errs.Err) *DaxSrc {
+	ds.afterSetupHooks = append(ds.afterSetupHooks, hook)
+	return ds
+}
+
+// BeforeClose is the method to register the function which is invoked with a
+// DaxConn instance when this DaxSrc is closed, for example to close the log
+// file opened by the function registered with AfterSetup method.
+// The registered functions are invoked in the reverse order of registration,
+// and only if Setup method has succeeded.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) BeforeClose(hook func(conn *DaxConn)) *DaxSrc {
+	ds.beforeCloseHooks = append(ds.beforeCloseHooks, hook)
+	return ds
+}
+
+// hasAfterSetup reports whether anything is invoked by runAfterSetup method.
+func (ds *DaxSrc) hasAfterSetup() bool {
+	return len(ds.afterSetupHooks) > 0 || ds.dispatchOnSetup
+}
+
+// runAfterSetup invokes the functions registered with AfterSetup method, and
+// returns the first error.
+// If DispatchOnSetup method is enabled, DaxConn#Dispatch method is invoked
+// after them.
+// If the context passed to SetupContext method is done, the functions not yet
+// invoked are skipped and the error with the reason: SetupCanceled is returned.
+// This method must be called while the lock of this DaxSrc is not held.
+func (ds *DaxSrc) runAfterSetup() errs.Err {
+	conn := &DaxConn{ds: ds, writeThrough: true}
+	for _, hook := range ds.afterSetupHooks {
+		if err := checkContext(ds.ctx); err.IsNotOk() {
+			return err
+		}
+		if err := hook(conn); err.IsNotOk() {
+			return err
+		}
+	}
+	if ds.dispatchOnSetup {
+		if err := checkContext(ds.ctx); err.IsNotOk() {
+			return err
+		}
+		return conn.Dispatch()
+	}
+	return errs.Ok()
+}
+
+// runBeforeClose invokes the functions registered with BeforeClose method in
+// the reverse order, only once after Setup method has succeeded.
+func (ds *DaxSrc) runBeforeClose() {
+	if !ds.isSetUp {
+		return
+	}
+	ds.isSetUp = false
+
+	conn := &DaxConn{ds: ds, writeThrough: true}
+	for i := len(ds.beforeCloseHooks) - 1; i >= 0; i-- {
+		ds.beforeCloseHooks[i](conn)
+	}
+}
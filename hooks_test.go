@@ -0,0 +1,119 @@
+package cliargdax_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+	"github.com/sttk/sabi/errs"
+)
+
+func TestHooks_AfterSetup(t *testing.T) {
+	var logs []string
+
+	ds := cliargdax.NewDaxSrcWithArgs([]string{"app", "--log-file=app.log"}).
+		AfterSetup(func(conn *cliargdax.DaxConn) errs.Err {
+			logs = append(logs, "open "+conn.Cmd().OptArg("log-file"))
+			return errs.Ok()
+		}).
+		AfterSetup(func(conn *cliargdax.DaxConn) errs.Err {
+			logs = append(logs, "seed")
+			return errs.Ok()
+		}).
+		BeforeClose(func(conn *cliargdax.DaxConn) {
+			logs = append(logs, "close "+conn.Cmd().OptArg("log-file"))
+		}).
+		BeforeClose(func(conn *cliargdax.DaxConn) {
+			logs = append(logs, "unseed")
+		})
+
+	err := ds.Setup(&noopAsyncGroup{})
+	assert.True(t, err.IsOk())
+	assert.Equal(t, logs, []string{"open app.log", "seed"})
+
+	ds.Close()
+	assert.Equal(t, logs, []string{
+		"open app.log", "seed", "unseed", "close app.log",
+	})
+
+	ds.Close()
+	assert.Equal(t, len(logs), 4)
+}
+
+func TestHooks_AfterSetup_error(t *testing.T) {
+	type FailToOpen struct{}
+
+	var logs []string
+
+	ds := cliargdax.NewDaxSrcWithArgs([]string{"app"}).
+		AfterSetup(func(conn *cliargdax.DaxConn) errs.Err {
+			logs = append(logs, "first")
+			return errs.New(FailToOpen{})
+		}).
+		AfterSetup(func(conn *cliargdax.DaxConn) errs.Err {
+			logs = append(logs, "second")
+			return errs.Ok()
+		}).
+		BeforeClose(func(conn *cliargdax.DaxConn) {
+			logs = append(logs, "close")
+		})
+
+	err := ds.Setup(&noopAsyncGroup{})
+	switch err.Reason().(type) {
+	case FailToOpen:
+	default:
+		assert.Fail(t, err.Error())
+	}
+
+	ds.Close()
+	assert.Equal(t, logs, []string{"first"})
+}
+
+func TestHooks_AfterSetup_parseError(t *testing.T) {
+	called := false
+
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{"app", "--foo"},
+		[]cliargs.OptCfg{cliargs.OptCfg{Name: "bar"}},
+	).
+		AfterSetup(func(conn *cliargdax.DaxConn) errs.Err {
+			called = true
+			return errs.Ok()
+		})
+	err := ds.Setup(&noopAsyncGroup{})
+	assert.True(t, err.IsNotOk())
+	ds.Close()
+	assert.False(t, called)
+}
+
+func TestHooks_AfterSetup_lazy(t *testing.T) {
+	type FailToSeed struct{}
+
+	count := 0
+
+	ds := cliargdax.NewDaxSrcWithArgs([]string{"app", "--seed=x"}).Lazy().
+		AfterSetup(func(conn *cliargdax.DaxConn) errs.Err {
+			count++
+			if conn.Cmd().OptArg("seed") == "x" {
+				return errs.New(FailToSeed{})
+			}
+			return errs.Ok()
+		})
+
+	err := ds.Setup(&noopAsyncGroup{})
+	assert.True(t, err.IsOk())
+	defer ds.Close()
+	assert.Equal(t, count, 0)
+
+	dc, err := ds.CreateDaxConn()
+	assert.True(t, err.IsOk())
+	conn := dc.(*cliargdax.DaxConn)
+
+	switch conn.ParseErr().Reason().(type) {
+	case FailToSeed:
+	default:
+		assert.Fail(t, conn.ParseErr().Error())
+	}
+	assert.Equal(t, conn.Cmd().OptArg("seed"), "x")
+	assert.Equal(t, count, 1)
+}
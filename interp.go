@@ -0,0 +1,238 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"strings"
+
+	"github.com/sttk/cliargs"
+	"github.com/sttk/sabi/errs"
+)
+
+type /* error reasons */ (
+	// OptionInterpolationCycle is the error reason which indicates that option
+	// arguments refer to each other with placeholders, like --a {b} --b {a}.
+	// The field Chain is the option names in the cycle, of which the first and
+	// the last are same, like [a, b, a].
+	OptionInterpolationCycle struct {
+		Chain []string
+	}
+
+	// UnknownOptionReference is the error reason which indicates that a
+	// placeholder in an option argument refers to an option which is not
+	// configured.
+	// The fields Option and Placeholder are the option name of the option
+	// argument and the placeholder, like {nmae}.
+	UnknownOptionReference struct {
+		Option      string
+		Placeholder string
+	}
+)
+
+// InterpolateOptArgs is the method to enable or disable the substitution of
+// placeholders in option arguments, like --output {name}.log, of which {name}
+// is replaced with the option argument of --name.
+// The substitution is done after parsing, so the referred option argument can
+// be taken from an environment variable, the config file, or the default
+// value, and it is also substituted if it has placeholders.
+// A placeholder can be an option name or alias, and the option arguments of
+// an array option are joined with commas, and the value of an option which
+// takes no option argument is true or false.
+// {{ is a literal {, and { which is not followed by an option name and } is
+// also literal.
+// The options of which fields have the struct tag: optliteral:"true", or of
+// which OptMeta#Literal is true, are not substituted.
+// If a placeholder refers to an option which is not configured, Setup method
+// fails with the reason: UnknownOptionReference, and if placeholders refer
+// to each other, it fails with the reason: OptionInterpolationCycle.
+// This mode is disabled by default.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) InterpolateOptArgs(enable bool) *DaxSrc {
+	ds.mode.interpolate = enable
+	return ds
+}
+
+// interpolator substitutes placeholders in option arguments.
+type interpolator struct {
+	cmd      cliargs.Cmd
+	optCfgs  []cliargs.OptCfg
+	metas    map[string]optMeta
+	indexes  map[string]int
+	resolved map[string][]string
+	stack    []string
+}
+
+// interpolateOptArgs substitutes placeholders in the option arguments of the
+// parsed command, and invokes the event handlers of the substituted options
+// again to store the substituted option arguments.
+func (r *parseResult) interpolateOptArgs(cfgs []cliargs.OptCfg) errs.Err {
+	ip := interpolator{
+		cmd:      r.cmd,
+		optCfgs:  cfgs,
+		metas:    r.metas,
+		indexes:  cfgIndexes(cfgs),
+		resolved: make(map[string][]string),
+	}
+
+	changed := make(map[string][]string)
+	for _, cfg := range cfgs {
+		if !cfg.HasArg || !r.cmd.HasOpt(cfg.Name) {
+			continue
+		}
+		a, err := ip.resolve(cfg.Name)
+		if err.IsNotOk() {
+			return err
+		}
+		if !equalStrings(a, r.cmd.OptArgs(cfg.Name)) {
+			changed[cfg.Name] = a
+		}
+	}
+	if len(changed) == 0 {
+		return errs.Ok()
+	}
+
+	opts := make(map[string][]string)
+	for _, name := range cmdOptNames(r.cmd, cfgs, nil) {
+		opts[name] = r.cmd.OptArgs(name)
+	}
+	for _, cfg := range cfgs {
+		a, exists := changed[cfg.Name]
+		if !exists {
+			continue
+		}
+		opts[cfg.Name] = a
+		if cfg.OnParsed != nil {
+			if e := (*cfg.OnParsed)(a); e != nil {
+				return r.wrapParseErr(e, nil, nil)
+			}
+		}
+	}
+	cmd, e := makeCmd(r.cmd.Name, r.cmd.Args(), opts)
+	if e != nil {
+		return r.wrapParseErr(e, nil, nil)
+	}
+	r.cmd = cmd
+	return errs.Ok()
+}
+
+// resolve returns the option arguments of the option in which placeholders
+// are substituted.
+func (ip *interpolator) resolve(name string) ([]string, errs.Err) {
+	if a, exists := ip.resolved[name]; exists {
+		return a, errs.Ok()
+	}
+	for i, n := range ip.stack {
+		if n == name {
+			chain := append(append([]string{}, ip.stack[i:]...), name)
+			return nil, errs.New(OptionInterpolationCycle{Chain: chain})
+		}
+	}
+
+	a := ip.cmd.OptArgs(name)
+	if ip.metas[name].literal {
+		ip.resolved[name] = a
+		return a, errs.Ok()
+	}
+
+	ip.stack = append(ip.stack, name)
+	out := make([]string, len(a))
+	for i, s := range a {
+		v, err := ip.substitute(name, s)
+		if err.IsNotOk() {
+			return nil, err
+		}
+		out[i] = v
+	}
+	ip.stack = ip.stack[:len(ip.stack)-1]
+
+	ip.resolved[name] = out
+	return out, errs.Ok()
+}
+
+// substitute replaces the placeholders in the option argument of the option.
+func (ip *interpolator) substitute(name, s string) (string, errs.Err) {
+	if !strings.Contains(s, "{") {
+		return s, errs.Ok()
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != '{' {
+			b.WriteByte(c)
+			continue
+		}
+		if i+1 < len(s) && s[i+1] == '{' {
+			b.WriteByte('{')
+			i++
+			continue
+		}
+		j := strings.IndexByte(s[i+1:], '}')
+		if j < 0 || !isOptNameLike(s[i+1:i+1+j]) {
+			b.WriteByte(c)
+			continue
+		}
+		ref := s[i+1 : i+1+j]
+		v, err := ip.valueOf(name, ref)
+		if err.IsNotOk() {
+			return "", err
+		}
+		b.WriteString(v)
+		i += j + 1
+	}
+	return b.String(), errs.Ok()
+}
+
+// valueOf returns the value of the option referred by the placeholder in the
+// option argument of the option.
+func (ip *interpolator) valueOf(name, ref string) (string, errs.Err) {
+	k, exists := ip.indexes[ref]
+	if !exists {
+		return "", errs.New(UnknownOptionReference{
+			Option: name, Placeholder: "{" + ref + "}",
+		})
+	}
+	cfg := ip.optCfgs[k]
+	if !cfg.HasArg {
+		if ip.cmd.HasOpt(cfg.Name) {
+			return "true", errs.Ok()
+		}
+		return "false", errs.Ok()
+	}
+	a, err := ip.resolve(cfg.Name)
+	if err.IsNotOk() {
+		return "", err
+	}
+	return strings.Join(a, ","), errs.Ok()
+}
+
+// isOptNameLike reports whether the string can be an option name, which
+// consists of alphanumerics and hyphens and does not start with a hyphen.
+func isOptNameLike(s string) bool {
+	if len(s) == 0 || s[0] == '-' {
+		return false
+	}
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,97 @@
+package cliargdax_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+)
+
+type interpOptions struct {
+	Name    string   `optcfg:"name,n" optdefault:"app"`
+	Dir     string   `optcfg:"dir" optdefault:"/tmp/{name}"`
+	Output  string   `optcfg:"output"`
+	Tags    []string `optcfg:"tag"`
+	Format  string   `optcfg:"format" optliteral:"true"`
+	Verbose bool     `optcfg:"verbose"`
+}
+
+func TestInterpolate_OptArgs(t *testing.T) {
+	options := interpOptions{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions([]string{
+		"app", "--output", "{dir}/{n}-{tag}.{verbose}", "--name", "x",
+		"--tag", "a", "--tag", "b", "--format", "{name}",
+	}, &options).InterpolateOptArgs(true)
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, options.Dir, "/tmp/x")
+	assert.Equal(t, options.Output, "/tmp/x/x-a,b.false")
+	assert.Equal(t, options.Format, "{name}")
+	assert.Equal(t, conn.Cmd().OptArg("output"), "/tmp/x/x-a,b.false")
+	assert.Equal(t, conn.Cmd().OptArg("dir"), "/tmp/x")
+
+	options = interpOptions{}
+	ds = cliargdax.NewDaxSrcWithArgsForOptions([]string{
+		"app", "--output", "{dir}",
+	}, &options)
+	_, err = setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, options.Output, "{dir}")
+	assert.Equal(t, options.Dir, "/tmp/{name}")
+}
+
+func TestInterpolate_literalBraces(t *testing.T) {
+	options := interpOptions{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions([]string{
+		"app", "--output", "{{name}}-{name-{ {}-{ a}-{name",
+	}, &options).InterpolateOptArgs(true)
+	_, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, options.Output, "{name}}-{name-{ {}-{ a}-{name")
+}
+
+func TestInterpolate_errors(t *testing.T) {
+	options := interpOptions{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions([]string{
+		"app", "--output", "{nmae}.log",
+	}, &options).InterpolateOptArgs(true)
+	_, err := setupWithOptCfgs(t, ds)
+	assert.Equal(t, err.Reason(), cliargdax.UnknownOptionReference{
+		Option: "output", Placeholder: "{nmae}",
+	})
+	assert.Equal(t, cliargdax.FormatError(err, nil),
+		"option --output refers to an unknown option: {nmae}\n"+
+			"Try '--help' for more information.")
+
+	ds = cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "--a", "{b}", "--b", "x{c}", "--c", "{a}",
+	}, []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "a", HasArg: true},
+		cliargs.OptCfg{Name: "b", HasArg: true},
+		cliargs.OptCfg{Name: "c", HasArg: true},
+	}).InterpolateOptArgs(true)
+	_, err = setupWithOptCfgs(t, ds)
+	assert.Equal(t, err.Reason(), cliargdax.OptionInterpolationCycle{
+		Chain: []string{"a", "b", "c", "a"},
+	})
+	assert.Equal(t, cliargdax.FormatError(err, nil),
+		"options refer to each other in their arguments: -a -> -b -> -c -> -a\n"+
+			"Try '--help' for more information.")
+}
+
+func TestInterpolate_metaLiteral(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "--a", "{b}", "--b", "x",
+	}, []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "a", HasArg: true},
+		cliargs.OptCfg{Name: "b", HasArg: true},
+	}).
+		SetMeta("a", cliargdax.OptMeta{Literal: true}).
+		InterpolateOptArgs(true)
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.Cmd().OptArg("a"), "{b}")
+	meta, _ := conn.Meta("a")
+	assert.True(t, meta.Literal)
+}
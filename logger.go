@@ -0,0 +1,125 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sttk/sabi/errs"
+)
+
+// Logger is the interface to receive the events of parsing command line
+// arguments, which is set with DaxSrc#WithLogger method.
+// Debugf receives the progress of the parsing, like the start and end of
+// Setup, the expansions of arguments, and the timings of the phases, and
+// Warnf receives the failures of the parsing and the warnings, like the
+// deprecations and the ignored unknown options.
+// The messages start with "cliargdax: ", and the values of secret options are
+// not written in them.
+type Logger interface {
+	Debugf(format string, args ...any)
+	Warnf(format string, args ...any)
+}
+
+// WithLogger is the method to set the Logger which receives the events of
+// parsing command line arguments in this DaxSrc.
+// If no Logger is set, no event is made.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) WithLogger(logger Logger) *DaxSrc {
+	ds.logger = logger
+	return ds
+}
+
+// now returns the current time if the Logger is set, otherwise the zero time
+// to avoid the overhead.
+func (ds *DaxSrc) now() time.Time {
+	if ds.logger == nil {
+		return time.Time{}
+	}
+	return time.Now()
+}
+
+// logPhase writes the time taken by the phase of the parsing from the start.
+func (ds *DaxSrc) logPhase(phase string, start time.Time) {
+	if ds.logger == nil {
+		return
+	}
+	ds.logger.Debugf("cliargdax: %s took %s", phase, time.Since(start))
+}
+
+// logSetupStart writes the start of Setup.
+func (ds *DaxSrc) logSetupStart() {
+	if ds.logger == nil {
+		return
+	}
+	ds.logger.Debugf("cliargdax: setup started with %d args", len(ds.osArgs)-1)
+	if ds.isLazy && ds.cfgErr.IsOk() {
+		ds.logger.Debugf("cliargdax: parsing is deferred until first access")
+	}
+}
+
+// logLazyParse writes that the lazy parsing is triggered.
+func (ds *DaxSrc) logLazyParse() {
+	if ds.logger == nil {
+		return
+	}
+	ds.logger.Debugf("cliargdax: lazy parsing triggered")
+}
+
+// logExpansion writes the number of arguments before and after an expansion
+// of command line arguments.
+func (ds *DaxSrc) logExpansion(what string, before, after []string) {
+	if ds.logger == nil {
+		return
+	}
+	ds.logger.Debugf("cliargdax: expanded %s: %d args to %d args",
+		what, len(before)-1, len(after)-1)
+}
+
+// logOverrideErr writes the error of an option override which is ignored.
+// This method must be called while the read lock of the DaxSrc is held.
+func (ds *DaxSrc) logOverrideErr(e error) {
+	if ds.logger == nil {
+		return
+	}
+	m, ok := errorMessage(e, ds.visibleOptCfgs(), ds.secretOpts())
+	if !ok {
+		m = fmt.Sprintf("%T", e)
+	}
+	ds.logger.Warnf("cliargdax: option override ignored: %s", m)
+}
+
+// logSetupEnd writes the result of the parsing and of the functions
+// registered with AfterSetup method with the time taken from the start, and
+// the warnings including the deprecations.
+// The message of the error is made with the option arguments of secret options
+// replaced with "****", and an error of which reason is unknown is written
+// with its reason type only.
+func (ds *DaxSrc) logSetupEnd(err errs.Err, start time.Time) {
+	if ds.logger == nil {
+		return
+	}
+	if err.IsNotOk() {
+		m, ok := errorMessage(err.Reason(), ds.visibleOptCfgs(), ds.secretOpts())
+		if !ok {
+			m = fmt.Sprintf("%T", err.Reason())
+		}
+		ds.logger.Warnf("cliargdax: setup failed in %s: %s",
+			time.Since(start), m)
+		return
+	}
+	for _, w := range ds.warnings {
+		m, ok := errorMessage(w.Reason(), ds.visibleOptCfgs(), ds.secretOpts())
+		if !ok {
+			m = fmt.Sprintf("%T", w.Reason())
+		}
+		ds.logger.Warnf("cliargdax: %s", m)
+	}
+	ds.logger.Debugf(
+		"cliargdax: parsed %d args, %d options, %d positionals, %d warnings in %s",
+		ds.stats.ArgCount, ds.stats.OptionCount, ds.stats.PositionalCount,
+		len(ds.warnings), time.Since(start))
+}
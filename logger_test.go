@@ -0,0 +1,150 @@
+package cliargdax_test
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+)
+
+type recordingLogger struct {
+	mutex  sync.Mutex
+	debugs []string
+	warns  []string
+}
+
+func (l *recordingLogger) Debugf(format string, args ...any) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.debugs = append(l.debugs, fmt.Sprintf(format, args...))
+}
+
+func (l *recordingLogger) Warnf(format string, args ...any) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.warns = append(l.warns, fmt.Sprintf(format, args...))
+}
+
+func (l *recordingLogger) hasDebug(prefix string) bool {
+	for _, s := range l.debugs {
+		if strings.HasPrefix(s, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func TestLogger_ok(t *testing.T) {
+	logger := &recordingLogger{}
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "foo"},
+		cliargs.OptCfg{Name: "bar", HasArg: true},
+	}
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "--foo", "--bar=1", "a", "b"}, optCfgs,
+	).WithLogger(logger)
+	_, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	assert.Equal(t, logger.debugs[0], "cliargdax: setup started with 4 args")
+	assert.True(t, logger.hasDebug("cliargdax: parsing took "))
+	assert.True(t, strings.HasPrefix(logger.debugs[len(logger.debugs)-1],
+		"cliargdax: parsed 4 args, 2 options, 2 positionals, 0 warnings in "))
+	assert.Equal(t, len(logger.warns), 0)
+}
+
+func TestLogger_failureIsRedacted(t *testing.T) {
+	logger := &recordingLogger{}
+	options := struct {
+		Port int `optcfg:"port" optsecret:"true"`
+	}{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions(
+		[]string{"app", "--port=s3cret"}, &options,
+	).WithLogger(logger)
+	_, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsNotOk())
+
+	assert.Equal(t, len(logger.warns), 1)
+	assert.True(t, strings.HasPrefix(logger.warns[0], "cliargdax: setup failed in "))
+	assert.True(t, strings.Contains(logger.warns[0], "****"))
+	assert.False(t, strings.Contains(logger.warns[0], "s3cret"))
+	for _, s := range logger.debugs {
+		assert.False(t, strings.Contains(s, "s3cret"))
+	}
+}
+
+func TestLogger_deprecation(t *testing.T) {
+	logger := &recordingLogger{}
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "old"},
+	}
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "--old"}, optCfgs,
+	).Deprecated("old", "use --new").WithLogger(logger)
+	_, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	assert.Equal(t, len(logger.warns), 1)
+	assert.True(t, strings.HasPrefix(logger.warns[0], "cliargdax: "))
+	assert.True(t, strings.Contains(logger.warns[0], "--old"))
+	assert.True(t, strings.HasPrefix(logger.debugs[len(logger.debugs)-1],
+		"cliargdax: parsed 1 args, 1 options, 0 positionals, 1 warnings in "))
+}
+
+func TestLogger_lazy(t *testing.T) {
+	logger := &recordingLogger{}
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "foo"},
+	}
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "--foo"}, optCfgs,
+	).Lazy().WithLogger(logger)
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, logger.debugs, []string{
+		"cliargdax: setup started with 1 args",
+		"cliargdax: parsing is deferred until first access",
+	})
+
+	assert.True(t, conn.Cmd().HasOpt("foo"))
+	assert.Equal(t, logger.debugs[2], "cliargdax: lazy parsing triggered")
+	assert.True(t, strings.HasPrefix(logger.debugs[len(logger.debugs)-1],
+		"cliargdax: parsed 1 args, 1 options, 0 positionals, 0 warnings in "))
+
+	n := len(logger.debugs)
+	conn.Cmd()
+	assert.Equal(t, len(logger.debugs), n)
+}
+
+func TestLogger_expansion(t *testing.T) {
+	logger := &recordingLogger{}
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "foo"},
+	}
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "--args-from-stdin", "c"}, optCfgs,
+	).ReadArgsFromStdin(cliargdax.StdinArgsCfg{
+		Reader: strings.NewReader("a\nb\n"),
+	}).WithLogger(logger)
+	_, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	assert.Equal(t, logger.debugs[1], "cliargdax: expanded stdin args: 2 args to 3 args")
+	assert.True(t, logger.hasDebug("cliargdax: expansion took "))
+}
+
+func TestLogger_nil(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "foo"},
+	}
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "--foo"}, optCfgs,
+	).WithLogger(nil)
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.True(t, conn.Cmd().HasOpt("foo"))
+}
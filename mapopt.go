@@ -0,0 +1,89 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"reflect"
+	"strings"
+)
+
+type /* error reasons */ (
+	// OptionArgIsNotKeyValue is the error reason which indicates that an option
+	// argument of a map option is not in the format: key=value.
+	// The fields Option and Value are the option name and the option argument.
+	OptionArgIsNotKeyValue struct {
+		Option string
+		Value  string
+	}
+
+	// OptionArgHasDuplicateKey is the error reason which indicates that a key
+	// is given multiple times to a map option of which field has the struct
+	// tag: optdupkey:"error".
+	// The fields Option and Key are the option name and the duplicated key.
+	OptionArgHasDuplicateKey struct {
+		Option string
+		Key    string
+	}
+)
+
+// OptMap is the method to retrieve the option arguments of the specified
+// option as a map, by splitting each option argument on the first "=".
+// An option argument without "=" is stored as a key with an empty value, and
+// if a key is given multiple times, the last value wins.
+// If the option is not given, this method returns an empty map.
+func (conn *DaxConn) OptMap(name string) map[string]string {
+	conn.ds.parseLazily()
+	conn.ds.mutex.RLock()
+	defer conn.ds.mutex.RUnlock()
+
+	m := make(map[string]string)
+	for _, a := range conn.overriddenCmd().OptArgs(name) {
+		k, v, _ := strings.Cut(a, "=")
+		m[k] = v
+	}
+	return m
+}
+
+var mapStringStringType = reflect.TypeOf(map[string]string{})
+
+// mapDecoderFor returns the fieldDecoder for a map[string]string field.
+// Each option argument is split on the first "=" into a key and a value.
+// By default, a duplicated key overwrites the previous value, but if the
+// struct tag: optdupkey:"error" is specified, it causes an error.
+func mapDecoderFor(sf reflect.StructField) (fieldDecoder, bool) {
+	if sf.Type != mapStringStringType {
+		return fieldDecoder{}, false
+	}
+
+	dupIsErr := (sf.Tag.Get("optdupkey") == "error")
+
+	set := func(fld reflect.Value, name string, a []string) error {
+		m := make(map[string]string, len(a))
+		for _, s := range a {
+			k, v, found := strings.Cut(s, "=")
+			if !found {
+				return reasonErr{option: name, reason: OptionArgIsNotKeyValue{
+					Option: name, Value: s,
+				}}
+			}
+			if _, exists := m[k]; exists && dupIsErr {
+				return reasonErr{option: name, reason: OptionArgHasDuplicateKey{
+					Option: name, Key: k,
+				}}
+			}
+			m[k] = v
+		}
+		fld.Set(reflect.ValueOf(m))
+		return nil
+	}
+
+	init := func(fld reflect.Value) {
+		if fld.IsNil() {
+			fld.Set(reflect.ValueOf(map[string]string{}))
+		}
+	}
+
+	return fieldDecoder{isArray: true, set: set, init: init}, true
+}
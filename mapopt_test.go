@@ -0,0 +1,126 @@
+package cliargdax_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+)
+
+func TestMapOpt_mapField(t *testing.T) {
+	type Options struct {
+		Labels map[string]string `optcfg:"label,l" optdesc:"labels"`
+		Annos  map[string]string `optcfg:"anno"`
+		Port   int               `optcfg:"port"`
+	}
+
+	options := Options{}
+	conn, err := setupForOptions(t, []string{
+		"app", "--label", "name=foo", "-l", "env=prod=1", "--port=80",
+		"--label=name=bar",
+	}, &options)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, options.Labels,
+		map[string]string{"name": "bar", "env": "prod=1"})
+	assert.NotNil(t, options.Annos)
+	assert.Equal(t, len(options.Annos), 0)
+	assert.Equal(t, options.Port, 80)
+
+	cfg := conn.OptCfgs()[0]
+	assert.Equal(t, cfg.Name, "label")
+	assert.Equal(t, cfg.Aliases, []string{"l"})
+	assert.True(t, cfg.HasArg)
+	assert.True(t, cfg.IsArray)
+	assert.Equal(t, cfg.Desc, "labels")
+}
+
+func TestMapOpt_mapField_default(t *testing.T) {
+	type Options struct {
+		Labels map[string]string `optcfg:"label=[a=1,b=2]"`
+	}
+
+	options := Options{}
+	_, err := setupForOptions(t, []string{"app"}, &options)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, options.Labels, map[string]string{"a": "1", "b": "2"})
+
+	type Options2 struct {
+		Labels map[string]string `optcfg:"label=[a]"`
+	}
+
+	options2 := Options2{}
+	_, err = setupForOptions(t, []string{"app"}, &options2)
+	switch r := err.Reason().(type) {
+	case cliargdax.FailToParseDefault:
+		assert.Equal(t, r.Option, "label")
+		assert.Equal(t, r.Field, "Labels")
+		assert.Equal(t, r.Input, "a")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestMapOpt_mapField_notKeyValue(t *testing.T) {
+	type Options struct {
+		Labels map[string]string `optcfg:"label"`
+	}
+
+	options := Options{}
+	_, err := setupForOptions(t, []string{
+		"app", "--label", "a=1", "--label", "b",
+	}, &options)
+	switch r := err.Reason().(type) {
+	case cliargdax.OptionArgIsNotKeyValue:
+		assert.Equal(t, r.Option, "label")
+		assert.Equal(t, r.Value, "b")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestMapOpt_mapField_duplicateKey(t *testing.T) {
+	type Options struct {
+		Labels map[string]string `optcfg:"label" optdupkey:"error"`
+	}
+
+	options := Options{}
+	_, err := setupForOptions(t, []string{
+		"app", "--label", "a=1", "--label", "a=2",
+	}, &options)
+	switch r := err.Reason().(type) {
+	case cliargdax.OptionArgHasDuplicateKey:
+		assert.Equal(t, r.Option, "label")
+		assert.Equal(t, r.Key, "a")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestMapOpt_illegalType(t *testing.T) {
+	type Options struct {
+		Labels map[string]int `optcfg:"label"`
+	}
+
+	options := Options{}
+	_, err := setupForOptions(t, []string{"app"}, &options)
+	switch r := err.Reason().(type) {
+	case cliargs.IllegalOptionType:
+		assert.Equal(t, r.Option, "label")
+		assert.Equal(t, r.Field, "Labels")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestMapOpt_OptMap(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgs([]string{
+		"app", "--label=name=foo", "--label=env=prod", "--label=flag",
+	})
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.OptMap("label"),
+		map[string]string{"name": "foo", "env": "prod", "flag": ""})
+	assert.Equal(t, conn.OptMap("none"), map[string]string{})
+}
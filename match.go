@@ -0,0 +1,183 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"strings"
+
+	"github.com/sttk/cliargs"
+	"github.com/sttk/sabi/errs"
+)
+
+type /* error reasons */ (
+	// AmbiguousOption is the error reason which indicates that an abbreviated
+	// long option matches multiple options.
+	// The field Option is the abbreviated option name given in command line
+	// arguments, and the field Candidates is the array of the option names and
+	// aliases which the abbreviated name matches.
+	AmbiguousOption struct {
+		Option     string
+		Candidates []string
+	}
+
+	// OptionNameCollidesIgnoringCase is the error reason which indicates that
+	// two option names or aliases in option configurations differ only in
+	// case though case-insensitive matching mode is enabled.
+	// The fields Option and Other are the colliding names.
+	OptionNameCollidesIgnoringCase struct {
+		Option string
+		Other  string
+	}
+)
+
+// AllowAbbrev is the method to enable or disable the abbreviation of long
+// options.
+// If enabled, a long option can be given with a prefix of its name or alias,
+// like --verb for --verbose, as long as the prefix matches only one option.
+// If the prefix matches multiple options, Setup method returns an errs.Err
+// with the reason: AmbiguousOption.
+// An option name or alias which exactly matches always wins over prefix
+// matches.
+// This mode is disabled by default.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) AllowAbbrev(allow bool) *DaxSrc {
+	ds.mode.allowAbbrev = allow
+	return ds
+}
+
+// IgnoreCase is the method to enable or disable the case-insensitive
+// matching of long options.
+// If enabled, long option names and aliases given in command line arguments
+// are matched with the configured ones ignoring case, like --Verbose for
+// --verbose, and the configured option names are used in cliargs.Cmd.
+// Short options, of which names are one character, remain case-sensitive.
+// If option names or aliases in the configurations differ only in case,
+// Setup method returns an errs.Err with the reason:
+// OptionNameCollidesIgnoringCase.
+// This mode is disabled by default.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) IgnoreCase(ignore bool) *DaxSrc {
+	ds.mode.ignoreCase = ignore
+	return ds
+}
+
+// nameMatcher matches long option names given in command line arguments with
+// the names and aliases of the option configurations, including the negated
+// forms of negatable options.
+type nameMatcher struct {
+	names   []string
+	targets map[string]string
+}
+
+func newNameMatcher(
+	optCfgs []cliargs.OptCfg, metas map[string]optMeta,
+) nameMatcher {
+	nm := nameMatcher{targets: make(map[string]string)}
+	add := func(name, target string) {
+		if _, exists := nm.targets[name]; !exists {
+			nm.names = append(nm.names, name)
+			nm.targets[name] = target
+		}
+	}
+
+	for _, cfg := range optCfgs {
+		if cfg.Name == "*" {
+			continue
+		}
+		add(cfg.Name, cfg.Name)
+		for _, a := range cfg.Aliases {
+			add(a, cfg.Name)
+		}
+	}
+
+	for _, cfg := range optCfgs {
+		if !metas[cfg.Name].negatable {
+			continue
+		}
+		add("no-"+cfg.Name, "no-"+cfg.Name)
+		for _, a := range cfg.Aliases {
+			if len(a) > 1 {
+				add("no-"+a, "no-"+cfg.Name)
+			}
+		}
+	}
+
+	return nm
+}
+
+// match returns the configured name or alias which the specified name
+// matches.
+// If the name matches none of them, this method returns the name as it is.
+func (nm nameMatcher) match(name string, mode parseMode) (string, errs.Err) {
+	if _, exists := nm.targets[name]; exists {
+		return name, errs.Ok()
+	}
+
+	if mode.ignoreCase && len(name) > 1 {
+		for _, s := range nm.names {
+			if len(s) > 1 && strings.EqualFold(s, name) {
+				return s, errs.Ok()
+			}
+		}
+	}
+
+	if !mode.allowAbbrev {
+		return name, errs.Ok()
+	}
+
+	hasPrefix := strings.HasPrefix
+	if mode.ignoreCase {
+		hasPrefix = func(s, prefix string) bool {
+			n := len(prefix)
+			return len(s) >= n && strings.EqualFold(s[:n], prefix)
+		}
+	}
+
+	var candidates []string
+	var matched string
+	targets := make(map[string]bool)
+	for _, s := range nm.names {
+		if hasPrefix(s, name) {
+			candidates = append(candidates, s)
+			if !targets[nm.targets[s]] {
+				targets[nm.targets[s]] = true
+				matched = s
+			}
+		}
+	}
+
+	switch len(targets) {
+	case 0:
+		return name, errs.Ok()
+	case 1:
+		return matched, errs.Ok()
+	default:
+		return name, errs.New(AmbiguousOption{
+			Option: name, Candidates: candidates,
+		})
+	}
+}
+
+// checkCaseCollisions checks that no two long option names or aliases of
+// different options differ only in case.
+func (nm nameMatcher) checkCaseCollisions() errs.Err {
+	folded := make(map[string]string, len(nm.names))
+	for _, s := range nm.names {
+		if len(s) <= 1 {
+			continue
+		}
+		key := strings.ToLower(s)
+		if other, exists := folded[key]; exists {
+			if nm.targets[other] != nm.targets[s] {
+				return errs.New(OptionNameCollidesIgnoringCase{
+					Option: other, Other: s,
+				})
+			}
+			continue
+		}
+		folded[key] = s
+	}
+	return errs.Ok()
+}
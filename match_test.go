@@ -0,0 +1,191 @@
+package cliargdax_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+)
+
+func TestMatch_AllowAbbrev(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "verbose", Aliases: []string{"v"}},
+		cliargs.OptCfg{Name: "output", Aliases: []string{"o"}, HasArg: true},
+		cliargs.OptCfg{Name: "color", Aliases: []string{"colour"}},
+	}
+
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "--verb", "--out", "a.txt", "--col", "b.txt",
+	}, optCfgs).AllowAbbrev(true)
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.True(t, conn.Cmd().HasOpt("verbose"))
+	assert.Equal(t, conn.Cmd().OptArg("output"), "a.txt")
+	assert.True(t, conn.Cmd().HasOpt("color"))
+	assert.Equal(t, conn.Cmd().Args(), []string{"b.txt"})
+
+	ds = cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "--o=x"}, optCfgs,
+	).AllowAbbrev(true)
+
+	conn, err = setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.Cmd().OptArg("output"), "x")
+}
+
+func TestMatch_AllowAbbrev_exactMatchWins(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "all"},
+		cliargs.OptCfg{Name: "allow"},
+		cliargs.OptCfg{Name: "allow-empty"},
+	}
+
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "--all", "--allow"}, optCfgs,
+	).AllowAbbrev(true)
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.True(t, conn.Cmd().HasOpt("all"))
+	assert.True(t, conn.Cmd().HasOpt("allow"))
+	assert.False(t, conn.Cmd().HasOpt("allow-empty"))
+}
+
+func TestMatch_AllowAbbrev_ambiguous(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "verbose"},
+		cliargs.OptCfg{Name: "version"},
+	}
+
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "--ver"}, optCfgs,
+	).AllowAbbrev(true)
+
+	_, err := setupWithOptCfgs(t, ds)
+	switch r := err.Reason().(type) {
+	case cliargdax.AmbiguousOption:
+		assert.Equal(t, r.Option, "ver")
+		assert.Equal(t, r.Candidates, []string{"verbose", "version"})
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestMatch_AllowAbbrev_disabledByDefault(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "verbose"},
+	}
+
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "--verb"}, optCfgs,
+	)
+
+	_, err := setupWithOptCfgs(t, ds)
+	switch r := err.Reason().(type) {
+	case cliargdax.UnconfiguredOptionWithSuggestion:
+		assert.Equal(t, r.Option, "verb")
+		assert.Equal(t, r.Suggestions, []string{"verbose"})
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestMatch_AllowAbbrev_negatable(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "color"},
+	}
+
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "--col", "--no-c"}, optCfgs,
+	).Negatable("color").AllowAbbrev(true)
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.Cmd().OptArg("color"), "false")
+}
+
+func TestMatch_IgnoreCase(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "verbose", Aliases: []string{"v"}},
+		cliargs.OptCfg{Name: "output", Aliases: []string{"out"}, HasArg: true},
+		cliargs.OptCfg{Name: "version", Aliases: []string{"V"}},
+	}
+
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "--Verbose", "--OUT", "a.txt", "-V",
+	}, optCfgs).IgnoreCase(true)
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.True(t, conn.Cmd().HasOpt("verbose"))
+	assert.Equal(t, conn.Cmd().OptArg("output"), "a.txt")
+	assert.True(t, conn.Cmd().HasOpt("version"))
+	assert.Equal(t, conn.Cmd().Args(), []string{})
+}
+
+func TestMatch_IgnoreCase_shortOptionsAreCaseSensitive(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "verbose", Aliases: []string{"v"}},
+	}
+
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "-V"}, optCfgs,
+	).IgnoreCase(true)
+
+	_, err := setupWithOptCfgs(t, ds)
+	switch r := err.Reason().(type) {
+	case cliargs.UnconfiguredOption:
+		assert.Equal(t, r.Option, "V")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestMatch_IgnoreCase_withAbbrev(t *testing.T) {
+	type Options struct {
+		Verbose bool   `optcfg:"verbose"`
+		Output  string `optcfg:"output"`
+	}
+
+	options := Options{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions(
+		[]string{"app", "--VERB", "--Out=x"}, &options,
+	).IgnoreCase(true).AllowAbbrev(true)
+
+	_, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.True(t, options.Verbose)
+	assert.Equal(t, options.Output, "x")
+}
+
+func TestMatch_IgnoreCase_collision(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "output", HasArg: true},
+		cliargs.OptCfg{Name: "Output", HasArg: true},
+		cliargs.OptCfg{Name: "v"},
+		cliargs.OptCfg{Name: "V"},
+	}
+
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app"}, optCfgs,
+	).IgnoreCase(true)
+
+	_, err := setupWithOptCfgs(t, ds)
+	switch r := err.Reason().(type) {
+	case cliargdax.OptionNameCollidesIgnoringCase:
+		assert.Equal(t, r.Option, "output")
+		assert.Equal(t, r.Other, "Output")
+	default:
+		assert.Fail(t, err.Error())
+	}
+
+	ds = cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "--Output=x"}, optCfgs,
+	)
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.Cmd().OptArg("Output"), "x")
+}
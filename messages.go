@@ -0,0 +1,327 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+// MsgID is the type for the identifiers of the user-facing messages which
+// are made by this package.
+type MsgID string
+
+// Messages is the map type for a message catalog, of which keys are message
+// identifiers and of which values are message templates.
+// A template has placeholders in the form: {name}, like {option}, which are
+// replaced with the values when a message is made, so the order of words can
+// differ for each language.
+// The placeholders of each message are shown in the English catalog returned
+// by EnglishMessages function.
+type Messages map[MsgID]string
+
+const (
+	// Messages for errors of parsing.
+	MsgUnknownOption               MsgID = "UnknownOption"
+	MsgDidYouMean                  MsgID = "DidYouMean"
+	MsgOr                          MsgID = "Or"
+	MsgListSeparator               MsgID = "ListSeparator"
+	MsgErrorSeparator              MsgID = "ErrorSeparator"
+	MsgOptionNeedsArg              MsgID = "OptionNeedsArg"
+	MsgCombinedOptionNeedsArg      MsgID = "CombinedOptionNeedsArg"
+	MsgOptionTakesNoArg            MsgID = "OptionTakesNoArg"
+	MsgOptionIsNotArray            MsgID = "OptionIsNotArray"
+	MsgOptionGivenTwice            MsgID = "OptionGivenTwice"
+	MsgNotEnoughOptionArgs         MsgID = "NotEnoughOptionArgs"
+	MsgOptionNotAvailableInVersion MsgID = "OptionNotAvailableInVersion"
+	MsgOptionInterpolationCycle    MsgID = "OptionInterpolationCycle"
+	MsgUnknownOptionReference      MsgID = "UnknownOptionReference"
+	MsgInvalidOption               MsgID = "InvalidOption"
+	MsgAmbiguousOption             MsgID = "AmbiguousOption"
+	MsgOptionsCollideIgnoreCase    MsgID = "OptionsCollideIgnoreCase"
+
+	// Messages for invalid option arguments.
+	MsgInvalidValue         MsgID = "InvalidValue"
+	MsgInvalidValueMustBe   MsgID = "InvalidValueMustBe"
+	MsgWithCause            MsgID = "WithCause"
+	MsgAnInteger            MsgID = "AnInteger"
+	MsgANonNegativeInteger  MsgID = "ANonNegativeInteger"
+	MsgANumber              MsgID = "ANumber"
+	MsgABool                MsgID = "ABool"
+	MsgADuration            MsgID = "ADuration"
+	MsgATime                MsgID = "ATime"
+	MsgAURL                 MsgID = "AURL"
+	MsgAnIPAddress          MsgID = "AnIPAddress"
+	MsgAPath                MsgID = "APath"
+	MsgKeyValue             MsgID = "KeyValue"
+	MsgValueNotInChoices    MsgID = "ValueNotInChoices"
+	MsgValueOutOfRange      MsgID = "ValueOutOfRange"
+	MsgRangeBetween         MsgID = "RangeBetween"
+	MsgRangeAtLeast         MsgID = "RangeAtLeast"
+	MsgRangeAtMost          MsgID = "RangeAtMost"
+	MsgDuplicateKey         MsgID = "DuplicateKey"
+	MsgOptCallbackFailed    MsgID = "OptCallbackFailed"
+	MsgOptCallbackFailedArg MsgID = "OptCallbackFailedArg"
+	MsgFailToReadOptionFile MsgID = "FailToReadOptionFile"
+	MsgFailToParseEnvVar    MsgID = "FailToParseEnvVar"
+	MsgFailToParseDefault   MsgID = "FailToParseDefault"
+
+	// Messages for validations.
+	MsgRequiredOptionMissing  MsgID = "RequiredOptionMissing"
+	MsgRequiredOptionsMissing MsgID = "RequiredOptionsMissing"
+	MsgOptionRequiresOther    MsgID = "OptionRequiresOther"
+	MsgOptionOutsideBlock     MsgID = "OptionOutsideBlock"
+	MsgBlockOptionMissing     MsgID = "BlockOptionMissing"
+	MsgOptionDeprecated       MsgID = "OptionDeprecated"
+	MsgOptionDeprecatedUse    MsgID = "OptionDeprecatedUse"
+	MsgWithDetail             MsgID = "WithDetail"
+	MsgDeprecationWarning     MsgID = "DeprecationWarning"
+	MsgDeprecationWarningUse  MsgID = "DeprecationWarningUse"
+	MsgMissingArg             MsgID = "MissingArg"
+	MsgTooManyArgs            MsgID = "TooManyArgs"
+	MsgUnknownSubCommand      MsgID = "UnknownSubCommand"
+	MsgRouteIsMissing         MsgID = "RouteIsMissing"
+
+	// Messages for files.
+	MsgFailToOpenArgFile            MsgID = "FailToOpenArgFile"
+	MsgFailToReadResponseFile       MsgID = "FailToReadResponseFile"
+	MsgResponseFileHasUnclosedQuote MsgID = "ResponseFileHasUnclosedQuote"
+	MsgResponseFileHasCycle         MsgID = "ResponseFileHasCycle"
+	MsgResponseFileIsTooDeep        MsgID = "ResponseFileIsTooDeep"
+	MsgFailToReadStdinArgs          MsgID = "FailToReadStdinArgs"
+	MsgTooManyStdinArgs             MsgID = "TooManyStdinArgs"
+	MsgStdinArgsTooLarge            MsgID = "StdinArgsTooLarge"
+	MsgSetupCanceled                MsgID = "SetupCanceled"
+	MsgUnsupportedConfigFormat      MsgID = "UnsupportedConfigFormat"
+	MsgFailToReadConfigFile         MsgID = "FailToReadConfigFile"
+	MsgFailToDecodeConfigFile       MsgID = "FailToDecodeConfigFile"
+	MsgConfigValueTypeMismatch      MsgID = "ConfigValueTypeMismatch"
+	MsgFailToParseConfigValue       MsgID = "FailToParseConfigValue"
+	MsgFailToWriteCompletion        MsgID = "FailToWriteCompletion"
+	MsgFailToWriteDoc               MsgID = "FailToWriteDoc"
+	MsgFailToMarshalResult          MsgID = "FailToMarshalResult"
+	MsgFailToMarshalSchema          MsgID = "FailToMarshalSchema"
+	MsgFailToUnmarshalResult        MsgID = "FailToUnmarshalResult"
+	MsgUnsupportedShell             MsgID = "UnsupportedShell"
+	MsgOptionsTypeMismatch          MsgID = "OptionsTypeMismatch"
+	MsgMetaForUnconfiguredOption    MsgID = "MetaForUnconfiguredOption"
+	MsgIllegalOptionType            MsgID = "IllegalOptionType"
+	MsgOptionStoreIsNotChangeable   MsgID = "OptionStoreIsNotChangeable"
+	MsgOptionStoreIsNotPointer      MsgID = "OptionStoreIsNotPointer"
+	MsgOptionStoreIsNil             MsgID = "OptionStoreIsNil"
+	MsgOptionStoreIsNotStruct       MsgID = "OptionStoreIsNotStruct"
+	MsgOptionStoreHasNoExported     MsgID = "OptionStoreHasNoExported"
+	MsgConfigIsArrayButHasNoArg     MsgID = "ConfigIsArrayButHasNoArg"
+	MsgConfigHasDefaultButHasNoArg  MsgID = "ConfigHasDefaultButHasNoArg"
+	MsgIllegalOptionRange           MsgID = "IllegalOptionRange"
+	MsgIllegalOptionNArgs           MsgID = "IllegalOptionNArgs"
+	MsgDuplicateOptName             MsgID = "DuplicateOptName"
+	MsgDuplicateOptAlias            MsgID = "DuplicateOptAlias"
+	MsgAliasCollidesWithName        MsgID = "AliasCollidesWithName"
+	MsgOptionStoresCollide          MsgID = "OptionStoresCollide"
+	MsgOptionStoreHasCycle          MsgID = "OptionStoreHasCycle"
+	MsgOptionStoreIsTooDeep         MsgID = "OptionStoreIsTooDeep"
+	MsgDerivedOptNameCollides       MsgID = "DerivedOptNameCollides"
+	MsgIllegalArgPosition           MsgID = "IllegalArgPosition"
+	MsgIllegalPositionalCfg         MsgID = "IllegalPositionalCfg"
+	MsgIllegalChangedField          MsgID = "IllegalChangedField"
+	MsgWildcardWithIgnoreUnknown    MsgID = "WildcardWithIgnoreUnknown"
+
+	// Messages for the help text, the version text, and documents.
+	MsgHelpHint        MsgID = "HelpHint"
+	MsgUsage           MsgID = "Usage"
+	MsgUsageOptions    MsgID = "UsageOptions"
+	MsgOptionsHeading  MsgID = "OptionsHeading"
+	MsgGroupHeading    MsgID = "GroupHeading"
+	MsgHelpOptDesc     MsgID = "HelpOptDesc"
+	MsgHelpSince       MsgID = "HelpSince"
+	MsgVersionOptDesc  MsgID = "VersionOptDesc"
+	MsgConfigOptDesc   MsgID = "ConfigOptDesc"
+	MsgVersionCommit   MsgID = "VersionCommit"
+	MsgVersionBuilt    MsgID = "VersionBuilt"
+	MsgDocName         MsgID = "DocName"
+	MsgDocSynopsis     MsgID = "DocSynopsis"
+	MsgDocOptions      MsgID = "DocOptions"
+	MsgDocGroup        MsgID = "DocGroup"
+	MsgDocOtherOptions MsgID = "DocOtherOptions"
+	MsgDocDefault      MsgID = "DocDefault"
+)
+
+var englishMessages = Messages{
+	MsgUnknownOption:               "unknown option: {option}",
+	MsgDidYouMean:                  "{message} (did you mean {options}?)",
+	MsgOr:                          " or ",
+	MsgListSeparator:               ", ",
+	MsgErrorSeparator:              "; ",
+	MsgOptionNeedsArg:              "option {option} requires an argument",
+	MsgCombinedOptionNeedsArg:      "option -{letter} in {arg} requires an argument but is not the last",
+	MsgOptionTakesNoArg:            "option {option} does not take an argument",
+	MsgOptionIsNotArray:            "option {option} cannot be given more than once",
+	MsgOptionGivenTwice:            "option {option} cannot be given more than once: {first} at argument {firstIndex} and {second} at argument {secondIndex}",
+	MsgNotEnoughOptionArgs:         "option {option} requires {expected} arguments but {found} given",
+	MsgOptionNotAvailableInVersion: "option {option} is not available in {current}: available from {since}",
+	MsgOptionInterpolationCycle:    "options refer to each other in their arguments: {chain}",
+	MsgUnknownOptionReference:      "option {option} refers to an unknown option: {placeholder}",
+	MsgInvalidOption:               "invalid option: {option}",
+	MsgAmbiguousOption:             "ambiguous option: {option} (could be {options})",
+	MsgOptionsCollideIgnoreCase:    "options {option} and {other} collide when case is ignored",
+
+	MsgInvalidValue:         "invalid value for option {option}: {value}",
+	MsgInvalidValueMustBe:   "invalid value for option {option}: {value} (must be {want})",
+	MsgWithCause:            "{message} ({cause})",
+	MsgAnInteger:            "an integer",
+	MsgANonNegativeInteger:  "a non-negative integer",
+	MsgANumber:              "a number",
+	MsgABool:                "true or false",
+	MsgADuration:            "a duration, like 1m30s",
+	MsgATime:                "a time in {layout}",
+	MsgAURL:                 "a URL",
+	MsgAnIPAddress:          "an IP address",
+	MsgAPath:                "a path",
+	MsgKeyValue:             "key=value",
+	MsgValueNotInChoices:    "invalid value for option {option}: {value} (choose from {choices})",
+	MsgValueOutOfRange:      "value for option {option} is out of range: {value} ({range})",
+	MsgRangeBetween:         "must be between {min} and {max}",
+	MsgRangeAtLeast:         "must be at least {min}",
+	MsgRangeAtMost:          "must be at most {max}",
+	MsgDuplicateKey:         "duplicated key for option {option}: {key}",
+	MsgOptCallbackFailed:    "failed to process option {option}",
+	MsgOptCallbackFailedArg: "failed to process option {option}: {value}",
+	MsgFailToReadOptionFile: "cannot read the file for option {option}: {path}",
+	MsgFailToParseEnvVar:    "invalid value of environment variable {envvar} for option {option}: {value}",
+	MsgFailToParseDefault:   "invalid default value for option {option}: {value}",
+
+	MsgRequiredOptionMissing:  "option {option} is required",
+	MsgRequiredOptionsMissing: "missing required options: {options}",
+	MsgOptionRequiresOther:    "option {option} requires option {other}",
+	MsgOptionOutsideBlock:     "option {option} must be given after option {block}",
+	MsgBlockOptionMissing:     "option {option} is required in block #{index} of option {block}",
+	MsgOptionDeprecated:       "option {option} is deprecated",
+	MsgOptionDeprecatedUse:    "option {option} is deprecated, use {other} instead",
+	MsgWithDetail:             "{message}: {detail}",
+	MsgDeprecationWarning:     "{option} is deprecated",
+	MsgDeprecationWarningUse:  "{option} is deprecated: use {other} instead",
+	MsgMissingArg:             "missing argument: {name}",
+	MsgTooManyArgs:            "too many arguments: {got} given, at most {max} allowed",
+	MsgUnknownSubCommand:      "unknown command: {name}",
+	MsgRouteIsMissing:         "a command is required: {routes}",
+
+	MsgFailToOpenArgFile:            "cannot open the file: {path}",
+	MsgFailToReadResponseFile:       "cannot read the response file: {path}",
+	MsgResponseFileHasUnclosedQuote: "unclosed quote in the response file: {path}",
+	MsgResponseFileHasCycle:         "response file includes itself: {path}",
+	MsgResponseFileIsTooDeep:        "response files are nested too deeply: {path}",
+	MsgFailToReadStdinArgs:          "cannot read arguments from the standard input",
+	MsgTooManyStdinArgs:             "too many arguments from the standard input: more than {limit}",
+	MsgStdinArgsTooLarge:            "arguments from the standard input are too large: more than {limit} bytes",
+	MsgSetupCanceled:                "parsing command line arguments was canceled",
+	MsgUnsupportedConfigFormat:      "unsupported config file format: {format} ({path})",
+	MsgFailToReadConfigFile:         "cannot read the config file: {path}",
+	MsgFailToDecodeConfigFile:       "cannot decode the config file: {path}",
+	MsgConfigValueTypeMismatch:      "invalid value in the config file for key {key}: {value}",
+	MsgFailToParseConfigValue:       "invalid value in the config file for key {key}: {value}",
+	MsgFailToWriteCompletion:        "cannot write the completion script for {shell}",
+	MsgFailToWriteDoc:               "cannot write the document in {format}",
+	MsgFailToMarshalResult:          "cannot encode the results of parsing",
+	MsgFailToMarshalSchema:          "cannot encode the schema of the command",
+	MsgFailToUnmarshalResult:        "cannot decode the results of parsing",
+	MsgUnsupportedShell:             "unsupported shell: {shell}",
+	MsgOptionsTypeMismatch:          "the option store is not of the requested type",
+	MsgMetaForUnconfiguredOption:    "metadata is set for unknown option {option}",
+	MsgIllegalOptionType:            "option {option} has an unsupported type",
+	MsgOptionStoreIsNotChangeable:   "the option store is not a pointer",
+	MsgOptionStoreIsNotPointer:      "the option store is not a pointer: {type}",
+	MsgOptionStoreIsNil:             "the option store is nil: {type}",
+	MsgOptionStoreIsNotStruct:       "the option store is not a pointer to a struct: {type}",
+	MsgOptionStoreHasNoExported:     "the option store has no exported field: {type}",
+	MsgConfigIsArrayButHasNoArg:     "option {option} is configured as an array but takes no argument",
+	MsgConfigHasDefaultButHasNoArg:  "option {option} is configured with a default value but takes no argument",
+	MsgIllegalOptionRange:           "option {option} has an illegal range",
+	MsgIllegalOptionNArgs:           "option {option} has an illegal number of arguments",
+	MsgDuplicateOptName:             "option {option} is configured more than once",
+	MsgDuplicateOptAlias:            "alias {alias} is configured more than once",
+	MsgAliasCollidesWithName:        "alias {alias} of option {option} collides with another option",
+	MsgOptionStoresCollide:          "option {option} is configured by multiple option stores",
+	MsgOptionStoreHasCycle:          "the option store has a cycle at field {field}",
+	MsgOptionStoreIsTooDeep:         "the option store is nested too deeply at field {field}",
+	MsgDerivedOptNameCollides:       "option {option} is configured by both field {field} and field {other}",
+	MsgIllegalArgPosition:           "illegal argument position for field {field}: {position}",
+	MsgIllegalPositionalCfg:         "illegal argument configuration: {name}",
+	MsgIllegalChangedField:          "illegal optchanged field {field}: {option}",
+	MsgWildcardWithIgnoreUnknown:    "the wildcard option \"*\" cannot be configured when unknown options are ignored",
+
+	MsgHelpHint:        "Try '{command}' for more information.",
+	MsgUsage:           "Usage: {command}",
+	MsgUsageOptions:    "[OPTIONS]",
+	MsgOptionsHeading:  "Options:",
+	MsgGroupHeading:    "{group} options:",
+	MsgHelpOptDesc:     "Print help.",
+	MsgHelpSince:       "{desc} (since {since})",
+	MsgVersionOptDesc:  "Print version.",
+	MsgConfigOptDesc:   "Read option values from the config file.",
+	MsgVersionCommit:   "commit: {commit}",
+	MsgVersionBuilt:    "built: {date}",
+	MsgDocName:         "NAME",
+	MsgDocSynopsis:     "SYNOPSIS",
+	MsgDocOptions:      "OPTIONS",
+	MsgDocGroup:        "{group} options",
+	MsgDocOtherOptions: "Other options",
+	MsgDocDefault:      "Default: {value}",
+}
+
+// catalog holds the message catalog set by SetMessages function.
+var catalog atomic.Value
+
+// EnglishMessages is the function to retrieve a copy of the English message
+// catalog, which is used by default.
+func EnglishMessages() Messages {
+	return copyMessages(englishMessages)
+}
+
+// SetMessages is the function to set the message catalog which is used to
+// make the user-facing messages of this package, like the messages of
+// FormatError function, the help text, and documents.
+// The messages which are not in the catalog are made with the English
+// catalog, and if the catalog is nil, the English catalog is used again.
+// The catalog is copied, so modifying it after calling this function does not
+// affect the messages.
+// The descriptions of the help option, the version option, and the config
+// option are made when DaxSrc#EnableHelpOpt, DaxSrc#EnableVersionInfo, and
+// DaxSrc#EnableConfigOpt methods are called, so this function should be
+// called before them.
+func SetMessages(messages Messages) {
+	catalog.Store(copyMessages(messages))
+}
+
+func copyMessages(messages Messages) Messages {
+	m := make(Messages, len(messages))
+	for id, s := range messages {
+		m[id] = s
+	}
+	return m
+}
+
+// msg makes the message of the identifier with the current catalog, in which
+// the placeholders are replaced with the values.
+// The arguments after the identifier are pairs of a placeholder name and its
+// value.
+func msg(id MsgID, params ...string) string {
+	tmpl, ok := "", false
+	if m, _ := catalog.Load().(Messages); m != nil {
+		tmpl, ok = m[id]
+	}
+	if !ok {
+		tmpl = englishMessages[id]
+	}
+	if len(params) < 2 {
+		return tmpl
+	}
+
+	pairs := make([]string, 0, len(params))
+	for i := 0; i+1 < len(params); i += 2 {
+		pairs = append(pairs, "{"+params[i]+"}", params[i+1])
+	}
+	return strings.NewReplacer(pairs...).Replace(tmpl)
+}
@@ -0,0 +1,133 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+var japaneseMessages = Messages{
+	MsgUnknownOption:               "不明なオプションです: {option}",
+	MsgDidYouMean:                  "{message} ({options} のことですか?)",
+	MsgOr:                          " または ",
+	MsgListSeparator:               "、",
+	MsgErrorSeparator:              "; ",
+	MsgOptionNeedsArg:              "オプション {option} には引数が必要です",
+	MsgCombinedOptionNeedsArg:      "{arg} 中のオプション -{letter} には引数が必要ですが、最後にありません",
+	MsgOptionTakesNoArg:            "オプション {option} は引数を取りません",
+	MsgOptionIsNotArray:            "オプション {option} は複数回指定できません",
+	MsgOptionGivenTwice:            "オプション {option} は複数回指定できません: 引数 {firstIndex} の {first} と引数 {secondIndex} の {second}",
+	MsgNotEnoughOptionArgs:         "オプション {option} には {expected} 個の引数が必要ですが、{found} 個指定されました",
+	MsgOptionNotAvailableInVersion: "オプション {option} は {current} では使用できません: {since} から使用できます",
+	MsgOptionInterpolationCycle:    "オプションの引数が互いを参照しています: {chain}",
+	MsgUnknownOptionReference:      "オプション {option} が不明なオプションを参照しています: {placeholder}",
+	MsgInvalidOption:               "不正なオプションです: {option}",
+	MsgAmbiguousOption:             "あいまいなオプションです: {option} (候補: {options})",
+	MsgOptionsCollideIgnoreCase:    "オプション {option} と {other} は大文字小文字を区別しないと衝突します",
+
+	MsgInvalidValue:         "オプション {option} の値が不正です: {value}",
+	MsgInvalidValueMustBe:   "オプション {option} の値が不正です: {value} ({want}を指定してください)",
+	MsgWithCause:            "{message} ({cause})",
+	MsgAnInteger:            "整数",
+	MsgANonNegativeInteger:  "0以上の整数",
+	MsgANumber:              "数値",
+	MsgABool:                "true または false",
+	MsgADuration:            "1m30s のような時間",
+	MsgATime:                "{layout} 形式の時刻",
+	MsgAURL:                 "URL",
+	MsgAnIPAddress:          "IPアドレス",
+	MsgAPath:                "パス",
+	MsgKeyValue:             "key=value 形式",
+	MsgValueNotInChoices:    "オプション {option} の値が不正です: {value} ({choices} から選択してください)",
+	MsgValueOutOfRange:      "オプション {option} の値が範囲外です: {value} ({range})",
+	MsgRangeBetween:         "{min} 以上 {max} 以下である必要があります",
+	MsgRangeAtLeast:         "{min} 以上である必要があります",
+	MsgRangeAtMost:          "{max} 以下である必要があります",
+	MsgDuplicateKey:         "オプション {option} のキーが重複しています: {key}",
+	MsgOptCallbackFailed:    "オプション {option} の処理に失敗しました",
+	MsgOptCallbackFailedArg: "オプション {option} の処理に失敗しました: {value}",
+	MsgFailToReadOptionFile: "オプション {option} のファイルを読み込めません: {path}",
+	MsgFailToParseEnvVar:    "オプション {option} の環境変数 {envvar} の値が不正です: {value}",
+	MsgFailToParseDefault:   "オプション {option} のデフォルト値が不正です: {value}",
+
+	MsgRequiredOptionMissing:  "オプション {option} は必須です",
+	MsgRequiredOptionsMissing: "必須オプションが指定されていません: {options}",
+	MsgOptionRequiresOther:    "オプション {option} にはオプション {other} が必要です",
+	MsgOptionOutsideBlock:     "オプション {option} はオプション {block} の後に指定してください",
+	MsgBlockOptionMissing:     "オプション {block} の {index} 番目のブロックにはオプション {option} が必須です",
+	MsgOptionDeprecated:       "オプション {option} は非推奨です",
+	MsgOptionDeprecatedUse:    "オプション {option} は非推奨です。代わりに {other} を使用してください",
+	MsgWithDetail:             "{message}: {detail}",
+	MsgDeprecationWarning:     "{option} は非推奨です",
+	MsgDeprecationWarningUse:  "{option} は非推奨です: 代わりに {other} を使用してください",
+	MsgMissingArg:             "引数が指定されていません: {name}",
+	MsgTooManyArgs:            "引数が多すぎます: {got} 個指定されましたが、最大 {max} 個です",
+	MsgUnknownSubCommand:      "不明なコマンドです: {name}",
+	MsgRouteIsMissing:         "コマンドを指定してください: {routes}",
+
+	MsgFailToOpenArgFile:            "ファイルを開けません: {path}",
+	MsgFailToReadResponseFile:       "レスポンスファイルを読み込めません: {path}",
+	MsgResponseFileHasUnclosedQuote: "レスポンスファイルに閉じられていない引用符があります: {path}",
+	MsgResponseFileHasCycle:         "レスポンスファイルが自身を含んでいます: {path}",
+	MsgResponseFileIsTooDeep:        "レスポンスファイルの入れ子が深すぎます: {path}",
+	MsgFailToReadStdinArgs:          "標準入力から引数を読み込めません",
+	MsgTooManyStdinArgs:             "標準入力からの引数が多すぎます: 最大 {limit} 個です",
+	MsgStdinArgsTooLarge:            "標準入力からの引数が大きすぎます: 最大 {limit} バイトです",
+	MsgSetupCanceled:                "コマンドライン引数の解析が中断されました",
+	MsgUnsupportedConfigFormat:      "サポートされていない設定ファイルの形式です: {format} ({path})",
+	MsgFailToReadConfigFile:         "設定ファイルを読み込めません: {path}",
+	MsgFailToDecodeConfigFile:       "設定ファイルを解析できません: {path}",
+	MsgConfigValueTypeMismatch:      "設定ファイルのキー {key} の値が不正です: {value}",
+	MsgFailToParseConfigValue:       "設定ファイルのキー {key} の値が不正です: {value}",
+	MsgFailToWriteCompletion:        "{shell} の補完スクリプトを書き込めません",
+	MsgFailToWriteDoc:               "{format} 形式のドキュメントを書き込めません",
+	MsgFailToMarshalResult:          "解析結果をエンコードできません",
+	MsgFailToMarshalSchema:          "コマンドのスキーマをエンコードできません",
+	MsgFailToUnmarshalResult:        "解析結果をデコードできません",
+	MsgUnsupportedShell:             "サポートされていないシェルです: {shell}",
+	MsgOptionsTypeMismatch:          "オプションストアが要求された型ではありません",
+	MsgMetaForUnconfiguredOption:    "不明なオプション {option} にメタデータが設定されています",
+	MsgIllegalOptionType:            "オプション {option} はサポートされていない型です",
+	MsgOptionStoreIsNotChangeable:   "オプションストアがポインタではありません",
+	MsgOptionStoreIsNotPointer:      "オプションストアがポインタではありません: {type}",
+	MsgOptionStoreIsNil:             "オプションストアが nil です: {type}",
+	MsgOptionStoreIsNotStruct:       "オプションストアが構造体へのポインタではありません: {type}",
+	MsgOptionStoreHasNoExported:     "オプションストアに公開フィールドがありません: {type}",
+	MsgConfigIsArrayButHasNoArg:     "オプション {option} は配列として設定されていますが、引数を取りません",
+	MsgConfigHasDefaultButHasNoArg:  "オプション {option} はデフォルト値が設定されていますが、引数を取りません",
+	MsgIllegalOptionRange:           "オプション {option} の範囲が不正です",
+	MsgIllegalOptionNArgs:           "オプション {option} の引数の個数が不正です",
+	MsgDuplicateOptName:             "オプション {option} が複数回設定されています",
+	MsgDuplicateOptAlias:            "別名 {alias} が複数回設定されています",
+	MsgAliasCollidesWithName:        "オプション {option} の別名 {alias} が他のオプションと衝突しています",
+	MsgOptionStoresCollide:          "オプション {option} が複数のオプションストアで設定されています",
+	MsgOptionStoreHasCycle:          "オプションストアのフィールド {field} に循環があります",
+	MsgOptionStoreIsTooDeep:         "オプションストアのフィールド {field} の入れ子が深すぎます",
+	MsgDerivedOptNameCollides:       "オプション {option} がフィールド {field} とフィールド {other} の両方で設定されています",
+	MsgIllegalArgPosition:           "フィールド {field} の引数の位置が不正です: {position}",
+	MsgIllegalPositionalCfg:         "引数の設定が不正です: {name}",
+	MsgIllegalChangedField:          "optchanged フィールド {field} が不正です: {option}",
+	MsgWildcardWithIgnoreUnknown:    "不明なオプションを無視する場合はワイルドカードのオプション \"*\" を設定できません",
+
+	MsgHelpHint:        "詳しくは '{command}' を実行してください。",
+	MsgUsage:           "使い方: {command}",
+	MsgUsageOptions:    "[オプション]",
+	MsgOptionsHeading:  "オプション:",
+	MsgGroupHeading:    "{group} オプション:",
+	MsgHelpOptDesc:     "ヘルプを表示します。",
+	MsgHelpSince:       "{desc} ({since} 以降)",
+	MsgVersionOptDesc:  "バージョンを表示します。",
+	MsgConfigOptDesc:   "設定ファイルからオプションの値を読み込みます。",
+	MsgVersionCommit:   "コミット: {commit}",
+	MsgVersionBuilt:    "ビルド日時: {date}",
+	MsgDocName:         "名前",
+	MsgDocSynopsis:     "書式",
+	MsgDocOptions:      "オプション",
+	MsgDocGroup:        "{group} オプション",
+	MsgDocOtherOptions: "その他のオプション",
+	MsgDocDefault:      "デフォルト: {value}",
+}
+
+// JapaneseMessages is the function to retrieve a copy of the Japanese message
+// catalog, which can be set with SetMessages function.
+func JapaneseMessages() Messages {
+	return copyMessages(japaneseMessages)
+}
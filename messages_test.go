@@ -0,0 +1,107 @@
+package cliargdax_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+)
+
+var messagesOptCfgs = []cliargs.OptCfg{
+	cliargs.OptCfg{Name: "verbose", Aliases: []string{"v"}, Desc: "Be verbose."},
+	cliargs.OptCfg{Name: "count", HasArg: true, Desc: "Number of items."},
+}
+
+func setMessages(t *testing.T, messages cliargdax.Messages) {
+	cliargdax.SetMessages(messages)
+	t.Cleanup(func() { cliargdax.SetMessages(nil) })
+}
+
+func TestMessages_Japanese_FormatError(t *testing.T) {
+	setMessages(t, cliargdax.JapaneseMessages())
+
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "--verbos"}, messagesOptCfgs,
+	).EnableHelpOpt("help", "h")
+	_, err := setupWithOptCfgs(t, ds)
+	assert.Equal(t, cliargdax.FormatError(err, messagesOptCfgs),
+		"不明なオプションです: --verbos (--verbose のことですか?)\n"+
+			"詳しくは '--help' を実行してください。")
+
+	ds = cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "--verbose=1"}, messagesOptCfgs,
+	)
+	_, err = setupWithOptCfgs(t, ds)
+	assert.Equal(t, cliargdax.FormatError(err, messagesOptCfgs),
+		"オプション --verbose は引数を取りません\n"+
+			"詳しくは '--help' を実行してください。")
+}
+
+func TestMessages_Japanese_HelpText(t *testing.T) {
+	setMessages(t, cliargdax.JapaneseMessages())
+
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app"}, messagesOptCfgs,
+	).EnableHelpOpt("help", "h")
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.HelpText(), "使い方: app [オプション]\n"+
+		"\n"+
+		"オプション:\n"+
+		"  -v, --verbose         Be verbose.\n"+
+		"      --count <COUNT>   Number of items.\n"+
+		"  -h, --help            ヘルプを表示します。\n")
+}
+
+func TestMessages_partialCatalog(t *testing.T) {
+	setMessages(t, cliargdax.Messages{
+		cliargdax.MsgOptionNeedsArg: "{option}: argument required",
+	})
+
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "--count"}, messagesOptCfgs,
+	)
+	_, err := setupWithOptCfgs(t, ds)
+	assert.Equal(t, cliargdax.FormatError(err, messagesOptCfgs),
+		"--count: argument required\n"+
+			"Try '--help' for more information.")
+}
+
+func TestMessages_SetMessages_nil(t *testing.T) {
+	setMessages(t, cliargdax.JapaneseMessages())
+	cliargdax.SetMessages(nil)
+
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "--count"}, messagesOptCfgs,
+	)
+	_, err := setupWithOptCfgs(t, ds)
+	assert.Equal(t, cliargdax.FormatError(err, messagesOptCfgs),
+		"option --count requires an argument\n"+
+			"Try '--help' for more information.")
+}
+
+func TestMessages_SetMessages_copies(t *testing.T) {
+	messages := cliargdax.Messages{
+		cliargdax.MsgOptionNeedsArg: "{option}: argument required",
+	}
+	setMessages(t, messages)
+	messages[cliargdax.MsgOptionNeedsArg] = "changed"
+
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "--count"}, messagesOptCfgs,
+	)
+	_, err := setupWithOptCfgs(t, ds)
+	assert.Equal(t, cliargdax.FormatError(err, messagesOptCfgs),
+		"--count: argument required\n"+
+			"Try '--help' for more information.")
+}
+
+func TestMessages_JapaneseMessages_coversEnglish(t *testing.T) {
+	ja := cliargdax.JapaneseMessages()
+	for id := range cliargdax.EnglishMessages() {
+		_, ok := ja[id]
+		assert.True(t, ok, string(id))
+	}
+	assert.Equal(t, len(ja), len(cliargdax.EnglishMessages()))
+}
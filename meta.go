@@ -0,0 +1,231 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/sttk/cliargs"
+	"github.com/sttk/sabi/errs"
+)
+
+type /* error reasons */ (
+	// MetaForUnconfiguredOption is the error reason which indicates that the
+	// metadata set by DaxSrc#SetMeta method is for an option which is not
+	// configured.
+	// The field Option is the option name of the metadata.
+	MetaForUnconfiguredOption struct {
+		Option string
+	}
+)
+
+// OptMeta is the struct type for the metadata of an option, which
+// cliargs.OptCfg does not have.
+// The fields Choices, Metavar, EnvVar, Default, Group, Hidden, and Secret are
+// same as the values of the struct tags: optchoices, optmetavar, optenv,
+// optdefault, optgroup, opthidden, and optsecret of an option store.
+// The values of Default for an array option are separated by commas.
+// The field Kind is the kind of the values of the option which are stored in
+// the map of DaxSrc created by NewDaxSrcDynamic function: reflect.Bool,
+// reflect.Int, reflect.Int64, reflect.Uint, reflect.Uint64, or
+// reflect.Float64, and the values are strings for other kinds.
+// The field BoolValue makes the option which takes no option argument accept
+// an attached bool value, like --color=false, as bool fields of option stores
+// do.
+// The fields NArgs and NArgsEnd are same as the values of the struct tags:
+// optnargs and optnargsend, of which "*" and "+" are ZeroOrMoreArgs and
+// OneOrMoreArgs, and NArgs is ignored if it is 0 or 1 or the option takes no
+// option argument.
+// The option arguments of a variadic option can be negative numbers if Kind
+// is a number kind.
+// The field Since is same as the value of the struct tag: optsince.
+// The field Literal is same as the struct tag: optliteral:"true".
+// The field Required is same as the struct tag: optrequired:"true", and the
+// fields Deprecated and Deprecation are same as the struct tag:
+// optdeprecated, of which value is Deprecation.
+type OptMeta struct {
+	Choices []string
+	Metavar string
+	EnvVar  string
+	Default string
+	Group   string
+	Hidden  bool
+	Secret  bool
+	Kind    reflect.Kind
+
+	BoolValue bool
+	NArgs     int
+	NArgsEnd  string
+	Since     string
+	Literal   bool
+
+	Required    bool
+	Deprecated  bool
+	Deprecation string
+}
+
+// SetMeta is the method to set the metadata of the option specified as the
+// first argument.
+// The metadata is merged into the option configuration in Setup method, and
+// is used in validations, and in the help text, completion scripts, and
+// documents.
+// The empty fields of the metadata do not overwrite the values which are set
+// by struct tags or other methods of this DaxSrc.
+// If the option is not configured, Setup method returns an errs.Err with the
+// reason: MetaForUnconfiguredOption.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) SetMeta(name string, meta OptMeta) *DaxSrc {
+	if ds.optMetas == nil {
+		ds.optMetas = make(map[string]OptMeta)
+	}
+	ds.optMetas[name] = meta
+	return ds
+}
+
+// Meta is the method to retrieve the metadata of the option, which is merged
+// from the metadata set by DaxSrc#SetMeta method, the struct tags, and other
+// methods of DaxSrc.
+// If the option is not configured, this method returns false as the second
+// result.
+func (conn *DaxConn) Meta(name string) (OptMeta, bool) {
+	conn.ds.parseLazily()
+	conn.ds.mutex.RLock()
+	defer conn.ds.mutex.RUnlock()
+
+	for _, cfg := range conn.ds.optCfgs {
+		if cfg.Name != name {
+			continue
+		}
+		m := conn.ds.metas[name]
+		defaults := cfg.Default
+		if defaults == nil {
+			defaults = m.defaults
+		}
+		return OptMeta{
+			Choices: m.choices.values,
+			Metavar: m.metavar,
+			EnvVar:  m.envVar,
+			Default: strings.Join(defaults, ","),
+			Group:   m.optGroup(),
+			Hidden:  m.hidden,
+			Secret:  m.secret,
+			Kind:    m.kind,
+
+			BoolValue: m.boolValue,
+			NArgs:     m.nargs,
+			NArgsEnd:  m.nargsEnd,
+			Since:     m.since,
+			Literal:   m.literal,
+
+			Required:    m.required,
+			Deprecated:  m.deprecated,
+			Deprecation: m.deprecation,
+		}, true
+	}
+	return OptMeta{}, false
+}
+
+// mergeOptMeta merges the metadata set by SetMeta method into the option
+// configuration and its metadata.
+func mergeOptMeta(cfg *cliargs.OptCfg, m *optMeta, meta OptMeta) {
+	if len(meta.Choices) > 0 {
+		m.choices = optChoices{values: meta.Choices}
+	}
+	if len(meta.Metavar) > 0 && cfg.HasArg {
+		m.metavar = meta.Metavar
+		if len(m.choices.values) == 0 {
+			cfg.ArgHelp = metavarArgHelp(meta.Metavar)
+		}
+	}
+	if len(meta.EnvVar) > 0 {
+		m.envVar = meta.EnvVar
+	}
+	if len(meta.Default) > 0 {
+		switch {
+		case !cfg.HasArg:
+			m.defaults = []string{meta.Default}
+		case cfg.IsArray:
+			cfg.Default = splitDefault(meta.Default)
+			m.defaults = cfg.Default
+		default:
+			cfg.Default = []string{meta.Default}
+			m.defaults = cfg.Default
+		}
+	}
+	if len(meta.Group) > 0 {
+		m.section = meta.Group
+	}
+	if meta.Hidden {
+		m.hidden = true
+	}
+	if meta.Secret {
+		m.secret = true
+	}
+	if meta.Kind != reflect.Invalid {
+		m.kind = meta.Kind
+	}
+	if meta.BoolValue && !cfg.HasArg {
+		m.boolValue = true
+	}
+	if cfg.HasArg {
+		setNArgs(m, meta.NArgs, meta.NArgsEnd, m.kind)
+	}
+	if len(meta.Since) > 0 {
+		m.since = meta.Since
+	}
+	if meta.Literal {
+		m.literal = true
+	}
+	if meta.Required {
+		m.required = true
+	}
+	if meta.Deprecated {
+		m.deprecated = true
+		m.deprecation = meta.Deprecation
+	}
+}
+
+// checkMetas checks that the options of the metadata set by SetMeta method
+// are configured in this DaxSrc or in its sub commands.
+func (ds *DaxSrc) checkMetas() errs.Err {
+	if len(ds.optMetas) == 0 {
+		return errs.Ok()
+	}
+
+	names := make(map[string]bool)
+	addNames := func(
+		cfgs []cliargs.OptCfg, stores []optStore, extra []cliargs.OptCfg,
+	) {
+		if len(stores) > 0 {
+			cfgs, _, _, _ = ds.buildOptCfgsWithExtra(stores, extra)
+		}
+		for _, cfg := range cfgs {
+			names[cfg.Name] = true
+		}
+	}
+	addNames(ds.optCfgs, ds.optionStores(), ds.extraOptCfgs)
+	for _, sub := range ds.subCmds {
+		addNames(sub.optCfgs, storesOf(sub.options, nil), nil)
+	}
+	for _, opt := range []*cliargs.OptCfg{ds.helpOpt, ds.versionOpt, ds.configOpt} {
+		if opt != nil {
+			names[opt.Name] = true
+		}
+	}
+
+	var unknown []string
+	for name := range ds.optMetas {
+		if !names[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return errs.New(MetaForUnconfiguredOption{Option: unknown[0]})
+	}
+	return errs.Ok()
+}
@@ -0,0 +1,132 @@
+package cliargdax_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+)
+
+func TestMeta_optCfgs(t *testing.T) {
+	t.Setenv("MY_APP_LEVEL", "3")
+
+	cfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "format", HasArg: true},
+		cliargs.OptCfg{Name: "level", HasArg: true},
+		cliargs.OptCfg{Name: "debug"},
+	}
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{"/path/to/app"}, cfgs).
+		SetMeta("format", cliargdax.OptMeta{
+			Choices: []string{"json", "yaml"}, Default: "json",
+		}).
+		SetMeta("level", cliargdax.OptMeta{EnvVar: "MY_APP_LEVEL"}).
+		SetMeta("debug", cliargdax.OptMeta{Hidden: true})
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	cmd := conn.Cmd()
+	assert.Equal(t, cmd.OptArg("format"), "json")
+	assert.Equal(t, cmd.OptArg("level"), "3")
+	assert.Equal(t, conn.OptSource("format"), cliargdax.SourceDefault)
+	assert.Equal(t, conn.OptSource("level"), cliargdax.SourceEnv)
+
+	m, ok := conn.Meta("format")
+	assert.True(t, ok)
+	assert.Equal(t, m, cliargdax.OptMeta{
+		Choices: []string{"json", "yaml"}, Default: "json",
+	})
+
+	m, ok = conn.Meta("level")
+	assert.True(t, ok)
+	assert.Equal(t, m, cliargdax.OptMeta{EnvVar: "MY_APP_LEVEL"})
+
+	m, ok = conn.Meta("debug")
+	assert.True(t, ok)
+	assert.True(t, m.Hidden)
+
+	_, ok = conn.Meta("unknown")
+	assert.False(t, ok)
+
+	help := conn.HelpText()
+	assert.True(t, strings.Contains(help, "--format {json|yaml}"))
+	assert.False(t, strings.Contains(help, "--debug"))
+}
+
+func TestMeta_choicesAreValidated(t *testing.T) {
+	cfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "format", HasArg: true},
+	}
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"/path/to/app", "--format=xml"}, cfgs,
+	).
+		SetMeta("format", cliargdax.OptMeta{Choices: []string{"json", "yaml"}})
+
+	_, err := setupWithOptCfgs(t, ds)
+	switch r := err.Reason().(type) {
+	case cliargdax.OptionValueNotInChoices:
+		assert.Equal(t, r.Option, "format")
+		assert.Equal(t, r.Value, "xml")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestMeta_optionStore(t *testing.T) {
+	options := struct {
+		Format string   `optcfg:"format" optchoices:"json,yaml,table"`
+		Tags   []string `optcfg:"tag"`
+	}{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions([]string{"/path/to/app"}, &options).
+		SetMeta("format", cliargdax.OptMeta{Default: "yaml"}).
+		SetMeta("tag", cliargdax.OptMeta{Default: "a,b"})
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, options.Format, "yaml")
+	assert.Equal(t, options.Tags, []string{"a", "b"})
+
+	m, ok := conn.Meta("format")
+	assert.True(t, ok)
+	assert.Equal(t, m.Choices, []string{"json", "yaml", "table"})
+	assert.Equal(t, m.Default, "yaml")
+}
+
+func TestMeta_unconfiguredOption(t *testing.T) {
+	cfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "format", HasArg: true},
+	}
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{"/path/to/app"}, cfgs).
+		SetMeta("fromat", cliargdax.OptMeta{Default: "json"})
+
+	_, err := setupWithOptCfgs(t, ds)
+	switch r := err.Reason().(type) {
+	case cliargdax.MetaForUnconfiguredOption:
+		assert.Equal(t, r.Option, "fromat")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestMeta_subCommand(t *testing.T) {
+	defer resetOsArgs()
+	os.Args = []string{"/path/to/app", "build", "--target=c"}
+
+	ds := cliargdax.NewDaxSrcWithSubCmds(map[string][]cliargs.OptCfg{
+		"build": []cliargs.OptCfg{
+			cliargs.OptCfg{Name: "target", HasArg: true},
+		},
+	}).
+		SetMeta("target", cliargdax.OptMeta{Choices: []string{"a", "b"}})
+
+	_, err := setupWithOptCfgs(t, ds)
+	switch r := err.Reason().(type) {
+	case cliargdax.OptionValueNotInChoices:
+		assert.Equal(t, r.Option, "target")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
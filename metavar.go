@@ -0,0 +1,53 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"strings"
+
+	"github.com/sttk/cliargs"
+)
+
+// metavarArgHelp makes the argument help from the metavar, like <FILE>.
+func metavarArgHelp(metavar string) string {
+	return "<" + metavar + ">"
+}
+
+// defaultMetavar makes the metavar of the option from its name, like
+// LOG_LEVEL for log-level.
+func defaultMetavar(name string) string {
+	return strings.ToUpper(strings.ReplaceAll(name, "-", "_"))
+}
+
+// argPlaceholder makes the placeholder of the option argument, which is the
+// argument help of the option, or its default metavar, like <LOG_LEVEL>, if
+// the argument help is empty.
+func argPlaceholder(cfg cliargs.OptCfg) string {
+	if len(cfg.ArgHelp) > 0 {
+		return cfg.ArgHelp
+	}
+	return metavarArgHelp(defaultMetavar(cfg.Name))
+}
+
+// argLabel makes the placeholder of the option argument followed by "..." if
+// the option is an array, like <FILE>...
+func argLabel(cfg cliargs.OptCfg) string {
+	s := argPlaceholder(cfg)
+	if cfg.IsArray {
+		s += "..."
+	}
+	return s
+}
+
+// argMetavar retrieves the metavar from the placeholder of the option
+// argument, like FILE for <FILE>, or the default metavar if the placeholder is
+// not in angle brackets.
+func argMetavar(cfg cliargs.OptCfg) string {
+	a := argPlaceholder(cfg)
+	if len(a) > 2 && a[0] == '<' && a[len(a)-1] == '>' {
+		return a[1 : len(a)-1]
+	}
+	return defaultMetavar(cfg.Name)
+}
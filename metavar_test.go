@@ -0,0 +1,82 @@
+package cliargdax_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+)
+
+type metavarOptions struct {
+	Output   string   `optcfg:"output,o" optmetavar:"FILE" optdesc:"Output file."`
+	Count    int      `optcfg:"count" optmetavar:"N" optdesc:"Count."`
+	Includes []string `optcfg:"include,I" optmetavar:"DIR" optdesc:"Include dir."`
+	LogLevel string   `optcfg:"log-level" optdesc:"Log level."`
+	Format   string   `optcfg:"format" optmetavar:"FMT" optchoices:"json,yaml"`
+	Verbose  bool     `optcfg:"verbose" optmetavar:"X"`
+}
+
+func TestMetavar_HelpText(t *testing.T) {
+	options := metavarOptions{}
+	conn, err := setupForOptions(t, []string{"app"}, &options)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.HelpText(), `Usage: app [OPTIONS]
+
+Options:
+  -o, --output <FILE>           Output file.
+      --count <N>               Count.
+  -I, --include <DIR>...        Include dir.
+      --log-level <LOG_LEVEL>   Log level.
+      --format {json|yaml}
+      --verbose
+`)
+	assert.Equal(t, conn.UsageLine(),
+		"Usage: app [-o <FILE>] [--count <N>] [-I <DIR>]... "+
+			"[--log-level <LOG_LEVEL>] [--format {json|yaml}] [--verbose]")
+
+	meta, ok := conn.Meta("output")
+	assert.True(t, ok)
+	assert.Equal(t, meta.Metavar, "FILE")
+	meta, ok = conn.Meta("verbose")
+	assert.True(t, ok)
+	assert.Equal(t, meta.Metavar, "")
+}
+
+func TestMetavar_optargTakesPrecedence(t *testing.T) {
+	type Options struct {
+		Output string `optcfg:"output" optarg:"<path>" optmetavar:"FILE"`
+	}
+	options := Options{}
+	conn, err := setupForOptions(t, []string{"app"}, &options)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.UsageLine(), "Usage: app [--output <path>]")
+}
+
+func TestMetavar_SetMeta(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{"app"},
+		[]cliargs.OptCfg{
+			cliargs.OptCfg{Name: "output", HasArg: true},
+			cliargs.OptCfg{Name: "file", HasArg: true, IsArray: true},
+		}).
+		SetMeta("output", cliargdax.OptMeta{Metavar: "PATH"}).
+		SetMeta("file", cliargdax.OptMeta{Metavar: "FILE"})
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.UsageLine(),
+		"Usage: app [--output <PATH>] [--file <FILE>]...")
+
+	var b bytes.Buffer
+	err = conn.GenMarkdownDoc(&b)
+	assert.True(t, err.IsOk())
+	assert.True(t, strings.Contains(b.String(), "### `--output <PATH>`\n"))
+	assert.True(t, strings.Contains(b.String(), "### `--file <FILE>...`\n"))
+
+	b.Reset()
+	err = conn.GenCompletion("zsh", &b)
+	assert.True(t, err.IsOk())
+	assert.True(t, strings.Contains(b.String(), "'--output[]:PATH:_files' \\\n"))
+	assert.True(t, strings.Contains(b.String(), "'--file[]:FILE:_files' \\\n"))
+}
@@ -0,0 +1,185 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/sttk/cliargs"
+	"github.com/sttk/sabi/errs"
+)
+
+type /* error reasons */ (
+	// DerivedOptNameCollides is the error reason which indicates that an
+	// option name derived from a field name of an option store by the name
+	// mapping is same as the option name of another field.
+	// The fields Option, Field, and OtherField are the option name and the
+	// paths of the two fields, like Server.Port.
+	DerivedOptNameCollides struct {
+		Option     string
+		Field      string
+		OtherField string
+	}
+)
+
+// NameMapping is the type of the convention to derive an option name from the
+// name of a field of an option store which has no option name in the optcfg
+// struct tag.
+type NameMapping int
+
+const (
+	// KebabCase derives an option name by joining the lower-cased words of the
+	// field name with hyphens, like log-level for LogLevel and server-url for
+	// ServerURL.
+	// This is the default mapping.
+	KebabCase NameMapping = iota
+
+	// LowerCamelCase derives an option name by lower-casing the first word of
+	// the field name and capitalizing the other words, like logLevel for
+	// LogLevel and serverUrl for ServerURL.
+	LowerCamelCase
+
+	// FieldNameAsIs uses the field name as it is as the option name, like
+	// LogLevel, and the lower-cased field name as the prefix of the options in
+	// a nested struct.
+	FieldNameAsIs
+)
+
+// NameMapping is the method to set the convention to derive option names from
+// the names of the fields of option stores which have no option name in the
+// optcfg struct tag.
+// The mapping is KebabCase by default, and an option name in the optcfg
+// struct tag is always used as it is.
+// The mapping is also applied to the default prefixes of the options in nested
+// structs, like log-config- for the field LogConfig.
+// Since cliargs package allows only alphanumerics and hyphens in option names,
+// snake_case is not available.
+// If a derived option name is same as another option name, the Setup method
+// of this DaxSrc returns an errs.Err with the reason: DerivedOptNameCollides.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) NameMapping(mapping NameMapping) *DaxSrc {
+	ds.nameMapping = mapping
+	return ds
+}
+
+// optName derives an option name from the field name.
+func (mapping NameMapping) optName(field string) string {
+	switch mapping {
+	case FieldNameAsIs:
+		return field
+	case LowerCamelCase:
+		words := splitWords(field)
+		for i, w := range words {
+			w = strings.ToLower(w)
+			if i > 0 && len(w) > 0 {
+				w = strings.ToUpper(w[:1]) + w[1:]
+			}
+			words[i] = w
+		}
+		return strings.Join(words, "")
+	default:
+		words := splitWords(field)
+		for i, w := range words {
+			words[i] = strings.ToLower(w)
+		}
+		return strings.Join(words, "-")
+	}
+}
+
+// prefix derives the default prefix of the options in a nested struct from
+// the field name.
+func (mapping NameMapping) prefix(field string) string {
+	if mapping == FieldNameAsIs {
+		return strings.ToLower(field) + "-"
+	}
+	return mapping.optName(field) + "-"
+}
+
+// splitWords splits the field name into words at the boundaries of the
+// camel case and at underscores, keeping initialisms like URL and ID as
+// single words and digits with the preceding word, like HTTP2 of HTTP2Port.
+func splitWords(name string) []string {
+	rs := []rune(name)
+	var words []string
+	start := 0
+	for i, r := range rs {
+		if r == '_' {
+			if i > start {
+				words = append(words, string(rs[start:i]))
+			}
+			start = i + 1
+			continue
+		}
+		if i == start || !unicode.IsUpper(r) {
+			continue
+		}
+		prev := rs[i-1]
+		nextIsLower := i+1 < len(rs) && unicode.IsLower(rs[i+1])
+		if unicode.IsLower(prev) || unicode.IsDigit(prev) ||
+			(unicode.IsUpper(prev) && nextIsLower) {
+			words = append(words, string(rs[start:i]))
+			start = i
+		}
+	}
+	if start < len(rs) {
+		words = append(words, string(rs[start:]))
+	}
+	return words
+}
+
+// derivedNameTag returns the struct field of which optcfg struct tag has the
+// option name derived from the field name if the tag has no option name, and
+// the derived name.
+// If the tag has an option name or the mapping is FieldNameAsIs, this
+// function returns the struct field as it is and an empty string.
+func derivedNameTag(
+	sf reflect.StructField, mapping NameMapping,
+) (reflect.StructField, string) {
+	if mapping == FieldNameAsIs {
+		return sf, ""
+	}
+
+	opt := sf.Tag.Get("optcfg")
+	arr := strings.SplitN(opt, "=", 2)
+	names := strings.Split(arr[0], ",")
+	if len(names[0]) > 0 {
+		return sf, ""
+	}
+	names[0] = mapping.optName(sf.Name)
+	arr[0] = strings.Join(names, ",")
+	opt = strings.Join(arr, "=")
+
+	sf.Tag = reflect.StructTag("optcfg:" + strconv.Quote(opt) + " " +
+		string(sf.Tag))
+	return sf, names[0]
+}
+
+// checkDerivedNames checks that no option name derived from a field name is
+// same as the option name or an alias of another field.
+func checkDerivedNames(
+	optCfgs []cliargs.OptCfg, fields []storeField, derived []bool,
+) errs.Err {
+	owners := make(map[string]int, len(optCfgs))
+	for i, cfg := range optCfgs {
+		for _, name := range append([]string{cfg.Name}, cfg.Aliases...) {
+			j, exists := owners[name]
+			if !exists {
+				owners[name] = i
+				continue
+			}
+			if j != i && (derived[i] || derived[j]) {
+				return errs.New(DerivedOptNameCollides{
+					Option:     name,
+					Field:      fields[j].path(),
+					OtherField: fields[i].path(),
+				})
+			}
+		}
+	}
+	return errs.Ok()
+}
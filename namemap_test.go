@@ -0,0 +1,98 @@
+package cliargdax_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+)
+
+type nameMapOptions struct {
+	LogLevel  string
+	ServerURL string `optcfg:",u"`
+	UserID    int
+	HTTP2Port int
+	Verbose   bool `optcfg:"verbose,v"`
+	LogConfig struct {
+		MaxSize int
+	}
+}
+
+func nameMapOptNames(conn *cliargdax.DaxConn) []string {
+	names := make([]string, 0)
+	for _, cfg := range conn.OptCfgs() {
+		names = append(names, cfg.Name)
+	}
+	return names
+}
+
+func TestNameMapping_kebabCase(t *testing.T) {
+	options := nameMapOptions{}
+	conn, err := setupForOptions(t, []string{
+		"app", "--log-level=debug", "-u", "http://x", "--user-id=7",
+		"--http2-port=8443", "-v", "--log-config-max-size=10",
+	}, &options)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, nameMapOptNames(conn), []string{
+		"log-level", "server-url", "user-id", "http2-port", "verbose",
+		"log-config-max-size",
+	})
+	assert.Equal(t, options.LogLevel, "debug")
+	assert.Equal(t, options.ServerURL, "http://x")
+	assert.Equal(t, options.UserID, 7)
+	assert.Equal(t, options.HTTP2Port, 8443)
+	assert.True(t, options.Verbose)
+	assert.Equal(t, options.LogConfig.MaxSize, 10)
+}
+
+func TestNameMapping_lowerCamelCase(t *testing.T) {
+	options := nameMapOptions{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions([]string{
+		"app", "--logLevel=info", "--serverUrl=http://y",
+	}, &options).NameMapping(cliargdax.LowerCamelCase)
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, nameMapOptNames(conn), []string{
+		"logLevel", "serverUrl", "userId", "http2Port", "verbose",
+		"logConfig-maxSize",
+	})
+	assert.Equal(t, options.LogLevel, "info")
+	assert.Equal(t, options.ServerURL, "http://y")
+}
+
+func TestNameMapping_fieldNameAsIs(t *testing.T) {
+	options := nameMapOptions{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions([]string{
+		"app", "--LogLevel=warn",
+	}, &options).NameMapping(cliargdax.FieldNameAsIs)
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, nameMapOptNames(conn), []string{
+		"LogLevel", "ServerURL", "UserID", "HTTP2Port", "verbose",
+		"logconfig-MaxSize",
+	})
+	assert.Equal(t, options.LogLevel, "warn")
+}
+
+func TestNameMapping_collision(t *testing.T) {
+	type Options struct {
+		ServerURL string
+		ServerUrl string
+	}
+	_, err := setupForOptions(t, []string{"app"}, &Options{})
+	assert.Equal(t, err.Reason(), cliargdax.DerivedOptNameCollides{
+		Option: "server-url", Field: "ServerURL", OtherField: "ServerUrl",
+	})
+	assert.Equal(t, cliargdax.FormatError(err, nil),
+		"option --server-url is configured by both field ServerURL and field "+
+			"ServerUrl\nTry '--help' for more information.")
+
+	type Explicit struct {
+		Level    string `optcfg:"log-level"`
+		LogLevel string
+	}
+	_, err = setupForOptions(t, []string{"app"}, &Explicit{})
+	assert.Equal(t, err.Reason(), cliargdax.DerivedOptNameCollides{
+		Option: "log-level", Field: "Level", OtherField: "LogLevel",
+	})
+}
@@ -0,0 +1,388 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/sttk/cliargs"
+	"github.com/sttk/sabi/errs"
+)
+
+type /* error reasons */ (
+	// NotEnoughOptionArgs is the error reason which indicates that an option
+	// which takes multiple option arguments per occurrence, like --map SRC DST,
+	// is not followed by enough command line arguments, or an option or the
+	// option terminator is in the middle of them.
+	// The fields Option, Expected, and Found are the option name, the number of
+	// the option arguments the option takes, and the number of the option
+	// arguments given.
+	NotEnoughOptionArgs struct {
+		Option   string
+		Expected int
+		Found    int
+	}
+
+	// IllegalOptionNArgs is the error reason which indicates that the optnargs
+	// struct tag of a field of an option store is invalid, because it is
+	// neither a positive integer, "*", nor "+", or the field is not a slice.
+	// The fields Option, Field, and NArgs are the option name, the field name,
+	// and the value of the struct tag.
+	IllegalOptionNArgs struct {
+		Option string
+		Field  string
+		NArgs  string
+	}
+)
+
+const (
+	// ZeroOrMoreArgs is the value of OptMeta#NArgs which makes the option take
+	// all the following command line arguments until the next option, and
+	// is same as the struct tag: optnargs:"*".
+	ZeroOrMoreArgs = -1
+
+	// OneOrMoreArgs is same as ZeroOrMoreArgs but the option requires at least
+	// one option argument, and is same as the struct tag: optnargs:"+".
+	OneOrMoreArgs = -2
+)
+
+// makeNArgs sets the number of the option arguments per occurrence specified
+// with the optnargs struct tag, and the sentinel argument specified with the
+// optnargsend struct tag, to the metadata of the option.
+func makeNArgs(
+	cfg *cliargs.OptCfg, m *optMeta, t reflect.Type, nargs, end string,
+) errs.Err {
+	var n int
+	switch nargs {
+	case "*":
+		n = ZeroOrMoreArgs
+	case "+":
+		n = OneOrMoreArgs
+	default:
+		var e error
+		n, e = strconv.Atoi(nargs)
+		if e != nil {
+			return errs.New(IllegalOptionNArgs{
+				Option: cfg.Name, Field: m.field, NArgs: nargs,
+			}, e)
+		}
+		if n < 1 {
+			return errs.New(IllegalOptionNArgs{
+				Option: cfg.Name, Field: m.field, NArgs: nargs,
+			})
+		}
+	}
+	if !cfg.HasArg || t.Kind() != reflect.Slice {
+		return errs.New(IllegalOptionNArgs{
+			Option: cfg.Name, Field: m.field, NArgs: nargs,
+		})
+	}
+	setNArgs(m, n, end, t.Elem().Kind())
+	return errs.Ok()
+}
+
+// setNArgs sets the number of the option arguments per occurrence to the
+// metadata of the option, and the sentinel argument if the option takes a
+// variable number of option arguments.
+// The option arguments of a variadic option can start with "-" if the kind of
+// the values is a number.
+func setNArgs(m *optMeta, n int, end string, kind reflect.Kind) {
+	switch {
+	case n > 1:
+		m.nargs = n
+	case n == ZeroOrMoreArgs || n == OneOrMoreArgs:
+		m.nargs = n
+		m.nargsEnd = end
+		m.nargsNumeric = isNumberKind(kind)
+	}
+}
+
+func isNumberKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64, reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+func (m optMeta) hasNArgs() bool {
+	return m.nargs > 1 || m.isVariadic()
+}
+
+func (m optMeta) isVariadic() bool {
+	return m.nargs == ZeroOrMoreArgs || m.nargs == OneOrMoreArgs
+}
+
+func hasNArgs(metas map[string]optMeta) bool {
+	for _, m := range metas {
+		if m.hasNArgs() {
+			return true
+		}
+	}
+	return false
+}
+
+// variadicOf returns the function which reports whether the option of the
+// specified name takes a variable number of option arguments, for
+// argScanner.
+// If no option is variadic, this function returns nil.
+func variadicOf(metas map[string]optMeta) func(string) (optMeta, bool) {
+	for _, m := range metas {
+		if m.isVariadic() {
+			return func(name string) (optMeta, bool) {
+				v := metas[name]
+				return v, v.isVariadic()
+			}
+		}
+	}
+	return nil
+}
+
+// scanVariadic moves the command line arguments following the variadic option
+// at the index i into the token, and returns the index of the last argument
+// moved.
+// If the sentinel argument is specified, all the arguments until it are
+// moved and the sentinel argument is dropped, otherwise the arguments until
+// the next option or the option terminator are moved, in which negative
+// numbers are not options if the values of the option are numbers.
+func (sc argScanner) scanVariadic(
+	osArgs []string, i int, tok *argToken, m optMeta,
+) int {
+	var values []string
+	if tok.hasValue {
+		values = append(values, tok.value)
+	}
+	for ; i+1 < len(osArgs); i++ {
+		arg := osArgs[i+1]
+		if len(m.nargsEnd) > 0 {
+			if arg == m.nargsEnd {
+				i++
+				break
+			}
+		} else if sc.isOptLike(i+1, arg) && !(m.nargsNumeric && isNumber(arg)) {
+			break
+		}
+		values = append(values, arg)
+	}
+	tok.isVariadic = true
+	if len(values) > 0 {
+		tok.value = values[0]
+		tok.hasValue = true
+		tok.extraValues = values[1:]
+	}
+	return i
+}
+
+// isOptLike reports whether the command line argument is an option or the
+// option terminator.
+func (sc argScanner) isOptLike(i int, arg string) bool {
+	if strings.HasPrefix(arg, "-") && len(arg) > 1 {
+		return !(sc.negativeNumbers && isNegativeNumber(arg))
+	}
+	_, ok := sc.scanSlashOpt(i, arg)
+	return ok
+}
+
+func isNumber(s string) bool {
+	_, e := strconv.ParseFloat(s, 64)
+	return e == nil
+}
+
+// consumeNArgs moves the command arguments following each occurrence of the
+// options which take a fixed number of option arguments into the token of the
+// occurrence.
+// If the command arguments are not enough, or a variadic option which
+// requires at least one option argument is given without it, this method
+// returns an errs.Err with the reason: NotEnoughOptionArgs.
+func (r *parseResult) consumeNArgs(toks []argToken) ([]argToken, errs.Err) {
+	if !hasNArgs(r.metas) {
+		return toks, errs.Ok()
+	}
+	consumed := make([]argToken, 0, len(toks))
+	for i := 0; i < len(toks); i++ {
+		tok := toks[i]
+		n := r.metas[tok.name].nargs
+		if tok.isVariadic && n == OneOrMoreArgs && !tok.hasValue {
+			return nil, errs.New(NotEnoughOptionArgs{
+				Option: tok.name, Expected: 1, Found: 0,
+			})
+		}
+		if !tok.isOpt() || n < 2 {
+			consumed = append(consumed, tok)
+			continue
+		}
+		found := 0
+		if tok.hasValue {
+			found = 1
+		}
+		for found > 0 && found < n && i+1 < len(toks) {
+			next := toks[i+1]
+			if next.isOpt() || next.isTerm {
+				break
+			}
+			tok.extraValues = append(tok.extraValues, next.value)
+			found++
+			i++
+		}
+		if found < n {
+			return nil, errs.New(NotEnoughOptionArgs{
+				Option: tok.name, Expected: n, Found: found,
+			})
+		}
+		consumed = append(consumed, tok)
+	}
+	return consumed, errs.Ok()
+}
+
+// nargsCfgs returns a copy of the option configurations in which the options
+// taking multiple option arguments are arrays, to receive all the option
+// arguments of each occurrence.
+// The options which are not arrays and are given more than once are left as
+// they are, to fail with the reason: OptionGivenTwice.
+func (r *parseResult) nargsCfgs(optCfgs []cliargs.OptCfg) []cliargs.OptCfg {
+	if !hasNArgs(r.metas) {
+		return optCfgs
+	}
+	cfgs := append([]cliargs.OptCfg{}, optCfgs...)
+	for i, cfg := range cfgs {
+		if !r.metas[cfg.Name].hasNArgs() {
+			continue
+		}
+		if _, dup := r.duplicates[cfg.Name]; !dup {
+			cfgs[i].IsArray = true
+		}
+	}
+	return cfgs
+}
+
+// countNArgs corrects the numbers of the occurrences of the options taking
+// multiple option arguments, of which each occurrence is counted once for
+// each option argument, with the tokens.
+func countNArgs(
+	counts map[string]int, metas map[string]optMeta, toks []argToken,
+) {
+	occurrences := make(map[string]int)
+	for _, tok := range toks {
+		if tok.isOpt() {
+			occurrences[tok.name]++
+		}
+	}
+	for name, m := range metas {
+		switch {
+		case !m.hasNArgs():
+		case occurrences[name] > 0:
+			counts[name] = occurrences[name]
+		case m.nargs > 1:
+			counts[name] = (counts[name] + m.nargs - 1) / m.nargs
+		}
+	}
+}
+
+// tupleSizes returns the numbers of the option arguments of the occurrences
+// of each variadic option in the tokens.
+func tupleSizes(toks []argToken) map[string][]int {
+	var sizes map[string][]int
+	for _, tok := range toks {
+		if !tok.isVariadic {
+			continue
+		}
+		if sizes == nil {
+			sizes = make(map[string][]int)
+		}
+		n := 0
+		if tok.hasValue {
+			n = 1 + len(tok.extraValues)
+		}
+		sizes[tok.name] = append(sizes[tok.name], n)
+	}
+	return sizes
+}
+
+// addEmptyVariadics makes a cliargs.Cmd in which the variadic options given
+// without option arguments are given with no option argument.
+// If there is no such option, this function returns the cliargs.Cmd as it is.
+func addEmptyVariadics(
+	cmd cliargs.Cmd, optCfgs []cliargs.OptCfg, toks []argToken,
+) cliargs.Cmd {
+	var empty []string
+	for _, tok := range toks {
+		if tok.isVariadic && !tok.hasValue && !cmd.HasOpt(tok.name) {
+			empty = append(empty, tok.name)
+		}
+	}
+	if len(empty) == 0 {
+		return cmd
+	}
+	opts := make(map[string][]string)
+	for _, name := range cmdOptNames(cmd, optCfgs, nil) {
+		opts[name] = cmd.OptArgs(name)
+	}
+	for _, name := range empty {
+		opts[name] = []string{}
+	}
+	if addedCmd, e := makeCmd(cmd.Name, cmd.Args(), opts); e == nil {
+		return addedCmd
+	}
+	return cmd
+}
+
+// OptTuples is the method to retrieve the option arguments of the option
+// grouped per occurrence, for an option which takes multiple option arguments
+// per occurrence with the optnargs struct tag or OptMeta#NArgs, like
+// [["a", "b"], ["c", "d"]] for --map a b --map c d.
+// For an option which takes an option argument per occurrence, each group has
+// an option argument.
+// For a variadic option, each group has the option arguments of an
+// occurrence, which can be empty, and the option arguments not given in
+// command line arguments, like ones of an environment variable, are in a
+// group.
+// If the option is not given, this method returns an empty array.
+func (conn *DaxConn) OptTuples(name string) [][]string {
+	conn.ds.parseLazily()
+	conn.ds.mutex.RLock()
+	defer conn.ds.mutex.RUnlock()
+
+	cmd := conn.overriddenCmd()
+	a := cmd.OptArgs(name)
+	m := conn.ds.metas[name]
+	if m.isVariadic() && cmd.HasOpt(name) {
+		return variadicTuples(a, conn.ds.tupleSizes[name])
+	}
+
+	n := m.nargs
+	if n < 1 {
+		n = 1
+	}
+	tuples := make([][]string, 0, (len(a)+n-1)/n)
+	for i := 0; i < len(a); i += n {
+		j := i + n
+		if j > len(a) {
+			j = len(a)
+		}
+		tuples = append(tuples, append([]string{}, a[i:j]...))
+	}
+	return tuples
+}
+
+func variadicTuples(a []string, sizes []int) [][]string {
+	total := 0
+	for _, n := range sizes {
+		total += n
+	}
+	if total != len(a) || len(sizes) == 0 {
+		return [][]string{append([]string{}, a...)}
+	}
+	tuples := make([][]string, len(sizes))
+	i := 0
+	for k, n := range sizes {
+		tuples[k] = append([]string{}, a[i:i+n]...)
+		i += n
+	}
+	return tuples
+}
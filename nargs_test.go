@@ -0,0 +1,248 @@
+package cliargdax_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+)
+
+type nargsOptions struct {
+	Map     []string `optcfg:"map,m" optnargs:"2"`
+	Verbose bool     `optcfg:"verbose,v"`
+}
+
+func TestNArgs_optnargs(t *testing.T) {
+	options := nargsOptions{}
+	conn, err := setupForOptions(t, []string{
+		"app", "--map", "a", "b", "x", "-m=c", "d", "--verbose", "y",
+	}, &options)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, options.Map, []string{"a", "b", "c", "d"})
+	assert.True(t, options.Verbose)
+	assert.Equal(t, conn.Cmd().OptArgs("map"), []string{"a", "b", "c", "d"})
+	assert.Equal(t, conn.OptTuples("map"), [][]string{{"a", "b"}, {"c", "d"}})
+	assert.Equal(t, conn.OptCount("map"), 2)
+	assert.Equal(t, conn.Cmd().Args(), []string{"x", "y"})
+
+	assert.Equal(t, conn.OptTuples("verbose"), [][]string{})
+	meta, _ := conn.Meta("map")
+	assert.Equal(t, meta.NArgs, 2)
+}
+
+func TestNArgs_notEnough(t *testing.T) {
+	cases := []struct {
+		args  []string
+		found int
+	}{
+		{args: []string{"app", "--map", "a"}, found: 1},
+		{args: []string{"app", "--map"}, found: 0},
+		{args: []string{"app", "--map", "a", "--verbose", "b"}, found: 1},
+		{args: []string{"app", "--map", "a", "--", "b"}, found: 1},
+	}
+	for _, c := range cases {
+		options := nargsOptions{}
+		_, err := setupForOptions(t, c.args, &options)
+		assert.Equal(t, err.Reason(), cliargdax.NotEnoughOptionArgs{
+			Option: "map", Expected: 2, Found: c.found,
+		}, c.args)
+	}
+
+	options := nargsOptions{}
+	_, err := setupForOptions(t, []string{"app", "-m", "a"}, &options)
+	assert.Equal(t, cliargdax.FormatError(err, nil),
+		"option --map requires 2 arguments but 1 given\n"+
+			"Try '--help' for more information.")
+}
+
+func TestNArgs_OptMeta(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "range", HasArg: true},
+		cliargs.OptCfg{Name: "point", HasArg: true, IsArray: true},
+	}
+
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "--range", "1", "9", "--point=1", "2", "3", "--point", "4", "5", "6",
+	}, optCfgs).
+		SetMeta("range", cliargdax.OptMeta{NArgs: 2}).
+		SetMeta("point", cliargdax.OptMeta{NArgs: 3})
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.OptTuples("range"), [][]string{{"1", "9"}})
+	assert.Equal(t, conn.OptTuples("point"),
+		[][]string{{"1", "2", "3"}, {"4", "5", "6"}})
+	assert.Equal(t, conn.Cmd().Args(), []string{})
+
+	ds = cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "--range", "1", "9", "--range", "2", "8",
+	}, optCfgs).SetMeta("range", cliargdax.OptMeta{NArgs: 2})
+	_, err = setupWithOptCfgs(t, ds)
+	assert.Equal(t, err.Reason(), cliargdax.OptionGivenTwice{
+		Option: "range", FirstIndex: 1, SecondIndex: 4,
+		FirstValue: "1", SecondValue: "2",
+	})
+
+	ds = cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "--range", "1", "9", "--range", "2", "8",
+	}, optCfgs).
+		SetMeta("range", cliargdax.OptMeta{NArgs: 2}).
+		OnDuplicate(cliargdax.LastWins)
+	conn, err = setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.OptTuples("range"), [][]string{{"2", "8"}})
+	assert.Equal(t, conn.Cmd().Args(), []string{})
+}
+
+func TestNArgs_illegalTag(t *testing.T) {
+	options0 := struct {
+		Map []string `optcfg:"map" optnargs:"x"`
+	}{}
+	_, err := setupForOptions(t, []string{"app"}, &options0)
+	assert.Equal(t, err.Reason(), cliargdax.IllegalOptionNArgs{
+		Option: "map", Field: "Map", NArgs: "x",
+	})
+
+	options1 := struct {
+		Map string `optcfg:"map" optnargs:"2"`
+	}{}
+	_, err = setupForOptions(t, []string{"app"}, &options1)
+	assert.Equal(t, err.Reason(), cliargdax.IllegalOptionNArgs{
+		Option: "map", Field: "Map", NArgs: "2",
+	})
+	assert.Equal(t, cliargdax.FormatError(err, nil),
+		"option --map has an illegal number of arguments\n"+
+			"Try '--help' for more information.")
+}
+
+type variadicOptions struct {
+	Files   []string  `optcfg:"files,f" optnargs:"*"`
+	Tags    []string  `optcfg:"tags" optnargs:"+"`
+	Offsets []int     `optcfg:"offsets" optnargs:"+"`
+	Names   []string  `optcfg:"names" optnargs:"*"`
+	Exec    []string  `optcfg:"exec" optnargs:"+" optnargsend:";"`
+	Ratios  []float64 `optcfg:"ratios" optnargs:"*"`
+	Verbose bool      `optcfg:"verbose,v"`
+}
+
+func TestNArgs_variadic(t *testing.T) {
+	options := variadicOptions{}
+	conn, err := setupForOptions(t, []string{
+		"app", "--files", "a", "b", "c", "--verbose", "-f=d", "-", "--", "x",
+	}, &options)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, options.Files, []string{"a", "b", "c", "d", "-"})
+	assert.True(t, options.Verbose)
+	assert.Equal(t, conn.OptTuples("files"),
+		[][]string{{"a", "b", "c"}, {"d", "-"}})
+	assert.Equal(t, conn.OptCount("files"), 2)
+	assert.Equal(t, conn.OptSource("files"), cliargdax.SourceAlias)
+	assert.Equal(t, conn.Cmd().Args(), []string{"x"})
+
+	options = variadicOptions{}
+	conn, err = setupForOptions(t, []string{
+		"app", "-vf", "a", "--files", "-v",
+	}, &options)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, options.Files, []string{"a"})
+	assert.Equal(t, conn.OptTuples("files"), [][]string{{"a"}, {}})
+	assert.Equal(t, conn.Cmd().Args(), []string{})
+}
+
+func TestNArgs_variadicEmpty(t *testing.T) {
+	options := variadicOptions{}
+	conn, err := setupForOptions(t, []string{"app", "--files", "-v"}, &options)
+	assert.True(t, err.IsOk())
+	assert.True(t, conn.Cmd().HasOpt("files"))
+	assert.Equal(t, conn.Cmd().OptArgs("files"), []string{})
+	assert.Equal(t, conn.OptTuples("files"), [][]string{{}})
+	assert.Equal(t, conn.OptSource("files"), cliargdax.SourceCLI)
+	assert.Equal(t, len(options.Files), 0)
+	assert.True(t, options.Verbose)
+
+	options = variadicOptions{}
+	_, err = setupForOptions(t, []string{"app", "--tags", "--verbose"}, &options)
+	assert.Equal(t, err.Reason(), cliargdax.NotEnoughOptionArgs{
+		Option: "tags", Expected: 1, Found: 0,
+	})
+	options = variadicOptions{}
+	_, err = setupForOptions(t, []string{"app", "--tags"}, &options)
+	assert.Equal(t, err.Reason(), cliargdax.NotEnoughOptionArgs{
+		Option: "tags", Expected: 1, Found: 0,
+	})
+}
+
+func TestNArgs_variadicNegativeNumbers(t *testing.T) {
+	options := variadicOptions{}
+	conn, err := setupForOptions(t, []string{
+		"app", "--offsets", "-1", "-2", "3", "--ratios", "-0.5", "1e3", "-v",
+	}, &options)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, options.Offsets, []int{-1, -2, 3})
+	assert.Equal(t, options.Ratios, []float64{-0.5, 1000})
+	assert.True(t, options.Verbose)
+	assert.Equal(t, conn.Cmd().Args(), []string{})
+
+	options = variadicOptions{}
+	_, err = setupForOptions(t, []string{
+		"app", "--names=-a", "b", "-1", "-1e5",
+	}, &options)
+	assert.Equal(t, err.Reason(), cliargs.OptionHasInvalidChar{Option: "1"})
+
+	options = variadicOptions{}
+	conn, err = setupForOptions(t, []string{
+		"app", "--names=-a", "b", "-v",
+	}, &options)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, options.Names, []string{"-a", "b"})
+	assert.Equal(t, conn.OptTuples("names"), [][]string{{"-a", "b"}})
+}
+
+func TestNArgs_variadicSentinel(t *testing.T) {
+	options := variadicOptions{}
+	conn, err := setupForOptions(t, []string{
+		"app", "--exec", "grep", "-v", "--", "x", ";", "-v", "y",
+		"--exec", "ls", "-l",
+	}, &options)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, options.Exec, []string{"grep", "-v", "--", "x", "ls", "-l"})
+	assert.Equal(t, conn.OptTuples("exec"),
+		[][]string{{"grep", "-v", "--", "x"}, {"ls", "-l"}})
+	assert.True(t, options.Verbose)
+	assert.Equal(t, conn.Cmd().Args(), []string{"y"})
+	assert.False(t, conn.HasTerminator())
+
+	options = variadicOptions{}
+	_, err = setupForOptions(t, []string{"app", "--exec", ";"}, &options)
+	assert.Equal(t, err.Reason(), cliargdax.NotEnoughOptionArgs{
+		Option: "exec", Expected: 1, Found: 0,
+	})
+}
+
+func TestNArgs_variadicOptMeta(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "offsets", HasArg: true, IsArray: true},
+		cliargs.OptCfg{Name: "cmd", HasArg: true},
+		cliargs.OptCfg{Name: "verbose"},
+	}
+
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "--offsets", "-1", "-2", "--cmd", "a", "-b", "+", "--verbose",
+	}, optCfgs).
+		SetMeta("offsets", cliargdax.OptMeta{
+			NArgs: cliargdax.ZeroOrMoreArgs, Kind: reflect.Int,
+		}).
+		SetMeta("cmd", cliargdax.OptMeta{
+			NArgs: cliargdax.OneOrMoreArgs, NArgsEnd: "+",
+		})
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.Cmd().OptArgs("offsets"), []string{"-1", "-2"})
+	assert.Equal(t, conn.Cmd().OptArgs("cmd"), []string{"a", "-b"})
+	assert.True(t, conn.Cmd().HasOpt("verbose"))
+
+	meta, _ := conn.Meta("cmd")
+	assert.Equal(t, meta.NArgs, cliargdax.OneOrMoreArgs)
+	assert.Equal(t, meta.NArgsEnd, "+")
+}
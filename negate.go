@@ -0,0 +1,176 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"reflect"
+	"strconv"
+
+	"github.com/sttk/cliargs"
+	"github.com/sttk/sabi/errs"
+)
+
+// Negatable is the method to make boolean options negatable.
+// A negatable option can be given also in the negated form: --no-name (or
+// --no-alias for an alias of which length is more than one), and the last
+// occurrence of the option and its negated form wins.
+// After parsing, the option is stored in cliargs.Cmd with the option name and
+// the resolved option argument: "true" or "false", which is also passed to
+// the event handler of the option.
+// Since the negated form takes no option argument, --no-name=value causes
+// an error: cliargs.OptionTakesNoArg.
+// This method works for options of which cliargs.OptCfg have no option
+// argument.
+// For option stores, the struct tag: optnegatable:"true" is also available
+// for bool fields.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) Negatable(names ...string) *DaxSrc {
+	if ds.negatables == nil {
+		ds.negatables = make(map[string]bool, len(names))
+	}
+	for _, name := range names {
+		ds.negatables[name] = true
+	}
+	return ds
+}
+
+func hasNegatable(metas map[string]optMeta) bool {
+	for _, m := range metas {
+		if m.negatable {
+			return true
+		}
+	}
+	return false
+}
+
+// makeNegatable wraps the event handler of the boolean option to set the
+// resolved option argument to the field.
+func makeNegatable(
+	cfg *cliargs.OptCfg, m *optMeta, t reflect.Type, fld reflect.Value,
+) errs.Err {
+	if t.Kind() != reflect.Bool {
+		return errs.New(cliargs.IllegalOptionType{
+			Option: cfg.Name, Field: m.field, Type: t,
+		})
+	}
+
+	m.negatable = true
+
+	if cfg.OnParsed != nil {
+		setter := *cfg.OnParsed
+		onParsed := func(a []string) error {
+			if len(a) > 0 {
+				b, _ := strconv.ParseBool(a[0])
+				fld.SetBool(b)
+				return nil
+			}
+			return setter(a)
+		}
+		cfg.OnParsed = &onParsed
+	}
+
+	return errs.Ok()
+}
+
+// resolveNegations replaces each occurrence of a negatable option and its
+// negated forms with a token of the option name and the resolved option
+// argument: "true" or "false".
+// The occurrences of boolean options accepting attached values are also
+// replaced, and an attached value which is not a bool value is left as it is
+// to be reported when parsing.
+func resolveNegations(
+	toks []argToken, optCfgs []cliargs.OptCfg, metas map[string]optMeta,
+) ([]argToken, errs.Err) {
+	negated := make(map[string]string)
+	for _, cfg := range optCfgs {
+		if !metas[cfg.Name].negatable {
+			continue
+		}
+		negated["no-"+cfg.Name] = cfg.Name
+		for _, a := range cfg.Aliases {
+			if len(a) > 1 {
+				negated["no-"+a] = cfg.Name
+			}
+		}
+	}
+
+	resolved := make([]argToken, 0, len(toks))
+
+	for _, tok := range toks {
+		if !tok.isOpt() {
+			resolved = append(resolved, tok)
+			continue
+		}
+
+		m := metas[tok.name]
+		value := "true"
+		if name, exists := negated[tok.name]; exists {
+			if tok.hasValue {
+				return nil, errs.New(cliargs.OptionTakesNoArg{Option: tok.name})
+			}
+			tok.name = name
+			value = "false"
+		} else if !m.negatable && !m.boolValue {
+			resolved = append(resolved, tok)
+			continue
+		} else if tok.hasValue && !m.boolValue {
+			return nil, errs.New(cliargs.OptionTakesNoArg{Option: tok.name})
+		} else if tok.hasValue {
+			value = tok.value
+			if b, e := parseBoolValue(value); e == nil {
+				value = strconv.FormatBool(b)
+			}
+		}
+
+		tok.value = value
+		tok.hasValue = true
+		resolved = append(resolved, tok)
+	}
+
+	return resolved, errs.Ok()
+}
+
+// mergeNegations merges the tokens of each negatable option and each boolean
+// option accepting attached values, which are resolved by resolveNegations
+// function, into the token at the position of the first occurrence with the
+// option argument of the last occurrence.
+func mergeNegations(toks []argToken, metas map[string]optMeta) []argToken {
+	merged := make([]argToken, 0, len(toks))
+	positions := make(map[string]int)
+
+	for _, tok := range toks {
+		if !tok.isOpt() {
+			merged = append(merged, tok)
+			continue
+		}
+		if m := metas[tok.name]; !m.negatable && !m.boolValue {
+			merged = append(merged, tok)
+			continue
+		}
+		if i, exists := positions[tok.name]; exists {
+			merged[i].value = tok.value
+			continue
+		}
+		positions[tok.name] = len(merged)
+		merged = append(merged, tok)
+	}
+
+	return merged
+}
+
+// negatableCfgs returns a copy of the option configurations in which
+// negatable options take an option argument, to receive the resolved option
+// arguments.
+func negatableCfgs(
+	optCfgs []cliargs.OptCfg, metas map[string]optMeta,
+) []cliargs.OptCfg {
+	cfgs := append([]cliargs.OptCfg{}, optCfgs...)
+	for i, cfg := range cfgs {
+		if metas[cfg.Name].negatable {
+			cfgs[i].HasArg = true
+		}
+	}
+	return cfgs
+}
@@ -0,0 +1,159 @@
+package cliargdax_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+)
+
+func TestNegatable_OptCfgs(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "color", Aliases: []string{"c", "colour"}},
+		cliargs.OptCfg{Name: "verbose", Aliases: []string{"v"}},
+	}
+
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "--color", "-v", "foo", "--no-colour"}, optCfgs,
+	).Negatable("color")
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.True(t, conn.Cmd().HasOpt("color"))
+	assert.Equal(t, conn.Cmd().OptArg("color"), "false")
+	assert.False(t, conn.Cmd().HasOpt("no-color"))
+	assert.True(t, conn.Cmd().HasOpt("verbose"))
+	assert.Equal(t, conn.Cmd().Args(), []string{"foo"})
+	assert.False(t, conn.OptCfgs()[0].HasArg)
+
+	ds = cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "--no-color", "-vc", "--", "--no-color"}, optCfgs,
+	).Negatable("color")
+
+	conn, err = setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.Cmd().OptArg("color"), "true")
+	assert.Equal(t, conn.Cmd().Args(), []string{"--no-color"})
+}
+
+func TestNegatable_OptCfgs_error(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "color"},
+		cliargs.OptCfg{Name: "verbose"},
+	}
+
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "--no-color=true"}, optCfgs,
+	).Negatable("color")
+	_, err := setupWithOptCfgs(t, ds)
+	switch r := err.Reason().(type) {
+	case cliargs.OptionTakesNoArg:
+		assert.Equal(t, r.Option, "no-color")
+	default:
+		assert.Fail(t, err.Error())
+	}
+
+	ds = cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "--color=false"}, optCfgs,
+	).Negatable("color")
+	_, err = setupWithOptCfgs(t, ds)
+	switch r := err.Reason().(type) {
+	case cliargs.OptionTakesNoArg:
+		assert.Equal(t, r.Option, "color")
+	default:
+		assert.Fail(t, err.Error())
+	}
+
+	ds = cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "--no-verbose"}, optCfgs,
+	).Negatable("color")
+	_, err = setupWithOptCfgs(t, ds)
+	switch r := err.Reason().(type) {
+	case cliargs.UnconfiguredOption:
+		assert.Equal(t, r.Option, "no-verbose")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestNegatable_optnegatable(t *testing.T) {
+	type Options struct {
+		Color  bool `optcfg:"color" optnegatable:"true" optdefault:"true"`
+		Pager  bool `optcfg:"pager" optnegatable:"true"`
+		Strict bool `optcfg:"strict"`
+	}
+
+	options := Options{}
+	conn, err := setupForOptions(t, []string{"app"}, &options)
+	assert.True(t, err.IsOk())
+	assert.True(t, options.Color)
+	assert.False(t, options.Pager)
+	assert.False(t, conn.Cmd().HasOpt("color"))
+
+	options = Options{}
+	conn, err = setupForOptions(t, []string{
+		"app", "--no-color", "--pager", "--no-pager", "--pager", "--strict",
+	}, &options)
+	assert.True(t, err.IsOk())
+	assert.False(t, options.Color)
+	assert.True(t, options.Pager)
+	assert.True(t, options.Strict)
+	assert.Equal(t, conn.Cmd().OptArg("color"), "false")
+	assert.Equal(t, conn.Cmd().OptArg("pager"), "true")
+
+	options = Options{}
+	_, err = setupForOptions(t, []string{"app", "--no-strict"}, &options)
+	switch r := err.Reason().(type) {
+	case cliargs.UnconfiguredOption:
+		assert.Equal(t, r.Option, "no-strict")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestNegatable_optnegatable_env(t *testing.T) {
+	type Options struct {
+		Color bool `optcfg:"color" optnegatable:"true" optdefault:"true" optenv:"MY_APP_COLOR"`
+	}
+
+	t.Setenv("MY_APP_COLOR", "false")
+
+	options := Options{}
+	_, err := setupForOptions(t, []string{"app"}, &options)
+	assert.True(t, err.IsOk())
+	assert.False(t, options.Color)
+
+	options = Options{}
+	_, err = setupForOptions(t, []string{"app", "--color"}, &options)
+	assert.True(t, err.IsOk())
+	assert.True(t, options.Color)
+
+	t.Setenv("MY_APP_COLOR", "x")
+
+	options = Options{}
+	_, err = setupForOptions(t, []string{"app"}, &options)
+	switch r := err.Reason().(type) {
+	case cliargdax.FailToParseEnvVar:
+		assert.Equal(t, r.Option, "color")
+		assert.Equal(t, r.Input, "x")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestNegatable_optnegatable_illegalType(t *testing.T) {
+	type Options struct {
+		Name string `optcfg:"name" optnegatable:"true"`
+	}
+
+	options := Options{}
+	_, err := setupForOptions(t, []string{"app"}, &options)
+	switch r := err.Reason().(type) {
+	case cliargs.IllegalOptionType:
+		assert.Equal(t, r.Option, "name")
+		assert.Equal(t, r.Field, "Name")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
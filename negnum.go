@@ -0,0 +1,89 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/sttk/cliargs"
+)
+
+// isNegativeNumber reports whether the command line argument is a negative
+// number in the forms: -<digits> or -<digits>.<digits>.
+// Other forms of numbers, like -1e5, are not negative numbers here because
+// they can also be combined short options, but they can be option arguments of
+// options which take them.
+func isNegativeNumber(arg string) bool {
+	if len(arg) < 2 || arg[0] != '-' {
+		return false
+	}
+	s := arg[1:]
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		return isDigits(s[:i]) && isDigits(s[i+1:])
+	}
+	return isDigits(s)
+}
+
+func isDigits(s string) bool {
+	if len(s) == 0 {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// hasNegativeNumber reports whether any of the command line arguments,
+// excluding the program path, is a negative number.
+func hasNegativeNumber(osArgs []string) bool {
+	for i := 1; i < len(osArgs); i++ {
+		if osArgs[i] == "--" {
+			return false
+		}
+		if isNegativeNumber(osArgs[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasDigitShortOpt reports whether any option of which name or alias is a
+// single digit is configured, in which case negative numbers are short
+// options.
+func hasDigitShortOpt(optCfgs []cliargs.OptCfg) bool {
+	isDigit := func(name string) bool {
+		return utf8.RuneCountInString(name) == 1 && isDigits(name)
+	}
+	for _, cfg := range optCfgs {
+		if isDigit(cfg.Name) {
+			return true
+		}
+		for _, a := range cfg.Aliases {
+			if isDigit(a) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// hasOptLikeArg reports whether any command argument before the option
+// terminator in the tokens starts with "-", which cliargs.ParseWith function
+// would parse as an option.
+func hasOptLikeArg(toks []argToken) bool {
+	for _, tok := range toks {
+		if tok.isTerm {
+			return false
+		}
+		if !tok.isOpt() && len(tok.value) > 1 && tok.value[0] == '-' {
+			return true
+		}
+	}
+	return false
+}
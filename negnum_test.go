@@ -0,0 +1,77 @@
+package cliargdax_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+)
+
+func TestNegativeNumber_noCfgs(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgs([]string{
+		"app", "-3", "--threshold", "-5", "-1.5", "-v", "x", "--", "-2",
+	})
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.Cmd().Args(), []string{"-3", "-5", "-1.5", "x", "-2"})
+	assert.True(t, conn.Cmd().HasOpt("threshold"))
+	assert.True(t, conn.Cmd().HasOpt("v"))
+	assert.Equal(t, conn.ArgsBeforeTerminator(), []string{"-3", "-5", "-1.5", "x"})
+	assert.Equal(t, conn.ArgsAfterTerminator(), []string{"-2"})
+}
+
+func TestNegativeNumber_optCfgs(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "threshold", Aliases: []string{"t"}, HasArg: true},
+		cliargs.OptCfg{Name: "verbose", Aliases: []string{"v"}},
+	}
+
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "--threshold", "-5", "-3", "-v", "-0.25",
+	}, optCfgs)
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.Cmd().OptArg("threshold"), "-5")
+	assert.True(t, conn.Cmd().HasOpt("verbose"))
+	assert.Equal(t, conn.Cmd().Args(), []string{"-3", "-0.25"})
+
+	ds = cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "-t", "-1e5", "-1e5",
+	}, optCfgs)
+	_, err = setupWithOptCfgs(t, ds)
+	assert.Equal(t, err.Reason(), cliargs.OptionHasInvalidChar{Option: "1"})
+
+	ds = cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "-t", "-1e5", "x",
+	}, optCfgs)
+	conn, err = setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.Cmd().OptArg("threshold"), "-1e5")
+	assert.Equal(t, conn.Cmd().Args(), []string{"x"})
+}
+
+func TestNegativeNumber_optionStore(t *testing.T) {
+	options := struct {
+		Threshold float64 `optcfg:"threshold,t"`
+		Verbose   bool    `optcfg:"verbose,v"`
+	}{}
+	conn, err := setupForOptions(t, []string{
+		"app", "-t", "-2.5", "-10", "-v",
+	}, &options)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, options.Threshold, -2.5)
+	assert.True(t, options.Verbose)
+	assert.Equal(t, conn.Cmd().Args(), []string{"-10"})
+}
+
+func TestNegativeNumber_digitShortOpt(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "one", Aliases: []string{"1"}},
+	}
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "-1", "-2",
+	}, optCfgs)
+	_, err := setupWithOptCfgs(t, ds)
+	assert.Equal(t, err.Reason(), cliargs.OptionHasInvalidChar{Option: "2"})
+}
@@ -0,0 +1,207 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/sttk/sabi/errs"
+)
+
+type /* error reasons */ (
+	// OptionStoreHasCycle is the error reason which indicates that a nested
+	// struct of an option store refers to a struct of the same type as its
+	// enclosing struct via a pointer.
+	// The field Field is the path of the field which makes the cycle, like
+	// Server.Parent.
+	OptionStoreHasCycle struct {
+		Field string
+	}
+
+	// OptionStoreIsTooDeep is the error reason which indicates that nested
+	// structs of an option store are deeper than the limit.
+	// The fields Field and Depth are the path of the field which exceeds the
+	// limit and the limit.
+	OptionStoreIsTooDeep struct {
+		Field string
+		Depth int
+	}
+)
+
+// maxNestDepth is the maximum depth of nested structs in an option store.
+const maxNestDepth = 8
+
+// storeField is a field of an option store or of a struct nested in it.
+// The field prefix is the prefix of the option name and long aliases, and the
+// field group is the path of the nested struct, like Server.TLS, which is
+// empty for a field directly in the option store.
+type storeField struct {
+	sf     reflect.StructField
+	fld    reflect.Value
+	prefix string
+	group  string
+}
+
+// path returns the path of the field, like Server.Port.
+func (f storeField) path() string {
+	if len(f.group) == 0 {
+		return f.sf.Name
+	}
+	return f.group + "." + f.sf.Name
+}
+
+// OptGroup is the method to retrieve the group of the specified option, which
+// is the value of the optgroup struct tag or of OptMeta#Group if set, or the
+// path of the nested struct in the option store, like Server.TLS, where the
+// option field is declared.
+// If the option is declared directly in the option store or in an anonymous
+// embedded struct of it, and no group is set, this method returns an empty
+// string.
+func (conn *DaxConn) OptGroup(name string) string {
+	conn.ds.parseLazily()
+	conn.ds.mutex.RLock()
+	defer conn.ds.mutex.RUnlock()
+	return conn.ds.metas[name].optGroup()
+}
+
+// collectFields collects the fields of the struct value, flattening nested
+// structs.
+// A field of a struct type or of a pointer type to a struct is treated as a
+// nested struct unless its type is supported as an option type.
+// The fields in a nested struct are prefixed with the value of the optprefix
+// struct tag, or with the field name mapped by the name mapping and a hyphen
+// by default, because cliargs package allows only alphanumerics and hyphens
+// in option names.
+// The fields in an anonymous embedded struct are not prefixed by default.
+// A field of which optcfg struct tag is "-" is skipped with all the fields in
+// it if it is a nested struct, and an unexported field is also skipped unless
+// it is an anonymous embedded struct.
+// If a pointer to a nested struct is nil and settable, it is set to a newly
+// allocated struct.
+func collectFields(
+	v reflect.Value, prefix, group string, path []reflect.Type,
+	fields []storeField, decs decoderMap, mapping NameMapping,
+) ([]storeField, errs.Err) {
+	t := v.Type()
+	path = append(path, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		fld := v.Field(i)
+
+		if sf.Tag.Get("optcfg") == "-" {
+			continue
+		}
+
+		st, isPtr, ok := nestedStructType(sf, decs)
+		if !sf.IsExported() && !(ok && sf.Anonymous) {
+			continue
+		}
+		if !ok {
+			fields = append(fields, storeField{
+				sf: sf, fld: fld, prefix: prefix, group: group,
+			})
+			continue
+		}
+
+		fieldPath := sf.Name
+		if len(group) > 0 {
+			fieldPath = group + "." + sf.Name
+		}
+
+		if len(path) >= maxNestDepth {
+			return nil, errs.New(OptionStoreIsTooDeep{
+				Field: fieldPath, Depth: maxNestDepth,
+			})
+		}
+		for _, pt := range path {
+			if pt == st {
+				return nil, errs.New(OptionStoreHasCycle{Field: fieldPath})
+			}
+		}
+
+		if isPtr {
+			if fld.IsNil() {
+				if fld.CanSet() {
+					fld.Set(reflect.New(st))
+					fld = fld.Elem()
+				} else {
+					fld = reflect.New(st).Elem()
+				}
+			} else {
+				fld = fld.Elem()
+			}
+		}
+
+		p, exists := sf.Tag.Lookup("optprefix")
+		if !exists && !sf.Anonymous {
+			p = mapping.prefix(sf.Name)
+		}
+
+		subGroup := group
+		if !sf.Anonymous {
+			subGroup = fieldPath
+		}
+
+		var err errs.Err
+		fields, err = collectFields(
+			fld, prefix+p, subGroup, path, fields, decs, mapping,
+		)
+		if err.IsNotOk() {
+			return nil, err
+		}
+	}
+
+	return fields, errs.Ok()
+}
+
+// nestedStructType returns the struct type of the field if the field is a
+// nested struct, and whether the field type is a pointer.
+func nestedStructType(
+	sf reflect.StructField, decs decoderMap,
+) (reflect.Type, bool, bool) {
+	t := sf.Type
+	isPtr := false
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+		isPtr = true
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, false, false
+	}
+	if _, _, isCustom := decoderFor(sf, decs); isCustom {
+		return nil, false, false
+	}
+	return t, isPtr, true
+}
+
+// prefixedTag returns the struct tag in which the option name and long
+// aliases in the optcfg struct tag are prefixed.
+// The new optcfg struct tag is put at the head of the struct tag, because
+// reflect.StructTag#Lookup method returns the first one.
+func prefixedTag(sf reflect.StructField, prefix string) reflect.StructTag {
+	if len(prefix) == 0 {
+		return sf.Tag
+	}
+
+	opt := sf.Tag.Get("optcfg")
+	arr := strings.SplitN(opt, "=", 2)
+	names := strings.Split(arr[0], ",")
+	if len(names[0]) == 0 {
+		names[0] = sf.Name
+	}
+	for i, name := range names {
+		if i == 0 || len(name) > 1 {
+			names[i] = prefix + name
+		}
+	}
+	arr[0] = strings.Join(names, ",")
+	opt = strings.Join(arr, "=")
+
+	return reflect.StructTag("optcfg:" + strconv.Quote(opt) + " " +
+		string(sf.Tag))
+}
@@ -0,0 +1,230 @@
+package cliargdax_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+)
+
+type Logging struct {
+	Level string `optcfg:"level,l=info"`
+}
+
+func TestNested_namedStruct(t *testing.T) {
+	type TLS struct {
+		Cert string `optcfg:"cert"`
+	}
+	type Server struct {
+		Host string `optcfg:"host,H=localhost"`
+		Port int    `optcfg:"port"`
+		TLS  TLS
+	}
+	type Options struct {
+		Verbose bool `optcfg:"verbose,v"`
+		Server  Server
+		Log     Logging `optprefix:"log-"`
+	}
+
+	options := Options{}
+	conn, err := setupForOptions(t, []string{
+		"app", "-v", "--server-port=8080", "--server-tls-cert", "a.pem",
+		"-l", "debug",
+	}, &options)
+	assert.True(t, err.IsOk())
+	assert.True(t, options.Verbose)
+	assert.Equal(t, options.Server.Host, "localhost")
+	assert.Equal(t, options.Server.Port, 8080)
+	assert.Equal(t, options.Server.TLS.Cert, "a.pem")
+	assert.Equal(t, options.Log.Level, "debug")
+
+	cfgs := conn.OptCfgs()
+	assert.Equal(t, len(cfgs), 5)
+	assert.Equal(t, cfgs[0].Name, "verbose")
+	assert.Equal(t, cfgs[1].Name, "server-host")
+	assert.Equal(t, cfgs[1].Aliases, []string{"H"})
+	assert.Equal(t, cfgs[1].Default, []string{"localhost"})
+	assert.Equal(t, cfgs[2].Name, "server-port")
+	assert.Equal(t, cfgs[3].Name, "server-tls-cert")
+	assert.Equal(t, cfgs[4].Name, "log-level")
+	assert.Equal(t, cfgs[4].Aliases, []string{"l"})
+
+	assert.Equal(t, conn.OptGroup("verbose"), "")
+	assert.Equal(t, conn.OptGroup("server-port"), "Server")
+	assert.Equal(t, conn.OptGroup("server-tls-cert"), "Server.TLS")
+	assert.Equal(t, conn.OptGroup("log-level"), "Log")
+}
+
+func TestNested_fieldNameAsOptionName(t *testing.T) {
+	type Server struct {
+		Port int
+	}
+	type Options struct {
+		Server Server
+	}
+
+	options := Options{}
+	conn, err := setupForOptions(t, []string{"app", "--server-port=80"},
+		&options)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, options.Server.Port, 80)
+	assert.Equal(t, conn.OptCfgs()[0].Name, "server-port")
+}
+
+func TestNested_embeddedStruct(t *testing.T) {
+	type Options struct {
+		Logging
+		Name string `optcfg:"name"`
+	}
+
+	options := Options{}
+	conn, err := setupForOptions(t, []string{"app", "--level=warn"}, &options)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, options.Level, "warn")
+
+	cfgs := conn.OptCfgs()
+	assert.Equal(t, cfgs[0].Name, "level")
+	assert.Equal(t, cfgs[1].Name, "name")
+	assert.Equal(t, conn.OptGroup("level"), "")
+
+	type Options2 struct {
+		Logging `optprefix:"logging-"`
+	}
+
+	options2 := Options2{}
+	conn, err = setupForOptions(t, []string{"app", "--logging-level=warn"},
+		&options2)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, options2.Level, "warn")
+	assert.Equal(t, conn.OptCfgs()[0].Name, "logging-level")
+}
+
+func TestNested_pointerStruct(t *testing.T) {
+	type Options struct {
+		Log *Logging
+	}
+
+	options := Options{}
+	_, err := setupForOptions(t, []string{"app", "--log-level=error"},
+		&options)
+	assert.True(t, err.IsOk())
+	assert.NotNil(t, options.Log)
+	assert.Equal(t, options.Log.Level, "error")
+}
+
+type cyclicNode struct {
+	Name   string `optcfg:"name"`
+	Parent *cyclicNode
+}
+
+func TestNested_cycle(t *testing.T) {
+	type Options struct {
+		Node cyclicNode
+	}
+
+	options := Options{}
+	_, err := setupForOptions(t, []string{"app"}, &options)
+	switch r := err.Reason().(type) {
+	case cliargdax.OptionStoreHasCycle:
+		assert.Equal(t, r.Field, "Node.Parent")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+type deep struct {
+	D struct {
+		D struct {
+			D struct {
+				D struct {
+					D struct {
+						D struct {
+							D struct {
+								D struct {
+									X int
+								}
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestNested_tooDeep(t *testing.T) {
+	options := deep{}
+	_, err := setupForOptions(t, []string{"app"}, &options)
+	switch r := err.Reason().(type) {
+	case cliargdax.OptionStoreIsTooDeep:
+		assert.Equal(t, r.Field, "D.D.D.D.D.D.D.D")
+		assert.Equal(t, r.Depth, 8)
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+type logging struct {
+	Format string `optcfg:"format"`
+}
+
+func TestNested_skippedFields(t *testing.T) {
+	type Cache struct {
+		Size int `optcfg:"size"`
+	}
+	type Options struct {
+		Verbose bool `optcfg:"verbose,v"`
+		Loaded  bool `optcfg:"-"`
+		Cache   `optcfg:"-"`
+		Stats   *Cache `optcfg:"-"`
+		logging
+		count int
+		cache Cache
+	}
+
+	options := Options{}
+	conn, err := setupForOptions(t, []string{
+		"app", "-v", "--format=json",
+	}, &options)
+	assert.True(t, err.IsOk())
+	assert.True(t, options.Verbose)
+	assert.Equal(t, options.Format, "json")
+	assert.Nil(t, options.Stats)
+
+	names := make([]string, 0)
+	for _, cfg := range conn.OptCfgs() {
+		names = append(names, cfg.Name)
+	}
+	assert.Equal(t, names, []string{"verbose", "format"})
+
+	for _, name := range []string{"loaded", "size", "count"} {
+		_, err = setupForOptions(t, []string{"app", "--" + name}, &options)
+		assert.Equal(t, err.Reason(), cliargs.UnconfiguredOption{Option: name})
+	}
+}
+
+func TestNested_unsupportedFieldType(t *testing.T) {
+	type Options struct {
+		Verbose bool `optcfg:"verbose"`
+		Done    chan bool
+	}
+
+	options := Options{}
+	_, err := setupForOptions(t, []string{"app"}, &options)
+	switch r := err.Reason().(type) {
+	case cliargs.IllegalOptionType:
+		assert.Equal(t, r.Field, "Done")
+	default:
+		assert.Fail(t, err.Error())
+	}
+
+	type Skipped struct {
+		Verbose bool      `optcfg:"verbose"`
+		Done    chan bool `optcfg:"-"`
+	}
+	skipped := Skipped{}
+	_, err = setupForOptions(t, []string{"app", "--verbose"}, &skipped)
+	assert.True(t, err.IsOk())
+	assert.True(t, skipped.Verbose)
+}
@@ -0,0 +1,108 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"net"
+	"net/url"
+	"path/filepath"
+	"reflect"
+)
+
+type /* error reasons */ (
+	// FailToParseURL is the error reason which indicates that an option
+	// argument cannot be converted to a url.URL value.
+	// The fields Option, Field, and Input are the option name, the field name,
+	// and the option argument.
+	FailToParseURL struct {
+		Option string
+		Field  string
+		Input  string
+	}
+
+	// FailToParseIP is the error reason which indicates that an option
+	// argument cannot be converted to a net.IP value.
+	// The fields Option, Field, and Input are the option name, the field name,
+	// and the option argument.
+	FailToParseIP struct {
+		Option string
+		Field  string
+		Input  string
+	}
+
+	// FailToResolvePath is the error reason which indicates that an option
+	// argument cannot be converted to an absolute path.
+	// The fields Option, Field, and Input are the option name, the field name,
+	// and the option argument.
+	FailToResolvePath struct {
+		Option string
+		Field  string
+		Input  string
+	}
+)
+
+// Path is the type for a field of an option store which takes a file path.
+// An option argument for a field of this type is cleaned and converted to an
+// absolute path.
+type Path string
+
+var (
+	urlType  = reflect.TypeOf(url.URL{})
+	ipType   = reflect.TypeOf(net.IP{})
+	pathType = reflect.TypeOf(Path(""))
+)
+
+// netParserFor returns the valueParser for url.URL, net.IP, or Path type.
+func netParserFor(
+	t reflect.Type, sf reflect.StructField,
+) (valueParser, string, bool) {
+	switch t {
+	case urlType:
+		p := func(name, s string) (reflect.Value, error) {
+			u, e := url.Parse(s)
+			if e != nil {
+				return reflect.Value{}, reasonErr{
+					option: name,
+					reason: FailToParseURL{Option: name, Field: sf.Name, Input: s},
+					cause:  e,
+				}
+			}
+			return reflect.ValueOf(*u), nil
+		}
+		return p, "url", true
+
+	case ipType:
+		p := func(name, s string) (reflect.Value, error) {
+			ip := net.ParseIP(s)
+			if ip == nil {
+				return reflect.Value{}, reasonErr{
+					option: name,
+					reason: FailToParseIP{Option: name, Field: sf.Name, Input: s},
+				}
+			}
+			return reflect.ValueOf(ip), nil
+		}
+		return p, "ip", true
+
+	case pathType:
+		p := func(name, s string) (reflect.Value, error) {
+			abs, e := filepath.Abs(s)
+			if e != nil || len(s) == 0 {
+				return reflect.Value{}, reasonErr{
+					option: name,
+					reason: FailToResolvePath{
+						Option: name, Field: sf.Name, Input: s,
+					},
+					cause: e,
+				}
+			}
+			return reflect.ValueOf(Path(abs)), nil
+		}
+		return p, "path", true
+
+	default:
+		return nil, "", false
+	}
+}
@@ -0,0 +1,144 @@
+package cliargdax_test
+
+import (
+	"net"
+	"net/url"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+)
+
+func TestNetOpt_urlField(t *testing.T) {
+	type Options struct {
+		Endpoint url.URL   `optcfg:"endpoint,e" optdesc:"endpoint url"`
+		Proxy    *url.URL  `optcfg:"proxy"`
+		Origins  []url.URL `optcfg:"origin"`
+	}
+
+	options := Options{}
+	conn, err := setupForOptions(t, []string{
+		"app", "-e", "https://example.com:8080/api?x=1",
+		"--origin=http://a.com", "--origin=http://b.com",
+	}, &options)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, options.Endpoint.Scheme, "https")
+	assert.Equal(t, options.Endpoint.Host, "example.com:8080")
+	assert.Equal(t, options.Endpoint.Path, "/api")
+	assert.Nil(t, options.Proxy)
+	assert.Equal(t, len(options.Origins), 2)
+	assert.Equal(t, options.Origins[1].Host, "b.com")
+
+	cfgs := conn.OptCfgs()
+	assert.Equal(t, len(cfgs), 3)
+	assert.Equal(t, cfgs[0].Name, "endpoint")
+	assert.True(t, cfgs[0].HasArg)
+	assert.Equal(t, cfgs[0].ArgHelp, "<url>")
+	assert.Equal(t, cfgs[0].Desc, "endpoint url")
+	assert.False(t, cfgs[1].IsArray)
+	assert.True(t, cfgs[2].IsArray)
+
+	options = Options{}
+	_, err = setupForOptions(t, []string{"app", "--proxy", "http://p:3128"},
+		&options)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, options.Proxy.Host, "p:3128")
+}
+
+func TestNetOpt_urlField_invalid(t *testing.T) {
+	type Options struct {
+		Endpoint url.URL `optcfg:"endpoint"`
+	}
+
+	options := Options{}
+	_, err := setupForOptions(t, []string{"app", "--endpoint=http://[::1"},
+		&options)
+	switch r := err.Reason().(type) {
+	case cliargdax.FailToParseURL:
+		assert.Equal(t, r.Option, "endpoint")
+		assert.Equal(t, r.Field, "Endpoint")
+		assert.Equal(t, r.Input, "http://[::1")
+		assert.NotNil(t, err.Cause())
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestNetOpt_ipField(t *testing.T) {
+	type Options struct {
+		Bind  net.IP   `optcfg:"bind=127.0.0.1"`
+		Peers []net.IP `optcfg:"peer"`
+		Gw    *net.IP  `optcfg:"gw"`
+	}
+
+	options := Options{}
+	conn, err := setupForOptions(t, []string{
+		"app", "--peer", "10.0.0.1", "--peer=::1",
+	}, &options)
+	assert.True(t, err.IsOk())
+	assert.True(t, options.Bind.Equal(net.ParseIP("127.0.0.1")))
+	assert.Equal(t, len(options.Peers), 2)
+	assert.True(t, options.Peers[1].Equal(net.IPv6loopback))
+	assert.Nil(t, options.Gw)
+
+	cfgs := conn.OptCfgs()
+	assert.Equal(t, cfgs[0].ArgHelp, "<ip>")
+	assert.Equal(t, cfgs[0].Default, []string{"127.0.0.1"})
+	assert.True(t, cfgs[1].IsArray)
+}
+
+func TestNetOpt_ipField_invalid(t *testing.T) {
+	type Options struct {
+		Bind net.IP `optcfg:"bind"`
+	}
+
+	options := Options{}
+	_, err := setupForOptions(t, []string{"app", "--bind=300.0.0.1"}, &options)
+	switch r := err.Reason().(type) {
+	case cliargdax.FailToParseIP:
+		assert.Equal(t, r.Option, "bind")
+		assert.Equal(t, r.Field, "Bind")
+		assert.Equal(t, r.Input, "300.0.0.1")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestNetOpt_pathField(t *testing.T) {
+	type Options struct {
+		Config cliargdax.Path   `optcfg:"config"`
+		Dirs   []cliargdax.Path `optcfg:"dir"`
+		Log    *cliargdax.Path  `optcfg:"log"`
+	}
+
+	options := Options{}
+	conn, err := setupForOptions(t, []string{
+		"app", "--config", "conf/../app.yml", "--dir=/tmp//a/", "--dir=b",
+	}, &options)
+	assert.True(t, err.IsOk())
+	cwd, _ := filepath.Abs(".")
+	assert.Equal(t, options.Config, cliargdax.Path(filepath.Join(cwd, "app.yml")))
+	assert.Equal(t, options.Dirs, []cliargdax.Path{
+		"/tmp/a", cliargdax.Path(filepath.Join(cwd, "b")),
+	})
+	assert.Nil(t, options.Log)
+	assert.Equal(t, conn.OptCfgs()[0].ArgHelp, "<path>")
+}
+
+func TestNetOpt_pathField_empty(t *testing.T) {
+	type Options struct {
+		Config cliargdax.Path `optcfg:"config"`
+	}
+
+	options := Options{}
+	_, err := setupForOptions(t, []string{"app", "--config="}, &options)
+	switch r := err.Reason().(type) {
+	case cliargdax.FailToResolvePath:
+		assert.Equal(t, r.Option, "config")
+		assert.Equal(t, r.Field, "Config")
+		assert.Equal(t, r.Input, "")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
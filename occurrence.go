@@ -0,0 +1,88 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+// OptOccurrence is the struct type which holds an occurrence of an option or
+// a command argument in command line arguments.
+// The field Name is the option name, which is resolved from an alias or an
+// abbreviation, or is empty for a command argument.
+// The field Spelling is the option as written in command line arguments with
+// its prefix, like -I or --include, in the same way as DaxConn#OptSpellings.
+// The field Value is the option argument if the field HasValue is true, or
+// is the command argument.
+// The field ExtraValues is the option arguments following Value for an option
+// which takes multiple option arguments per occurrence.
+// The field Index is the index of the occurrence in the command line
+// arguments including the program path, like os.Args.
+type OptOccurrence struct {
+	Name        string
+	Spelling    string
+	Value       string
+	HasValue    bool
+	ExtraValues []string
+	Index       int
+}
+
+// Occurrences is the method to retrieve the occurrences of options in command
+// line arguments in the order in which they are given, across different
+// options, like -I a -D X -I b, for example to process them in that order.
+// Each character of combined short options, like -vvv, is an occurrence, and
+// options which take no option argument are also included.
+// Options of which values are taken from environment variables or the config
+// file are not included.
+func (conn *DaxConn) Occurrences() []OptOccurrence {
+	conn.ds.parseLazily()
+	conn.ds.mutex.RLock()
+	defer conn.ds.mutex.RUnlock()
+	occs := make([]OptOccurrence, 0, len(conn.ds.occurrences))
+	for _, occ := range conn.ds.occurrences {
+		if len(occ.Name) > 0 {
+			occs = append(occs, occ)
+		}
+	}
+	return occs
+}
+
+// OccurrencesWithArgs is the method to retrieve the occurrences of options
+// and command arguments in command line arguments in the order in which they
+// are given, so that the whole command line arguments can be replayed.
+// The occurrences of command arguments have empty names and have the command
+// arguments as their values.
+// See Occurrences method for details.
+func (conn *DaxConn) OccurrencesWithArgs() []OptOccurrence {
+	conn.ds.parseLazily()
+	conn.ds.mutex.RLock()
+	defer conn.ds.mutex.RUnlock()
+	return append([]OptOccurrence{}, conn.ds.occurrences...)
+}
+
+// recordOccurrences records the occurrences of the options and the command
+// arguments in the tokens.
+func (r *parseResult) recordOccurrences(toks []argToken) {
+	r.occurrences = make([]OptOccurrence, 0, len(toks))
+	for _, tok := range toks {
+		switch {
+		case tok.isTerm:
+			continue
+		case !tok.isOpt():
+			r.occurrences = append(r.occurrences, OptOccurrence{
+				Value: tok.value, Index: tok.index,
+			})
+		default:
+			spelling := tok.spelling
+			if len(spelling) == 0 {
+				spelling = optArg(tok.name)
+			}
+			occ := OptOccurrence{
+				Name: tok.name, Spelling: spelling,
+				Value: tok.value, HasValue: tok.hasValue, Index: tok.index,
+			}
+			if len(tok.extraValues) > 0 {
+				occ.ExtraValues = append([]string{}, tok.extraValues...)
+			}
+			r.occurrences = append(r.occurrences, occ)
+		}
+	}
+}
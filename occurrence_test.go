@@ -0,0 +1,74 @@
+package cliargdax_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+)
+
+func newOccurrenceDaxSrc(args []string) *cliargdax.DaxSrc {
+	return cliargdax.NewDaxSrcWithArgsAndOptCfgs(args, []cliargs.OptCfg{
+		cliargs.OptCfg{
+			Name: "include", Aliases: []string{"I"}, HasArg: true, IsArray: true,
+		},
+		cliargs.OptCfg{
+			Name: "define", Aliases: []string{"D"}, HasArg: true, IsArray: true,
+		},
+		cliargs.OptCfg{Name: "verbose", Aliases: []string{"v"}},
+	})
+}
+
+func TestOccurrences(t *testing.T) {
+	ds := newOccurrenceDaxSrc([]string{
+		"cc", "-I", "a", "main.c", "-D", "X", "-vv", "--include=b", "--", "-x",
+	})
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	assert.Equal(t, conn.Occurrences(), []cliargdax.OptOccurrence{
+		cliargdax.OptOccurrence{
+			Name: "include", Spelling: "-I", Value: "a", HasValue: true, Index: 1,
+		},
+		cliargdax.OptOccurrence{
+			Name: "define", Spelling: "-D", Value: "X", HasValue: true, Index: 4,
+		},
+		cliargdax.OptOccurrence{Name: "verbose", Spelling: "-v", Index: 6},
+		cliargdax.OptOccurrence{Name: "verbose", Spelling: "-v", Index: 6},
+		cliargdax.OptOccurrence{
+			Name: "include", Spelling: "--include", Value: "b", HasValue: true,
+			Index: 7,
+		},
+	})
+
+	occs := conn.OccurrencesWithArgs()
+	assert.Equal(t, len(occs), 7)
+	assert.Equal(t, occs[1], cliargdax.OptOccurrence{Value: "main.c", Index: 3})
+	assert.Equal(t, occs[6], cliargdax.OptOccurrence{Value: "-x", Index: 9})
+}
+
+func TestOccurrences_abbrev(t *testing.T) {
+	ds := newOccurrenceDaxSrc([]string{"cc", "--incl", "a", "--def=Y"}).
+		AllowAbbrev(true)
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	assert.Equal(t, conn.Occurrences(), []cliargdax.OptOccurrence{
+		cliargdax.OptOccurrence{
+			Name: "include", Spelling: "--incl", Value: "a", HasValue: true,
+			Index: 1,
+		},
+		cliargdax.OptOccurrence{
+			Name: "define", Spelling: "--def", Value: "Y", HasValue: true, Index: 3,
+		},
+	})
+}
+
+func TestOccurrences_none(t *testing.T) {
+	ds := newOccurrenceDaxSrc([]string{"cc"})
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.Occurrences(), []cliargdax.OptOccurrence{})
+	assert.Equal(t, conn.OccurrencesWithArgs(), []cliargdax.OptOccurrence{})
+}
@@ -0,0 +1,81 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"github.com/sttk/sabi/errs"
+)
+
+type /* error reasons */ (
+	// OptCallbackFailed is the error reason which indicates that a function
+	// registered with DaxSrc#OnOpt method returned an error.
+	// The fields Option, Value, and Cause are the option name, the option
+	// argument, and the error returned by the function.
+	OptCallbackFailed struct {
+		Option string
+		Value  string
+		Cause  error
+	}
+)
+
+// optCallback is a function registered with OnOpt method and its option
+// name.
+type optCallback struct {
+	name string
+	fn   func(value string) errs.Err
+}
+
+// OnOpt is the method to register the function which is invoked for each
+// occurrence of the specified option in command line arguments, in the order
+// of command line arguments, during the parsing.
+// The option is matched by its name after aliases, abbreviations, and
+// negations are resolved, and the function is called with the option
+// argument, or with an empty string if the option takes no argument.
+// A negated option, like --no-color, calls the function with "false", and the
+// option without the prefix calls it with "true".
+// The function registered with the name "*" is invoked for every option with
+// a string of the form name=value, or name if the option has no argument.
+// The options given by environment variables or a config file do not invoke
+// the functions.
+// If a function returns an error, the parsing stops and Setup method, or
+// DaxConn#ParseErr method in lazy mode, returns an errs.Err with the reason:
+// OptCallbackFailed, which holds the returned error as its cause.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) OnOpt(name string, fn func(value string) errs.Err) *DaxSrc {
+	ds.optCallbacks = append(ds.optCallbacks, optCallback{name: name, fn: fn})
+	return ds
+}
+
+// runOptCallbacks invokes the functions registered with OnOpt method for the
+// option tokens in order.
+func runOptCallbacks(callbacks []optCallback, toks []argToken) errs.Err {
+	if len(callbacks) == 0 {
+		return errs.Ok()
+	}
+	for _, tok := range toks {
+		if !tok.isOpt() {
+			continue
+		}
+		for _, cb := range callbacks {
+			value := tok.value
+			switch cb.name {
+			case tok.name:
+			case "*":
+				value = tok.name
+				if tok.hasValue {
+					value += "=" + tok.value
+				}
+			default:
+				continue
+			}
+			if err := cb.fn(value); err.IsNotOk() {
+				return errs.New(OptCallbackFailed{
+					Option: tok.name, Value: tok.value, Cause: err,
+				}, err)
+			}
+		}
+	}
+	return errs.Ok()
+}
@@ -0,0 +1,122 @@
+package cliargdax_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+	"github.com/sttk/sabi/errs"
+)
+
+var onOptCfgs = []cliargs.OptCfg{
+	cliargs.OptCfg{Name: "verbose", Aliases: []string{"v"}},
+	cliargs.OptCfg{
+		Name: "define", Aliases: []string{"D"}, HasArg: true, IsArray: true,
+	},
+	cliargs.OptCfg{Name: "name", HasArg: true},
+}
+
+type FailToDefine struct{}
+
+func TestOnOpt_order(t *testing.T) {
+	var events []string
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "-v", "--define", "A=1", "x", "-vD", "B=2", "--verbose",
+	}, onOptCfgs).
+		OnOpt("verbose", func(value string) errs.Err {
+			events = append(events, "verbose")
+			return errs.Ok()
+		}).
+		OnOpt("define", func(value string) errs.Err {
+			events = append(events, "define "+value)
+			return errs.Ok()
+		})
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, events, []string{
+		"verbose", "define A=1", "verbose", "define B=2", "verbose",
+	})
+	assert.Equal(t, conn.Cmd().OptArgs("define"), []string{"A=1", "B=2"})
+}
+
+func TestOnOpt_wildcard(t *testing.T) {
+	var events []string
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "--name=foo", "-v", "x"}, onOptCfgs,
+	).OnOpt("*", func(value string) errs.Err {
+		events = append(events, value)
+		return errs.Ok()
+	})
+	_, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, events, []string{"name=foo", "verbose"})
+}
+
+func TestOnOpt_negatable(t *testing.T) {
+	type Options struct {
+		Color bool `optcfg:"color" optnegatable:"true"`
+	}
+
+	var values []string
+	options := Options{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions(
+		[]string{"app", "--color", "--no-color"}, &options,
+	).OnOpt("color", func(value string) errs.Err {
+		values = append(values, value)
+		return errs.Ok()
+	})
+	_, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, values, []string{"true", "false"})
+	assert.False(t, options.Color)
+}
+
+func TestOnOpt_error(t *testing.T) {
+	var values []string
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "-D", "A=1", "-D", "B", "-D", "C=3"}, onOptCfgs,
+	).OnOpt("define", func(value string) errs.Err {
+		values = append(values, value)
+		if !strings.Contains(value, "=") {
+			return errs.New(FailToDefine{})
+		}
+		return errs.Ok()
+	})
+	_, err := setupWithOptCfgs(t, ds)
+	switch r := err.Reason().(type) {
+	case cliargdax.OptCallbackFailed:
+		assert.Equal(t, r.Option, "define")
+		assert.Equal(t, r.Value, "B")
+		var e errs.Err
+		assert.True(t, errors.As(r.Cause, &e))
+		_, ok := e.Reason().(FailToDefine)
+		assert.True(t, ok)
+	default:
+		assert.Fail(t, err.Error())
+	}
+	assert.Equal(t, values, []string{"A=1", "B"})
+	assert.Equal(t, cliargdax.FormatError(err, onOptCfgs),
+		"failed to process option --define: \"B\" ({reason=FailToDefine})\n"+
+			"Try '--help' for more information.")
+}
+
+func TestOnOpt_lazy(t *testing.T) {
+	count := 0
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "-v", "-v"}, onOptCfgs,
+	).Lazy().OnOpt("verbose", func(value string) errs.Err {
+		count++
+		return errs.Ok()
+	})
+	err := ds.Setup(&noopAsyncGroup{})
+	t.Cleanup(ds.Close)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, count, 0)
+
+	dc, _ := ds.CreateDaxConn()
+	assert.Equal(t, dc.(*cliargdax.DaxConn).Cmd().HasOpt("verbose"), true)
+	assert.Equal(t, count, 2)
+}
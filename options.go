@@ -0,0 +1,650 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/sttk/cliargs"
+	"github.com/sttk/sabi/errs"
+)
+
+type /* error reasons */ (
+	// FailToParseEnvVar is the error reason which indicates that the value of
+	// an environment variable, which is specified with the optenv struct tag of
+	// a field of an option store, cannot be converted to the field type.
+	// The fields Option, Field, EnvVar, and Input are the option name, the
+	// field name, the environment variable name, and its value.
+	FailToParseEnvVar struct {
+		Option string
+		Field  string
+		EnvVar string
+		Input  string
+	}
+
+	// FailToParseDefault is the error reason which indicates that a default
+	// value of a field of an option store, which is specified with the optcfg or
+	// optdefault struct tag, cannot be converted to the field type.
+	// The fields Option, Field, and Input are the option name, the field name,
+	// and the default value literal.
+	FailToParseDefault struct {
+		Option string
+		Field  string
+		Input  string
+	}
+
+	// FailToParseElement is the error reason which indicates that an element of
+	// option arguments, which are split with the separator specified with the
+	// optsep struct tag, cannot be converted to the element type of the field.
+	// The fields Option, Field, Input, and Index are the option name, the field
+	// name, the element, and the index of the element in all elements of the
+	// option.
+	FailToParseElement struct {
+		Option string
+		Field  string
+		Input  string
+		Index  int
+	}
+)
+
+type optMeta struct {
+	field    string
+	envVar   string
+	defaults []string
+	required bool
+	choices  optChoices
+	metavar  string
+	rng      *optRange
+	setCount func(n int)
+
+	setChanged func(changed bool)
+
+	negatable    bool
+	boolValue    bool
+	nargs        int
+	nargsEnd     string
+	nargsNumeric bool
+	sep          string
+	group        string
+	section      string
+
+	hidden      bool
+	deprecated  bool
+	deprecation string
+	since       string
+	fromFile    bool
+	secret      bool
+	literal     bool
+	kind        reflect.Kind
+	valueType   reflect.Type
+}
+
+// MakeOptCfgsFor is the function to make an array of cliargs.OptCfg from an
+// option store without parsing command line arguments, for example to
+// generate the help text, completion scripts, or documents in a build step.
+// This function interprets all the struct tags in the same way as the Setup
+// method of DaxSrc created by NewDaxSrcForOptions function, and the
+// configurations are also checked by ValidateOptCfgs function.
+// The event handlers of the returned configurations store option arguments
+// into the fields of the specified option store.
+// The options of which fields have the struct tag: opthidden:"true" are also
+// included.
+// If a field type is not supported, this function returns an errs.Err with
+// the reason: cliargs.IllegalOptionType, of which Field is the field name.
+func MakeOptCfgsFor(options any) ([]cliargs.OptCfg, errs.Err) {
+	cfgs, _, _, err := buildOptCfgs(options, nil, KebabCase)
+	return cfgs, err
+}
+
+// buildOptCfgs makes the option configurations, the metadata of options, and
+// the bindings of command arguments from the option store, and checks the
+// option configurations.
+func buildOptCfgs(
+	options any, decs decoderMap, mapping NameMapping,
+) ([]cliargs.OptCfg, map[string]optMeta, []argBinding, errs.Err) {
+	cfgs, metas, binds, err := makeOptCfgsFor(options, decs, mapping)
+	if err.IsNotOk() {
+		return nil, nil, nil, err
+	}
+	if err := ValidateOptCfgs(cfgs); err.IsNotOk() {
+		return nil, nil, nil, err
+	}
+	return setChoicesArgHelp(cfgs, metas), metas, binds, errs.Ok()
+}
+
+func makeOptCfgsFor(
+	options any, decs decoderMap, mapping NameMapping,
+) ([]cliargs.OptCfg, map[string]optMeta, []argBinding, errs.Err) {
+	rv := reflect.ValueOf(options)
+	if rv.Kind() != reflect.Ptr {
+		return nil, nil, nil,
+			errs.New(cliargs.OptionStoreIsNotChangeable{})
+	}
+
+	fields, err := collectFields(rv.Elem(), "", "", nil, nil, decs, mapping)
+	if err.IsNotOk() {
+		return nil, nil, nil, err
+	}
+
+	fields, changed, err := splitChangedFields(fields)
+	if err.IsNotOk() {
+		return nil, nil, nil, err
+	}
+
+	fields, binds, err := makeArgBindings(fields, decs)
+	if err.IsNotOk() {
+		return nil, nil, nil, err
+	}
+
+	optCfgs := make([]cliargs.OptCfg, len(fields))
+	metas := make(map[string]optMeta, len(optCfgs))
+
+	derived := make([]bool, len(fields))
+	for i, f := range fields {
+		fld, name := derivedNameTag(f.sf, mapping)
+		derived[i] = len(name) > 0
+		fld.Tag = prefixedTag(fld, f.prefix)
+		c, dec, err := makeOptCfgFor(fld, f.fld, decs)
+		if err.IsNotOk() {
+			return nil, nil, nil, err
+		}
+		optCfgs[i] = c
+		cfg := &optCfgs[i]
+		m := optMeta{
+			field:     fld.Name,
+			envVar:    fld.Tag.Get("optenv"),
+			group:     f.group,
+			valueType: fld.Type,
+		}
+
+		if g := fld.Tag.Get("optgroup"); len(g) > 0 {
+			m.section = g
+		}
+
+		if sep := fld.Tag.Get("optsep"); len(sep) > 0 && cfg.IsArray {
+			m.sep = sep
+			makeSeparated(cfg, m)
+		}
+
+		if n, exists := fld.Tag.Lookup("optnargs"); exists {
+			end := fld.Tag.Get("optnargsend")
+			if err := makeNArgs(cfg, &m, fld.Type, n, end); err.IsNotOk() {
+				return nil, nil, nil, err
+			}
+		}
+
+		if h, exists := fld.Tag.Lookup("opthidden"); exists {
+			m.hidden = (h != "false")
+		}
+
+		if d, exists := fld.Tag.Lookup("optdeprecated"); exists {
+			m.deprecated = true
+			m.deprecation = d
+		}
+
+		if v := fld.Tag.Get("optsince"); len(v) > 0 {
+			m.since = v
+		}
+
+		if f, exists := fld.Tag.Lookup("optfromfile"); exists {
+			m.fromFile = (f != "false")
+		}
+
+		if s, exists := fld.Tag.Lookup("optsecret"); exists {
+			m.secret = (s != "false")
+		}
+
+		if l, exists := fld.Tag.Lookup("optliteral"); exists {
+			m.literal = (l != "false")
+		}
+
+		if req, exists := fld.Tag.Lookup("optrequired"); exists {
+			m.required = (req != "false")
+		}
+
+		if choices := fld.Tag.Get("optchoices"); len(choices) > 0 {
+			m.choices.values = strings.Split(choices, ",")
+			m.choices.fold = (fld.Tag.Get("optchoicesfold") == "true")
+		}
+
+		if mv := fld.Tag.Get("optmetavar"); len(mv) > 0 && cfg.HasArg {
+			m.metavar = mv
+			if len(fld.Tag.Get("optarg")) == 0 && len(m.choices.values) == 0 {
+				cfg.ArgHelp = metavarArgHelp(mv)
+			}
+		}
+
+		if def, exists := fld.Tag.Lookup("optdefault"); exists {
+			switch {
+			case !cfg.HasArg:
+				cfg.Default = nil
+				m.defaults = []string{def}
+			case cfg.IsArray:
+				cfg.Default = splitDefault(def)
+			default:
+				cfg.Default = []string{def}
+			}
+		}
+
+		if cfg.Default != nil {
+			m.defaults = cfg.Default
+		}
+
+		if dec != nil {
+			err = validateCustomDefault(cfg, m, dec, fld.Type)
+		} else {
+			err = validateDefault(cfg, m, fld.Type, f.fld)
+		}
+		if err.IsNotOk() {
+			return nil, nil, nil, err
+		}
+
+		min, hasMin := fld.Tag.Lookup("optmin")
+		max, hasMax := fld.Tag.Lookup("optmax")
+		if (hasMin || hasMax) && dec != nil {
+			return nil, nil, nil, errs.New(IllegalOptionRange{
+				Option: cfg.Name, Field: m.field, Min: min, Max: max,
+			})
+		}
+		if hasMin || hasMax {
+			rng, err := newOptRange(cfg.Name, m.field, fld.Type, min, max)
+			if err.IsNotOk() {
+				return nil, nil, nil, err
+			}
+			for _, def := range m.elements(cfg.Default) {
+				if !rng.contains(def) {
+					return nil, nil, nil, errs.New(OptionValueOutOfRange{
+						Option: cfg.Name, Value: def, Min: min, Max: max,
+					})
+				}
+			}
+			m.rng = rng
+		}
+
+		if n, exists := fld.Tag.Lookup("optnegatable"); exists && n != "false" {
+			err := makeNegatable(cfg, &m, fld.Type, f.fld)
+			if err.IsNotOk() {
+				return nil, nil, nil, err
+			}
+		}
+
+		if c, exists := fld.Tag.Lookup("optcount"); exists && c != "false" {
+			err := makeCounter(cfg, &m, fld.Type, f.fld)
+			if err.IsNotOk() {
+				return nil, nil, nil, err
+			}
+		}
+
+		if fld.Type.Kind() == reflect.Bool && dec == nil {
+			makeBoolValue(cfg, &m, f.fld)
+		}
+
+		metas[cfg.Name] = m
+	}
+
+	if err := checkDerivedNames(optCfgs, fields, derived); err.IsNotOk() {
+		return nil, nil, nil, err
+	}
+
+	if err := bindChangedFields(changed, optCfgs, metas); err.IsNotOk() {
+		return nil, nil, nil, err
+	}
+
+	return optCfgs, metas, binds, errs.Ok()
+}
+
+func splitDefault(def string) []string {
+	if len(def) == 0 {
+		return []string{}
+	}
+	return strings.Split(def, ",")
+}
+
+// validateDefault checks that the default values can be converted to the
+// field type, in order to fail fast even if the option is given in command
+// line arguments.
+// Because a boolean option takes no option argument, its default value is not
+// set to the OptCfg but is applied by wrapping the event handler.
+func validateDefault(
+	cfg *cliargs.OptCfg, m optMeta, t reflect.Type, fld reflect.Value,
+) errs.Err {
+	if t.Kind() == reflect.Bool {
+		if len(m.defaults) == 0 {
+			return errs.Ok()
+		}
+		b, e := strconv.ParseBool(m.defaults[0])
+		if e != nil {
+			return errs.New(FailToParseDefault{
+				Option: cfg.Name, Field: m.field, Input: m.defaults[0],
+			}, e)
+		}
+		if b && cfg.OnParsed != nil {
+			setter := *cfg.OnParsed
+			onParsed := func(a []string) error {
+				if a == nil {
+					fld.SetBool(true)
+					return nil
+				}
+				return setter(a)
+			}
+			cfg.OnParsed = &onParsed
+		}
+		return errs.Ok()
+	}
+
+	if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+
+	for _, def := range m.elements(cfg.Default) {
+		var e error
+		switch t.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+			reflect.Int64:
+			_, e = strconv.ParseInt(def, 0, t.Bits())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+			reflect.Uint64:
+			_, e = strconv.ParseUint(def, 0, t.Bits())
+		case reflect.Float32, reflect.Float64:
+			_, e = strconv.ParseFloat(def, t.Bits())
+		}
+		if e != nil {
+			return errs.New(FailToParseDefault{
+				Option: cfg.Name, Field: m.field, Input: def,
+			}, e)
+		}
+	}
+
+	return errs.Ok()
+}
+
+// makeSeparated wraps the event handler of the array option to split each
+// option argument with the separator before converting.
+func makeSeparated(cfg *cliargs.OptCfg, m optMeta) {
+	if cfg.OnParsed == nil {
+		return
+	}
+	setter := *cfg.OnParsed
+	name := cfg.Name
+	onParsed := func(a []string) error {
+		if a == nil {
+			return setter(a)
+		}
+		elems := m.elements(a)
+		e := setter(elems)
+		if e == nil {
+			return nil
+		}
+		input := inputOfErr(e)
+		for i, elem := range elems {
+			if elem == input {
+				return reasonErr{option: name, reason: FailToParseElement{
+					Option: name, Field: m.field, Input: elem, Index: i,
+				}, cause: e}
+			}
+		}
+		return e
+	}
+	cfg.OnParsed = &onParsed
+}
+
+// elements splits each of the values with the separator of the option, and
+// returns all the split elements.
+// A separator in a value can be escaped with a backslash, and a backslash can
+// be escaped with another backslash.
+// If the option has no separator, this method returns the values as they are.
+func (m optMeta) elements(values []string) []string {
+	if len(m.sep) == 0 || values == nil {
+		return values
+	}
+
+	elems := make([]string, 0, len(values))
+	for _, v := range values {
+		var b strings.Builder
+		for i := 0; i < len(v); i++ {
+			switch {
+			case v[i] == '\\' && strings.HasPrefix(v[i+1:], m.sep):
+				b.WriteString(m.sep)
+				i += len(m.sep)
+			case v[i] == '\\' && i+1 < len(v) && v[i+1] == '\\':
+				b.WriteByte('\\')
+				i++
+			case strings.HasPrefix(v[i:], m.sep):
+				elems = append(elems, b.String())
+				b.Reset()
+				i += len(m.sep) - 1
+			default:
+				b.WriteByte(v[i])
+			}
+		}
+		elems = append(elems, b.String())
+	}
+	return elems
+}
+
+// validateCustomDefault checks that the default values can be converted to
+// the field type which is not supported by cliargs package, by converting them
+// to a value which is not set to the field.
+func validateCustomDefault(
+	cfg *cliargs.OptCfg, m optMeta, dec *fieldDecoder, t reflect.Type,
+) errs.Err {
+	if cfg.Default == nil {
+		return errs.Ok()
+	}
+	e := dec.set(reflect.New(t).Elem(), cfg.Name, m.elements(cfg.Default))
+	if e != nil {
+		input := strings.Join(cfg.Default, ",")
+		if re, ok := e.(reasonErr); ok {
+			return errs.New(FailToParseDefault{
+				Option: cfg.Name, Field: m.field, Input: input,
+			}, re.toErr())
+		}
+		return errs.New(FailToParseDefault{
+			Option: cfg.Name, Field: m.field, Input: input,
+		}, e)
+	}
+	return errs.Ok()
+}
+
+// makeCounter changes the option to a flag which can be given multiple times,
+// and makes the function to set the number of occurrences to the field.
+// If the option is not given, the field is set to the default value.
+func makeCounter(
+	cfg *cliargs.OptCfg, m *optMeta, t reflect.Type, fld reflect.Value,
+) errs.Err {
+	var def int64
+	if len(m.defaults) > 0 {
+		def, _ = strconv.ParseInt(m.defaults[0], 0, 64)
+	}
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		m.setCount = func(n int) {
+			if n == 0 {
+				fld.SetInt(def)
+			} else {
+				fld.SetInt(int64(n))
+			}
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64:
+		m.setCount = func(n int) {
+			if n == 0 {
+				fld.SetUint(uint64(def))
+			} else {
+				fld.SetUint(uint64(n))
+			}
+		}
+	default:
+		return errs.New(cliargs.IllegalOptionType{
+			Option: cfg.Name, Field: m.field, Type: t,
+		})
+	}
+
+	cfg.HasArg = false
+	cfg.IsArray = false
+	cfg.Default = nil
+	cfg.OnParsed = nil
+	return errs.Ok()
+}
+
+// makeEnvArgs makes option arguments from environment variables for options
+// which have environment variable names but are not given in command line
+// arguments.
+// To check whether options are given, this function scans command line
+// arguments into tokens instead of parsing them, so that errors of unknown or
+// malformed options are left to be reported by the parsing which follows.
+func makeEnvArgs(
+	osArgs []string, optCfgs []cliargs.OptCfg, metas map[string]optMeta,
+) ([]string, map[string]string, errs.Err) {
+	hasEnv := false
+	for _, m := range metas {
+		if len(m.envVar) > 0 {
+			hasEnv = true
+			break
+		}
+	}
+	if !hasEnv {
+		return nil, nil, errs.Ok()
+	}
+
+	counts := countOpts(osArgs, optCfgs)
+
+	var envArgs []string
+	envOpts := make(map[string]string)
+
+	for _, cfg := range optCfgs {
+		m := metas[cfg.Name]
+		if len(m.envVar) == 0 || counts[cfg.Name] > 0 {
+			continue
+		}
+		v := os.Getenv(m.envVar)
+		if len(v) == 0 {
+			continue
+		}
+
+		if m.setCount != nil {
+			n, e := strconv.ParseUint(v, 0, 8)
+			if e != nil {
+				return nil, nil, errs.New(FailToParseEnvVar{
+					Option: cfg.Name, Field: m.field, EnvVar: m.envVar, Input: v,
+				}, e)
+			}
+			for ; n > 0; n-- {
+				envArgs = append(envArgs, optArg(cfg.Name))
+			}
+			continue
+		}
+
+		if m.negatable {
+			b, e := strconv.ParseBool(v)
+			if e != nil {
+				return nil, nil, errs.New(FailToParseEnvVar{
+					Option: cfg.Name, Field: m.field, EnvVar: m.envVar, Input: v,
+				}, e)
+			}
+			envArgs = append(envArgs, optArg(cfg.Name, strconv.FormatBool(b)))
+			continue
+		}
+
+		if m.boolValue {
+			b, e := parseBoolValue(v)
+			if e != nil {
+				return nil, nil, errs.New(FailToParseEnvVar{
+					Option: cfg.Name, Field: m.field, EnvVar: m.envVar, Input: v,
+				}, e)
+			}
+			envArgs = append(envArgs, boolOptArgs(cfg, b)...)
+			continue
+		}
+
+		if !cfg.HasArg {
+			b, e := strconv.ParseBool(v)
+			if e != nil {
+				return nil, nil, errs.New(FailToParseEnvVar{
+					Option: cfg.Name, Field: m.field, EnvVar: m.envVar, Input: v,
+				}, e)
+			}
+			if b {
+				envArgs = append(envArgs, optArg(cfg.Name))
+			}
+			continue
+		}
+
+		envArgs = append(envArgs, optArg(cfg.Name, v))
+		envOpts[cfg.Name] = v
+	}
+
+	return envArgs, envOpts, errs.Ok()
+}
+
+// optArg makes a command line argument of an option with the option name and
+// an optional option argument.
+func optArg(name string, value ...string) string {
+	var a string
+	if len(name) == 1 {
+		a = "-" + name
+	} else {
+		a = "--" + name
+	}
+	if len(value) > 0 {
+		a += "=" + value[0]
+	}
+	return a
+}
+
+// insertArgs inserts the arguments just after the program path, where they
+// are never taken as an option argument of a preceding option nor placed
+// after "--".
+func insertArgs(osArgs []string, args []string) []string {
+	if len(args) == 0 {
+		return osArgs
+	}
+	a := make([]string, 0, len(osArgs)+len(args)+1)
+	if len(osArgs) > 0 {
+		a = append(a, osArgs[0])
+	} else {
+		a = append(a, "")
+	}
+	a = append(a, args...)
+	if len(osArgs) > 1 {
+		a = append(a, osArgs[1:]...)
+	}
+	return a
+}
+
+func inputOfErr(e error) string {
+	switch r := e.(type) {
+	case cliargs.FailToParseInt:
+		return r.Input
+	case cliargs.FailToParseUint:
+		return r.Input
+	case cliargs.FailToParseFloat:
+		return r.Input
+	default:
+		return ""
+	}
+}
+
+func optionOfErr(e error) string {
+	switch r := e.(type) {
+	case reasonErr:
+		return r.option
+	case cliargs.FailToParseInt:
+		return r.Option
+	case cliargs.FailToParseUint:
+		return r.Option
+	case cliargs.FailToParseFloat:
+		return r.Option
+	case cliargs.InvalidOption:
+		return r.GetOpt()
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,456 @@
+package cliargdax_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+	"github.com/sttk/sabi/errs"
+)
+
+func setupForOptions(
+	t *testing.T, args []string, options any,
+) (*cliargdax.DaxConn, errs.Err) {
+	ds := cliargdax.NewDaxSrcWithArgsForOptions(args, options)
+	err := ds.Setup(&noopAsyncGroup{})
+	t.Cleanup(ds.Close)
+	if err.IsNotOk() {
+		return nil, err
+	}
+	dc, err := ds.CreateDaxConn()
+	assert.True(t, err.IsOk())
+	return dc.(*cliargdax.DaxConn), err
+}
+
+func TestOptions_optenv_ok(t *testing.T) {
+	type Options struct {
+		Verbose bool     `optcfg:"verbose,v" optenv:"MY_APP_VERBOSE"`
+		Port    int      `optcfg:"port" optenv:"MY_APP_PORT"`
+		Host    string   `optcfg:"host=localhost" optenv:"MY_APP_HOST"`
+		Tags    []string `optcfg:"tag" optenv:"MY_APP_TAG"`
+		Name    string   `optcfg:"name" optenv:"MY_APP_NAME"`
+	}
+
+	t.Setenv("MY_APP_VERBOSE", "true")
+	t.Setenv("MY_APP_PORT", "8080")
+	t.Setenv("MY_APP_HOST", "example.com")
+	t.Setenv("MY_APP_TAG", "a")
+	t.Setenv("MY_APP_NAME", "")
+
+	options := Options{}
+	conn, err := setupForOptions(t, []string{"app", "--tag", "b", "c"}, &options)
+	assert.True(t, err.IsOk())
+
+	assert.True(t, options.Verbose)
+	assert.Equal(t, options.Port, 8080)
+	assert.Equal(t, options.Host, "example.com")
+	assert.Equal(t, options.Tags, []string{"b"})
+	assert.Equal(t, options.Name, "")
+
+	cmd := conn.Cmd()
+	assert.Equal(t, cmd.Name, "app")
+	assert.Equal(t, cmd.Args(), []string{"c"})
+	assert.True(t, cmd.HasOpt("verbose"))
+	assert.Equal(t, cmd.OptArg("port"), "8080")
+	assert.Equal(t, cmd.OptArgs("tag"), []string{"b"})
+	assert.False(t, cmd.HasOpt("name"))
+}
+
+func TestOptions_optenv_cliHasPrecedence(t *testing.T) {
+	type Options struct {
+		Verbose bool `optcfg:"verbose,v" optenv:"MY_APP_VERBOSE"`
+		Port    int  `optcfg:"port,p=80" optenv:"MY_APP_PORT"`
+	}
+
+	t.Setenv("MY_APP_VERBOSE", "false")
+	t.Setenv("MY_APP_PORT", "8080")
+
+	options := Options{}
+	_, err := setupForOptions(t, []string{"app", "-p", "9000"}, &options)
+	assert.True(t, err.IsOk())
+	assert.False(t, options.Verbose)
+	assert.Equal(t, options.Port, 9000)
+
+	t.Setenv("MY_APP_VERBOSE", "1")
+
+	options = Options{}
+	_, err = setupForOptions(t, []string{"app"}, &options)
+	assert.True(t, err.IsOk())
+	assert.True(t, options.Verbose)
+	assert.Equal(t, options.Port, 8080)
+}
+
+func TestOptions_optenv_notSet(t *testing.T) {
+	type Options struct {
+		Port int `optcfg:"port=80" optenv:"MY_APP_PORT_NOT_SET"`
+		Num  int `optcfg:"num" optenv:"MY_APP_NUM_NOT_SET"`
+	}
+
+	options := Options{}
+	_, err := setupForOptions(t, []string{}, &options)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, options.Port, 80)
+	assert.Equal(t, options.Num, 0)
+}
+
+func TestOptions_optenv_invalidValue(t *testing.T) {
+	type Options struct {
+		Port int `optcfg:"port" optenv:"MY_APP_PORT"`
+	}
+
+	t.Setenv("MY_APP_PORT", "abc")
+
+	options := Options{}
+	_, err := setupForOptions(t, []string{"app"}, &options)
+	switch r := err.Reason().(type) {
+	case cliargdax.FailToParseEnvVar:
+		assert.Equal(t, r.Option, "port")
+		assert.Equal(t, r.Field, "Port")
+		assert.Equal(t, r.EnvVar, "MY_APP_PORT")
+		assert.Equal(t, r.Input, "abc")
+	default:
+		assert.Fail(t, err.Error())
+	}
+
+	options = Options{}
+	_, err = setupForOptions(t, []string{"app", "--port=abc"}, &options)
+	switch r := err.Reason().(type) {
+	case cliargs.FailToParseInt:
+		assert.Equal(t, r.Option, "port")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestOptions_optenv_invalidBoolValue(t *testing.T) {
+	type Options struct {
+		Verbose bool `optcfg:"verbose" optenv:"MY_APP_VERBOSE"`
+	}
+
+	t.Setenv("MY_APP_VERBOSE", "maybe")
+
+	options := Options{}
+	_, err := setupForOptions(t, []string{"app"}, &options)
+	switch r := err.Reason().(type) {
+	case cliargdax.FailToParseEnvVar:
+		assert.Equal(t, r.Option, "verbose")
+		assert.Equal(t, r.Field, "Verbose")
+		assert.Equal(t, r.EnvVar, "MY_APP_VERBOSE")
+		assert.Equal(t, r.Input, "maybe")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestOptions_optdefault_ok(t *testing.T) {
+	type Options struct {
+		Port    int       `optcfg:"port" optdefault:"8080"`
+		Host    string    `optcfg:"host" optdefault:"localhost"`
+		Color   bool      `optcfg:"color" optdefault:"true"`
+		Quiet   bool      `optcfg:"quiet" optdefault:"false"`
+		Ids     []int     `optcfg:"id" optdefault:"1,2,3"`
+		Names   []string  `optcfg:"name" optdefault:""`
+		Rates   []float64 `optcfg:"rate=[0.1]" optdefault:"0.5,1.5"`
+		Timeout uint      `optcfg:"timeout=10"`
+	}
+
+	options := Options{}
+	conn, err := setupForOptions(t, []string{"app"}, &options)
+	assert.True(t, err.IsOk())
+
+	assert.Equal(t, options.Port, 8080)
+	assert.Equal(t, options.Host, "localhost")
+	assert.True(t, options.Color)
+	assert.False(t, options.Quiet)
+	assert.Equal(t, options.Ids, []int{1, 2, 3})
+	assert.Equal(t, options.Names, []string{})
+	assert.Equal(t, options.Rates, []float64{0.5, 1.5})
+	assert.Equal(t, options.Timeout, uint(10))
+
+	cfgs := conn.OptCfgs()
+	assert.Equal(t, cfgs[0].Default, []string{"8080"})
+	assert.Equal(t, cfgs[1].Default, []string{"localhost"})
+	assert.Nil(t, cfgs[2].Default)
+	assert.Nil(t, cfgs[3].Default)
+	assert.Equal(t, cfgs[4].Default, []string{"1", "2", "3"})
+	assert.Equal(t, cfgs[5].Default, []string{})
+	assert.Equal(t, cfgs[6].Default, []string{"0.5", "1.5"})
+	assert.Equal(t, cfgs[7].Default, []string{"10"})
+
+	cmd := conn.Cmd()
+	assert.Equal(t, cmd.OptArg("port"), "8080")
+	assert.Equal(t, cmd.OptArgs("id"), []string{"1", "2", "3"})
+}
+
+func TestOptions_optdefault_overriddenByCli(t *testing.T) {
+	type Options struct {
+		Port  int   `optcfg:"port" optdefault:"8080"`
+		Color bool  `optcfg:"color" optdefault:"true"`
+		Ids   []int `optcfg:"id" optdefault:"1,2,3"`
+	}
+
+	options := Options{}
+	_, err := setupForOptions(t,
+		[]string{"app", "--port=80", "--color", "--id=9"}, &options)
+	assert.True(t, err.IsOk())
+
+	assert.Equal(t, options.Port, 80)
+	assert.True(t, options.Color)
+	assert.Equal(t, options.Ids, []int{9})
+}
+
+func TestOptions_optdefault_overriddenByEnv(t *testing.T) {
+	type Options struct {
+		Port int `optcfg:"port" optenv:"MY_APP_PORT" optdefault:"8080"`
+	}
+
+	t.Setenv("MY_APP_PORT", "9090")
+
+	options := Options{}
+	_, err := setupForOptions(t, []string{"app"}, &options)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, options.Port, 9090)
+}
+
+func TestOptions_optdefault_invalid(t *testing.T) {
+	type IntOptions struct {
+		Port int `optcfg:"port" optdefault:"http"`
+	}
+	type BoolOptions struct {
+		Color bool `optcfg:"color" optdefault:"yes"`
+	}
+	type ArrayOptions struct {
+		Ids []uint8 `optcfg:"id" optdefault:"1,256"`
+	}
+	type TagOptions struct {
+		Rate float32 `optcfg:"rate=x"`
+	}
+
+	testCases := []struct {
+		options any
+		option  string
+		field   string
+		input   string
+	}{
+		{&IntOptions{}, "port", "Port", "http"},
+		{&BoolOptions{}, "color", "Color", "yes"},
+		{&ArrayOptions{}, "id", "Ids", "256"},
+		{&TagOptions{}, "rate", "Rate", "x"},
+	}
+
+	for _, tc := range testCases {
+		_, err := setupForOptions(t, []string{"app", "--" + tc.option}, tc.options)
+		switch r := err.Reason().(type) {
+		case cliargdax.FailToParseDefault:
+			assert.Equal(t, r.Option, tc.option)
+			assert.Equal(t, r.Field, tc.field)
+			assert.Equal(t, r.Input, tc.input)
+		default:
+			assert.Fail(t, err.Error())
+		}
+	}
+}
+
+func TestOptions_optcount(t *testing.T) {
+	type Options struct {
+		Verbose int   `optcfg:"verbose,v" optcount:"true"`
+		Quiet   uint8 `optcfg:"quiet,q" optcount:"true"`
+		Level   int   `optcfg:"level,l=2" optcount:"true"`
+	}
+
+	options := Options{}
+	conn, err := setupForOptions(t, []string{
+		"app", "-vvq", "--verbose", "-v", "foo",
+	}, &options)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, options.Verbose, 4)
+	assert.Equal(t, options.Quiet, uint8(1))
+	assert.Equal(t, options.Level, 2)
+	assert.Equal(t, conn.Cmd().Args(), []string{"foo"})
+	assert.True(t, conn.Cmd().HasOpt("verbose"))
+	assert.False(t, conn.OptCfgs()[0].HasArg)
+	assert.Equal(t, conn.OptCount("verbose"), 4)
+	assert.Equal(t, conn.OptCount("level"), 0)
+
+	options = Options{}
+	_, err = setupForOptions(t, []string{"app", "-lll"}, &options)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, options.Level, 3)
+}
+
+func TestOptions_optcount_env(t *testing.T) {
+	type Options struct {
+		Verbose int `optcfg:"verbose,v" optcount:"true" optenv:"MY_APP_VERBOSE"`
+	}
+
+	t.Setenv("MY_APP_VERBOSE", "3")
+
+	options := Options{}
+	_, err := setupForOptions(t, []string{"app"}, &options)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, options.Verbose, 3)
+
+	options = Options{}
+	_, err = setupForOptions(t, []string{"app", "-v"}, &options)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, options.Verbose, 1)
+}
+
+func TestOptions_optcount_error(t *testing.T) {
+	type Options struct {
+		Verbose int    `optcfg:"verbose,v" optcount:"true"`
+		Name    string `optcfg:"name" optcount:"true"`
+	}
+
+	options := Options{}
+	_, err := setupForOptions(t, []string{"app", "-v"}, &options)
+	switch r := err.Reason().(type) {
+	case cliargs.IllegalOptionType:
+		assert.Equal(t, r.Option, "name")
+		assert.Equal(t, r.Field, "Name")
+	default:
+		assert.Fail(t, err.Error())
+	}
+
+	type Options2 struct {
+		Verbose int `optcfg:"verbose,v" optcount:"true"`
+	}
+
+	options2 := Options2{}
+	_, err = setupForOptions(t, []string{"app", "--verbose=3"}, &options2)
+	switch r := err.Reason().(type) {
+	case cliargs.OptionTakesNoArg:
+		assert.Equal(t, r.Option, "verbose")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestOptions_optsep(t *testing.T) {
+	type Options struct {
+		Hosts  []string  `optcfg:"hosts" optsep:","`
+		Ports  []int     `optcfg:"ports=[80]" optsep:";"`
+		Ratios []float64 `optcfg:"ratios" optsep:"::"`
+		Names  []string  `optcfg:"names"`
+	}
+
+	options := Options{}
+	conn, err := setupForOptions(t, []string{
+		"app", "--hosts", "a,b", "--hosts", "c", "--hosts=d\\,e,f\\\\",
+		"--ratios=0.5::1.5", "--names=x,y",
+	}, &options)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, options.Hosts, []string{"a", "b", "c", "d,e", "f\\"})
+	assert.Equal(t, options.Ports, []int{80})
+	assert.Equal(t, options.Ratios, []float64{0.5, 1.5})
+	assert.Equal(t, options.Names, []string{"x,y"})
+	assert.Equal(t, conn.Cmd().OptArgs("hosts"),
+		[]string{"a,b", "c", "d\\,e,f\\\\"})
+
+	options = Options{}
+	_, err = setupForOptions(t, []string{
+		"app", "--ports=1;2", "--ports", "3;x;4",
+	}, &options)
+	switch r := err.Reason().(type) {
+	case cliargdax.FailToParseElement:
+		assert.Equal(t, r.Option, "ports")
+		assert.Equal(t, r.Field, "Ports")
+		assert.Equal(t, r.Input, "x")
+		assert.Equal(t, r.Index, 3)
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestOptions_optsep_withConstraints(t *testing.T) {
+	type Options struct {
+		Formats []string `optcfg:"format" optsep:"," optchoices:"json,yaml"`
+		Ports   []int    `optcfg:"port" optsep:"," optmax:"100"`
+	}
+
+	options := Options{}
+	_, err := setupForOptions(t, []string{
+		"app", "--format=json,yaml", "--port=1,100",
+	}, &options)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, options.Formats, []string{"json", "yaml"})
+	assert.Equal(t, options.Ports, []int{1, 100})
+
+	options = Options{}
+	_, err = setupForOptions(t, []string{"app", "--port=1,101"}, &options)
+	switch r := err.Reason().(type) {
+	case cliargdax.OptionValueOutOfRange:
+		assert.Equal(t, r.Option, "port")
+		assert.Equal(t, r.Value, "101")
+	default:
+		assert.Fail(t, err.Error())
+	}
+
+	type Options2 struct {
+		Ports []int `optcfg:"port" optsep:"," optdefault:"1,x"`
+	}
+
+	options2 := Options2{}
+	_, err = setupForOptions(t, []string{"app"}, &options2)
+	switch r := err.Reason().(type) {
+	case cliargdax.FailToParseDefault:
+		assert.Equal(t, r.Option, "port")
+		assert.Equal(t, r.Input, "x")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestOptions_MakeOptCfgsFor(t *testing.T) {
+	type Options struct {
+		Verbose int      `optcfg:"verbose,v" optcount:"true" optdesc:"Verbosity."`
+		Format  string   `optcfg:"format=json" optchoices:"json,yaml"`
+		Tags    []string `optcfg:"tag" optsep:","`
+		Secret  bool     `optcfg:"secret" opthidden:"true"`
+	}
+
+	options := Options{}
+	cfgs, err := cliargdax.MakeOptCfgsFor(&options)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, len(cfgs), 4)
+
+	assert.Equal(t, cfgs[0].Name, "verbose")
+	assert.Equal(t, cfgs[0].Aliases, []string{"v"})
+	assert.False(t, cfgs[0].HasArg)
+	assert.Equal(t, cfgs[0].Desc, "Verbosity.")
+
+	assert.Equal(t, cfgs[1].Name, "format")
+	assert.True(t, cfgs[1].HasArg)
+	assert.Equal(t, cfgs[1].Default, []string{"json"})
+	assert.Equal(t, cfgs[1].ArgHelp, "{json|yaml}")
+
+	assert.Equal(t, cfgs[2].Name, "tag")
+	assert.True(t, cfgs[2].IsArray)
+
+	assert.Equal(t, cfgs[3].Name, "secret")
+
+	assert.Equal(t, options, Options{})
+}
+
+func TestOptions_MakeOptCfgsFor_error(t *testing.T) {
+	type Options struct {
+		Level float64 `optcfg:"level" optcount:"true"`
+	}
+
+	_, err := cliargdax.MakeOptCfgsFor(&Options{})
+	switch r := err.Reason().(type) {
+	case cliargs.IllegalOptionType:
+		assert.Equal(t, r.Option, "level")
+		assert.Equal(t, r.Field, "Level")
+	default:
+		assert.Fail(t, err.Error())
+	}
+
+	_, err = cliargdax.MakeOptCfgsFor(Options{})
+	switch err.Reason().(type) {
+	case cliargs.OptionStoreIsNotChangeable:
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
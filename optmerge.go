@@ -0,0 +1,174 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"sort"
+
+	"github.com/sttk/cliargs"
+	"github.com/sttk/sabi/errs"
+)
+
+// MergeOption is the enum type which changes the rules of MergeOptCfgs
+// function.
+type MergeOption int
+
+const (
+	// AllowOverride indicates that an option configuration in the extra array
+	// overrides the option configurations in the base array of which names or
+	// aliases collide with it.
+	AllowOverride MergeOption = iota + 1
+)
+
+// OptCfgOrder is the enum type which indicates the key to sort option
+// configurations with SortOptCfgs function.
+type OptCfgOrder int
+
+const (
+	// DeclaredOrder indicates that option configurations are kept in the
+	// order in which they are declared.
+	DeclaredOrder OptCfgOrder = iota
+
+	// ByName indicates that option configurations are sorted by their names.
+	ByName
+)
+
+// MergeOptCfgs is the function to concatenate the option configurations in
+// extra to the ones in base, for example to append hand-written option
+// configurations, like the wildcard "*" configuration, to the ones made from
+// an option store.
+// The merged array is checked by ValidateOptCfgs function, so if a name or an
+// alias in extra collides with one in base, this function returns an errs.Err
+// with the reason: DuplicateOptName, DuplicateOptAlias, or
+// AliasCollidesWithName, of which indexes are the ones in the merged array.
+// If AllowOverride is specified, an option configuration in extra wins
+// instead: it replaces the one of the same name in base at its position, and
+// the aliases in base which collide with its name or aliases are removed, as
+// well as the option configurations in base of which names collide with them.
+// A replacing option configuration of which OnParsed field is nil takes over
+// the field of the replaced one, so that the option value is still stored
+// into the field of an option store.
+// The arrays passed to this function are not modified.
+func MergeOptCfgs(
+	base, extra []cliargs.OptCfg, opts ...MergeOption,
+) ([]cliargs.OptCfg, errs.Err) {
+	var cfgs []cliargs.OptCfg
+	if hasMergeOption(opts, AllowOverride) {
+		cfgs = overrideOptCfgs(base, extra)
+	} else {
+		cfgs = make([]cliargs.OptCfg, 0, len(base)+len(extra))
+		cfgs = append(append(cfgs, base...), extra...)
+	}
+	if err := ValidateOptCfgs(cfgs); err.IsNotOk() {
+		return nil, err
+	}
+	return cfgs, errs.Ok()
+}
+
+func hasMergeOption(opts []MergeOption, opt MergeOption) bool {
+	for _, o := range opts {
+		if o == opt {
+			return true
+		}
+	}
+	return false
+}
+
+// overrideOptCfgs merges the option configurations in extra to the ones in
+// base, removing the names and aliases in base which collide with extra.
+func overrideOptCfgs(base, extra []cliargs.OptCfg) []cliargs.OptCfg {
+	extraIndexes := make(map[string]int, len(extra))
+	taken := make(map[string]bool, len(extra))
+	for i, cfg := range extra {
+		if _, exists := extraIndexes[cfg.Name]; !exists {
+			extraIndexes[cfg.Name] = i
+		}
+		taken[cfg.Name] = true
+		for _, a := range cfg.Aliases {
+			taken[a] = true
+		}
+	}
+
+	cfgs := make([]cliargs.OptCfg, 0, len(base)+len(extra))
+	placed := make([]bool, len(extra))
+
+	for _, cfg := range base {
+		if i, exists := extraIndexes[cfg.Name]; exists {
+			if !placed[i] {
+				e := extra[i]
+				if e.OnParsed == nil {
+					e.OnParsed = cfg.OnParsed
+				}
+				cfgs = append(cfgs, e)
+				placed[i] = true
+			}
+			continue
+		}
+		if taken[cfg.Name] {
+			continue
+		}
+		var aliases []string
+		for _, a := range cfg.Aliases {
+			if !taken[a] {
+				aliases = append(aliases, a)
+			}
+		}
+		if len(aliases) != len(cfg.Aliases) {
+			cfg.Aliases = aliases
+		}
+		cfgs = append(cfgs, cfg)
+	}
+
+	for i, cfg := range extra {
+		if !placed[i] {
+			cfgs = append(cfgs, cfg)
+		}
+	}
+	return cfgs
+}
+
+// SortOptCfgs is the function to make a sorted copy of the option
+// configurations, for example to control the order of options in the help
+// text.
+// The keys are applied in the specified order, and the option configurations
+// which are equal by all the keys are kept in their declared order.
+// If no key is specified, or only DeclaredOrder is specified, the copy is in
+// the declared order.
+// The array passed to this function is not modified.
+func SortOptCfgs(cfgs []cliargs.OptCfg, by ...OptCfgOrder) []cliargs.OptCfg {
+	sorted := append([]cliargs.OptCfg{}, cfgs...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		for _, key := range by {
+			if key == ByName && sorted[i].Name != sorted[j].Name {
+				return sorted[i].Name < sorted[j].Name
+			}
+		}
+		return false
+	})
+	return sorted
+}
+
+// buildOptCfgsWithExtra makes the option configurations and others from the
+// option stores by buildOptCfgsForStores function, and merges the extra
+// option configurations, which are specified with
+// NewDaxSrcForOptionsWithExtra function, to them.
+func (ds *DaxSrc) buildOptCfgsWithExtra(
+	stores []optStore, extra []cliargs.OptCfg,
+) ([]cliargs.OptCfg, map[string]optMeta, []argBinding, errs.Err) {
+	cfgs, metas, binds, err := buildOptCfgsForStores(
+		stores, ds.decoders, ds.nameMapping,
+	)
+	if err.IsNotOk() {
+		return nil, nil, nil, err
+	}
+	if len(extra) == 0 {
+		return cfgs, metas, binds, errs.Ok()
+	}
+	cfgs, err = MergeOptCfgs(cfgs, extra, ds.mergeOpts...)
+	if err.IsNotOk() {
+		return nil, nil, nil, err
+	}
+	return cfgs, metas, binds, errs.Ok()
+}
@@ -0,0 +1,152 @@
+package cliargdax_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+)
+
+var mergeBaseCfgs = []cliargs.OptCfg{
+	cliargs.OptCfg{Name: "verbose", Aliases: []string{"v"}},
+	cliargs.OptCfg{Name: "output", Aliases: []string{"o"}, HasArg: true},
+	cliargs.OptCfg{Name: "quiet", Aliases: []string{"q"}},
+}
+
+func optCfgNames(cfgs []cliargs.OptCfg) []string {
+	names := make([]string, len(cfgs))
+	for i, cfg := range cfgs {
+		names[i] = cfg.Name
+	}
+	return names
+}
+
+func TestMergeOptCfgs_concatenate(t *testing.T) {
+	extra := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "debug", Aliases: []string{"d"}},
+		cliargs.OptCfg{Name: "*"},
+	}
+	cfgs, err := cliargdax.MergeOptCfgs(mergeBaseCfgs, extra)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, optCfgNames(cfgs),
+		[]string{"verbose", "output", "quiet", "debug", "*"})
+	assert.Equal(t, len(mergeBaseCfgs), 3)
+}
+
+func TestMergeOptCfgs_collision(t *testing.T) {
+	_, err := cliargdax.MergeOptCfgs(mergeBaseCfgs, []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "output", HasArg: true},
+	})
+	switch r := err.Reason().(type) {
+	case cliargdax.DuplicateOptName:
+		assert.Equal(t, r.Name, "output")
+		assert.Equal(t, r.Indexes, []int{1, 3})
+	default:
+		assert.Fail(t, err.Error())
+	}
+
+	_, err = cliargdax.MergeOptCfgs(mergeBaseCfgs, []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "debug", Aliases: []string{"v"}},
+	})
+	switch r := err.Reason().(type) {
+	case cliargdax.DuplicateOptAlias:
+		assert.Equal(t, r.Alias, "v")
+		assert.Equal(t, r.Indexes, []int{0, 3})
+	default:
+		assert.Fail(t, err.Error())
+	}
+
+	_, err = cliargdax.MergeOptCfgs(mergeBaseCfgs, []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "q"},
+	})
+	switch r := err.Reason().(type) {
+	case cliargdax.AliasCollidesWithName:
+		assert.Equal(t, r.Alias, "q")
+		assert.Equal(t, r.Name, "quiet")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestMergeOptCfgs_AllowOverride(t *testing.T) {
+	extra := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "output", HasArg: true, Desc: "Output file."},
+		cliargs.OptCfg{Name: "debug", Aliases: []string{"v"}},
+		cliargs.OptCfg{Name: "q", Aliases: []string{"silent"}},
+	}
+	cfgs, err := cliargdax.MergeOptCfgs(
+		mergeBaseCfgs, extra, cliargdax.AllowOverride,
+	)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, cfgs, []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "verbose"},
+		cliargs.OptCfg{Name: "output", HasArg: true, Desc: "Output file."},
+		cliargs.OptCfg{Name: "quiet"},
+		cliargs.OptCfg{Name: "debug", Aliases: []string{"v"}},
+		cliargs.OptCfg{Name: "q", Aliases: []string{"silent"}},
+	})
+	assert.Equal(t, mergeBaseCfgs[0].Aliases, []string{"v"})
+	assert.Equal(t, mergeBaseCfgs[2].Aliases, []string{"q"})
+}
+
+func TestSortOptCfgs(t *testing.T) {
+	cfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "verbose"},
+		cliargs.OptCfg{Name: "*"},
+		cliargs.OptCfg{Name: "alpha"},
+		cliargs.OptCfg{Name: "output"},
+	}
+	assert.Equal(t, optCfgNames(cliargdax.SortOptCfgs(cfgs, cliargdax.ByName)),
+		[]string{"*", "alpha", "output", "verbose"})
+	assert.Equal(t,
+		optCfgNames(cliargdax.SortOptCfgs(cfgs, cliargdax.DeclaredOrder)),
+		[]string{"verbose", "*", "alpha", "output"})
+	assert.Equal(t, optCfgNames(cliargdax.SortOptCfgs(cfgs)),
+		[]string{"verbose", "*", "alpha", "output"})
+	assert.Equal(t, optCfgNames(cfgs),
+		[]string{"verbose", "*", "alpha", "output"})
+}
+
+type extraOptions struct {
+	Verbose bool   `optcfg:"verbose,v" optdesc:"Be verbose."`
+	Output  string `optcfg:"output,o=out.txt" optdesc:"Output file."`
+}
+
+func TestNewDaxSrcForOptionsWithExtra(t *testing.T) {
+	options := extraOptions{}
+	ds := cliargdax.NewDaxSrcForOptionsWithExtra(&options, []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "dry-run", Aliases: []string{"n"}},
+		cliargs.OptCfg{Name: "*"},
+	}).WithArgs([]string{"app", "-v", "-n", "--unknown=1", "x"})
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.True(t, options.Verbose)
+	assert.Equal(t, options.Output, "out.txt")
+	assert.True(t, conn.Cmd().HasOpt("dry-run"))
+	assert.Equal(t, conn.Cmd().OptArg("unknown"), "1")
+	assert.Equal(t, optCfgNames(conn.OptCfgs()),
+		[]string{"verbose", "output", "dry-run", "*"})
+}
+
+func TestNewDaxSrcForOptionsWithExtra_collision(t *testing.T) {
+	options := extraOptions{}
+	ds := cliargdax.NewDaxSrcForOptionsWithExtra(&options, []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "output", HasArg: true, Desc: "Output path."},
+	}).WithArgs([]string{"app", "--output=a"})
+	_, err := setupWithOptCfgs(t, ds)
+	switch r := err.Reason().(type) {
+	case cliargdax.DuplicateOptName:
+		assert.Equal(t, r.Name, "output")
+	default:
+		assert.Fail(t, err.Error())
+	}
+
+	ds = cliargdax.NewDaxSrcForOptionsWithExtra(&options, []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "output", HasArg: true, Desc: "Output path."},
+	}, cliargdax.AllowOverride).WithArgs([]string{"app", "--output=a"})
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, options.Output, "a")
+	assert.Equal(t, conn.OptCfgs()[1].Desc, "Output path.")
+}
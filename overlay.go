@@ -0,0 +1,208 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/sttk/cliargs"
+)
+
+// connOverlay is the struct type which holds the option values and the option
+// store which override the results of parsing only for reads through a
+// DaxConn instance.
+// The fields route and skipArgs are the route of which handler is invoked
+// with the DaxConn instance and the number of the command arguments skipped
+// for it.
+type connOverlay struct {
+	opts       map[string][]string
+	options    any
+	hasOptions bool
+	route      string
+	skipArgs   int
+	mutex      sync.Mutex
+}
+
+// WithOptOverride is the method to override the option argument of the
+// specified option only for reads through this DaxConn instance, like
+// DaxConn#Cmd method.
+// The parsed results in the DaxSrc instance are not changed, so other
+// DaxConn instances see the original values.
+// If the option is configured to take no option argument, the value is
+// ignored and the option is just treated as given.
+// If the option name cannot be given in command line arguments, for example
+// because it has an invalid character, the override is ignored and a warning
+// is written to the Logger set with DaxSrc#WithLogger method.
+// The override vanishes when this DaxConn instance is closed.
+// This method returns this DaxConn instance itself for method chaining.
+func (conn *DaxConn) WithOptOverride(name, value string) *DaxConn {
+	conn.ds.parseLazily()
+	conn.ds.mutex.RLock()
+	takesArg := true
+	for _, cfg := range conn.ds.optCfgs {
+		if cfg.Name == name {
+			takesArg = cfg.HasArg
+			break
+		}
+	}
+	conn.ds.mutex.RUnlock()
+
+	a := []string{}
+	if takesArg {
+		a = []string{value}
+	}
+	if _, e := makeCmd("", nil, map[string][]string{name: a}); e != nil {
+		conn.ds.mutex.RLock()
+		conn.ds.logOverrideErr(e)
+		conn.ds.mutex.RUnlock()
+		return conn
+	}
+
+	conn.overlay.mutex.Lock()
+	defer conn.overlay.mutex.Unlock()
+	if conn.overlay.opts == nil {
+		conn.overlay.opts = make(map[string][]string)
+	}
+	conn.overlay.opts[name] = a
+	return conn
+}
+
+// WithOptionsOverride is the method to override the option store only for
+// reads through this DaxConn instance by DaxConn#Options method.
+// The option store in the DaxSrc instance is not changed, so other DaxConn
+// instances see the original one.
+// The override vanishes when this DaxConn instance is closed.
+// This method returns this DaxConn instance itself for method chaining.
+func (conn *DaxConn) WithOptionsOverride(opts any) *DaxConn {
+	conn.overlay.mutex.Lock()
+	defer conn.overlay.mutex.Unlock()
+	conn.overlay.options = opts
+	conn.overlay.hasOptions = true
+	return conn
+}
+
+// overriddenOptions returns the option store set by WithOptionsOverride
+// method, and whether it is set.
+func (conn *DaxConn) overriddenOptions() (any, bool) {
+	conn.overlay.mutex.Lock()
+	defer conn.overlay.mutex.Unlock()
+	return conn.overlay.options, conn.overlay.hasOptions
+}
+
+// overriddenCmd returns the cliargs.Cmd of the DaxSrc instance, of which
+// option arguments are replaced with ones set by WithOptOverride method, and
+// of which command arguments skipped for a route are removed.
+// This method must be called while the read lock of the DaxSrc is held.
+func (conn *DaxConn) overriddenCmd() cliargs.Cmd {
+	ds := conn.ds
+	conn.overlay.mutex.Lock()
+	defer conn.overlay.mutex.Unlock()
+	if len(conn.overlay.opts) == 0 && conn.overlay.skipArgs == 0 {
+		return ds.cmd
+	}
+
+	opts := make(map[string][]string)
+	for _, name := range cmdOptNames(ds.cmd, ds.optCfgs, ds.counts) {
+		opts[name] = ds.cmd.OptArgs(name)
+	}
+	for name, a := range conn.overlay.opts {
+		opts[name] = a
+	}
+	args := ds.cmd.Args()
+	if n := conn.overlay.skipArgs; n <= len(args) {
+		args = args[n:]
+	}
+	cmd, e := makeCmd(ds.cmd.Name, args, opts)
+	if e != nil {
+		ds.logOverrideErr(e)
+		return ds.cmd
+	}
+	return cmd
+}
+
+// overriddenOptNames returns the names of the options overridden by
+// WithOptOverride method.
+func (conn *DaxConn) overriddenOptNames() []string {
+	conn.overlay.mutex.Lock()
+	defer conn.overlay.mutex.Unlock()
+	names := make([]string, 0, len(conn.overlay.opts))
+	for name := range conn.overlay.opts {
+		names = append(names, name)
+	}
+	return names
+}
+
+// clear discards the option values and the option store of this overlay.
+func (ov *connOverlay) clear() {
+	ov.mutex.Lock()
+	defer ov.mutex.Unlock()
+	ov.opts = nil
+	ov.options = nil
+	ov.hasOptions = false
+}
+
+// cmdOptNames returns the sorted names of the options which the cliargs.Cmd
+// has, which are the configured options and the options counted in command
+// line arguments.
+func cmdOptNames(
+	cmd cliargs.Cmd, optCfgs []cliargs.OptCfg, counts map[string]int,
+) []string {
+	var names []string
+	seen := make(map[string]bool)
+	add := func(name string) {
+		if !seen[name] && cmd.HasOpt(name) {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+	for name := range counts {
+		add(name)
+	}
+	for _, cfg := range optCfgs {
+		if cfg.Name != "*" {
+			add(cfg.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// makeCmd makes a cliargs.Cmd which has the command name, the command
+// arguments, and the options, by parsing command line arguments made from
+// them.
+// If the command line arguments cannot be parsed, for example because an
+// option name has an invalid character, this function returns the error of
+// the parsing.
+func makeCmd(
+	name string, args []string, opts map[string][]string,
+) (cliargs.Cmd, error) {
+	names := make([]string, 0, len(opts))
+	for n := range opts {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	cfgs := make([]cliargs.OptCfg, 0, len(names))
+	osArgs := []string{name}
+	for _, n := range names {
+		a := opts[n]
+		cfgs = append(cfgs, cliargs.OptCfg{
+			Name: n, HasArg: len(a) > 0, IsArray: len(a) > 1,
+		})
+		if len(a) == 0 {
+			osArgs = append(osArgs, optArg(n))
+		}
+		for _, v := range a {
+			osArgs = append(osArgs, optArg(n, v))
+		}
+	}
+	osArgs = append(osArgs, "--")
+	osArgs = append(osArgs, args...)
+
+	cmd, e := cliargs.ParseWith(osArgs, cfgs)
+	cmd.Name = name
+	return cmd, e
+}
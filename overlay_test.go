@@ -0,0 +1,118 @@
+package cliargdax_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+	"github.com/sttk/sabi"
+	"github.com/sttk/sabi/errs"
+)
+
+func TestOverlay_WithOptOverride(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"/path/to/app", "--level=1", "--tag=a", "--tag=b", "file",
+	}, []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "level", HasArg: true},
+		cliargs.OptCfg{Name: "tag", HasArg: true, IsArray: true},
+		cliargs.OptCfg{Name: "dry-run"},
+		cliargs.OptCfg{Name: "env", HasArg: true, IsArray: true},
+	})
+	conn1, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	dc, err := ds.CreateDaxConn()
+	assert.True(t, err.IsOk())
+	conn2 := dc.(*cliargdax.DaxConn)
+
+	conn1.WithOptOverride("level", "9").
+		WithOptOverride("dry-run", "ignored").
+		WithOptOverride("env", "k=v")
+
+	cmd := conn1.Cmd()
+	assert.Equal(t, cmd.Name, "app")
+	assert.Equal(t, cmd.Args(), []string{"file"})
+	assert.Equal(t, cmd.OptArg("level"), "9")
+	assert.Equal(t, cmd.OptArgs("tag"), []string{"a", "b"})
+	assert.True(t, cmd.HasOpt("dry-run"))
+	assert.Equal(t, cmd.OptArgs("dry-run"), []string{})
+	assert.Equal(t, conn1.OptMap("env"), map[string]string{"k": "v"})
+
+	cmd = conn2.Cmd()
+	assert.Equal(t, cmd.OptArg("level"), "1")
+	assert.False(t, cmd.HasOpt("dry-run"))
+	assert.Equal(t, conn2.OptMap("env"), map[string]string{})
+
+	conn1.Close()
+	assert.Equal(t, conn1.Cmd().OptArg("level"), "1")
+	assert.False(t, conn1.Cmd().HasOpt("dry-run"))
+}
+
+func TestOverlay_WithOptOverride_invalidName(t *testing.T) {
+	logger := &recordingLogger{}
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "--level=1", "file",
+	}, []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "level", HasArg: true},
+	}).WithLogger(logger)
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	conn.WithOptOverride("bad name", "x").WithOptOverride("level", "9")
+
+	cmd := conn.Cmd()
+	assert.Equal(t, cmd.OptArg("level"), "9")
+	assert.False(t, cmd.HasOpt("bad name"))
+	assert.Equal(t, cmd.Args(), []string{"file"})
+	assert.Equal(t, len(logger.warns), 1)
+	assert.True(t, strings.HasPrefix(logger.warns[0],
+		"cliargdax: option override ignored: "), logger.warns[0])
+}
+
+func TestOverlay_WithOptionsOverride(t *testing.T) {
+	type Options struct {
+		Level int `optcfg:"level=1"`
+	}
+	opts := Options{}
+	conn1, err := setupForOptions(t, []string{"app"}, &opts)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, opts.Level, 1)
+
+	dryRun := Options{Level: 5}
+	conn1.WithOptionsOverride(&dryRun)
+	assert.Equal(t, conn1.Options(), &dryRun)
+
+	conn1.SetOptions(&Options{Level: 7})
+	assert.Equal(t, conn1.Options(), &dryRun)
+
+	conn1.Close()
+	assert.Equal(t, conn1.Options().(*Options).Level, 7)
+}
+
+func TestOverlay_vanishesAfterTxn(t *testing.T) {
+	base := sabi.NewDaxBase()
+	defer base.Close()
+
+	base.Uses("cliarg", cliargdax.NewDaxSrcWithArgs(
+		[]string{"/path/to/app", "--mode=real"},
+	))
+
+	err := sabi.Txn(base, func(dax sabi.Dax) errs.Err {
+		conn, err := sabi.GetDaxConn[*cliargdax.DaxConn](base, "cliarg")
+		assert.True(t, err.IsOk())
+		conn.WithOptOverride("mode", "dry")
+		assert.Equal(t, conn.Cmd().OptArg("mode"), "dry")
+		return errs.Ok()
+	})
+	assert.True(t, err.IsOk())
+
+	err = sabi.Txn(base, func(dax sabi.Dax) errs.Err {
+		conn, err := sabi.GetDaxConn[*cliargdax.DaxConn](base, "cliarg")
+		assert.True(t, err.IsOk())
+		assert.Equal(t, conn.Cmd().OptArg("mode"), "real")
+		return errs.Ok()
+	})
+	assert.True(t, err.IsOk())
+}
@@ -0,0 +1,428 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"context"
+	"io/fs"
+	"path"
+	"time"
+
+	"github.com/sttk/cliargs"
+	"github.com/sttk/sabi/errs"
+)
+
+var anyOptCfgs = []cliargs.OptCfg{cliargs.OptCfg{Name: "*"}}
+
+type parseResult struct {
+	ctx     context.Context
+	fsys    fs.FS
+	cmd     cliargs.Cmd
+	optCfgs []cliargs.OptCfg
+	metas   map[string]optMeta
+	osArgs  []string
+	counts  map[string]int
+	mode    parseMode
+
+	termIndex int
+	hasTerm   bool
+
+	unknownOpts  []string
+	wildcardOpts []string
+	warnings     []errs.Err
+
+	binds []argBinding
+
+	helpRequested    bool
+	versionRequested bool
+	appVersion       string
+
+	aliases      map[string]deprecatedAlias
+	optAliases   map[string]string
+	deprecations []OptionDeprecated
+	rawOptArgs   map[string][]string
+	spellings    map[string][]string
+	tupleSizes   map[string][]int
+	occurrences  []OptOccurrence
+	optBlocks    []OptBlockCfg
+	blocks       map[string][]map[string][]string
+
+	config     map[string]any
+	configPath string
+
+	sources map[string]ValueSource
+
+	callbacks []optCallback
+
+	dupPolicy   DuplicatePolicy
+	dupPolicies map[string]DuplicatePolicy
+	duplicates  map[string]OptionGivenTwice
+}
+
+// parseMode is the set of switches which change the rules of parsing command
+// line arguments.
+type parseMode struct {
+	allowAbbrev     bool
+	ignoreCase      bool
+	stopAtFirstArg  bool
+	ignoreUnknown   bool
+	collectAll      bool
+	allowSlash      bool
+	allowSingleDash bool
+	interpolate     bool
+}
+
+func (ds *DaxSrc) parse() (err errs.Err) {
+	if ds.recordTokens {
+		defer func() {
+			ds.tokenInfos = ds.explainTokens(err)
+		}()
+	}
+
+	if err := ds.checkMetas(); err.IsNotOk() {
+		return err
+	}
+
+	begin, reads := time.Now(), 0
+	defer func() {
+		ds.stats = ds.makeStats(reads, time.Since(begin))
+	}()
+
+	start := ds.now()
+
+	if ds.expandRspFiles {
+		args, files, n, err := expandResponseFiles(
+			ds.ctx, ds.fsys, ds.rawArgs,
+		)
+		if err.IsNotOk() {
+			return err
+		}
+		reads = n
+		ds.logExpansion("response files", ds.osArgs, args)
+		ds.osArgs = args
+		ds.argFiles = files
+	}
+
+	if ds.stdinArgs != nil {
+		args, files, err := expandStdinArgs(
+			ds.ctx, ds.osArgs, ds.argFiles, *ds.stdinArgs,
+		)
+		if err.IsNotOk() {
+			return err
+		}
+		ds.logExpansion("stdin args", ds.osArgs, args)
+		ds.osArgs = args
+		ds.argFiles = files
+	}
+
+	if ds.expandRspFiles || ds.stdinArgs != nil {
+		ds.logPhase("expansion", start)
+		start = ds.now()
+	}
+	defer ds.logPhase("parsing", start)
+
+	if ds.subCmds != nil {
+		return ds.parseSubCmd()
+	}
+
+	r, err := ds.parseArgs(
+		ds.osArgs, ds.optCfgs, ds.optionStores(), ds.extraOptCfgs,
+	)
+	if err.IsNotOk() {
+		if _, ok := err.Reason().(MultipleParseErrors); ok {
+			ds.setResult(r)
+		}
+		return err
+	}
+	if ds.isDynamic {
+		opts, err := makeDynamicOptions(r.cmd, r.optCfgs, r.counts, r.metas)
+		if err.IsNotOk() {
+			return err
+		}
+		ds.options = opts
+	}
+	ds.setResult(r)
+	ds.handleHelp()
+	ds.handleDeprecations()
+	return errs.Ok()
+}
+
+func (ds *DaxSrc) setResult(r parseResult) {
+	ds.cmd = r.cmd
+	if len(ds.cmdName) > 0 {
+		ds.cmd.Name = ds.cmdName
+	}
+	ds.optCfgs = r.optCfgs
+	ds.metas = r.metas
+	ds.counts = r.counts
+	ds.termIndex = r.termIndex
+	ds.hasTerm = r.hasTerm
+	ds.unknownOpts = r.unknownOpts
+	ds.wildcardOpts = r.wildcardOpts
+	ds.warnings = r.warnings
+	ds.helpRequested = r.helpRequested
+	ds.versionRequested = r.versionRequested
+	ds.deprecations = r.deprecations
+	ds.rawOptArgs = r.rawOptArgs
+	ds.spellings = r.spellings
+	ds.occurrences = r.occurrences
+	ds.blocks = r.blocks
+	ds.tupleSizes = r.tupleSizes
+	ds.configPath = r.configPath
+	ds.sources = r.sources
+}
+
+func (ds *DaxSrc) parseArgs(
+	osArgs []string, optCfgs []cliargs.OptCfg, stores []optStore,
+	extra []cliargs.OptCfg,
+) (parseResult, errs.Err) {
+	r := parseResult{
+		ctx: ds.ctx, optCfgs: optCfgs, osArgs: osArgs, mode: ds.mode,
+		aliases: ds.deprecatedAliases, optAliases: ds.deprecatedOptAliases,
+		callbacks: ds.optCallbacks, appVersion: ds.appVersion,
+		dupPolicy: ds.dupPolicy, dupPolicies: ds.dupPolicies,
+		optBlocks: ds.optBlocks, fsys: ds.fsys,
+	}
+
+	if len(stores) > 0 {
+		cfgs, metas, binds, err := ds.buildOptCfgsWithExtra(stores, extra)
+		if err.IsNotOk() {
+			return r, err
+		}
+		r.optCfgs = cfgs
+		r.metas = metas
+		r.binds = binds
+	}
+	if err := r.checkWildcard(); err.IsNotOk() {
+		return r, err
+	}
+
+	r.helpRequested = ds.addFlagOpt(&r, ds.helpOpt)
+	r.versionRequested = ds.addFlagOpt(&r, ds.versionOpt)
+	ds.applyMetas(&r)
+
+	err := ds.loadConfig(&r)
+	if e := r.parse(); err.IsOk() {
+		err = e
+	}
+	if r.mode.collectAll {
+		err = ds.validateAll(&r, err)
+	} else {
+		if err.IsOk() {
+			err = r.bindArgs()
+		}
+		if err.IsOk() {
+			err = ds.validate(r)
+		}
+	}
+	if r.helpRequested || r.versionRequested {
+		return r, errs.Ok()
+	}
+	return r, err
+}
+
+// parse parses command line arguments with the option configurations and the
+// metadata of options in this parseResult.
+// Command line arguments are normalized and supplemented with values of
+// environment variables and of the config file before they are passed to
+// cliargs.ParseWith function, and osArgs of this parseResult is replaced with
+// them.
+func (r *parseResult) parse() errs.Err {
+	cfgs := r.optCfgs
+	if len(cfgs) == 0 {
+		cfgs = anyOptCfgs
+	}
+
+	args, cfgs, toks, err := r.normalize(cfgs)
+	if err.IsOk() {
+		err = r.checkOptVersions(toks)
+	}
+	if err.IsNotOk() {
+		r.cmd, _ = cliargs.ParseWith(nil, nil)
+		r.cmd.Name = cmdName(r.osArgs)
+		return err
+	}
+
+	envArgs, envOpts, err := makeEnvArgs(args, cfgs, r.metas)
+	if err.IsNotOk() {
+		return err
+	}
+	args = insertArgs(args, envArgs)
+
+	cfgArgs, cfgOpts, err := makeConfigArgs(args, cfgs, r.metas, r.config)
+	if err.IsNotOk() {
+		return err
+	}
+	args = insertArgs(args, cfgArgs)
+
+	var cmd cliargs.Cmd
+	var errList []error
+	if r.mode.collectAll {
+		cmd, errList = parseAllWith(args, cfgs)
+	} else {
+		var e error
+		cmd, e = cliargs.ParseWith(args, cfgs)
+		if e != nil {
+			errList = []error{e}
+		}
+	}
+	cmd.Name = cmdName(r.osArgs)
+	cmd = addEmptyVariadics(cmd, cfgs, toks)
+	r.cmd = cmd
+
+	if len(errList) > 0 {
+		combined := findCombinedOptsNeedingArg(r.osArgs, cfgs, r.mode)
+		for i, e := range errList {
+			switch n := e.(type) {
+			case cliargs.OptionNeedsArg:
+				if c, exists := combined[n.Option]; exists {
+					errList[i] = reasonErr{option: n.Option, reason: c, cause: e}
+				}
+			case cliargs.OptionIsNotArray:
+				if d, exists := r.duplicates[n.Option]; exists {
+					errList[i] = reasonErr{option: n.Option, reason: d, cause: e}
+				}
+			}
+		}
+	}
+
+	r.sources = makeSources(cmd, cfgs, toks, envArgs, cfgArgs)
+	r.collectWildcardOpts(toks, cfgs)
+	r.recordOccurrences(toks)
+	r.osArgs = args
+
+	r.termIndex, r.hasTerm = findTerminator(toks)
+	r.counts = countOpts(args, cfgs)
+	countNArgs(r.counts, r.metas, toks)
+	r.tupleSizes = tupleSizes(toks)
+	r.cmd = trimBoolValues(cmd, cfgs, r.metas, r.counts)
+	if r.mode.interpolate && len(errList) == 0 {
+		if err := r.interpolateOptArgs(cfgs); err.IsNotOk() {
+			return err
+		}
+	}
+	for _, cfg := range cfgs {
+		if m := r.metas[cfg.Name]; m.setCount != nil {
+			m.setCount(r.counts[cfg.Name])
+		}
+	}
+	for name, m := range r.metas {
+		if m.setChanged != nil {
+			src := r.sources[name]
+			m.setChanged(src == SourceCLI || src == SourceAlias)
+		}
+	}
+	r.checkDeprecatedOpts()
+
+	if r.mode.collectAll {
+		parseErrs := make([]errs.Err, len(errList))
+		for i, e := range errList {
+			parseErrs[i] = r.wrapParseErr(e, envOpts, cfgOpts)
+		}
+		return joinParseErrs(parseErrs)
+	}
+
+	if len(errList) > 0 {
+		return r.wrapParseErr(errList[0], envOpts, cfgOpts)
+	}
+
+	return errs.Ok()
+}
+
+// wrapParseErr makes an errs.Err from an error of parsing, of which reason is
+// FailToParseEnvVar or FailToParseConfigValue if the option argument is taken
+// from an environment variable or the config file.
+func (r *parseResult) wrapParseErr(
+	e error, envOpts map[string]string, cfgOpts map[string]configValue,
+) errs.Err {
+	name := optionOfErr(e)
+	if input, exists := envOpts[name]; exists {
+		m := r.metas[name]
+		return errs.New(FailToParseEnvVar{
+			Option: name, Field: m.field, EnvVar: m.envVar, Input: input,
+		}, e)
+	}
+	if v, exists := cfgOpts[name]; exists {
+		input := inputOfErr(e)
+		if len(input) == 0 {
+			input = v.input
+		}
+		return errs.New(FailToParseConfigValue{
+			Option: name, Field: r.metas[name].field, Key: v.key, Input: input,
+		}, e)
+	}
+	if u, ok := e.(cliargs.UnconfiguredOption); ok && !hasWildcard(r.optCfgs) {
+		s := suggestOptNames(u.Option, r.visibleOptCfgs())
+		if len(s) > 0 {
+			return errs.New(UnconfiguredOptionWithSuggestion{
+				Option: u.Option, Suggestions: s,
+			}, e)
+		}
+	}
+	if re, ok := e.(reasonErr); ok {
+		return re.toErr()
+	}
+	return errs.New(e)
+}
+
+// visibleOptCfgs returns the option configurations in this parseResult
+// excluding hidden options.
+func (r *parseResult) visibleOptCfgs() []cliargs.OptCfg {
+	cfgs := make([]cliargs.OptCfg, 0, len(r.optCfgs))
+	for _, cfg := range r.optCfgs {
+		if !r.metas[cfg.Name].hidden {
+			cfgs = append(cfgs, cfg)
+		}
+	}
+	return cfgs
+}
+
+// cmdName returns the base name of the program path, which is an empty string
+// if the command line arguments or the program path are empty.
+func cmdName(osArgs []string) string {
+	if len(osArgs) == 0 || len(osArgs[0]) == 0 {
+		return ""
+	}
+	return path.Base(osArgs[0])
+}
+
+// reparse parses the command line arguments captured at Setup again with the
+// option configurations or for the option store.
+// The option store of this DaxSrc is replaced only if isFor is true, which is
+// the case of DaxConn#ReparseFor method, so that DaxConn#Reparse method keeps
+// the option store set by the constructor or DaxConn#SetOptions method.
+func (ds *DaxSrc) reparse(
+	index []int, optCfgs []cliargs.OptCfg, options any, isFor bool,
+) (cliargs.Cmd, []cliargs.OptCfg, errs.Err) {
+	ds.parseLazily()
+
+	ds.mutex.RLock()
+	osArgs := ds.osArgs
+	ds.mutex.RUnlock()
+	if len(index) > 0 && index[0] > 0 {
+		if index[0] < len(osArgs) {
+			osArgs = osArgs[index[0]:]
+		} else {
+			osArgs = nil
+		}
+	}
+
+	r, err := ds.parseArgs(osArgs, optCfgs, storesOf(options, nil), nil)
+	if err.IsNotOk() {
+		return r.cmd, r.optCfgs, err
+	}
+
+	ds.mutex.Lock()
+	defer ds.mutex.Unlock()
+
+	ds.savePrevResult()
+	ds.setResult(r)
+	if isFor {
+		ds.options = options
+	}
+	ds.parseErr = errs.Ok()
+
+	return r.cmd, r.optCfgs, err
+}
@@ -0,0 +1,176 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"reflect"
+	"strconv"
+
+	"github.com/sttk/sabi/errs"
+)
+
+type /* error reasons */ (
+	// IllegalArgPosition is the error reason which indicates that the optpos
+	// struct tag of a field of an option store is invalid, because it is
+	// neither a non-negative integer nor "rest", it duplicates another one, or
+	// it is "rest" but the field is not an array.
+	// The fields Field and Position are the field name and the tag value.
+	IllegalArgPosition struct {
+		Field    string
+		Position string
+	}
+)
+
+// argBinding is the binding of a field of an option store to command
+// arguments.
+// The field index is the position of the command argument, or -1 if the
+// field takes the rest command arguments which are not bound to other fields.
+// If the field required is true, the command argument at the position must be
+// given.
+type argBinding struct {
+	field    string
+	index    int
+	required bool
+	set      func(a []string) error
+}
+
+// makeArgBindings takes the fields with the optpos struct tag out of the
+// fields of an option store, and makes argBinding instances for them.
+// The values of command arguments are converted in the same way as option
+// arguments, and a field of a pointer type to a type supported by cliargs
+// package is set to a pointer to the converted value.
+// A field of io.Reader type is set to os.Stdin if the command argument is "-",
+// or to the file opened with the command argument as its path.
+func makeArgBindings(
+	fields []storeField, decs decoderMap,
+) ([]storeField, []argBinding, errs.Err) {
+	optFields := make([]storeField, 0, len(fields))
+	var binds []argBinding
+	used := make(map[int]bool)
+
+	for _, f := range fields {
+		pos, exists := f.sf.Tag.Lookup("optpos")
+		if !exists {
+			optFields = append(optFields, f)
+			continue
+		}
+
+		illegal := errs.New(IllegalArgPosition{Field: f.sf.Name, Position: pos})
+
+		b := argBinding{field: f.sf.Name, index: -1}
+		if pos != "rest" {
+			i, e := strconv.Atoi(pos)
+			if e != nil || i < 0 {
+				return nil, nil, illegal
+			}
+			b.index = i
+		}
+		if used[b.index] {
+			return nil, nil, illegal
+		}
+		used[b.index] = true
+
+		if f.sf.Type == readerType {
+			if b.index < 0 {
+				return nil, nil, illegal
+			}
+			b.set = makeReaderBinding(f.sf.Name, f.fld)
+			b.required = true
+			binds = append(binds, b)
+			continue
+		}
+
+		sf := reflect.StructField{Name: f.sf.Name, Type: f.sf.Type}
+		fld := f.fld
+		var ptr reflect.Value
+		if _, _, isCustom := decoderFor(sf, decs); !isCustom &&
+			sf.Type.Kind() == reflect.Ptr {
+			ptr = reflect.New(sf.Type.Elem())
+			sf.Type = sf.Type.Elem()
+			fld = ptr.Elem()
+		}
+
+		cfg, _, err := makeOptCfgFor(sf, fld, decs)
+		if err.IsNotOk() {
+			return nil, nil, err
+		}
+		if b.index < 0 && !cfg.IsArray {
+			return nil, nil, illegal
+		}
+		if cfg.OnParsed != nil {
+			b.set = *cfg.OnParsed
+			if ptr.IsValid() {
+				setter, dst := b.set, f.fld
+				b.set = func(a []string) error {
+					if e := setter(a); e != nil {
+						return e
+					}
+					if dst.CanSet() {
+						dst.Set(ptr)
+					}
+					return nil
+				}
+			}
+		}
+
+		switch f.sf.Type.Kind() {
+		case reflect.Ptr, reflect.Slice:
+		default:
+			b.required = true
+		}
+
+		binds = append(binds, b)
+	}
+
+	return optFields, binds, errs.Ok()
+}
+
+// bindArgs sets the command arguments to the fields bound by the optpos
+// struct tag.
+// If a command argument bound to a field which is neither a pointer nor an
+// array is not given, this method returns an errs.Err with the reason:
+// MissingPositionalArg.
+func (r *parseResult) bindArgs() errs.Err {
+	if len(r.binds) == 0 {
+		return errs.Ok()
+	}
+
+	args := r.cmd.Args()
+	claimed := make(map[int]bool, len(r.binds))
+	for _, b := range r.binds {
+		if b.index >= 0 {
+			claimed[b.index] = true
+		}
+	}
+
+	for _, b := range r.binds {
+		var a []string
+		if b.index >= 0 {
+			if b.index < len(args) {
+				a = []string{args[b.index]}
+			} else if b.required {
+				return errs.New(MissingPositionalArg{Name: b.field})
+			}
+		} else {
+			for i, arg := range args {
+				if !claimed[i] {
+					a = append(a, arg)
+				}
+			}
+		}
+
+		if len(a) == 0 || b.set == nil {
+			continue
+		}
+		if e := b.set(a); e != nil {
+			if re, ok := e.(reasonErr); ok {
+				return re.toErr()
+			}
+			return errs.New(e)
+		}
+	}
+
+	return errs.Ok()
+}
@@ -0,0 +1,143 @@
+package cliargdax_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+)
+
+func TestPosBind_optpos(t *testing.T) {
+	type Options struct {
+		Force bool     `optcfg:"force,f"`
+		Src   string   `optpos:"0"`
+		Dst   string   `optpos:"1"`
+		Rest  []string `optpos:"rest"`
+	}
+
+	options := Options{}
+	conn, err := setupForOptions(t, []string{
+		"cp", "a.txt", "-f", "b.txt", "c", "d",
+	}, &options)
+	assert.True(t, err.IsOk())
+	assert.True(t, options.Force)
+	assert.Equal(t, options.Src, "a.txt")
+	assert.Equal(t, options.Dst, "b.txt")
+	assert.Equal(t, options.Rest, []string{"c", "d"})
+
+	cfgs := conn.OptCfgs()
+	assert.Equal(t, len(cfgs), 1)
+	assert.Equal(t, cfgs[0].Name, "force")
+	assert.Equal(t, conn.Cmd().Args(), []string{"a.txt", "b.txt", "c", "d"})
+}
+
+func TestPosBind_optpos_restExcludesIndexed(t *testing.T) {
+	type Options struct {
+		Rest []string `optpos:"rest"`
+		Last string   `optpos:"2"`
+		Dbg  bool     `optcfg:"debug"`
+	}
+
+	options := Options{}
+	_, err := setupForOptions(t, []string{"app", "a", "b", "c", "d"}, &options)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, options.Last, "c")
+	assert.Equal(t, options.Rest, []string{"a", "b", "d"})
+}
+
+func TestPosBind_optpos_typeConversion(t *testing.T) {
+	type Options struct {
+		Count int      `optpos:"0"`
+		Ratio *float64 `optpos:"1"`
+		Nums  []uint   `optpos:"rest"`
+		Dbg   bool     `optcfg:"debug"`
+	}
+
+	options := Options{}
+	_, err := setupForOptions(t, []string{"app", "3", "0.5", "1", "2"},
+		&options)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, options.Count, 3)
+	assert.Equal(t, *options.Ratio, 0.5)
+	assert.Equal(t, options.Nums, []uint{1, 2})
+
+	options = Options{}
+	_, err = setupForOptions(t, []string{"app", "x"}, &options)
+	switch r := err.Reason().(type) {
+	case cliargs.FailToParseInt:
+		assert.Equal(t, r.Option, "Count")
+		assert.Equal(t, r.Input, "x")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestPosBind_optpos_missing(t *testing.T) {
+	type Options struct {
+		Src  string   `optpos:"0"`
+		Dst  string   `optpos:"1"`
+		Opt  *string  `optpos:"2"`
+		Rest []string `optpos:"rest"`
+	}
+
+	options := Options{}
+	_, err := setupForOptions(t, []string{"app", "a", "b"}, &options)
+	assert.True(t, err.IsOk())
+	assert.Nil(t, options.Opt)
+	assert.Nil(t, options.Rest)
+
+	options = Options{}
+	_, err = setupForOptions(t, []string{"app", "a"}, &options)
+	switch r := err.Reason().(type) {
+	case cliargdax.MissingPositionalArg:
+		assert.Equal(t, r.Name, "Dst")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestPosBind_optpos_illegal(t *testing.T) {
+	type Options struct {
+		Src string `optpos:"first"`
+	}
+
+	options := Options{}
+	_, err := setupForOptions(t, []string{"app", "a"}, &options)
+	switch r := err.Reason().(type) {
+	case cliargdax.IllegalArgPosition:
+		assert.Equal(t, r.Field, "Src")
+		assert.Equal(t, r.Position, "first")
+	default:
+		assert.Fail(t, err.Error())
+	}
+
+	type Options2 struct {
+		Src  string `optpos:"0"`
+		Dest string `optpos:"0"`
+	}
+
+	options2 := Options2{}
+	_, err = setupForOptions(t, []string{"app", "a"}, &options2)
+	switch r := err.Reason().(type) {
+	case cliargdax.IllegalArgPosition:
+		assert.Equal(t, r.Field, "Dest")
+		assert.Equal(t, r.Position, "0")
+	default:
+		assert.Fail(t, err.Error())
+	}
+
+	type Options3 struct {
+		Rest string `optpos:"rest"`
+	}
+
+	options3 := Options3{}
+	_, err = setupForOptions(t, []string{"app", "a"}, &options3)
+	switch r := err.Reason().(type) {
+	case cliargdax.IllegalArgPosition:
+		assert.Equal(t, r.Field, "Rest")
+		assert.Equal(t, r.Position, "rest")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
@@ -0,0 +1,137 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"github.com/sttk/sabi/errs"
+)
+
+type /* error reasons */ (
+	// MissingPositionalArg is the error reason which indicates that a required
+	// positional argument is not given in command line arguments.
+	// The field Name is the name of the positional argument.
+	MissingPositionalArg struct {
+		Name string
+	}
+
+	// TooManyPositionalArgs is the error reason which indicates that command
+	// arguments are more than the positional argument configurations allow.
+	// The fields Max and Got are the maximum number of positional arguments
+	// and the number of the given command arguments.
+	TooManyPositionalArgs struct {
+		Max int
+		Got int
+	}
+
+	// IllegalPositionalCfg is the error reason which indicates that a
+	// positional argument configuration is variadic but is not the last one.
+	// The field Name is the name of the positional argument.
+	IllegalPositionalCfg struct {
+		Name string
+	}
+)
+
+// ArgCfg is the struct to configure a positional argument, which is a
+// command argument at a position.
+// The field Name is the name to retrieve the positional argument, and the
+// field Desc is its description.
+// If the field Required is true, the positional argument must be given.
+// If the field Variadic is true, the positional argument takes all rest
+// command arguments, and this configuration must be the last one.
+// A variadic positional argument which is required must take at least one
+// command argument.
+type ArgCfg struct {
+	Name     string
+	Required bool
+	Variadic bool
+	Desc     string
+}
+
+// PositionalCfgs is the method to set the configurations of positional
+// arguments.
+// After parsing, if a required positional argument is not given, Setup
+// method returns an errs.Err with the reason: MissingPositionalArg, and if
+// command arguments are more than the configurations, it returns an errs.Err
+// with the reason: TooManyPositionalArgs.
+// If a variadic configuration is not the last one, Setup method returns an
+// errs.Err with the reason: IllegalPositionalCfg.
+// The positional arguments can be retrieved by their names with
+// DaxConn#Arg and DaxConn#ArgList methods.
+// This method is for DaxSrc instances without sub commands.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) PositionalCfgs(cfgs []ArgCfg) *DaxSrc {
+	ds.argCfgs = cfgs
+	return ds
+}
+
+// Arg is the method to retrieve the positional argument of the specified
+// name.
+// If the positional argument is variadic, this method returns the first one
+// of the command arguments which it takes.
+// If the positional argument is not given or is not configured, this method
+// returns an empty string.
+func (conn *DaxConn) Arg(name string) string {
+	list := conn.ArgList(name)
+	if len(list) == 0 {
+		return ""
+	}
+	return list[0]
+}
+
+// ArgList is the method to retrieve the command arguments which the
+// positional argument of the specified name takes.
+// If the positional argument is not variadic, this method returns an array
+// of at most one element.
+// If the positional argument is not given or is not configured, this method
+// returns an empty array.
+func (conn *DaxConn) ArgList(name string) []string {
+	conn.ds.parseLazily()
+	conn.ds.mutex.RLock()
+	defer conn.ds.mutex.RUnlock()
+
+	args := conn.ds.cmd.Args()
+	for i, cfg := range conn.ds.argCfgs {
+		if cfg.Name != name {
+			continue
+		}
+		if i >= len(args) {
+			break
+		}
+		if cfg.Variadic {
+			return append([]string{}, args[i:]...)
+		}
+		return []string{args[i]}
+	}
+	return []string{}
+}
+
+// checkPositionals checks that the command arguments fit the positional
+// argument configurations.
+func checkPositionals(r parseResult, cfgs []ArgCfg) errs.Err {
+	if len(cfgs) == 0 {
+		return errs.Ok()
+	}
+
+	for i, cfg := range cfgs {
+		if cfg.Variadic && i != len(cfgs)-1 {
+			return errs.New(IllegalPositionalCfg{Name: cfg.Name})
+		}
+	}
+
+	args := r.cmd.Args()
+	for i, cfg := range cfgs {
+		if cfg.Required && i >= len(args) {
+			return errs.New(MissingPositionalArg{Name: cfg.Name})
+		}
+	}
+
+	if !cfgs[len(cfgs)-1].Variadic && len(args) > len(cfgs) {
+		return errs.New(TooManyPositionalArgs{
+			Max: len(cfgs), Got: len(args),
+		})
+	}
+
+	return errs.Ok()
+}
@@ -0,0 +1,112 @@
+package cliargdax_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+)
+
+func copyArgCfgs() []cliargdax.ArgCfg {
+	return []cliargdax.ArgCfg{
+		{Name: "source", Required: true},
+		{Name: "dest", Required: true},
+		{Name: "extras", Variadic: true},
+	}
+}
+
+func TestPositional_PositionalCfgs_ok(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "force", Aliases: []string{"f"}},
+	}
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"cp", "a.txt", "-f", "b.txt", "c", "d",
+	}, optCfgs).PositionalCfgs(copyArgCfgs())
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.Arg("source"), "a.txt")
+	assert.Equal(t, conn.Arg("dest"), "b.txt")
+	assert.Equal(t, conn.Arg("extras"), "c")
+	assert.Equal(t, conn.ArgList("source"), []string{"a.txt"})
+	assert.Equal(t, conn.ArgList("extras"), []string{"c", "d"})
+	assert.Equal(t, conn.Arg("unknown"), "")
+	assert.Equal(t, conn.ArgList("unknown"), []string{})
+}
+
+func TestPositional_PositionalCfgs_optionalNotGiven(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"cp", "a.txt", "b.txt",
+	}, []cliargs.OptCfg{}).PositionalCfgs(copyArgCfgs())
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.Arg("extras"), "")
+	assert.Equal(t, conn.ArgList("extras"), []string{})
+}
+
+func TestPositional_PositionalCfgs_missing(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"cp", "a.txt",
+	}, []cliargs.OptCfg{}).PositionalCfgs(copyArgCfgs())
+
+	_, err := setupWithOptCfgs(t, ds)
+	switch r := err.Reason().(type) {
+	case cliargdax.MissingPositionalArg:
+		assert.Equal(t, r.Name, "dest")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestPositional_PositionalCfgs_tooMany(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"mv", "a", "b", "c",
+	}, []cliargs.OptCfg{}).PositionalCfgs([]cliargdax.ArgCfg{
+		{Name: "source", Required: true},
+		{Name: "dest"},
+	})
+
+	_, err := setupWithOptCfgs(t, ds)
+	switch r := err.Reason().(type) {
+	case cliargdax.TooManyPositionalArgs:
+		assert.Equal(t, r.Max, 2)
+		assert.Equal(t, r.Got, 3)
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestPositional_PositionalCfgs_variadicNotLast(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "a",
+	}, []cliargs.OptCfg{}).PositionalCfgs([]cliargdax.ArgCfg{
+		{Name: "files", Variadic: true},
+		{Name: "dest"},
+	})
+
+	_, err := setupWithOptCfgs(t, ds)
+	switch r := err.Reason().(type) {
+	case cliargdax.IllegalPositionalCfg:
+		assert.Equal(t, r.Name, "files")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestPositional_PositionalCfgs_requiredVariadic(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"rm",
+	}, []cliargs.OptCfg{}).PositionalCfgs([]cliargdax.ArgCfg{
+		{Name: "files", Required: true, Variadic: true},
+	})
+
+	_, err := setupWithOptCfgs(t, ds)
+	switch r := err.Reason().(type) {
+	case cliargdax.MissingPositionalArg:
+		assert.Equal(t, r.Name, "files")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
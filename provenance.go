@@ -0,0 +1,138 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"github.com/sttk/cliargs"
+)
+
+// ValueSource is the enum type which indicates where the value of an option
+// came from.
+type ValueSource int
+
+const (
+	// SourceUnset indicates that the option has no value.
+	SourceUnset ValueSource = iota
+
+	// SourceCLI indicates that the option is given by its name in command line
+	// arguments.
+	SourceCLI
+
+	// SourceAlias indicates that the option is given by its alias in command
+	// line arguments.
+	SourceAlias
+
+	// SourceEnv indicates that the value of the option came from the
+	// environment variable specified with the optenv struct tag.
+	SourceEnv
+
+	// SourceConfig indicates that the value of the option came from the config
+	// file.
+	SourceConfig
+
+	// SourceDefault indicates that the value of the option is the default
+	// value.
+	SourceDefault
+)
+
+// String is the method to retrieve the name of this ValueSource, like "cli".
+func (src ValueSource) String() string {
+	switch src {
+	case SourceCLI:
+		return "cli"
+	case SourceAlias:
+		return "alias"
+	case SourceEnv:
+		return "env"
+	case SourceConfig:
+		return "config"
+	case SourceDefault:
+		return "default"
+	default:
+		return "unset"
+	}
+}
+
+// OptSource is the method to retrieve where the value of the specified option
+// came from.
+// If an option is given multiple times in command line arguments, the source
+// is decided by its last occurrence.
+// If the option has no value, this method returns SourceUnset.
+func (conn *DaxConn) OptSource(name string) ValueSource {
+	conn.ds.parseLazily()
+	conn.ds.mutex.RLock()
+	defer conn.ds.mutex.RUnlock()
+	return conn.ds.sources[name]
+}
+
+// Provenance is the method to retrieve a map of which keys are the names of
+// the configured options and the given options, and of which values are
+// where their values came from.
+// The returned map is a copy, so modifying it does not affect the internal
+// state of the DaxSrc.
+func (conn *DaxConn) Provenance() map[string]ValueSource {
+	conn.ds.parseLazily()
+	conn.ds.mutex.RLock()
+	defer conn.ds.mutex.RUnlock()
+
+	m := make(map[string]ValueSource, len(conn.ds.sources))
+	for _, cfg := range conn.ds.optCfgs {
+		if cfg.Name != "*" {
+			m[cfg.Name] = SourceUnset
+		}
+	}
+	for name, src := range conn.ds.sources {
+		m[name] = src
+	}
+	return m
+}
+
+// makeSources makes a map of which keys are the names of the options in the
+// cliargs.Cmd and of which values are where their values came from, with the
+// tokens of command line arguments and the arguments made from environment
+// variables and the config file.
+func makeSources(
+	cmd cliargs.Cmd, optCfgs []cliargs.OptCfg,
+	toks []argToken, envArgs, cfgArgs []string,
+) map[string]ValueSource {
+	sources := make(map[string]ValueSource)
+
+	mark := func(args []string, src ValueSource) {
+		if len(args) == 0 {
+			return
+		}
+		for _, tok := range scanArgsWith(append([]string{""}, args...), optCfgs) {
+			if tok.isOpt() {
+				sources[tok.name] = src
+			}
+		}
+	}
+	mark(cfgArgs, SourceConfig)
+	mark(envArgs, SourceEnv)
+
+	for _, tok := range toks {
+		if !tok.isOpt() {
+			continue
+		}
+		if tok.isAlias {
+			sources[tok.name] = SourceAlias
+		} else {
+			sources[tok.name] = SourceCLI
+		}
+	}
+
+	for _, name := range cmdOptNames(cmd, optCfgs, nil) {
+		if _, exists := sources[name]; !exists {
+			sources[name] = SourceDefault
+		}
+	}
+
+	for name := range sources {
+		if !cmd.HasOpt(name) {
+			delete(sources, name)
+		}
+	}
+	return sources
+}
@@ -0,0 +1,84 @@
+package cliargdax_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+)
+
+func TestProvenance_OptSource(t *testing.T) {
+	os.Setenv("MY_APP_USER", "alice")
+	defer os.Unsetenv("MY_APP_USER")
+
+	type Options struct {
+		Verbose bool   `optcfg:"verbose,v"`
+		Name    string `optcfg:"name,n"`
+		User    string `optcfg:"user" optenv:"MY_APP_USER"`
+		Host    string `optcfg:"host"`
+		Port    int    `optcfg:"port=80"`
+		Timeout int    `optcfg:"timeout"`
+		Debug   bool   `optcfg:"debug"`
+	}
+
+	path := writeTempFile(t, "app.json", `{"host": "example.com"}`)
+
+	opts := Options{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions(
+		[]string{"app", "--verbose", "-n", "foo"}, &opts,
+	).WithConfigFile(path, "")
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	assert.Equal(t, conn.OptSource("verbose"), cliargdax.SourceCLI)
+	assert.Equal(t, conn.OptSource("name"), cliargdax.SourceAlias)
+	assert.Equal(t, conn.OptSource("user"), cliargdax.SourceEnv)
+	assert.Equal(t, conn.OptSource("host"), cliargdax.SourceConfig)
+	assert.Equal(t, conn.OptSource("port"), cliargdax.SourceDefault)
+	assert.Equal(t, conn.OptSource("timeout"), cliargdax.SourceUnset)
+	assert.Equal(t, conn.OptSource("unknown"), cliargdax.SourceUnset)
+
+	assert.Equal(t, conn.Provenance(), map[string]cliargdax.ValueSource{
+		"verbose": cliargdax.SourceCLI,
+		"name":    cliargdax.SourceAlias,
+		"user":    cliargdax.SourceEnv,
+		"host":    cliargdax.SourceConfig,
+		"port":    cliargdax.SourceDefault,
+		"timeout": cliargdax.SourceUnset,
+		"debug":   cliargdax.SourceUnset,
+	})
+
+	m := conn.Provenance()
+	m["verbose"] = cliargdax.SourceUnset
+	assert.Equal(t, conn.OptSource("verbose"), cliargdax.SourceCLI)
+}
+
+func TestProvenance_lastOccurrenceWins(t *testing.T) {
+	type Options struct {
+		Tags []string `optcfg:"tag,t"`
+	}
+	opts := Options{}
+	conn, err := setupForOptions(t, []string{"app", "-t", "a", "--tag=b"}, &opts)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.OptSource("tag"), cliargdax.SourceCLI)
+}
+
+func TestProvenance_noCfgs(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgs([]string{"app", "--foo", "-b", "x"})
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.Provenance(), map[string]cliargdax.ValueSource{
+		"foo": cliargdax.SourceCLI,
+		"b":   cliargdax.SourceCLI,
+	})
+}
+
+func TestProvenance_ValueSource_String(t *testing.T) {
+	assert.Equal(t, cliargdax.SourceUnset.String(), "unset")
+	assert.Equal(t, cliargdax.SourceCLI.String(), "cli")
+	assert.Equal(t, cliargdax.SourceAlias.String(), "alias")
+	assert.Equal(t, cliargdax.SourceEnv.String(), "env")
+	assert.Equal(t, cliargdax.SourceConfig.String(), "config")
+	assert.Equal(t, cliargdax.SourceDefault.String(), "default")
+}
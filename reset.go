@@ -0,0 +1,137 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"reflect"
+
+	"github.com/sttk/cliargs"
+	"github.com/sttk/sabi/errs"
+)
+
+// initState is the state of a DaxSrc before the first parsing, which is
+// restored before parsing again and by Reset method.
+type initState struct {
+	optCfgs []cliargs.OptCfg
+	options any
+	isSaved bool
+}
+
+// WithArgs is the method to replace the command line arguments to be parsed
+// at the next Setup, for example to reuse this DaxSrc after Close method.
+// The first element of the array is treated as the program path, like
+// os.Args.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) WithArgs(args []string) *DaxSrc {
+	ds.args = append([]string{}, args...)
+	return ds
+}
+
+// Reset is the method to restore this DaxSrc to the state before the first
+// Setup.
+// The results of the parsing are cleared, the option store passed to the
+// constructor is restored if it was replaced, and the option stores of this
+// DaxSrc, including the added ones and the ones of sub commands, are set to
+// their zero values.
+// The configurations made by other methods of this DaxSrc, like AddOptions,
+// Lazy, and WithArgs, are kept.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) Reset() *DaxSrc {
+	ds.mutex.Lock()
+	defer ds.mutex.Unlock()
+	ds.saveInitState()
+	ds.restoreInitState()
+	ds.prevResult = nil
+	ds.isSetUp = false
+	return ds
+}
+
+// prepareToParse saves the state before the first parsing, or restores it if
+// this DaxSrc has already been set up, so that no result of the previous
+// parsing remains.
+func (ds *DaxSrc) prepareToParse() {
+	if ds.initial.isSaved {
+		ds.savePrevResult()
+		ds.restoreInitState()
+		return
+	}
+	ds.saveInitState()
+}
+
+func (ds *DaxSrc) saveInitState() {
+	if ds.initial.isSaved {
+		return
+	}
+	ds.initial = initState{
+		optCfgs: ds.optCfgs, options: ds.options, isSaved: true,
+	}
+}
+
+// restoreInitState releases the results of the parsing, and sets the option
+// stores to their zero values.
+func (ds *DaxSrc) restoreInitState() {
+	ds.releaseResults()
+
+	stores := storesOf(ds.options, ds.addedStores)
+	if ds.isDynamic {
+		stores = ds.addedStores
+	}
+	for _, s := range stores {
+		zeroOptions(s.options)
+	}
+	for _, sub := range ds.subCmds {
+		zeroOptions(sub.options)
+	}
+}
+
+// releaseResults clears the results of the parsing, and restores the option
+// configurations and the option store passed to the constructor.
+func (ds *DaxSrc) releaseResults() {
+	if !ds.initial.isSaved {
+		return
+	}
+	ds.optCfgs = ds.initial.optCfgs
+	ds.options = ds.initial.options
+
+	ds.osArgs = nil
+	ds.rawArgs = nil
+	ds.argFiles = nil
+	ds.cmd = cliargs.Cmd{}
+	ds.metas = nil
+	ds.counts = nil
+	ds.termIndex = 0
+	ds.hasTerm = false
+	ds.unknownOpts = nil
+	ds.wildcardOpts = nil
+	ds.warnings = nil
+	ds.helpRequested = false
+	ds.versionRequested = false
+	ds.deprecations = nil
+	ds.rawOptArgs = nil
+	ds.spellings = nil
+	ds.occurrences = nil
+	ds.blocks = nil
+	ds.tupleSizes = nil
+	ds.tokenInfos = nil
+	ds.configPath = ""
+	ds.sources = nil
+	ds.subCmdName = ""
+	ds.subCmd = cliargs.Cmd{}
+	ds.subOptCfgs = nil
+	ds.subOptions = nil
+	ds.isParsed = false
+	ds.parseErr = errs.Ok()
+	ds.stats = ParseStats{}
+}
+
+// zeroOptions sets the struct instance pointed by the option store to its
+// zero value.
+func zeroOptions(options any) {
+	v := reflect.ValueOf(options)
+	if v.Kind() != reflect.Ptr || v.IsNil() {
+		return
+	}
+	v.Elem().Set(reflect.Zero(v.Elem().Type()))
+}
@@ -0,0 +1,150 @@
+package cliargdax_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+)
+
+type resetOptions struct {
+	Name  string   `optcfg:"name"`
+	Level int      `optcfg:"level"`
+	Tags  []string `optcfg:"tag"`
+}
+
+func TestReset_setupThreeTimes(t *testing.T) {
+	options := resetOptions{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions(
+		[]string{"app", "--name=foo", "--tag=a", "x"}, &options,
+	)
+	defer ds.Close()
+
+	err := ds.Setup(&noopAsyncGroup{})
+	assert.True(t, err.IsOk())
+	dc, _ := ds.CreateDaxConn()
+	conn := dc.(*cliargdax.DaxConn)
+	assert.Equal(t, options, resetOptions{Name: "foo", Tags: []string{"a"}})
+	assert.Equal(t, conn.Cmd().Args(), []string{"x"})
+	ds.Close()
+
+	ds.WithArgs([]string{"app", "--level=3"})
+	err = ds.Setup(&noopAsyncGroup{})
+	assert.True(t, err.IsOk())
+	assert.Equal(t, options, resetOptions{Level: 3})
+	assert.Equal(t, conn.Cmd().Args(), []string{})
+	assert.Equal(t, conn.RawArgs(), []string{"--level=3"})
+	assert.Equal(t, len(conn.OptCfgs()), 3)
+	ds.Close()
+
+	ds.WithArgs([]string{"app", "--level=x"})
+	err = ds.Setup(&noopAsyncGroup{})
+	switch err.Reason().(type) {
+	case cliargs.FailToParseInt:
+	default:
+		assert.Fail(t, err.Error())
+	}
+	assert.Equal(t, options, resetOptions{})
+	assert.Equal(t, len(conn.Cmd().Args()), 0)
+}
+
+func TestReset_setupAgainWithOptCfgs(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "foo", HasArg: true},
+	}
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "--foo=1"}, optCfgs,
+	).EnableHelpOpt("help", "h")
+	defer ds.Close()
+
+	for _, v := range []string{"1", "2", "3"} {
+		ds.WithArgs([]string{"app", "--foo=" + v})
+		err := ds.Setup(&noopAsyncGroup{})
+		assert.True(t, err.IsOk())
+		dc, _ := ds.CreateDaxConn()
+		conn := dc.(*cliargdax.DaxConn)
+		assert.Equal(t, conn.Cmd().OptArg("foo"), v)
+		assert.Equal(t, len(conn.OptCfgs()), 2)
+	}
+}
+
+func TestReset_Close_releasesResults(t *testing.T) {
+	options := resetOptions{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions(
+		[]string{"app", "--name=foo", "x"}, &options,
+	)
+	err := ds.Setup(&noopAsyncGroup{})
+	assert.True(t, err.IsOk())
+	dc, _ := ds.CreateDaxConn()
+	conn := dc.(*cliargdax.DaxConn)
+
+	ds.Close()
+	assert.Equal(t, len(conn.Cmd().Args()), 0)
+	assert.Equal(t, conn.RawArgs(), []string{})
+	assert.Equal(t, options.Name, "foo")
+}
+
+func TestReset_Reset(t *testing.T) {
+	options := resetOptions{}
+	added := struct {
+		Verbose bool `optcfg:"verbose"`
+	}{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions(
+		[]string{"app", "--name=foo", "--verbose"}, &options,
+	).AddOptions("added", &added)
+	defer ds.Close()
+
+	err := ds.Setup(&noopAsyncGroup{})
+	assert.True(t, err.IsOk())
+	dc, _ := ds.CreateDaxConn()
+	conn := dc.(*cliargdax.DaxConn)
+	assert.Equal(t, options.Name, "foo")
+	assert.True(t, added.Verbose)
+
+	other := resetOptions{Name: "bar"}
+	conn.SetOptions(&other)
+	assert.True(t, conn.Commit(&noopAsyncGroup{}).IsOk())
+	assert.Equal(t, conn.Options(), &other)
+
+	ds.Reset()
+	assert.Equal(t, options, resetOptions{})
+	assert.False(t, added.Verbose)
+	assert.Equal(t, conn.Options(), &options)
+	assert.Equal(t, len(conn.Cmd().Args()), 0)
+	assert.Equal(t, other.Name, "bar")
+
+	err = ds.Setup(&noopAsyncGroup{})
+	assert.True(t, err.IsOk())
+	assert.Equal(t, options.Name, "foo")
+}
+
+func TestReset_releasesSpellingsTuplesAndStats(t *testing.T) {
+	options := nargsOptions{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions(
+		[]string{"app", "-m", "a", "b", "x"}, &options,
+	)
+	defer ds.Close()
+
+	err := ds.Setup(&noopAsyncGroup{})
+	assert.True(t, err.IsOk())
+	dc, _ := ds.CreateDaxConn()
+	conn := dc.(*cliargdax.DaxConn)
+	assert.Equal(t, conn.OptSpellings("map"), []string{"-m"})
+	assert.Equal(t, conn.OptTuples("map"), [][]string{{"a", "b"}})
+	assert.True(t, conn.Stats().Parsed)
+
+	ds.Close()
+	assert.Equal(t, conn.OptSpellings("map"), []string{})
+	assert.Equal(t, conn.OptTuples("map"), [][]string{})
+	assert.Equal(t, conn.Stats(), cliargdax.ParseStats{})
+
+	err = ds.Setup(&noopAsyncGroup{})
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.OptSpellings("map"), []string{"-m"})
+
+	ds.Reset()
+	assert.Equal(t, conn.OptSpellings("map"), []string{})
+	assert.Equal(t, conn.OptTuples("map"), [][]string{})
+	assert.Equal(t, conn.Stats(), cliargdax.ParseStats{})
+}
@@ -0,0 +1,248 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"encoding/json"
+
+	"github.com/sttk/cliargs"
+	"github.com/sttk/sabi/errs"
+)
+
+type /* error reasons */ (
+	// FailToMarshalResult is the error reason which indicates that the results
+	// of parsing, including the option store, cannot be serialized to JSON.
+	FailToMarshalResult struct{}
+
+	// FailToUnmarshalResult is the error reason which indicates that the data
+	// is not a JSON made by DaxConn#ResultJSON method.
+	FailToUnmarshalResult struct{}
+)
+
+// Result is the struct type which holds the results of parsing reconstructed
+// from a JSON by UnmarshalResult function.
+// This struct provides the same query methods as cliargs.Cmd.
+type Result struct {
+	name    string
+	args    []string
+	opts    map[string][]string
+	options json.RawMessage
+	subCmd  *Result
+}
+
+type resultJSON struct {
+	Name    string         `json:"name"`
+	Args    []string       `json:"args"`
+	Opts    map[string]any `json:"opts"`
+	Options any            `json:"options,omitempty"`
+	SubCmd  *resultJSON    `json:"subcmd,omitempty"`
+	Stats   *ParseStats    `json:"stats,omitempty"`
+}
+
+type rawResultJSON struct {
+	Name    string                     `json:"name"`
+	Args    []string                   `json:"args"`
+	Opts    map[string]json.RawMessage `json:"opts"`
+	Options json.RawMessage            `json:"options"`
+	SubCmd  *rawResultJSON             `json:"subcmd"`
+}
+
+// ResultJSON is the method to serialize the results of parsing to JSON.
+// The JSON is an object which has the command name as "name", the command
+// arguments as "args", the options as "opts", and the option store as
+// "options" if exists, which is serialized with its own JSON tags.
+// If a sub command is specified, the results of parsing it are also
+// serialized as an object of the same structure under "subcmd".
+// In "opts", an option which takes no option argument is serialized as true,
+// an array option or an option given multiple option arguments is serialized
+// as an array of strings, and other options are serialized as strings.
+// Since the keys of "opts" are sorted, the JSON is stable for the same
+// results.
+// The option arguments of secret options in "opts", and the values of the
+// fields for them in "options", are replaced with "****".
+// If DaxSrc#StatsInResult method is called with true, the statistics of
+// parsing are also serialized under "stats".
+func (conn *DaxConn) ResultJSON() ([]byte, errs.Err) {
+	conn.ds.parseLazily()
+	conn.ds.mutex.RLock()
+	defer conn.ds.mutex.RUnlock()
+
+	ds := conn.ds
+	options := ds.options
+	if opts, ok := conn.overriddenOptions(); ok {
+		options = opts
+	}
+	secrets := ds.secretOpts()
+	res := makeResultJSON(
+		conn.overriddenCmd(), ds.optCfgs, ds.counts,
+		redactOptions(options, ds.metas), secrets,
+	)
+	if len(ds.subCmdName) > 0 {
+		var subMetas map[string]optMeta
+		if ds.subOptions != nil {
+			_, subMetas, _, _ = buildOptCfgs(
+				ds.subOptions, ds.decoders, ds.nameMapping,
+			)
+		}
+		sub := makeResultJSON(
+			ds.subCmd, ds.subOptCfgs, nil,
+			redactOptions(ds.subOptions, subMetas), secrets,
+		)
+		res.SubCmd = &sub
+	}
+	if ds.statsInResult {
+		stats := ds.stats
+		res.Stats = &stats
+	}
+
+	data, e := json.Marshal(res)
+	if e != nil {
+		return nil, errs.New(FailToMarshalResult{}, e)
+	}
+	return data, errs.Ok()
+}
+
+func makeResultJSON(
+	cmd cliargs.Cmd, optCfgs []cliargs.OptCfg, counts map[string]int,
+	options any, secrets map[string]bool,
+) resultJSON {
+	opts := make(map[string]any)
+	for _, name := range cmdOptNames(cmd, optCfgs, counts) {
+		a := cmd.OptArgs(name)
+		if secrets[name] {
+			a = redactValues(a)
+		}
+		switch {
+		case len(a) == 0:
+			opts[name] = true
+		case isArrayOpt(optCfgs, name) || len(a) > 1:
+			opts[name] = a
+		default:
+			opts[name] = a[0]
+		}
+	}
+
+	args := cmd.Args()
+	if args == nil {
+		args = []string{}
+	}
+	return resultJSON{
+		Name: cmd.Name, Args: args, Opts: opts, Options: options,
+	}
+}
+
+func isArrayOpt(optCfgs []cliargs.OptCfg, name string) bool {
+	for _, cfg := range optCfgs {
+		if cfg.Name == name {
+			return cfg.IsArray
+		}
+	}
+	return false
+}
+
+// UnmarshalResult is the function to reconstruct the results of parsing from
+// the JSON made by DaxConn#ResultJSON method.
+func UnmarshalResult(data []byte) (Result, errs.Err) {
+	var raw rawResultJSON
+	if e := json.Unmarshal(data, &raw); e != nil {
+		return Result{}, errs.New(FailToUnmarshalResult{}, e)
+	}
+	return makeResult(&raw)
+}
+
+func makeResult(raw *rawResultJSON) (Result, errs.Err) {
+	res := Result{
+		name:    raw.Name,
+		args:    raw.Args,
+		opts:    make(map[string][]string, len(raw.Opts)),
+		options: raw.Options,
+	}
+	if res.args == nil {
+		res.args = []string{}
+	}
+
+	for name, v := range raw.Opts {
+		var b bool
+		if json.Unmarshal(v, &b) == nil {
+			if b {
+				res.opts[name] = []string{}
+			}
+			continue
+		}
+		var s string
+		if json.Unmarshal(v, &s) == nil {
+			res.opts[name] = []string{s}
+			continue
+		}
+		var a []string
+		if e := json.Unmarshal(v, &a); e != nil {
+			return Result{}, errs.New(FailToUnmarshalResult{}, e)
+		}
+		res.opts[name] = a
+	}
+
+	if raw.SubCmd != nil {
+		sub, err := makeResult(raw.SubCmd)
+		if err.IsNotOk() {
+			return Result{}, err
+		}
+		res.subCmd = &sub
+	}
+	return res, errs.Ok()
+}
+
+// Name is the method to retrieve the command name.
+func (res Result) Name() string {
+	return res.name
+}
+
+// Args is the method to retrieve the command arguments.
+func (res Result) Args() []string {
+	return res.args
+}
+
+// HasOpt is the method to check whether the option is given.
+func (res Result) HasOpt(name string) bool {
+	_, exists := res.opts[name]
+	return exists
+}
+
+// OptArg is the method to retrieve the first option argument of the option.
+// If the option is not given or has no option argument, this method returns
+// an empty string.
+func (res Result) OptArg(name string) string {
+	a := res.opts[name]
+	if len(a) == 0 {
+		return ""
+	}
+	return a[0]
+}
+
+// OptArgs is the method to retrieve the option arguments of the option.
+func (res Result) OptArgs(name string) []string {
+	return res.opts[name]
+}
+
+// Options is the method to deserialize the option store in the JSON to the
+// struct instance specified as the argument with its JSON tags.
+// If the JSON has no option store, this method does nothing.
+func (res Result) Options(options any) errs.Err {
+	if len(res.options) == 0 || string(res.options) == "null" {
+		return errs.Ok()
+	}
+	if e := json.Unmarshal(res.options, options); e != nil {
+		return errs.New(FailToUnmarshalResult{}, e)
+	}
+	return errs.Ok()
+}
+
+// SubCmd is the method to retrieve the results of parsing the sub command.
+// If the JSON has no sub command, the second returned value is false.
+func (res Result) SubCmd() (Result, bool) {
+	if res.subCmd == nil {
+		return Result{}, false
+	}
+	return *res.subCmd, true
+}
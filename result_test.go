@@ -0,0 +1,125 @@
+package cliargdax_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+)
+
+func TestResult_ResultJSON_noCfgs(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgs([]string{
+		"/path/to/app", "-v", "--foo=1", "--foo=2", "--bar=x", "arg",
+	})
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	data, err := conn.ResultJSON()
+	assert.True(t, err.IsOk())
+	assert.Equal(t, string(data), `{"name":"app","args":["arg"],`+
+		`"opts":{"bar":"x","foo":["1","2"],"v":true}}`)
+
+	res, err := cliargdax.UnmarshalResult(data)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, res.Name(), "app")
+	assert.Equal(t, res.Args(), []string{"arg"})
+	assert.True(t, res.HasOpt("v"))
+	assert.Equal(t, res.OptArg("v"), "")
+	assert.Equal(t, res.OptArgs("v"), []string{})
+	assert.Equal(t, res.OptArgs("foo"), []string{"1", "2"})
+	assert.Equal(t, res.OptArg("bar"), "x")
+	assert.False(t, res.HasOpt("baz"))
+
+	_, ok := res.SubCmd()
+	assert.False(t, ok)
+}
+
+func TestResult_ResultJSON_optCfgs(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"/path/to/app", "--tag=a",
+	}, []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "tag", HasArg: true, IsArray: true},
+		cliargs.OptCfg{Name: "level", HasArg: true, Default: []string{"3"}},
+		cliargs.OptCfg{Name: "quiet"},
+	})
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	data, err := conn.ResultJSON()
+	assert.True(t, err.IsOk())
+	assert.Equal(t, string(data),
+		`{"name":"app","args":[],"opts":{"level":"3","tag":["a"]}}`)
+}
+
+func TestResult_ResultJSON_options(t *testing.T) {
+	type Options struct {
+		Verbose bool     `optcfg:"verbose,v" json:"verbose"`
+		Port    int      `optcfg:"port=80" json:"port"`
+		Tags    []string `optcfg:"tag" json:"tags,omitempty"`
+	}
+	opts := Options{}
+	conn, err := setupForOptions(t, []string{"app", "-v", "file"}, &opts)
+	assert.True(t, err.IsOk())
+
+	data, err := conn.ResultJSON()
+	assert.True(t, err.IsOk())
+	assert.Equal(t, string(data), `{"name":"app","args":["file"],`+
+		`"opts":{"port":"80","verbose":true},`+
+		`"options":{"verbose":true,"port":80}}`)
+
+	res, err := cliargdax.UnmarshalResult(data)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, res.OptArg("port"), "80")
+
+	restored := Options{}
+	assert.True(t, res.Options(&restored).IsOk())
+	assert.Equal(t, restored, opts)
+}
+
+func TestResult_ResultJSON_subCmd(t *testing.T) {
+	defer resetOsArgs()
+
+	os.Args = []string{"/path/to/app", "--verbose", "commit", "-m", "hi", "f"}
+
+	ds := cliargdax.NewDaxSrcWithSubCmds(map[string][]cliargs.OptCfg{
+		"commit": []cliargs.OptCfg{
+			cliargs.OptCfg{Name: "message", Aliases: []string{"m"}, HasArg: true},
+		},
+	})
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	data, err := conn.ResultJSON()
+	assert.True(t, err.IsOk())
+	assert.Equal(t, string(data), `{"name":"app","args":[],`+
+		`"opts":{"verbose":true},`+
+		`"subcmd":{"name":"commit","args":["f"],"opts":{"message":"hi"}}}`)
+
+	res, err := cliargdax.UnmarshalResult(data)
+	assert.True(t, err.IsOk())
+	assert.True(t, res.HasOpt("verbose"))
+
+	sub, ok := res.SubCmd()
+	assert.True(t, ok)
+	assert.Equal(t, sub.Name(), "commit")
+	assert.Equal(t, sub.Args(), []string{"f"})
+	assert.Equal(t, sub.OptArg("message"), "hi")
+}
+
+func TestResult_UnmarshalResult_error(t *testing.T) {
+	_, err := cliargdax.UnmarshalResult([]byte(`{"name":`))
+	switch err.Reason().(type) {
+	case cliargdax.FailToUnmarshalResult:
+	default:
+		assert.Fail(t, err.Error())
+	}
+
+	_, err = cliargdax.UnmarshalResult([]byte(`{"opts":{"a":1}}`))
+	switch err.Reason().(type) {
+	case cliargdax.FailToUnmarshalResult:
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/sttk/sabi/errs"
+)
+
+type /* error reasons */ (
+	// UnknownRoute is the error reason which indicates that the first command
+	// argument does not match any route registered with DaxSrc#Route method.
+	// The field Name is the unknown route, which includes the preceding words
+	// for a nested route, like "remote ad", and the field Suggestions is the
+	// registered routes which are similar to it.
+	UnknownRoute struct {
+		Name        string
+		Suggestions []string
+	}
+
+	// RouteIsMissing is the error reason which indicates that no command
+	// argument is given to select a route and no default route is registered.
+	// The field Routes is the registered routes.
+	RouteIsMissing struct {
+		Routes []string
+	}
+)
+
+// RouteHandler is the function type of the handler of a route, which is
+// invoked by DaxConn#Dispatch method with a DaxConn instance of which
+// command arguments are the ones after the route.
+type RouteHandler func(conn *DaxConn) errs.Err
+
+// Route is the method to register the handler which is invoked by
+// DaxConn#Dispatch method when the first command arguments are the route.
+// A route can consist of multiple words separated by spaces for a nested
+// route, like "remote add", which matches the command arguments: remote add,
+// and the longest matching route is selected.
+// Registering a handler for the same route replaces the previous one, and nil
+// removes the route.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) Route(route string, handler RouteHandler) *DaxSrc {
+	route = strings.Join(strings.Fields(route), " ")
+	if handler == nil {
+		delete(ds.routes, route)
+		return ds
+	}
+	if ds.routes == nil {
+		ds.routes = make(map[string]RouteHandler)
+	}
+	ds.routes[route] = handler
+	return ds
+}
+
+// DefaultRoute is the method to register the handler which is invoked by
+// DaxConn#Dispatch method when no command argument is given, or when the
+// first command argument does not match any route if RouteUnknownToDefault
+// method is enabled.
+// The DaxConn instance passed to the handler has all the command arguments.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) DefaultRoute(handler RouteHandler) *DaxSrc {
+	ds.defaultRoute = handler
+	return ds
+}
+
+// RouteUnknownToDefault is the method to make DaxConn#Dispatch method invoke
+// the default route instead of failing when the first command argument does
+// not match any route.
+// This mode is disabled by default.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) RouteUnknownToDefault(enable bool) *DaxSrc {
+	ds.routeUnknownToDefault = enable
+	return ds
+}
+
+// DispatchOnSetup is the method to make Setup method, or the first parsing in
+// lazy mode, invoke DaxConn#Dispatch method after the functions registered
+// with AfterSetup method, and return the error of the handler.
+// This mode is disabled by default.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) DispatchOnSetup(enable bool) *DaxSrc {
+	ds.dispatchOnSetup = enable
+	return ds
+}
+
+// Dispatch is the method to invoke the handler of the route which matches the
+// first command arguments, with a DaxConn instance of which DaxConn#Cmd
+// method returns a cliargs.Cmd having only the command arguments after the
+// route.
+// If no command argument is given, the default route is invoked, and if it is
+// not registered, this method returns an errs.Err with the reason:
+// RouteIsMissing.
+// If the first command argument does not match any route, this method returns
+// an errs.Err with the reason: UnknownRoute, unless RouteUnknownToDefault
+// method is enabled and the default route is registered.
+// If the parsing failed, this method returns the error of the parsing, and if
+// the help or the version is requested, this method invokes no handler.
+// A handler can call this method again for the routes following its route.
+func (conn *DaxConn) Dispatch() errs.Err {
+	if err := conn.ParseErr(); err.IsNotOk() {
+		return err
+	}
+
+	ds := conn.ds
+	ds.mutex.RLock()
+	requested := ds.helpRequested || ds.versionRequested
+	ds.mutex.RUnlock()
+	if requested {
+		return errs.Ok()
+	}
+
+	args := conn.Cmd().Args()
+
+	for n := len(args); n > 0; n-- {
+		route := strings.Join(args[:n], " ")
+		if handler, ok := ds.routes[route]; ok {
+			return handler(conn.routed(route, n))
+		}
+	}
+
+	if ds.defaultRoute != nil &&
+		(len(args) == 0 || ds.routeUnknownToDefault) {
+		return ds.defaultRoute(conn.routed("", 0))
+	}
+
+	routes := make([]string, 0, len(ds.routes))
+	for route := range ds.routes {
+		routes = append(routes, route)
+	}
+	sort.Strings(routes)
+
+	if len(args) == 0 {
+		return errs.New(RouteIsMissing{Routes: routes})
+	}
+
+	n := 1
+	for ; n < len(args); n++ {
+		prefix := strings.Join(args[:n], " ") + " "
+		found := false
+		for _, route := range routes {
+			if strings.HasPrefix(route, prefix) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			break
+		}
+	}
+	name := strings.Join(args[:n], " ")
+	return errs.New(UnknownRoute{
+		Name: name, Suggestions: similarNames(name, routes),
+	})
+}
+
+// RouteName is the method to retrieve the route of which handler is invoked
+// with this DaxConn instance, like "remote add".
+// If this DaxConn instance is not passed to a handler of a route, or is
+// passed to the default route, this method returns an empty string.
+func (conn *DaxConn) RouteName() string {
+	conn.overlay.mutex.Lock()
+	defer conn.overlay.mutex.Unlock()
+	return conn.overlay.route
+}
+
+// routed returns a new DaxConn instance for the handler of the route, which
+// skips the command arguments of the route, and inherits the option values
+// overridden in this DaxConn instance.
+func (conn *DaxConn) routed(route string, n int) *DaxConn {
+	rc := &DaxConn{ds: conn.ds, writeThrough: true}
+
+	conn.overlay.mutex.Lock()
+	defer conn.overlay.mutex.Unlock()
+	if len(conn.overlay.opts) > 0 {
+		rc.overlay.opts = make(map[string][]string, len(conn.overlay.opts))
+		for name, a := range conn.overlay.opts {
+			rc.overlay.opts[name] = a
+		}
+	}
+	rc.overlay.route = route
+	rc.overlay.skipArgs = conn.overlay.skipArgs + n
+	return rc
+}
@@ -0,0 +1,208 @@
+package cliargdax_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+	"github.com/sttk/sabi/errs"
+)
+
+func newRouteDaxSrc(args []string, logs *[]string) *cliargdax.DaxSrc {
+	handler := func(conn *cliargdax.DaxConn) errs.Err {
+		*logs = append(*logs, conn.RouteName()+":"+
+			strings.Join(conn.Cmd().Args(), ",")+":"+conn.Cmd().OptArg("name"))
+		return errs.Ok()
+	}
+	return cliargdax.NewDaxSrcWithArgsAndOptCfgs(args, []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "name", HasArg: true},
+	}).
+		Route("serve", handler).
+		Route("remote add", handler).
+		Route("remote  remove", handler).
+		Route("remote", handler)
+}
+
+func TestRoute_Dispatch(t *testing.T) {
+	var logs []string
+	ds := newRouteDaxSrc([]string{"app", "serve", "--name=x", "a", "b"}, &logs)
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	err = conn.Dispatch()
+	assert.True(t, err.IsOk())
+	assert.Equal(t, logs, []string{"serve:a,b:x"})
+	assert.Equal(t, conn.Cmd().Args(), []string{"serve", "a", "b"})
+	assert.Equal(t, conn.RouteName(), "")
+
+	logs = nil
+	ds = newRouteDaxSrc([]string{"app", "remote", "add", "origin"}, &logs)
+	conn, err = setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.True(t, conn.Dispatch().IsOk())
+
+	ds = newRouteDaxSrc([]string{"app", "remote", "remove"}, &logs)
+	conn, err = setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.True(t, conn.Dispatch().IsOk())
+
+	ds = newRouteDaxSrc([]string{"app", "remote", "list"}, &logs)
+	conn, err = setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.True(t, conn.Dispatch().IsOk())
+	assert.Equal(t, logs, []string{
+		"remote add:origin:", "remote remove::", "remote:list:",
+	})
+}
+
+func TestRoute_unknown(t *testing.T) {
+	var logs []string
+	ds := newRouteDaxSrc([]string{"app", "serv"}, &logs)
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	err = conn.Dispatch()
+	assert.Equal(t, err.Reason(), cliargdax.UnknownRoute{
+		Name: "serv", Suggestions: []string{"serve"},
+	})
+	assert.Equal(t, cliargdax.FormatError(err, nil),
+		"unknown command: serv (did you mean serve?)\n"+
+			"Try '--help' for more information.")
+	assert.Equal(t, len(logs), 0)
+
+	ds = newRouteDaxSrc([]string{"app", "xyz"}, &logs).
+		Route("remote", nil)
+	conn, err = setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	err = conn.Dispatch()
+	assert.Equal(t, err.Reason(), cliargdax.UnknownRoute{Name: "xyz"})
+	assert.Equal(t, cliargdax.FormatError(err, nil),
+		"unknown command: xyz\n"+
+			"Try '--help' for more information.")
+
+	ds = newRouteDaxSrc([]string{"app"}, &logs)
+	conn, err = setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	err = conn.Dispatch()
+	assert.Equal(t, err.Reason(), cliargdax.RouteIsMissing{
+		Routes: []string{"remote", "remote add", "remote remove", "serve"},
+	})
+	assert.Equal(t, cliargdax.FormatError(err, nil),
+		"a command is required: remote, remote add, remote remove, serve\n"+
+			"Try '--help' for more information.")
+}
+
+func TestRoute_unknownNested(t *testing.T) {
+	var logs []string
+	handler := func(conn *cliargdax.DaxConn) errs.Err {
+		logs = append(logs, conn.RouteName())
+		return errs.Ok()
+	}
+	ds := cliargdax.NewDaxSrcWithArgs([]string{"app", "remote", "ad", "x"}).
+		Route("remote add", handler).
+		Route("remote remove", handler)
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	err = conn.Dispatch()
+	assert.Equal(t, err.Reason(), cliargdax.UnknownRoute{
+		Name: "remote ad", Suggestions: []string{"remote add"},
+	})
+
+	ds = cliargdax.NewDaxSrcWithArgs([]string{"app", "remote"}).
+		Route("remote add", handler).
+		Route("remote remove", handler)
+	conn, err = setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	err = conn.Dispatch()
+	assert.Equal(t, err.Reason(), cliargdax.UnknownRoute{
+		Name: "remote", Suggestions: []string{"remote add", "remote remove"},
+	})
+	assert.Equal(t, len(logs), 0)
+}
+
+func TestRoute_DefaultRoute(t *testing.T) {
+	var logs []string
+	def := func(conn *cliargdax.DaxConn) errs.Err {
+		logs = append(logs, "default:"+strings.Join(conn.Cmd().Args(), ","))
+		return errs.Ok()
+	}
+
+	ds := newRouteDaxSrc([]string{"app"}, &logs).DefaultRoute(def)
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.True(t, conn.Dispatch().IsOk())
+
+	ds = newRouteDaxSrc([]string{"app", "xyz", "a"}, &logs).DefaultRoute(def)
+	conn, err = setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	err = conn.Dispatch()
+	assert.Equal(t, err.Reason(), cliargdax.UnknownRoute{Name: "xyz"})
+
+	ds = newRouteDaxSrc([]string{"app", "xyz", "a"}, &logs).
+		DefaultRoute(def).
+		RouteUnknownToDefault(true)
+	conn, err = setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.True(t, conn.Dispatch().IsOk())
+
+	assert.Equal(t, logs, []string{"default:", "default:xyz,a"})
+}
+
+func TestRoute_DispatchOnSetup(t *testing.T) {
+	var logs []string
+	ds := newRouteDaxSrc([]string{"app", "serve", "a"}, &logs).
+		AfterSetup(func(conn *cliargdax.DaxConn) errs.Err {
+			logs = append(logs, "hook")
+			return errs.Ok()
+		}).
+		DispatchOnSetup(true)
+	_, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, logs, []string{"hook", "serve:a:"})
+
+	logs = nil
+	ds = newRouteDaxSrc([]string{"app", "serve", "a"}, &logs).
+		Route("serve", func(conn *cliargdax.DaxConn) errs.Err {
+			return errs.New(cliargdax.UnknownRoute{Name: "fail"})
+		}).
+		DispatchOnSetup(true)
+	_, err = setupWithOptCfgs(t, ds)
+	assert.Equal(t, err.Reason(), cliargdax.UnknownRoute{Name: "fail"})
+
+	ds = newRouteDaxSrc([]string{"app", "serve", "a"}, &logs).
+		DispatchOnSetup(true).Lazy()
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, len(logs), 0)
+	assert.True(t, conn.ParseErr().IsOk())
+	assert.Equal(t, logs, []string{"serve:a:"})
+}
+
+func TestRoute_helpRequested(t *testing.T) {
+	var logs []string
+	ds := newRouteDaxSrc([]string{"app", "serve", "--help"}, &logs).
+		EnableHelpOpt("help").
+		OnHelp(func(string) {})
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.True(t, conn.Dispatch().IsOk())
+	assert.Equal(t, len(logs), 0)
+}
+
+func TestRoute_nestedDispatch(t *testing.T) {
+	var logs []string
+	ds := cliargdax.NewDaxSrcWithArgs([]string{"app", "remote", "add", "x"})
+	ds.Route("remote", func(conn *cliargdax.DaxConn) errs.Err {
+		logs = append(logs, conn.RouteName()+":"+strings.Join(conn.Cmd().Args(), ","))
+		return conn.Dispatch()
+	}).Route("add", func(conn *cliargdax.DaxConn) errs.Err {
+		logs = append(logs, conn.RouteName()+":"+strings.Join(conn.Cmd().Args(), ","))
+		return errs.Ok()
+	})
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.True(t, conn.Dispatch().IsOk())
+	assert.Equal(t, logs, []string{"remote:add,x", "add:x"})
+}
@@ -0,0 +1,210 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"context"
+	"io/fs"
+	"strings"
+	"unicode"
+
+	"github.com/sttk/sabi/errs"
+)
+
+type /* error reasons */ (
+	// FailToReadResponseFile is the error reason which indicates that a
+	// response file given as @path in command line arguments cannot be read.
+	// The field Path is the path of the response file.
+	FailToReadResponseFile struct {
+		Path string
+	}
+
+	// ResponseFileHasUnclosedQuote is the error reason which indicates that a
+	// quotation in a response file is not closed.
+	// The field Path is the path of the response file.
+	ResponseFileHasUnclosedQuote struct {
+		Path string
+	}
+
+	// ResponseFileHasCycle is the error reason which indicates that a response
+	// file includes itself directly or indirectly.
+	// The field Path is the path of the response file which makes the cycle.
+	ResponseFileHasCycle struct {
+		Path string
+	}
+
+	// ResponseFileIsTooDeep is the error reason which indicates that nested
+	// response files are deeper than the limit.
+	// The fields Path and Depth are the path of the response file which
+	// exceeds the limit and the limit.
+	ResponseFileIsTooDeep struct {
+		Path  string
+		Depth int
+	}
+)
+
+// maxResponseFileDepth is the maximum depth of nested response files.
+const maxResponseFileDepth = 8
+
+// ExpandResponseFiles is the method to enable or disable the expansion of
+// response files.
+// If enabled, each command line argument of the form @path is replaced with
+// the arguments written in the file before parsing.
+// The arguments in a response file are separated by white spaces and
+// newlines, an argument containing white spaces can be quoted with single or
+// double quotes, and lines starting with # are comments.
+// A response file can include other response files up to 8 levels, and a
+// response file which includes itself is an error.
+// Arguments after the option terminator "--" are not expanded.
+// DaxConn#RawArgs method returns the arguments before the expansion.
+// This mode is disabled by default.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) ExpandResponseFiles(expand bool) *DaxSrc {
+	ds.expandRspFiles = expand
+	return ds
+}
+
+// rspExpander expands response files in command line arguments.
+// The field files is the array of the paths of the response files from which
+// the expanded arguments are read, and of which elements are empty for the
+// arguments given directly.
+// The field reads is the number of the response files read.
+// The field ctx is checked before each response file is read, and the field
+// fsys is the file system from which response files are read.
+type rspExpander struct {
+	ctx        context.Context
+	fsys       fs.FS
+	stack      []string
+	paths      []string
+	files      []string
+	reads      int
+	terminated bool
+}
+
+// expandResponseFiles returns the command line arguments in which response
+// files are expanded, the paths of the response files from which the
+// arguments are read, and the number of the response files read.
+// The first element, which is the program path, is not expanded.
+func expandResponseFiles(
+	ctx context.Context, fsys fs.FS, osArgs []string,
+) ([]string, []string, int, errs.Err) {
+	if len(osArgs) == 0 {
+		return osArgs, nil, 0, errs.Ok()
+	}
+	ex := rspExpander{ctx: ctx, fsys: fsys, files: []string{""}}
+	args, err := ex.expand(osArgs[1:], []string{osArgs[0]})
+	if err.IsNotOk() {
+		return nil, nil, 0, err
+	}
+	return args, ex.files, ex.reads, errs.Ok()
+}
+
+func (ex *rspExpander) expand(
+	args []string, out []string,
+) ([]string, errs.Err) {
+	for _, arg := range args {
+		if ex.terminated || len(arg) < 2 || arg[0] != '@' {
+			if arg == "--" {
+				ex.terminated = true
+			}
+			out = append(out, arg)
+			if len(ex.paths) > 0 {
+				ex.files = append(ex.files, ex.paths[len(ex.paths)-1])
+			} else {
+				ex.files = append(ex.files, "")
+			}
+			continue
+		}
+
+		path := arg[1:]
+		abs, e := filePathKey(ex.fsys, path)
+		if e != nil {
+			return nil, errs.New(FailToReadResponseFile{Path: path}, e)
+		}
+		for _, p := range ex.stack {
+			if p == abs {
+				return nil, errs.New(ResponseFileHasCycle{Path: path})
+			}
+		}
+		if len(ex.stack) >= maxResponseFileDepth {
+			return nil, errs.New(ResponseFileIsTooDeep{
+				Path: path, Depth: maxResponseFileDepth,
+			})
+		}
+
+		if err := checkContext(ex.ctx); err.IsNotOk() {
+			return nil, err
+		}
+		b, e := readFile(ex.fsys, path)
+		if e != nil {
+			return nil, errs.New(FailToReadResponseFile{Path: path}, e)
+		}
+		ex.reads++
+		words, ok := splitResponseFile(string(b))
+		if !ok {
+			return nil, errs.New(ResponseFileHasUnclosedQuote{Path: path})
+		}
+
+		ex.stack = append(ex.stack, abs)
+		ex.paths = append(ex.paths, path)
+		var err errs.Err
+		out, err = ex.expand(words, out)
+		if err.IsNotOk() {
+			return nil, err
+		}
+		ex.stack = ex.stack[:len(ex.stack)-1]
+		ex.paths = ex.paths[:len(ex.paths)-1]
+	}
+	return out, errs.Ok()
+}
+
+// splitResponseFile divides the contents of a response file into arguments.
+// If a quotation is not closed, this function returns false as the second
+// result.
+func splitResponseFile(text string) ([]string, bool) {
+	var words []string
+
+	for _, line := range strings.Split(text, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "#") {
+			continue
+		}
+
+		var word strings.Builder
+		inWord := false
+		var quote rune
+
+		for _, r := range line {
+			switch {
+			case quote != 0:
+				if r == quote {
+					quote = 0
+				} else {
+					word.WriteRune(r)
+				}
+			case r == '"' || r == '\'':
+				quote = r
+				inWord = true
+			case unicode.IsSpace(r):
+				if inWord {
+					words = append(words, word.String())
+					word.Reset()
+					inWord = false
+				}
+			default:
+				word.WriteRune(r)
+				inWord = true
+			}
+		}
+
+		if quote != 0 {
+			return nil, false
+		}
+		if inWord {
+			words = append(words, word.String())
+		}
+	}
+
+	return words, true
+}
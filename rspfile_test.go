@@ -0,0 +1,136 @@
+package cliargdax_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+)
+
+func TestRspFile_ExpandResponseFiles(t *testing.T) {
+	dir := t.TempDir()
+	inner := filepath.Join(dir, "inner.rsp")
+	outer := filepath.Join(dir, "outer.rsp")
+	assert.Nil(t, os.WriteFile(inner, []byte("--level=3\n"), 0600))
+	assert.Nil(t, os.WriteFile(outer, []byte(
+		"# options\n"+
+			"  --name 'John Smith'\n"+
+			"--tag=\"a b\" @"+inner+"\n"+
+			"   # indented comment\n"+
+			"file1\n"), 0600))
+
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "name", HasArg: true},
+		cliargs.OptCfg{Name: "tag", HasArg: true},
+		cliargs.OptCfg{Name: "level", HasArg: true},
+	}
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "@" + outer, "file2", "@"}, optCfgs,
+	).ExpandResponseFiles(true)
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	cmd := conn.Cmd()
+	assert.Equal(t, cmd.OptArg("name"), "John Smith")
+	assert.Equal(t, cmd.OptArg("tag"), "a b")
+	assert.Equal(t, cmd.OptArg("level"), "3")
+	assert.Equal(t, cmd.Args(), []string{"file1", "file2", "@"})
+	assert.Equal(t, conn.RawArgs(), []string{"@" + outer, "file2", "@"})
+}
+
+func TestRspFile_ExpandResponseFiles_disabled(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgs([]string{"app", "@args.rsp"})
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.Cmd().Args(), []string{"@args.rsp"})
+}
+
+func TestRspFile_ExpandResponseFiles_terminator(t *testing.T) {
+	dir := t.TempDir()
+	rsp := filepath.Join(dir, "args.rsp")
+	assert.Nil(t, os.WriteFile(rsp, []byte("--foo -- --bar\n"), 0600))
+
+	ds := cliargdax.NewDaxSrcWithArgs(
+		[]string{"app", "@" + rsp, "@" + rsp},
+	).ExpandResponseFiles(true)
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.True(t, conn.Cmd().HasOpt("foo"))
+	assert.False(t, conn.Cmd().HasOpt("bar"))
+	assert.True(t, conn.HasTerminator())
+	assert.Equal(t, conn.ArgsAfterTerminator(), []string{"--bar", "@" + rsp})
+}
+
+func TestRspFile_ExpandResponseFiles_cycle(t *testing.T) {
+	dir := t.TempDir()
+	a := filepath.Join(dir, "a.rsp")
+	b := filepath.Join(dir, "b.rsp")
+	assert.Nil(t, os.WriteFile(a, []byte("@"+b), 0600))
+	assert.Nil(t, os.WriteFile(b, []byte("--x @"+a), 0600))
+
+	ds := cliargdax.NewDaxSrcWithArgs([]string{"app", "@" + a}).
+		ExpandResponseFiles(true)
+	_, err := setupWithOptCfgs(t, ds)
+	switch r := err.Reason().(type) {
+	case cliargdax.ResponseFileHasCycle:
+		assert.Equal(t, r.Path, a)
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestRspFile_ExpandResponseFiles_tooDeep(t *testing.T) {
+	dir := t.TempDir()
+	for i := 0; i < 9; i++ {
+		content := ""
+		if i < 8 {
+			content = "@" + filepath.Join(dir, string(rune('a'+i+1)))
+		}
+		path := filepath.Join(dir, string(rune('a'+i)))
+		assert.Nil(t, os.WriteFile(path, []byte(content), 0600))
+	}
+
+	ds := cliargdax.NewDaxSrcWithArgs(
+		[]string{"app", "@" + filepath.Join(dir, "a")},
+	).ExpandResponseFiles(true)
+	_, err := setupWithOptCfgs(t, ds)
+	switch r := err.Reason().(type) {
+	case cliargdax.ResponseFileIsTooDeep:
+		assert.Equal(t, r.Path, filepath.Join(dir, "i"))
+		assert.Equal(t, r.Depth, 8)
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestRspFile_ExpandResponseFiles_errors(t *testing.T) {
+	dir := t.TempDir()
+	missing := filepath.Join(dir, "missing.rsp")
+
+	ds := cliargdax.NewDaxSrcWithArgs([]string{"app", "@" + missing}).
+		ExpandResponseFiles(true)
+	_, err := setupWithOptCfgs(t, ds)
+	switch r := err.Reason().(type) {
+	case cliargdax.FailToReadResponseFile:
+		assert.Equal(t, r.Path, missing)
+		assert.True(t, os.IsNotExist(err.Cause()))
+	default:
+		assert.Fail(t, err.Error())
+	}
+
+	unclosed := filepath.Join(dir, "unclosed.rsp")
+	assert.Nil(t, os.WriteFile(unclosed, []byte("--name 'abc\n"), 0600))
+	ds = cliargdax.NewDaxSrcWithArgs([]string{"app", "@" + unclosed}).
+		ExpandResponseFiles(true)
+	_, err = setupWithOptCfgs(t, ds)
+	switch r := err.Reason().(type) {
+	case cliargdax.ResponseFileHasUnclosedQuote:
+		assert.Equal(t, r.Path, unclosed)
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
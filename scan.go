@@ -0,0 +1,464 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/sttk/cliargs"
+	"github.com/sttk/sabi/errs"
+)
+
+// argToken is a token of command line arguments, which is either an option or
+// a command argument.
+// The field index is the index of the element of command line arguments from
+// which this token is taken.
+// The field isTerm indicates whether this token is the option terminator "--",
+// and the field isImplicit indicates whether the terminator is not given in
+// command line arguments but inserted before the first command argument in
+// the mode to stop parsing options at it.
+// The field spelling is the option name as written in command line arguments
+// with its prefix, like --name, -n, or /n, and the field alias is the alias
+// from which the option name is resolved.
+// The field extraValues is the option arguments following the first one for
+// an option which takes multiple option arguments per occurrence, and the
+// field isVariadic indicates whether the option takes a variable number of
+// option arguments.
+type argToken struct {
+	index      int
+	name       string
+	value      string
+	hasValue   bool
+	isShort    bool
+	isTerm     bool
+	isImplicit bool
+	isAlias    bool
+	isNextArg  bool
+	spelling   string
+	alias      string
+
+	extraValues []string
+	isVariadic  bool
+}
+
+func (tok argToken) isOpt() bool {
+	return len(tok.name) > 0
+}
+
+// argScanner divides command line arguments to tokens with the same rules as
+// cliargs package.
+// The function takesArg reports whether the option of the specified name
+// takes the next argument as its option argument when it is given without
+// "=".
+// If the function spell is not nil, long option names are replaced with its
+// results before takesArg is called.
+// If stopAtFirstArg is true, the first command argument and all arguments
+// after it are treated as command arguments.
+// If allowSlash is true, arguments in the forms: /name and /name:value are
+// also options if the function isConfigured reports that the name is
+// configured.
+// If allowSingleDash is true, arguments in the forms: -name and -name=value
+// are long options if the function isConfigured reports that the name is
+// configured.
+// If the function variadic is not nil and reports that an option takes a
+// variable number of option arguments, the following arguments are taken as
+// its option arguments.
+// If negativeNumbers is true, negative numbers, like -5 and -1.5, are command
+// arguments instead of short options.
+type argScanner struct {
+	takesArg        func(string) bool
+	spell           func(string) string
+	stopAtFirstArg  bool
+	allowSlash      bool
+	allowSingleDash bool
+	isConfigured    func(string) bool
+	variadic        func(string) (optMeta, bool)
+	negativeNumbers bool
+}
+
+// variadicOpt reports whether the option of the specified name takes a
+// variable number of option arguments.
+func (sc argScanner) variadicOpt(name string) (optMeta, bool) {
+	if sc.variadic == nil {
+		return optMeta{}, false
+	}
+	return sc.variadic(name)
+}
+
+// scan divides command line arguments, excluding the program path, to tokens.
+func (sc argScanner) scan(osArgs []string) []argToken {
+	var toks []argToken
+	if len(osArgs) <= 1 {
+		return toks
+	}
+	toks = make([]argToken, 0, len(osArgs))
+
+	isNonOpt := false
+	var prev argToken
+	hasPrev := false
+
+	for i := 1; i < len(osArgs); i++ {
+		arg := osArgs[i]
+
+		if isNonOpt {
+			toks = append(toks, argToken{index: i, value: arg})
+			continue
+		}
+
+		if hasPrev {
+			prev.value = arg
+			prev.hasValue = true
+			prev.isNextArg = true
+			toks = append(toks, prev)
+			hasPrev = false
+			continue
+		}
+
+		if arg == "--" {
+			toks = append(toks, argToken{index: i, isTerm: true})
+			isNonOpt = true
+			continue
+		}
+
+		if strings.HasPrefix(arg, "--") {
+			tok := argToken{index: i, name: arg[2:]}
+			if j := strings.IndexByte(tok.name, '='); j > 0 {
+				tok.value = tok.name[j+1:]
+				tok.hasValue = true
+				tok.name = tok.name[:j]
+			}
+			tok.spelling = "--" + tok.name
+			if sc.spell != nil {
+				tok.name = sc.spell(tok.name)
+			}
+			if m, ok := sc.variadicOpt(tok.name); ok {
+				i = sc.scanVariadic(osArgs, i, &tok, m)
+				toks = append(toks, tok)
+				continue
+			}
+			if !tok.hasValue && sc.takesArg(tok.name) && i < len(osArgs)-1 {
+				prev, hasPrev = tok, true
+				continue
+			}
+			toks = append(toks, tok)
+			continue
+		}
+
+		if tok, ok := sc.scanSingleDashOpt(i, arg); ok {
+			if m, ok := sc.variadicOpt(tok.name); ok {
+				i = sc.scanVariadic(osArgs, i, &tok, m)
+				toks = append(toks, tok)
+				continue
+			}
+			if !tok.hasValue && sc.takesArg(tok.name) && i < len(osArgs)-1 {
+				prev, hasPrev = tok, true
+				continue
+			}
+			toks = append(toks, tok)
+			continue
+		}
+
+		if strings.HasPrefix(arg, "-") && len(arg) > 1 &&
+			!(sc.negativeNumbers && isNegativeNumber(arg)) {
+			shorts := arg[1:]
+			for j := 0; j < len(shorts); {
+				_, size := utf8.DecodeRuneInString(shorts[j:])
+				k := j + size
+				tok := argToken{
+					index: i, name: shorts[j:k], isShort: true,
+					spelling: "-" + shorts[j:k],
+				}
+				if k < len(shorts) && shorts[k] == '=' {
+					tok.value = shorts[k+1:]
+					tok.hasValue = true
+				}
+				if m, ok := sc.variadicOpt(tok.name); ok && (tok.hasValue || k == len(shorts)) {
+					i = sc.scanVariadic(osArgs, i, &tok, m)
+					toks = append(toks, tok)
+					break
+				}
+				if tok.hasValue {
+					toks = append(toks, tok)
+					break
+				}
+				if k == len(shorts) && sc.takesArg(tok.name) && i < len(osArgs)-1 {
+					prev, hasPrev = tok, true
+					break
+				}
+				toks = append(toks, tok)
+				j = k
+			}
+			continue
+		}
+
+		if tok, ok := sc.scanSlashOpt(i, arg); ok {
+			if m, ok := sc.variadicOpt(tok.name); ok {
+				i = sc.scanVariadic(osArgs, i, &tok, m)
+				toks = append(toks, tok)
+				continue
+			}
+			if !tok.hasValue && sc.takesArg(tok.name) && i < len(osArgs)-1 {
+				prev, hasPrev = tok, true
+				continue
+			}
+			toks = append(toks, tok)
+			continue
+		}
+
+		if sc.stopAtFirstArg {
+			term := argToken{index: i, isTerm: true, isImplicit: true}
+			toks = append(toks, term)
+			isNonOpt = true
+		}
+		toks = append(toks, argToken{index: i, value: arg})
+	}
+
+	if hasPrev {
+		toks = append(toks, prev)
+	}
+
+	return toks
+}
+
+// cfgIndexes makes a map of which keys are option names and aliases and of
+// which values are indexes of the option configurations.
+func cfgIndexes(optCfgs []cliargs.OptCfg) map[string]int {
+	m := make(map[string]int, len(optCfgs))
+	for i, cfg := range optCfgs {
+		if cfg.Name == "*" {
+			continue
+		}
+		m[cfg.Name] = i
+		for _, a := range cfg.Aliases {
+			m[a] = i
+		}
+	}
+	return m
+}
+
+// scanArgsWith divides command line arguments to tokens with the option
+// configurations, and resolves aliases in the tokens to option names.
+// The tokens of which names are resolved from aliases are marked with isAlias.
+func scanArgsWith(osArgs []string, optCfgs []cliargs.OptCfg) []argToken {
+	return scanArgsBy(argScanner{}, osArgs, optCfgs)
+}
+
+// scanArgsBy is same as scanArgsWith function but uses the specified scanner
+// of which takesArg is set from the option configurations.
+func scanArgsBy(
+	sc argScanner, osArgs []string, optCfgs []cliargs.OptCfg,
+) []argToken {
+	indexes := cfgIndexes(optCfgs)
+	sc.takesArg = func(name string) bool {
+		i, exists := indexes[name]
+		return exists && optCfgs[i].HasArg
+	}
+	sc.isConfigured = func(name string) bool {
+		_, exists := indexes[name]
+		return exists
+	}
+	if variadic := sc.variadic; variadic != nil {
+		sc.variadic = func(name string) (optMeta, bool) {
+			i, exists := indexes[name]
+			if !exists {
+				return optMeta{}, false
+			}
+			return variadic(optCfgs[i].Name)
+		}
+	}
+
+	toks := sc.scan(osArgs)
+	for i, tok := range toks {
+		if j, exists := indexes[tok.name]; exists {
+			toks[i].name = optCfgs[j].Name
+			if tok.name != optCfgs[j].Name {
+				toks[i].isAlias = true
+				toks[i].alias = tok.name
+			}
+		}
+	}
+	return toks
+}
+
+// countOpts counts the occurrences of each option in command line arguments.
+func countOpts(osArgs []string, optCfgs []cliargs.OptCfg) map[string]int {
+	counts := make(map[string]int, len(optCfgs))
+	for _, tok := range scanArgsWith(osArgs, optCfgs) {
+		if tok.isOpt() {
+			counts[tok.name]++
+		}
+	}
+	return counts
+}
+
+// normalize rewrites command line arguments to the canonical forms which
+// cliargs.ParseWith function can parse, and returns them with the option
+// configurations for the parsing and the tokens of the arguments.
+// If no rewriting is needed, this method returns the arguments and the
+// configurations as they are.
+// The functions registered with DaxSrc#OnOpt method are invoked with the
+// tokens before the duplicated options are resolved and the occurrences of
+// negatable options and boolean options are merged.
+func (r *parseResult) normalize(
+	optCfgs []cliargs.OptCfg,
+) ([]string, []cliargs.OptCfg, []argToken, errs.Err) {
+	osArgs, metas, mode := r.osArgs, r.metas, r.mode
+
+	if !mode.allowAbbrev && !mode.ignoreCase && !mode.stopAtFirstArg &&
+		!mode.ignoreUnknown && !hasNegatable(metas) && len(r.aliases) == 0 &&
+		!hasFromFile(metas) && !mode.allowSlash && !mode.allowSingleDash &&
+		!r.hasDuplicateResolution() && !hasNArgs(metas) &&
+		!hasNegativeNumber(osArgs) {
+		toks := scanArgsWith(osArgs, optCfgs)
+		if !hasBoolValueArg(toks, metas) {
+			r.recordSpellings(toks)
+			r.checkDeprecatedOptAliases(toks)
+			if err := runOptCallbacks(r.callbacks, toks); err.IsNotOk() {
+				return osArgs, optCfgs, nil, err
+			}
+			r.resolveDuplicates(toks, optCfgs)
+			return osArgs, optCfgs, toks, errs.Ok()
+		}
+	}
+
+	sc := argScanner{
+		stopAtFirstArg:  mode.stopAtFirstArg,
+		allowSlash:      mode.allowSlash,
+		allowSingleDash: mode.allowSingleDash,
+		variadic:        variadicOf(metas),
+		negativeNumbers: !hasDigitShortOpt(optCfgs),
+	}
+	err := errs.Ok()
+
+	if mode.allowAbbrev || mode.ignoreCase {
+		nm := newNameMatcher(optCfgs, metas)
+		if mode.ignoreCase {
+			err = nm.checkCaseCollisions()
+			if err.IsNotOk() {
+				return osArgs, optCfgs, nil, err
+			}
+		}
+		sc.spell = func(name string) string {
+			s, e := nm.match(name, mode)
+			if e.IsNotOk() && err.IsOk() {
+				err = e
+			}
+			return s
+		}
+	}
+
+	if len(r.aliases) > 0 {
+		spell := sc.spell
+		sc.spell = func(name string) string {
+			if spell != nil {
+				name = spell(name)
+			}
+			return r.replaceDeprecatedAlias(name)
+		}
+	}
+
+	toks := scanArgsBy(sc, osArgs, optCfgs)
+	if err.IsNotOk() {
+		return osArgs, optCfgs, nil, err
+	}
+
+	toks, err = resolveNegations(toks, optCfgs, metas)
+	if err.IsNotOk() {
+		return osArgs, optCfgs, nil, err
+	}
+
+	if mode.ignoreUnknown {
+		toks = r.removeUnknownOpts(toks, optCfgs)
+	}
+
+	toks, err = r.consumeNArgs(toks)
+	if err.IsNotOk() {
+		return osArgs, optCfgs, nil, err
+	}
+
+	r.recordSpellings(toks)
+	r.checkDeprecatedOptAliases(toks)
+
+	toks, err = r.readOptFiles(toks)
+	if err.IsNotOk() {
+		return osArgs, optCfgs, nil, err
+	}
+
+	err = runOptCallbacks(r.callbacks, toks)
+	if err.IsNotOk() {
+		return osArgs, optCfgs, nil, err
+	}
+	err = r.makeOptBlocks(toks)
+	if err.IsNotOk() {
+		return osArgs, optCfgs, nil, err
+	}
+	toks = r.resolveDuplicates(toks, optCfgs)
+	toks = mergeNegations(toks, metas)
+
+	cfgs := boolValueCfgs(negatableCfgs(optCfgs, metas), metas)
+	cfgs = r.nargsCfgs(cfgs)
+	return joinArgs(osArgs, toks), cfgs, toks, errs.Ok()
+}
+
+// joinArgs makes command line arguments from the program path of osArgs and
+// the tokens, in which options are written in the forms: --name=value or
+// -n=value.
+// If a command argument before the option terminator starts with "-", like a
+// negative number, all the command arguments are placed after "--" following
+// all the options, to be parsed as command arguments.
+func joinArgs(osArgs []string, toks []argToken) []string {
+	if len(osArgs) == 0 {
+		return osArgs
+	}
+
+	args := make([]string, 1, len(toks)+2)
+	args[0] = osArgs[0]
+
+	if hasOptLikeArg(toks) {
+		var cmdArgs []string
+		for _, tok := range toks {
+			switch {
+			case tok.isTerm:
+			case !tok.isOpt():
+				cmdArgs = append(cmdArgs, tok.value)
+			default:
+				args = appendTokenArgs(args, tok)
+			}
+		}
+		args = append(args, "--")
+		return append(args, cmdArgs...)
+	}
+
+	for _, tok := range toks {
+		switch {
+		case tok.isTerm:
+			args = append(args, "--")
+		case !tok.isOpt():
+			args = append(args, tok.value)
+		default:
+			args = appendTokenArgs(args, tok)
+		}
+	}
+
+	return args
+}
+
+// appendTokenArgs appends the option of the token to the command line
+// arguments, once for each option argument if it has multiple ones.
+func appendTokenArgs(args []string, tok argToken) []string {
+	switch {
+	case tok.isVariadic && !tok.hasValue:
+		return args
+	case tok.hasValue:
+		args = append(args, optArg(tok.name, tok.value))
+		for _, v := range tok.extraValues {
+			args = append(args, optArg(tok.name, v))
+		}
+		return args
+	default:
+		return append(args, optArg(tok.name))
+	}
+}
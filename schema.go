@@ -0,0 +1,260 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"sort"
+
+	"github.com/sttk/cliargs"
+	"github.com/sttk/sabi/errs"
+)
+
+type /* error reasons */ (
+	// FailToMarshalSchema is the error reason which indicates that the schema
+	// of the command cannot be serialized to JSON.
+	FailToMarshalSchema struct{}
+)
+
+// SchemaVersion is the version of the format of the JSON made by
+// ExportSchema function and DaxConn#ExportSchema method, which is written as
+// "schemaVersion" and is incremented when the format is changed
+// incompatibly.
+const SchemaVersion = 1
+
+type schemaJSON struct {
+	SchemaVersion int             `json:"schemaVersion"`
+	Name          string          `json:"name"`
+	Options       []schemaOptJSON `json:"options"`
+	Positionals   []schemaArgJSON `json:"positionals"`
+	SubCommands   []schemaSubJSON `json:"subcommands,omitempty"`
+	Routes        []string        `json:"routes,omitempty"`
+}
+
+type schemaOptJSON struct {
+	Name        string   `json:"name"`
+	Aliases     []string `json:"aliases"`
+	Type        string   `json:"type"`
+	HasArg      bool     `json:"hasArg"`
+	IsArray     bool     `json:"isArray"`
+	ArgHelp     string   `json:"argHelp,omitempty"`
+	Default     []string `json:"default"`
+	Choices     []string `json:"choices"`
+	EnvVar      string   `json:"envVar,omitempty"`
+	Required    bool     `json:"required"`
+	Hidden      bool     `json:"hidden"`
+	Secret      bool     `json:"secret"`
+	Deprecated  bool     `json:"deprecated"`
+	Deprecation string   `json:"deprecation,omitempty"`
+	Since       string   `json:"since,omitempty"`
+	Group       string   `json:"group,omitempty"`
+	Desc        string   `json:"description"`
+}
+
+type schemaArgJSON struct {
+	Name     string `json:"name"`
+	Required bool   `json:"required"`
+	Variadic bool   `json:"variadic"`
+	Desc     string `json:"description"`
+}
+
+type schemaSubJSON struct {
+	Name    string          `json:"name"`
+	Options []schemaOptJSON `json:"options"`
+}
+
+// ExportSchema is the function to make a JSON which describes the command
+// with the option configurations, their metadata, and the positional argument
+// configurations, for example to generate documents or a wrapper UI of the
+// command.
+// The JSON is an object which has the format version as "schemaVersion",
+// which is SchemaVersion, the command name as "name", the options as
+// "options", and the positional arguments as "positionals".
+// Each option has its name, aliases, type, whether it takes an option
+// argument and whether it is an array, default values which are null if not
+// set, choices, environment variable, whether it is required, hidden, secret,
+// and deprecated, group, and description, in the order of the OptCfg array.
+// The type of an option is the name of Kind of its metadata, like "int", or
+// "bool" for an option which takes no option argument and "string" for other
+// options if Kind is not set.
+// The default values of secret options are replaced with "****".
+// If failing to serialize, this function returns an errs.Err with the
+// reason: FailToMarshalSchema.
+func ExportSchema(
+	cmdName string, cfgs []cliargs.OptCfg, metas map[string]OptMeta,
+	positionals []ArgCfg,
+) ([]byte, errs.Err) {
+	optCfgs := make([]cliargs.OptCfg, len(cfgs))
+	optMetas := make(map[string]optMeta, len(cfgs))
+	for i, cfg := range cfgs {
+		var m optMeta
+		if meta, exists := metas[cfg.Name]; exists {
+			mergeOptMeta(&cfg, &m, meta)
+		}
+		optCfgs[i] = cfg
+		optMetas[cfg.Name] = m
+	}
+
+	return marshalSchema(schemaJSON{
+		SchemaVersion: SchemaVersion,
+		Name:          cmdName,
+		Options:       makeSchemaOpts(setChoicesArgHelp(optCfgs, optMetas), optMetas),
+		Positionals:   makeSchemaArgs(positionals),
+	})
+}
+
+// ExportSchema is the method to make a JSON which describes the command with
+// the OptCfg array of this DaxConn, including hidden options, and the
+// metadata merged from the struct tags and the methods of DaxSrc.
+// The types of the options of an option store are the names of the field
+// types, like "int" or "time.Duration", of which array options are the
+// element types.
+// If sub commands are registered, the JSON also has their options as
+// "subcommands", and if routes are registered with DaxSrc#Route method, it
+// also has them as "routes", in the order of their names.
+// The options are made from the option stores even if the parsing failed.
+// See ExportSchema function for details.
+func (conn *DaxConn) ExportSchema() ([]byte, errs.Err) {
+	conn.ds.parseLazily()
+	conn.ds.mutex.RLock()
+	defer conn.ds.mutex.RUnlock()
+
+	ds := conn.ds
+	cfgs, metas := ds.optCfgs, ds.metas
+	if stores := ds.optionStores(); len(cfgs) == 0 && len(stores) > 0 {
+		cfgs, metas, _, _ = ds.buildOptCfgsWithExtra(stores, ds.extraOptCfgs)
+	}
+	schema := schemaJSON{
+		SchemaVersion: SchemaVersion,
+		Name:          ds.hintCmdName(),
+		Options:       makeSchemaOpts(cfgs, metas),
+		Positionals:   makeSchemaArgs(ds.argCfgs),
+	}
+
+	names := make([]string, 0, len(ds.subCmds))
+	for name := range ds.subCmds {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		sub := ds.subCmds[name]
+		subCfgs, subMetas := sub.optCfgs, map[string]optMeta(nil)
+		if sub.options != nil {
+			c, m, _, err := buildOptCfgs(
+				sub.options, ds.decoders, ds.nameMapping,
+			)
+			if err.IsNotOk() {
+				return nil, err
+			}
+			subCfgs, subMetas = c, m
+		}
+		schema.SubCommands = append(schema.SubCommands, schemaSubJSON{
+			Name: name, Options: makeSchemaOpts(subCfgs, subMetas),
+		})
+	}
+
+	for route := range ds.routes {
+		schema.Routes = append(schema.Routes, route)
+	}
+	sort.Strings(schema.Routes)
+
+	return marshalSchema(schema)
+}
+
+func marshalSchema(schema schemaJSON) ([]byte, errs.Err) {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if e := enc.Encode(schema); e != nil {
+		return nil, errs.New(FailToMarshalSchema{}, e)
+	}
+	return bytes.TrimSuffix(b.Bytes(), []byte("\n")), errs.Ok()
+}
+
+func makeSchemaOpts(
+	cfgs []cliargs.OptCfg, metas map[string]optMeta,
+) []schemaOptJSON {
+	opts := make([]schemaOptJSON, 0, len(cfgs))
+	for _, cfg := range cfgs {
+		if cfg.Name == "*" {
+			continue
+		}
+		m := metas[cfg.Name]
+
+		defaults := cfg.Default
+		if defaults == nil {
+			defaults = m.defaults
+		}
+		if m.secret && defaults != nil {
+			a := make([]string, len(defaults))
+			for i := range a {
+				a[i] = redacted
+			}
+			defaults = a
+		}
+
+		opts = append(opts, schemaOptJSON{
+			Name:        cfg.Name,
+			Aliases:     nonNilStrings(cfg.Aliases),
+			Type:        schemaType(cfg, m),
+			HasArg:      cfg.HasArg,
+			IsArray:     cfg.IsArray,
+			ArgHelp:     cfg.ArgHelp,
+			Default:     defaults,
+			Choices:     nonNilStrings(m.choices.values),
+			EnvVar:      m.envVar,
+			Required:    m.required,
+			Hidden:      m.hidden,
+			Secret:      m.secret,
+			Deprecated:  m.deprecated,
+			Deprecation: m.deprecation,
+			Since:       m.since,
+			Group:       m.optGroup(),
+			Desc:        cfg.Desc,
+		})
+	}
+	return opts
+}
+
+func makeSchemaArgs(argCfgs []ArgCfg) []schemaArgJSON {
+	args := make([]schemaArgJSON, len(argCfgs))
+	for i, a := range argCfgs {
+		args[i] = schemaArgJSON{
+			Name: a.Name, Required: a.Required, Variadic: a.Variadic, Desc: a.Desc,
+		}
+	}
+	return args
+}
+
+// schemaType returns the type name of the values of the option.
+func schemaType(cfg cliargs.OptCfg, m optMeta) string {
+	if t := m.valueType; t != nil {
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if cfg.IsArray && t.Kind() == reflect.Slice {
+			t = t.Elem()
+		}
+		return t.String()
+	}
+	if m.kind != reflect.Invalid {
+		return m.kind.String()
+	}
+	if !cfg.HasArg {
+		return "bool"
+	}
+	return "string"
+}
+
+// nonNilStrings returns an empty array instead of nil, so that the JSON has
+// [] instead of null.
+func nonNilStrings(a []string) []string {
+	if a == nil {
+		return []string{}
+	}
+	return a
+}
@@ -0,0 +1,86 @@
+package cliargdax_test
+
+import (
+	"os"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+	"github.com/sttk/sabi/errs"
+)
+
+func TestExportSchema(t *testing.T) {
+	cfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "verbose", Aliases: []string{"v"}, Desc: "Verbose."},
+		cliargs.OptCfg{
+			Name: "count", HasArg: true, Default: []string{"3"}, ArgHelp: "<N>",
+		},
+		cliargs.OptCfg{Name: "format", HasArg: true},
+		cliargs.OptCfg{Name: "*"},
+	}
+	metas := map[string]cliargdax.OptMeta{
+		"count": cliargdax.OptMeta{Kind: reflect.Int, Required: true},
+		"format": cliargdax.OptMeta{
+			Choices: []string{"json", "yaml"}, EnvVar: "FORMAT", Group: "Output",
+			Deprecated: true, Deprecation: "use --output",
+		},
+	}
+	data, err := cliargdax.ExportSchema("app", cfgs, metas, []cliargdax.ArgCfg{
+		cliargdax.ArgCfg{Name: "src", Required: true, Desc: "Source."},
+		cliargdax.ArgCfg{Name: "dst", Variadic: true},
+	})
+	assert.True(t, err.IsOk())
+	assert.Equal(t, string(data), `{"schemaVersion":1,"name":"app","options":[`+
+		`{"name":"verbose","aliases":["v"],"type":"bool","hasArg":false,"isArray":false,"default":null,"choices":[],"required":false,"hidden":false,"secret":false,"deprecated":false,"description":"Verbose."},`+
+		`{"name":"count","aliases":[],"type":"int","hasArg":true,"isArray":false,"argHelp":"<N>","default":["3"],"choices":[],"required":true,"hidden":false,"secret":false,"deprecated":false,"description":""},`+
+		`{"name":"format","aliases":[],"type":"string","hasArg":true,"isArray":false,"argHelp":"{json|yaml}","default":null,"choices":["json","yaml"],"envVar":"FORMAT","required":false,"hidden":false,"secret":false,"deprecated":true,"deprecation":"use --output","group":"Output","description":""}],`+
+		`"positionals":[`+
+		`{"name":"src","required":true,"variadic":false,"description":"Source."},`+
+		`{"name":"dst","required":false,"variadic":true,"description":""}]}`)
+	assert.Equal(t, cliargdax.SchemaVersion, 1)
+}
+
+type schemaOptions struct {
+	Timeout time.Duration `optcfg:"timeout,t" optdefault:"5s" optdesc:"Timeout."`
+	Ports   []int         `optcfg:"port" optrequired:"true"`
+	Token   string        `optcfg:"token" optdefault:"abc" optsecret:"true" opthidden:"true"`
+}
+
+func TestDaxConn_ExportSchema(t *testing.T) {
+	options := schemaOptions{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions([]string{
+		"app", "--port=1", "remote",
+	}, &options).
+		Route("remote", func(*cliargdax.DaxConn) errs.Err { return errs.Ok() }).
+		Route("serve", func(*cliargdax.DaxConn) errs.Err { return errs.Ok() })
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	data, err := conn.ExportSchema()
+	assert.True(t, err.IsOk())
+	assert.Equal(t, string(data), `{"schemaVersion":1,"name":"app","options":[`+
+		`{"name":"timeout","aliases":["t"],"type":"time.Duration","hasArg":true,"isArray":false,"argHelp":"<duration>","default":["5s"],"choices":[],"required":false,"hidden":false,"secret":false,"deprecated":false,"description":"Timeout."},`+
+		`{"name":"port","aliases":[],"type":"int","hasArg":true,"isArray":true,"default":null,"choices":[],"required":true,"hidden":false,"secret":false,"deprecated":false,"description":""},`+
+		`{"name":"token","aliases":[],"type":"string","hasArg":true,"isArray":false,"default":["****"],"choices":[],"required":false,"hidden":true,"secret":true,"deprecated":false,"description":""}],`+
+		`"positionals":[],"routes":["remote","serve"]}`)
+}
+
+func TestDaxConn_ExportSchema_subCmds(t *testing.T) {
+	defer resetOsArgs()
+
+	os.Args = []string{"/path/to/app", "run", "--port=1"}
+	ds := cliargdax.NewDaxSrcWithSubCmdsForOptions(map[string]any{
+		"run":   &schemaOptions{},
+		"build": &struct{ Out string }{},
+	})
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	data, err := conn.ExportSchema()
+	assert.True(t, err.IsOk())
+	assert.Contains(t, string(data), `"subcommands":[{"name":"build","options":[{"name":"out",`)
+	assert.Contains(t, string(data), `{"name":"run","options":[{"name":"timeout",`)
+}
@@ -0,0 +1,356 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+
+	"github.com/sttk/cliargs"
+)
+
+// redacted is the string which replaces the values of secret options.
+const redacted = "****"
+
+// RedactedArgs is the method to retrieve the command line arguments which
+// were captured at Setup, excluding the program path, in the same way as
+// RawArgs method, but of which option arguments of secret options are
+// replaced with "****".
+// An option is secret if the field of the option store has the struct tag:
+// optsecret:"true", or if OptMeta.Secret is set by DaxSrc#SetMeta method.
+// This method is the safe counterpart to RawArgs method for logging.
+func (conn *DaxConn) RedactedArgs() []string {
+	conn.ds.parseLazily()
+	conn.ds.mutex.RLock()
+	defer conn.ds.mutex.RUnlock()
+
+	ds := conn.ds
+	if len(ds.rawArgs) <= 1 {
+		return []string{}
+	}
+	args := append([]string{}, ds.rawArgs...)
+
+	secrets := ds.secretOpts()
+	if len(secrets) == 0 {
+		return args[1:]
+	}
+
+	cfgs := append(append([]cliargs.OptCfg{}, ds.optCfgs...), ds.subOptCfgs...)
+	for _, tok := range ds.scanRawArgs(cfgs) {
+		if !tok.isOpt() || !tok.hasValue || !secrets[tok.name] {
+			continue
+		}
+		if tok.isNextArg {
+			args[tok.index+1] = redacted
+		} else {
+			a := args[tok.index]
+			args[tok.index] = a[:len(a)-len(tok.value)] + redacted
+		}
+	}
+	return args[1:]
+}
+
+// scanRawArgs divides the command line arguments captured at Setup to tokens
+// with the same rules as the parsing, of which option names are resolved from
+// aliases, abbreviations, and deprecated aliases.
+func (ds *DaxSrc) scanRawArgs(cfgs []cliargs.OptCfg) []argToken {
+	return ds.scanArgsAsParsed(ds.rawArgs, cfgs)
+}
+
+// scanArgsAsParsed divides the command line arguments to tokens in the same
+// way as scanRawArgs method.
+func (ds *DaxSrc) scanArgsAsParsed(
+	osArgs []string, cfgs []cliargs.OptCfg,
+) []argToken {
+	sc := argScanner{
+		stopAtFirstArg:  ds.mode.stopAtFirstArg,
+		allowSlash:      ds.mode.allowSlash,
+		allowSingleDash: ds.mode.allowSingleDash,
+	}
+	if ds.mode.allowAbbrev || ds.mode.ignoreCase {
+		nm := newNameMatcher(cfgs, ds.metas)
+		sc.spell = func(name string) string {
+			s, _ := nm.match(name, ds.mode)
+			return s
+		}
+	}
+	if len(ds.deprecatedAliases) > 0 {
+		r := parseResult{optCfgs: cfgs, aliases: ds.deprecatedAliases}
+		spell := sc.spell
+		sc.spell = func(name string) string {
+			if spell != nil {
+				name = spell(name)
+			}
+			return r.replaceDeprecatedAlias(name)
+		}
+	}
+	return scanArgsBy(sc, osArgs, cfgs)
+}
+
+// redactValues returns an array of which elements are "****" and of which
+// length is same as the specified array.
+func redactValues(values []string) []string {
+	a := make([]string, len(values))
+	for i := range a {
+		a[i] = redacted
+	}
+	return a
+}
+
+// secretOpts returns the set of the names of secret options, which are
+// available even if the parsing failed.
+func (ds *DaxSrc) secretOpts() map[string]bool {
+	secrets := make(map[string]bool)
+	addSecrets := func(metas map[string]optMeta) {
+		for name, m := range metas {
+			if m.secret {
+				secrets[name] = true
+			}
+		}
+	}
+
+	if ds.metas != nil {
+		addSecrets(ds.metas)
+	} else if stores := ds.optionStores(); len(stores) > 0 {
+		_, metas, _, _ := buildOptCfgsForStores(
+			stores, ds.decoders, ds.nameMapping,
+		)
+		addSecrets(metas)
+	}
+	for _, sub := range ds.subCmds {
+		if sub.options != nil {
+			_, metas, _, _ := buildOptCfgs(
+				sub.options, ds.decoders, ds.nameMapping,
+			)
+			addSecrets(metas)
+		}
+	}
+	for name, meta := range ds.optMetas {
+		if meta.Secret {
+			secrets[name] = true
+		}
+	}
+	return secrets
+}
+
+// redactOptions returns the JSON of the option store of which values of the
+// fields for secret options are replaced with "****".
+// If the option store has no secret option, this function returns the option
+// store as it is.
+func redactOptions(options any, metas map[string]optMeta) any {
+	if options == nil {
+		return nil
+	}
+	t := reflect.TypeOf(options)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return options
+	}
+
+	var paths [][]string
+	for _, m := range metas {
+		if !m.secret {
+			continue
+		}
+		if p := jsonKeyPath(t, m.group, m.field); p != nil {
+			paths = append(paths, p)
+		}
+	}
+	if len(paths) == 0 {
+		return options
+	}
+
+	data, e := json.Marshal(options)
+	if e != nil {
+		return options
+	}
+	var v any
+	if e := json.Unmarshal(data, &v); e != nil {
+		return options
+	}
+	for _, p := range paths {
+		obj, ok := v.(map[string]any)
+		for _, key := range p[:len(p)-1] {
+			if !ok {
+				break
+			}
+			obj, ok = obj[key].(map[string]any)
+		}
+		if !ok {
+			continue
+		}
+		if _, exists := obj[p[len(p)-1]]; exists {
+			obj[p[len(p)-1]] = redacted
+		}
+	}
+	return v
+}
+
+// jsonKeyPath returns the keys of JSON objects to the field of the option
+// store, which is specified with the path of the nested struct and the field
+// name.
+// If the field is not found or is omitted in JSON, this function returns nil.
+func jsonKeyPath(t reflect.Type, group string, field string) []string {
+	names := []string{field}
+	if len(group) > 0 {
+		names = append(strings.Split(group, "."), field)
+	}
+
+	keys := make([]string, 0, len(names))
+	for _, name := range names {
+		for t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			return nil
+		}
+		sf, ok := t.FieldByName(name)
+		if !ok {
+			return nil
+		}
+		key := sf.Name
+		if tag := sf.Tag.Get("json"); len(tag) > 0 {
+			if tag == "-" {
+				return nil
+			}
+			if k, _, _ := strings.Cut(tag, ","); len(k) > 0 {
+				key = k
+			}
+		}
+		keys = append(keys, key)
+		t = sf.Type
+	}
+	return keys
+}
+
+// redactReason returns a copy of the error reason of which values of secret
+// options are replaced with "****".
+func redactReason(reason any, secrets map[string]bool) any {
+	if len(secrets) == 0 {
+		return reason
+	}
+	switch r := reason.(type) {
+	case cliargs.FailToParseInt:
+		if secrets[r.Option] {
+			r.Input = redacted
+		}
+		return r
+	case cliargs.FailToParseUint:
+		if secrets[r.Option] {
+			r.Input = redacted
+		}
+		return r
+	case cliargs.FailToParseFloat:
+		if secrets[r.Option] {
+			r.Input = redacted
+		}
+		return r
+	case OptionValueNotInChoices:
+		if secrets[r.Option] {
+			r.Value = redacted
+		}
+		return r
+	case OptionGivenTwice:
+		if secrets[r.Option] {
+			r.FirstValue = redacted
+			r.SecondValue = redacted
+		}
+		return r
+	case OptionValueOutOfRange:
+		if secrets[r.Option] {
+			r.Value = redacted
+		}
+		return r
+	case OptionValidationFailed:
+		if secrets[r.Option] {
+			r.Value = redacted
+			r.Cause = nil
+		}
+		if len(r.Failures) > 0 {
+			failures := make([]OptionValidationFailed, len(r.Failures))
+			for i, f := range r.Failures {
+				failures[i] = redactReason(f, secrets).(OptionValidationFailed)
+			}
+			r.Failures = failures
+		}
+		return r
+	case OptCallbackFailed:
+		if secrets[r.Option] {
+			r.Value = redacted
+			r.Cause = nil
+		}
+		return r
+	case OptionArgIsNotKeyValue:
+		if secrets[r.Option] {
+			r.Value = redacted
+		}
+		return r
+	case OptionArgHasDuplicateKey:
+		if secrets[r.Option] {
+			r.Key = redacted
+		}
+		return r
+	case OptionArgUnmarshalFailed:
+		if secrets[r.Option] {
+			r.Value = redacted
+			r.Cause = nil
+		}
+		return r
+	case FailToParseEnvVar:
+		if secrets[r.Option] {
+			r.Input = redacted
+		}
+		return r
+	case FailToParseDefault:
+		if secrets[r.Option] {
+			r.Input = redacted
+		}
+		return r
+	case FailToParseElement:
+		if secrets[r.Option] {
+			r.Input = redacted
+		}
+		return r
+	case FailToParseDuration:
+		if secrets[r.Option] {
+			r.Input = redacted
+		}
+		return r
+	case FailToParseTime:
+		if secrets[r.Option] {
+			r.Input = redacted
+		}
+		return r
+	case FailToParseURL:
+		if secrets[r.Option] {
+			r.Input = redacted
+		}
+		return r
+	case FailToParseIP:
+		if secrets[r.Option] {
+			r.Input = redacted
+		}
+		return r
+	case FailToResolvePath:
+		if secrets[r.Option] {
+			r.Input = redacted
+		}
+		return r
+	case ConfigValueTypeMismatch:
+		if secrets[r.Option] {
+			r.Input = redacted
+		}
+		return r
+	case FailToParseConfigValue:
+		if secrets[r.Option] {
+			r.Input = redacted
+		}
+		return r
+	}
+	return reason
+}
@@ -0,0 +1,150 @@
+package cliargdax_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+)
+
+func TestSecret_RedactedArgs(t *testing.T) {
+	options := struct {
+		User     string   `optcfg:"user,u"`
+		Password string   `optcfg:"password,p" optsecret:"true"`
+		Token    string   `optcfg:"token" optsecret:"true"`
+		Keys     []string `optcfg:"key,k" optsecret:"true"`
+	}{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions([]string{
+		"/path/to/app", "-u", "me", "--password", "pw 1", "--token=tk",
+		"-k", "k1", "-k=k2", "arg", "--", "--token=x",
+	}, &options)
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	assert.Equal(t, options.Password, "pw 1")
+	assert.Equal(t, options.Token, "tk")
+	assert.Equal(t, options.Keys, []string{"k1", "k2"})
+
+	assert.Equal(t, conn.RawArgs(), []string{
+		"-u", "me", "--password", "pw 1", "--token=tk",
+		"-k", "k1", "-k=k2", "arg", "--", "--token=x",
+	})
+	assert.Equal(t, conn.RedactedArgs(), []string{
+		"-u", "me", "--password", "****", "--token=****",
+		"-k", "****", "-k=****", "arg", "--", "--token=x",
+	})
+}
+
+func TestSecret_RedactedArgs_abbrevAndDeprecatedAlias(t *testing.T) {
+	cfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "password", HasArg: true, IsArray: true},
+		cliargs.OptCfg{Name: "port", HasArg: true},
+	}
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"/path/to/app", "--pass=pw", "--pwd", "pw2", "--port=80",
+	}, cfgs).
+		AllowAbbrev(true).
+		DeprecatedAlias("pwd", "password", "").
+		SetMeta("password", cliargdax.OptMeta{Secret: true})
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk(), err.Error())
+
+	m, ok := conn.Meta("password")
+	assert.True(t, ok)
+	assert.True(t, m.Secret)
+
+	assert.Equal(t, conn.RedactedArgs(), []string{
+		"--pass=****", "--pwd", "****", "--port=80",
+	})
+}
+
+func TestSecret_FormatError(t *testing.T) {
+	options := struct {
+		Pin int `optcfg:"pin" optsecret:"true"`
+	}{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions(
+		[]string{"/path/to/app", "--pin=12a4"}, &options,
+	).
+		Lazy()
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	err = conn.ParseErr()
+	assert.True(t, err.IsNotOk())
+
+	assert.Equal(t, conn.FormatError(err),
+		"invalid value for option --pin: \"****\" (must be an integer)\n"+
+			"Try 'app --help' for more information.")
+	assert.Equal(t, cliargdax.FormatError(err, conn.OptCfgs()),
+		"invalid value for option --pin: \"12a4\" (must be an integer)\n"+
+			"Try '--help' for more information.")
+}
+
+func TestSecret_FormatError_validation(t *testing.T) {
+	cfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "mode", HasArg: true},
+	}
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"/path/to/app", "--mode=hush"}, cfgs,
+	).
+		Choices("mode", "a", "b").
+		SetMeta("mode", cliargdax.OptMeta{Secret: true}).
+		Lazy()
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	err = conn.ParseErr()
+	assert.Equal(t, conn.FormatError(err),
+		"invalid value for option --mode: \"****\" (choose from a, b)\n"+
+			"Try 'app --help' for more information.")
+}
+
+func TestSecret_ResultJSON(t *testing.T) {
+	type DB struct {
+		Password string `optcfg:"password" optsecret:"true" json:"pass"`
+		Host     string `optcfg:"host" json:"host"`
+	}
+	options := struct {
+		Token string `optcfg:"token" optsecret:"true"`
+		DB    DB
+	}{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions([]string{
+		"/path/to/app", "--token=tk", "--db-password=pw", "--db-host=h",
+	}, &options)
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	data, err := conn.ResultJSON()
+	assert.True(t, err.IsOk())
+	assert.Equal(t, string(data), `{"name":"app","args":[],`+
+		`"opts":{"db-host":"h","db-password":"****","token":"****"},`+
+		`"options":{"DB":{"host":"h","pass":"****"},"Token":"****"}}`)
+
+	assert.Equal(t, options.Token, "tk")
+	assert.Equal(t, options.DB.Password, "pw")
+	assert.Equal(t, conn.Cmd().OptArg("token"), "tk")
+}
+
+func TestSecret_BuildCommandLineString(t *testing.T) {
+	cfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "token", HasArg: true},
+		cliargs.OptCfg{Name: "user", HasArg: true},
+	}
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"/path/to/app", "--token=tk", "--user=me",
+	}, cfgs).
+		SetMeta("token", cliargdax.OptMeta{Secret: true})
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	assert.Equal(t, conn.BuildArgs(nil, nil), []string{"--token=tk", "--user=me"})
+	assert.Equal(t, conn.BuildCommandLineString(nil, nil),
+		"'--token=****' --user=me")
+}
@@ -0,0 +1,149 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/sttk/sabi/errs"
+)
+
+type /* error reasons */ (
+	// OptionNotAvailableInVersion is the error reason which indicates that an
+	// option given in command line arguments is available only from a version
+	// newer than the application version set by DaxSrc#WithAppVersion method.
+	// The fields Option, Since, and Current are the option name, the version
+	// from which the option is available, and the application version.
+	OptionNotAvailableInVersion struct {
+		Option  string
+		Since   string
+		Current string
+	}
+)
+
+// WithAppVersion is the method to set the version of the application, which
+// is compared with the versions from which options are available, set by the
+// optsince struct tag or OptMeta#Since field.
+// If an option given in command line arguments is available only from a
+// newer version, Setup method fails with the reason:
+// OptionNotAvailableInVersion.
+// Options without the version are always available, and all options are
+// available if the application version is not set.
+// Versions are compared as semantic versions with or without the prefix "v",
+// of which missing minor and patch numbers are 0, and a pre-release version,
+// like 2.1.0-beta.1, is older than its release version, so an option
+// available from 2.1 is not available in 2.1.0-beta.1 unless its version is
+// also a pre-release version, like 2.1.0-beta.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) WithAppVersion(version string) *DaxSrc {
+	ds.appVersion = version
+	return ds
+}
+
+// checkOptVersions checks that the options in the tokens are available in the
+// application version.
+func (r *parseResult) checkOptVersions(toks []argToken) errs.Err {
+	if len(r.appVersion) == 0 {
+		return errs.Ok()
+	}
+	for _, tok := range toks {
+		if !tok.isOpt() {
+			continue
+		}
+		since := r.metas[tok.name].since
+		if len(since) > 0 && compareVersions(since, r.appVersion) > 0 {
+			return errs.New(OptionNotAvailableInVersion{
+				Option: tok.name, Since: since, Current: r.appVersion,
+			})
+		}
+	}
+	return errs.Ok()
+}
+
+// compareVersions returns a negative number, zero, or a positive number if
+// the version a is older than, same as, or newer than the version b.
+// The build metadata after "+" is ignored.
+func compareVersions(a, b string) int {
+	coreA, preA := splitVersion(a)
+	coreB, preB := splitVersion(b)
+
+	n := len(coreA)
+	if len(coreB) > n {
+		n = len(coreB)
+	}
+	for i := 0; i < n; i++ {
+		x, y := "0", "0"
+		if i < len(coreA) {
+			x = coreA[i]
+		}
+		if i < len(coreB) {
+			y = coreB[i]
+		}
+		if c := compareVersionIds(x, y); c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case len(preA) == 0 && len(preB) == 0:
+		return 0
+	case len(preA) == 0:
+		return 1
+	case len(preB) == 0:
+		return -1
+	}
+	for i := 0; i < len(preA) && i < len(preB); i++ {
+		if c := compareVersionIds(preA[i], preB[i]); c != 0 {
+			return c
+		}
+	}
+	return len(preA) - len(preB)
+}
+
+// splitVersion divides the version into the dot-separated identifiers of the
+// core version and of the pre-release version.
+func splitVersion(v string) ([]string, []string) {
+	v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+	if i := strings.IndexByte(v, '+'); i >= 0 {
+		v = v[:i]
+	}
+	var pre []string
+	if i := strings.IndexByte(v, '-'); i >= 0 {
+		pre = strings.Split(v[i+1:], ".")
+		v = v[:i]
+	}
+	return strings.Split(v, "."), pre
+}
+
+// compareVersionIds compares the identifiers numerically if both are numbers,
+// otherwise compares them lexically, and a number is older than a non-number.
+func compareVersionIds(x, y string) int {
+	nx, ex := strconv.ParseUint(x, 10, 64)
+	ny, ey := strconv.ParseUint(y, 10, 64)
+	switch {
+	case ex == nil && ey == nil:
+		switch {
+		case nx < ny:
+			return -1
+		case nx > ny:
+			return 1
+		}
+		return 0
+	case ex == nil:
+		return -1
+	case ey == nil:
+		return 1
+	}
+	return strings.Compare(x, y)
+}
+
+// versionText returns the version with the prefix "v" for messages.
+func versionText(v string) string {
+	if strings.HasPrefix(v, "v") {
+		return v
+	}
+	return "v" + v
+}
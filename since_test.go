@@ -0,0 +1,105 @@
+package cliargdax_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+)
+
+type sinceOptions struct {
+	Beta    bool   `optcfg:"beta-feature,b" optdesc:"Enable the beta feature." optsince:"2.1"`
+	Level   string `optcfg:"level" optsince:"v2.0.1"`
+	Verbose bool   `optcfg:"verbose" optdesc:"Print verbose messages."`
+}
+
+func TestSince_WithAppVersion(t *testing.T) {
+	options := sinceOptions{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions([]string{
+		"app", "--verbose", "--level=x", "-b",
+	}, &options).WithAppVersion("2.0.3")
+	_, err := setupWithOptCfgs(t, ds)
+	assert.Equal(t, err.Reason(), cliargdax.OptionNotAvailableInVersion{
+		Option: "beta-feature", Since: "2.1", Current: "2.0.3",
+	})
+	assert.Equal(t, cliargdax.FormatError(err, nil),
+		"option --beta-feature is not available in v2.0.3: available from v2.1\n"+
+			"Try '--help' for more information.")
+
+	options = sinceOptions{}
+	ds = cliargdax.NewDaxSrcWithArgsForOptions([]string{
+		"app", "--verbose", "--level=x", "-b",
+	}, &options).WithAppVersion("v2.1.0")
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.True(t, options.Beta)
+	meta, _ := conn.Meta("beta-feature")
+	assert.Equal(t, meta.Since, "2.1")
+
+	options = sinceOptions{}
+	ds = cliargdax.NewDaxSrcWithArgsForOptions([]string{
+		"app", "--level=x",
+	}, &options).WithAppVersion("2.0.0")
+	_, err = setupWithOptCfgs(t, ds)
+	assert.Equal(t, err.Reason(), cliargdax.OptionNotAvailableInVersion{
+		Option: "level", Since: "v2.0.1", Current: "2.0.0",
+	})
+
+	options = sinceOptions{}
+	ds = cliargdax.NewDaxSrcWithArgsForOptions([]string{
+		"app", "--verbose",
+	}, &options).WithAppVersion("1.0")
+	_, err = setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	options = sinceOptions{}
+	ds = cliargdax.NewDaxSrcWithArgsForOptions([]string{
+		"app", "-b", "--level=x",
+	}, &options)
+	_, err = setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+}
+
+func TestSince_preRelease(t *testing.T) {
+	cases := []struct {
+		since   string
+		current string
+		ok      bool
+	}{
+		{since: "2.1", current: "2.1.0-beta.1", ok: false},
+		{since: "2.1.0-beta", current: "2.1.0-beta.1", ok: true},
+		{since: "2.1.0-beta.2", current: "2.1.0-beta.10", ok: true},
+		{since: "2.1.0-beta.10", current: "2.1.0-beta.2", ok: false},
+		{since: "2.1.0-alpha", current: "2.1.0-beta", ok: true},
+		{since: "2.1.0-rc.1", current: "2.1.0", ok: true},
+		{since: "2.1.0-1", current: "2.1.0-alpha", ok: true},
+		{since: "2.10", current: "2.9.9", ok: false},
+		{since: "2.1", current: "2.1.0+build.5", ok: true},
+	}
+	for _, c := range cases {
+		optCfgs := []cliargs.OptCfg{cliargs.OptCfg{Name: "beta"}}
+		ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+			[]string{"app", "--beta"}, optCfgs,
+		).SetMeta("beta", cliargdax.OptMeta{Since: c.since}).
+			WithAppVersion(c.current)
+		_, err := setupWithOptCfgs(t, ds)
+		assert.Equal(t, err.IsOk(), c.ok, c)
+	}
+}
+
+func TestSince_helpText(t *testing.T) {
+	options := sinceOptions{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions([]string{"app"}, &options).
+		WithAppVersion("2.0.3").
+		HelpWidth(80)
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.HelpText(), `Usage: app [OPTIONS]
+
+Options:
+  -b, --beta-feature    Enable the beta feature. (since v2.1)
+      --level <LEVEL>   (since v2.0.1)
+      --verbose         Print verbose messages.
+`)
+}
@@ -0,0 +1,52 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// AllowSingleDashLongOpts is the method to enable or disable the option forms
+// of the standard flag package: -name and -name=value, in addition to --name
+// and --name=value.
+// In this mode, an argument starting with a single "-" of which part before
+// "=" is longer than one character is treated as a long option if the part is
+// a configured option name or alias, instead of being divided into short
+// options.
+// If the part also consists of configured short options, like -verbose where
+// -v, -e, -r, ... are configured, the long option is preferred.
+// The other arguments starting with a single "-" are divided into short
+// options as usual.
+// This mode is disabled by default.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) AllowSingleDashLongOpts(allow bool) *DaxSrc {
+	ds.mode.allowSingleDash = allow
+	return ds
+}
+
+// scanSingleDashOpt makes a token from an argument in the form: -name or
+// -name=value, and returns false as the second result if the argument is not
+// in the form or the name is not configured.
+func (sc argScanner) scanSingleDashOpt(i int, arg string) (argToken, bool) {
+	if !sc.allowSingleDash || len(arg) < 3 || arg[0] != '-' || arg[1] == '-' {
+		return argToken{}, false
+	}
+
+	tok := argToken{index: i, name: arg[1:]}
+	if j := strings.IndexByte(tok.name, '='); j >= 0 {
+		tok.value = tok.name[j+1:]
+		tok.hasValue = true
+		tok.name = tok.name[:j]
+	}
+	if utf8.RuneCountInString(tok.name) < 2 {
+		return argToken{}, false
+	}
+	if sc.isConfigured == nil || !sc.isConfigured(tok.name) {
+		return argToken{}, false
+	}
+	tok.spelling = "-" + tok.name
+	return tok, true
+}
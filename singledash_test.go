@@ -0,0 +1,128 @@
+package cliargdax_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+)
+
+func TestSingleDashLongOpts(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "verbose", Aliases: []string{"v"}},
+		cliargs.OptCfg{
+			Name: "name", Aliases: []string{"n"}, HasArg: true, IsArray: true,
+		},
+		cliargs.OptCfg{Name: "level", HasArg: true},
+		cliargs.OptCfg{Name: "a"},
+		cliargs.OptCfg{Name: "b"},
+	}
+
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "-verbose", "-name=foo", "-level", "3", "-ab", "-n", "bar", "x",
+	}, optCfgs).AllowSingleDashLongOpts(true)
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	cmd := conn.Cmd()
+	assert.True(t, cmd.HasOpt("verbose"))
+	assert.Equal(t, cmd.OptArgs("name"), []string{"foo", "bar"})
+	assert.Equal(t, cmd.OptArg("level"), "3")
+	assert.True(t, cmd.HasOpt("a"))
+	assert.True(t, cmd.HasOpt("b"))
+	assert.Equal(t, cmd.Args(), []string{"x"})
+	assert.Equal(t, conn.OptSource("verbose"), cliargdax.SourceCLI)
+}
+
+func TestSingleDashLongOpts_preferLongName(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "all", Aliases: []string{"a"}},
+		cliargs.OptCfg{Name: "l"},
+		cliargs.OptCfg{Name: "al"},
+	}
+
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "-al",
+	}, optCfgs).AllowSingleDashLongOpts(true)
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.True(t, conn.Cmd().HasOpt("al"))
+	assert.False(t, conn.Cmd().HasOpt("all"))
+	assert.False(t, conn.Cmd().HasOpt("l"))
+
+	ds = cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "-la",
+	}, optCfgs).AllowSingleDashLongOpts(true)
+	conn, err = setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.True(t, conn.Cmd().HasOpt("all"))
+	assert.True(t, conn.Cmd().HasOpt("l"))
+	assert.False(t, conn.Cmd().HasOpt("al"))
+}
+
+func TestSingleDashLongOpts_takesNoArg(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "-verbose=yes",
+	}, []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "verbose"},
+	}).AllowSingleDashLongOpts(true)
+	_, err := setupWithOptCfgs(t, ds)
+	assert.Equal(t, err.Reason(), cliargs.OptionTakesNoArg{Option: "verbose"})
+}
+
+func TestSingleDashLongOpts_withStore(t *testing.T) {
+	type Options struct {
+		Verbose bool   `optcfg:"verbose,v"`
+		Name    string `optcfg:"name"`
+	}
+	options := Options{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions([]string{
+		"app", "-verbose", "-name", "foo",
+	}, &options).
+		AllowSingleDashLongOpts(true).
+		EnableHelpOpt("help", "h")
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, options, Options{Verbose: true, Name: "foo"})
+	assert.False(t, conn.HelpRequested())
+
+	options = Options{}
+	ds = cliargdax.NewDaxSrcWithArgsForOptions([]string{
+		"app", "-help",
+	}, &options).
+		AllowSingleDashLongOpts(true).
+		EnableHelpOpt("help", "h")
+	conn, err = setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.True(t, conn.HelpRequested())
+}
+
+func TestSingleDashLongOpts_disabledByDefault(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "-ab",
+	}, []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "ab"},
+		cliargs.OptCfg{Name: "a"},
+		cliargs.OptCfg{Name: "b"},
+	})
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.False(t, conn.Cmd().HasOpt("ab"))
+	assert.True(t, conn.Cmd().HasOpt("a"))
+	assert.True(t, conn.Cmd().HasOpt("b"))
+}
+
+func TestSingleDashLongOpts_Explain(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "-verbose", "-v",
+	}, []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "verbose", Aliases: []string{"v"}},
+		cliargs.OptCfg{Name: "e"},
+	}).AllowSingleDashLongOpts(true).RecordTokens(true)
+	conn, _ := setupWithOptCfgs(t, ds)
+	infos := conn.Explain()
+	assert.Equal(t, infos[0].Kind, cliargdax.LongOptionToken)
+	assert.Equal(t, infos[0].Options, []string{"verbose"})
+	assert.Equal(t, infos[1].Kind, cliargdax.ShortOptionsToken)
+}
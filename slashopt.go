@@ -0,0 +1,45 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"strings"
+)
+
+// AllowSlashOpts is the method to enable or disable the Windows-style option
+// forms: /name and /name:value, in addition to --name and --name=value.
+// In this mode, an argument starting with "/" is treated as an option only if
+// the part before ":" is a configured option name or alias, so an absolute
+// path like /usr/bin is still a command argument.
+// The options given in these forms are stored in cliargs.Cmd with the
+// configured option names, in the same way as the options given with "--".
+// This mode is disabled by default.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) AllowSlashOpts(allow bool) *DaxSrc {
+	ds.mode.allowSlash = allow
+	return ds
+}
+
+// scanSlashOpt makes a token from an argument in the form: /name or
+// /name:value, and returns false as the second result if the argument is not
+// in the form or the name is not configured.
+func (sc argScanner) scanSlashOpt(i int, arg string) (argToken, bool) {
+	if !sc.allowSlash || len(arg) < 2 || arg[0] != '/' {
+		return argToken{}, false
+	}
+
+	tok := argToken{index: i, name: arg[1:]}
+	if j := strings.IndexByte(tok.name, ':'); j >= 0 {
+		tok.value = tok.name[j+1:]
+		tok.hasValue = true
+		tok.name = tok.name[:j]
+	}
+	if sc.isConfigured == nil || !sc.isConfigured(tok.name) {
+		return argToken{}, false
+	}
+	tok.isShort = (len([]rune(tok.name)) == 1)
+	tok.spelling = "/" + tok.name
+	return tok, true
+}
@@ -0,0 +1,65 @@
+package cliargdax_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+)
+
+func TestSlashOpts(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "verbose", Aliases: []string{"v"}},
+		cliargs.OptCfg{Name: "out", Aliases: []string{"o"}, HasArg: true},
+		cliargs.OptCfg{Name: "level", HasArg: true},
+	}
+
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "/verbose", "/out:file.txt", "/level", "3", "/usr/bin",
+		"C:\\work",
+	}, optCfgs).AllowSlashOpts(true)
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	cmd := conn.Cmd()
+	assert.True(t, cmd.HasOpt("verbose"))
+	assert.Equal(t, cmd.OptArg("out"), "file.txt")
+	assert.Equal(t, cmd.OptArg("level"), "3")
+	assert.Equal(t, cmd.Args(), []string{"/usr/bin", "C:\\work"})
+
+	ds = cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "/v", "/o:a.txt", "--", "/verbose",
+	}, optCfgs).AllowSlashOpts(true)
+	conn, err = setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	cmd = conn.Cmd()
+	assert.True(t, cmd.HasOpt("verbose"))
+	assert.False(t, cmd.HasOpt("v"))
+	assert.Equal(t, cmd.OptArg("out"), "a.txt")
+	assert.Equal(t, cmd.Args(), []string{"/verbose"})
+	assert.Equal(t, conn.OptSource("verbose"), cliargdax.SourceAlias)
+}
+
+func TestSlashOpts_takesNoArg(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "/verbose:yes",
+	}, []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "verbose"},
+	}).AllowSlashOpts(true)
+	_, err := setupWithOptCfgs(t, ds)
+	assert.Equal(t, err.Reason(), cliargs.OptionTakesNoArg{Option: "verbose"})
+}
+
+func TestSlashOpts_disabledByDefault(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "/verbose",
+	}, []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "verbose"},
+	})
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.False(t, conn.Cmd().HasOpt("verbose"))
+	assert.Equal(t, conn.Cmd().Args(), []string{"/verbose"})
+}
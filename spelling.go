@@ -0,0 +1,31 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+// OptSpellings is the method to retrieve the spellings of the option as
+// written in command line arguments, in the order of the occurrences, like
+// ["--output", "-o"].
+// A spelling has its prefix, and is the alias, the abbreviation, or the
+// negated form if it is given so, like -o, --out, or --no-color.
+// Each character of combined short options, like -vvv, is a spelling: -v.
+// If the option is not given in command line arguments, this method returns
+// an empty array, even if its value is taken from an environment variable or
+// the config file.
+func (conn *DaxConn) OptSpellings(name string) []string {
+	conn.ds.parseLazily()
+	conn.ds.mutex.RLock()
+	defer conn.ds.mutex.RUnlock()
+	return append([]string{}, conn.ds.spellings[name]...)
+}
+
+// recordSpellings records the spellings of the options in the tokens.
+func (r *parseResult) recordSpellings(toks []argToken) {
+	r.spellings = make(map[string][]string)
+	for _, tok := range toks {
+		if tok.isOpt() && len(tok.spelling) > 0 {
+			r.spellings[tok.name] = append(r.spellings[tok.name], tok.spelling)
+		}
+	}
+}
@@ -0,0 +1,53 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"github.com/sttk/cliargs"
+	"github.com/sttk/sabi/errs"
+)
+
+// Parse is the function to parse command line arguments without a
+// sabi.DaxBase, and returns the results of the parsing directly.
+// The first element of args is treated as the program path, like os.Args.
+// Since no option configuration is given, all options in args are accepted.
+// This function parses args with the same pipeline as DaxSrc#Setup method, by
+// setting up a DaxSrc created by NewDaxSrcWithArgs function.
+func Parse(args []string) (cliargs.Cmd, errs.Err) {
+	return parseStandalone(NewDaxSrcWithArgs(args))
+}
+
+// ParseWith is the function to parse command line arguments with the option
+// configurations without a sabi.DaxBase, and returns the results of the
+// parsing directly.
+// The first element of args is treated as the program path, like os.Args.
+// This function parses args with the same pipeline as DaxSrc#Setup method, by
+// setting up a DaxSrc created by NewDaxSrcWithArgsAndOptCfgs function, so the
+// option configurations are checked by ValidateOptCfgs function, and the
+// default values and environment variables of the options are applied.
+func ParseWith(
+	args []string, cfgs []cliargs.OptCfg,
+) (cliargs.Cmd, errs.Err) {
+	return parseStandalone(NewDaxSrcWithArgsAndOptCfgs(args, cfgs))
+}
+
+// ParseInto is the function to parse command line arguments and to store the
+// results into the option store, which is a pointer of a struct instance of
+// any type, without a sabi.DaxBase.
+// The first element of args is treated as the program path, like os.Args.
+// This function parses args with the same pipeline as DaxSrc#Setup method, by
+// setting up a DaxSrc created by NewDaxSrcWithArgsForOptions function, so the
+// struct tags of the option store, like validations and defaults, are
+// applied.
+func ParseInto(args []string, opts any) (cliargs.Cmd, errs.Err) {
+	return parseStandalone(NewDaxSrcWithArgsForOptions(args, opts))
+}
+
+// parseStandalone sets up the DaxSrc, and returns the results of the parsing.
+func parseStandalone(ds *DaxSrc) (cliargs.Cmd, errs.Err) {
+	err := ds.Setup(nil)
+	defer ds.Close()
+	return ds.cmd, err
+}
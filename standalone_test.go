@@ -0,0 +1,81 @@
+package cliargdax_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+)
+
+func TestParse(t *testing.T) {
+	cmd, err := cliargdax.Parse([]string{"/path/to/app", "-a", "--foo=1", "bar"})
+	assert.True(t, err.IsOk())
+	assert.Equal(t, cmd.Name, "app")
+	assert.Equal(t, cmd.Args(), []string{"bar"})
+	assert.True(t, cmd.HasOpt("a"))
+	assert.Equal(t, cmd.OptArg("foo"), "1")
+}
+
+func TestParseWith(t *testing.T) {
+	cfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "foo", HasArg: true},
+		cliargs.OptCfg{Name: "bar", HasArg: true, Default: []string{"def"}},
+	}
+
+	cmd, err := cliargdax.ParseWith([]string{"/path/to/app", "--foo=1"}, cfgs)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, cmd.OptArg("foo"), "1")
+	assert.Equal(t, cmd.OptArg("bar"), "def")
+}
+
+func TestParseWith_error(t *testing.T) {
+	cfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "foo", HasArg: true},
+	}
+
+	_, err := cliargdax.ParseWith([]string{"/path/to/app", "--baz"}, cfgs)
+	switch err.Reason().(type) {
+	case cliargs.UnconfiguredOption:
+	default:
+		assert.Fail(t, err.Error())
+	}
+
+	cfgs = append(cfgs, cliargs.OptCfg{Name: "foo"})
+	_, err = cliargdax.ParseWith([]string{"/path/to/app"}, cfgs)
+	switch err.Reason().(type) {
+	case cliargdax.DuplicateOptName:
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestParseInto(t *testing.T) {
+	t.Setenv("MY_APP_PORT", "8080")
+
+	options := struct {
+		Verbose bool   `optcfg:"verbose,v"`
+		Port    int    `optcfg:"port" optenv:"MY_APP_PORT"`
+		Host    string `optcfg:"host=localhost"`
+	}{}
+
+	cmd, err := cliargdax.ParseInto([]string{"/path/to/app", "-v", "x"}, &options)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, cmd.Args(), []string{"x"})
+	assert.True(t, options.Verbose)
+	assert.Equal(t, options.Port, 8080)
+	assert.Equal(t, options.Host, "localhost")
+}
+
+func TestParseInto_error(t *testing.T) {
+	options := struct {
+		Port int `optcfg:"port" optmin:"1"`
+	}{}
+
+	_, err := cliargdax.ParseInto([]string{"/path/to/app", "--port=0"}, &options)
+	switch err.Reason().(type) {
+	case cliargdax.OptionValueOutOfRange:
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
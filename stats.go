@@ -0,0 +1,66 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"time"
+)
+
+// ParseStats is the struct type which holds the statistics of parsing command
+// line arguments, which is retrieved by DaxConn#Stats method.
+// The field ArgCount is the number of the command line arguments after the
+// expansions of response files and of the arguments from the standard input,
+// excluding the program path.
+// The fields OptionCount and PositionalCount are the numbers of the given
+// options and the command arguments, including the ones of a sub command.
+// The field ExpandedFileCount is the number of the response files read.
+// The field Duration is the time taken by the parsing, and the field Parsed
+// indicates whether the parsing has been done, which is false until the first
+// access in lazy mode.
+type ParseStats struct {
+	ArgCount          int           `json:"arg_count"`
+	OptionCount       int           `json:"option_count"`
+	PositionalCount   int           `json:"positional_count"`
+	ExpandedFileCount int           `json:"expanded_file_count"`
+	Duration          time.Duration `json:"duration_ns"`
+	Parsed            bool          `json:"parsed"`
+}
+
+// Stats is the method to retrieve the statistics of parsing command line
+// arguments.
+// This method does not trigger the parsing in lazy mode, and returns a
+// ParseStats of which all fields are zero values before the parsing.
+func (conn *DaxConn) Stats() ParseStats {
+	conn.ds.mutex.RLock()
+	defer conn.ds.mutex.RUnlock()
+	return conn.ds.stats
+}
+
+// StatsInResult is the method to include the statistics of parsing under
+// "stats" in the JSON made by DaxConn#ResultJSON method.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) StatsInResult(include bool) *DaxSrc {
+	ds.statsInResult = include
+	return ds
+}
+
+// makeStats makes the statistics of the parsing which has just been done.
+func (ds *DaxSrc) makeStats(files int, d time.Duration) ParseStats {
+	stats := ParseStats{
+		ExpandedFileCount: files,
+		Duration:          d,
+		Parsed:            true,
+	}
+	if len(ds.osArgs) > 1 {
+		stats.ArgCount = len(ds.osArgs) - 1
+	}
+	stats.OptionCount = len(cmdOptNames(ds.cmd, ds.optCfgs, ds.counts))
+	stats.PositionalCount = len(ds.cmd.Args())
+	if len(ds.subCmdName) > 0 {
+		stats.OptionCount += len(cmdOptNames(ds.subCmd, ds.subOptCfgs, nil))
+		stats.PositionalCount += len(ds.subCmd.Args())
+	}
+	return stats
+}
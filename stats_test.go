@@ -0,0 +1,92 @@
+package cliargdax_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+)
+
+func TestStats_Stats(t *testing.T) {
+	dir := t.TempDir()
+	inner := filepath.Join(dir, "inner.rsp")
+	outer := filepath.Join(dir, "outer.rsp")
+	assert.Nil(t, os.WriteFile(inner, []byte("--bar=2\n"), 0600))
+	assert.Nil(t, os.WriteFile(outer, []byte("--foo @"+inner+" x\n"), 0600))
+
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "foo"},
+		cliargs.OptCfg{Name: "bar", HasArg: true},
+	}
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "@" + outer, "y", "--foo"}, optCfgs,
+	).ExpandResponseFiles(true)
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	stats := conn.Stats()
+	assert.Equal(t, stats.ArgCount, 5)
+	assert.Equal(t, stats.OptionCount, 2)
+	assert.Equal(t, stats.PositionalCount, 2)
+	assert.Equal(t, stats.ExpandedFileCount, 2)
+	assert.True(t, stats.Duration > 0)
+	assert.True(t, stats.Parsed)
+
+	dc, err := ds.CreateDaxConn()
+	assert.True(t, err.IsOk())
+	assert.Equal(t, dc.(*cliargdax.DaxConn).Stats(), stats)
+}
+
+func TestStats_beforeSetup(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgs([]string{"app", "--foo"})
+	dc, err := ds.CreateDaxConn()
+	assert.True(t, err.IsOk())
+	assert.Equal(t, dc.(*cliargdax.DaxConn).Stats(), cliargdax.ParseStats{})
+}
+
+func TestStats_lazy(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgs([]string{"app", "--foo", "a"}).Lazy()
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.Stats(), cliargdax.ParseStats{})
+
+	assert.True(t, conn.Cmd().HasOpt("foo"))
+	stats := conn.Stats()
+	assert.True(t, stats.Parsed)
+	assert.Equal(t, stats.ArgCount, 2)
+	assert.Equal(t, stats.OptionCount, 1)
+	assert.Equal(t, stats.PositionalCount, 1)
+}
+
+func TestStats_ResultJSON(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgs([]string{"app", "--foo", "a"})
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	data, err := conn.ResultJSON()
+	assert.True(t, err.IsOk())
+	var m map[string]any
+	assert.Nil(t, json.Unmarshal(data, &m))
+	_, exists := m["stats"]
+	assert.False(t, exists)
+
+	ds = cliargdax.NewDaxSrcWithArgs([]string{"app", "--foo", "a"}).
+		StatsInResult(true)
+	conn, err = setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	data, err = conn.ResultJSON()
+	assert.True(t, err.IsOk())
+
+	var res struct {
+		Stats cliargdax.ParseStats `json:"stats"`
+	}
+	assert.Nil(t, json.Unmarshal(data, &res))
+	assert.Equal(t, res.Stats, conn.Stats())
+
+	r, err := cliargdax.UnmarshalResult(data)
+	assert.True(t, err.IsOk())
+	assert.True(t, r.HasOpt("foo"))
+}
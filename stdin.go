@@ -0,0 +1,60 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"io"
+	"os"
+	"reflect"
+)
+
+type /* error reasons */ (
+	// FailToOpenArgFile is the error reason which indicates that the file
+	// specified by a command argument bound to an io.Reader field cannot be
+	// opened.
+	// The fields Field and Path are the field name and the command argument.
+	FailToOpenArgFile struct {
+		Field string
+		Path  string
+	}
+)
+
+// stdinArg is the command argument which means the standard input.
+const stdinArg = "-"
+
+var readerType = reflect.TypeOf((*io.Reader)(nil)).Elem()
+
+// IsStdinArg is the function to check whether the command argument means the
+// standard input (or the standard output), which is a standalone "-".
+// A standalone "-" is never treated as an option but always as a command
+// argument, even before "--".
+func IsStdinArg(s string) bool {
+	return s == stdinArg
+}
+
+// makeReaderBinding makes the function to set an io.Reader to the field bound
+// to a command argument, which is os.Stdin if the argument is "-", or the file
+// of which path is the argument.
+// The opened file is not closed by this package, so it should be closed with
+// an assertion to io.Closer by an application.
+func makeReaderBinding(
+	field string, fld reflect.Value,
+) func(a []string) error {
+	return func(a []string) error {
+		if IsStdinArg(a[0]) {
+			fld.Set(reflect.ValueOf(os.Stdin))
+			return nil
+		}
+		f, e := os.Open(a[0])
+		if e != nil {
+			return reasonErr{
+				reason: FailToOpenArgFile{Field: field, Path: a[0]},
+				cause:  e,
+			}
+		}
+		fld.Set(reflect.ValueOf(f))
+		return nil
+	}
+}
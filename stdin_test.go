@@ -0,0 +1,94 @@
+package cliargdax_test
+
+import (
+	"io"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+)
+
+func TestStdin_IsStdinArg(t *testing.T) {
+	assert.True(t, cliargdax.IsStdinArg("-"))
+	assert.False(t, cliargdax.IsStdinArg("--"))
+	assert.False(t, cliargdax.IsStdinArg("-x"))
+	assert.False(t, cliargdax.IsStdinArg(""))
+}
+
+func TestStdin_dashIsArg(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "verbose", Aliases: []string{"v"}},
+	}
+
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "-", "-v", "--", "-", "-v"}, optCfgs,
+	)
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.Cmd().Args(), []string{"-", "-", "-v"})
+	assert.True(t, conn.Cmd().HasOpt("verbose"))
+
+	ds = cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "-v", "-"}, optCfgs,
+	).StopAtFirstArg(true)
+	conn, err = setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.Cmd().Args(), []string{"-"})
+
+	ds = cliargdax.NewDaxSrcWithArgs([]string{"app", "-", "--foo"})
+	conn, err = setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.Cmd().Args(), []string{"-"})
+	assert.True(t, conn.Cmd().HasOpt("foo"))
+}
+
+func TestStdin_combinedFormIsError(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgs([]string{"app", "-=x"})
+	_, err := setupWithOptCfgs(t, ds)
+	switch err.Reason().(type) {
+	case cliargs.OptionHasInvalidChar:
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestStdin_readerField(t *testing.T) {
+	type Options struct {
+		Input io.Reader `optpos:"0"`
+	}
+
+	opts := Options{}
+	_, err := setupForOptions(t, []string{"app", "-"}, &opts)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, opts.Input, os.Stdin)
+
+	path := writeTempFile(t, "input.txt", "hello")
+	opts = Options{}
+	_, err = setupForOptions(t, []string{"app", path}, &opts)
+	assert.True(t, err.IsOk())
+	b, e := io.ReadAll(opts.Input)
+	assert.Nil(t, e)
+	assert.Equal(t, string(b), "hello")
+	opts.Input.(io.Closer).Close()
+
+	opts = Options{}
+	_, err = setupForOptions(t, []string{"app", path + ".missing"}, &opts)
+	switch r := err.Reason().(type) {
+	case cliargdax.FailToOpenArgFile:
+		assert.Equal(t, r.Field, "Input")
+		assert.Equal(t, r.Path, path+".missing")
+	default:
+		assert.Fail(t, err.Error())
+	}
+
+	opts = Options{}
+	_, err = setupForOptions(t, []string{"app"}, &opts)
+	switch r := err.Reason().(type) {
+	case cliargdax.MissingPositionalArg:
+		assert.Equal(t, r.Name, "Input")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
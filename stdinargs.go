@@ -0,0 +1,224 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/sttk/sabi/errs"
+)
+
+type /* error reasons */ (
+	// FailToReadStdinArgs is the error reason which indicates that the
+	// arguments requested by the sentinel argument cannot be read from the
+	// standard input or the reader specified in StdinArgsCfg.
+	FailToReadStdinArgs struct{}
+
+	// TooManyStdinArgs is the error reason which indicates that the arguments
+	// read from the standard input are more than the limit.
+	// The field Limit is the maximum number of the arguments.
+	TooManyStdinArgs struct {
+		Limit int
+	}
+
+	// StdinArgsTooLarge is the error reason which indicates that the input
+	// from which the arguments are read is larger than the limit.
+	// The field Limit is the maximum number of bytes of the input.
+	StdinArgsTooLarge struct {
+		Limit int
+	}
+)
+
+const (
+	// defaultStdinArgsSentinel is the command line argument which requests to
+	// read arguments from the standard input by default.
+	defaultStdinArgsSentinel = "--args-from-stdin"
+
+	// defaultMaxStdinArgs is the maximum number of arguments read from the
+	// standard input by default.
+	defaultMaxStdinArgs = 100000
+
+	// defaultMaxStdinArgsBytes is the maximum number of bytes of the input
+	// from which arguments are read by default.
+	defaultMaxStdinArgsBytes = 16 << 20
+)
+
+// StdinArgsCfg is the struct type for the configuration of reading command
+// line arguments from the standard input.
+// The field Sentinel is the command line argument which requests to read the
+// arguments, and is "--args-from-stdin" if it is empty.
+// The field Reader is the source of the arguments, and is os.Stdin if it is
+// nil.
+// The fields MaxArgs and MaxBytes are the maximum number of the arguments and
+// the maximum number of bytes of the input, and are 100000 and 16 MiB if they
+// are not positive.
+type StdinArgsCfg struct {
+	Sentinel string
+	Reader   io.Reader
+	MaxArgs  int
+	MaxBytes int
+}
+
+// ReadArgsFromStdin is the method to enable the mode to read additional
+// command line arguments from the standard input when the sentinel argument,
+// like --args-from-stdin, is given, for example to pass more file paths than
+// the length limit of a command line.
+// The sentinel argument is replaced with the arguments read from the input,
+// one argument per line without quoting, and blank lines and lines starting
+// with # are skipped.
+// The input is read only for the first sentinel argument, and the following
+// ones are removed.
+// The sentinel argument after the option terminator "--" is not replaced but
+// is a command argument, while the option terminator in the input works as
+// usual.
+// If response files are expanded, the sentinel argument can also be written
+// in a response file, and the arguments read from the input are not expanded
+// as response files.
+// If the arguments or the input exceed the limits in StdinArgsCfg, the parsing
+// fails with the reason: TooManyStdinArgs or StdinArgsTooLarge, and if
+// failing to read the input, it fails with the reason: FailToReadStdinArgs.
+// If the context passed to SetupContext method is done while waiting for the
+// input, the parsing fails with the reason: SetupCanceled.
+// In that case, the goroutine reading the input is left blocked until the
+// reader returns, so a program which sets up a DaxSrc for each request should
+// set the Reader field of StdinArgsCfg to a reader which it closes after the
+// cancellation, because a goroutine leaks for each canceled setup reading
+// os.Stdin.
+// DaxConn#RawArgs method returns the arguments including the sentinel
+// argument.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) ReadArgsFromStdin(cfg StdinArgsCfg) *DaxSrc {
+	if len(cfg.Sentinel) == 0 {
+		cfg.Sentinel = defaultStdinArgsSentinel
+	}
+	if cfg.MaxArgs <= 0 {
+		cfg.MaxArgs = defaultMaxStdinArgs
+	}
+	if cfg.MaxBytes <= 0 {
+		cfg.MaxBytes = defaultMaxStdinArgsBytes
+	}
+	ds.stdinArgs = &cfg
+	return ds
+}
+
+// expandStdinArgs replaces the sentinel argument in the command line arguments
+// with the arguments read from the input, and returns them with the paths of
+// the response files which are shifted with the replacement.
+// The first element, which is the program path, is not replaced.
+func expandStdinArgs(
+	ctx context.Context, osArgs []string, files []string, cfg StdinArgsCfg,
+) ([]string, []string, errs.Err) {
+	if len(osArgs) == 0 {
+		return osArgs, files, errs.Ok()
+	}
+
+	args := make([]string, 1, len(osArgs))
+	args[0] = osArgs[0]
+	var argFiles []string
+	if files != nil {
+		argFiles = make([]string, 1, len(files))
+		argFiles[0] = files[0]
+	}
+
+	read, terminated := false, false
+	for i, arg := range osArgs[1:] {
+		if terminated || arg != cfg.Sentinel {
+			if arg == "--" {
+				terminated = true
+			}
+			args = append(args, arg)
+			if files != nil {
+				argFiles = append(argFiles, files[i+1])
+			}
+			continue
+		}
+		if read {
+			continue
+		}
+		read = true
+
+		lines, err := readStdinArgsContext(ctx, cfg)
+		if err.IsNotOk() {
+			return nil, nil, err
+		}
+		args = append(args, lines...)
+		if files != nil {
+			argFiles = append(argFiles, make([]string, len(lines))...)
+		}
+	}
+	return args, argFiles, errs.Ok()
+}
+
+// readStdinArgsContext reads the arguments from the input in the same way as
+// readStdinArgs function, but stops waiting for the input when the context is
+// done.
+// In that case, the reading goroutine remains until the reader returns, for
+// example when the pipe is closed.
+func readStdinArgsContext(
+	ctx context.Context, cfg StdinArgsCfg,
+) ([]string, errs.Err) {
+	if err := checkContext(ctx); err.IsNotOk() {
+		return nil, err
+	}
+	if ctx == nil || ctx.Done() == nil {
+		return readStdinArgs(cfg)
+	}
+
+	type result struct {
+		args []string
+		err  errs.Err
+	}
+	ch := make(chan result, 1)
+	go func() {
+		args, err := readStdinArgs(cfg)
+		ch <- result{args, err}
+	}()
+
+	select {
+	case res := <-ch:
+		return res.args, res.err
+	case <-ctx.Done():
+		return nil, checkContext(ctx)
+	}
+}
+
+// readStdinArgs reads the arguments from the input, one argument per line.
+func readStdinArgs(cfg StdinArgsCfg) ([]string, errs.Err) {
+	r := cfg.Reader
+	if r == nil {
+		r = os.Stdin
+	}
+	br := bufio.NewReader(io.LimitReader(r, int64(cfg.MaxBytes)+1))
+
+	var args []string
+	size := 0
+	for {
+		line, e := br.ReadString('\n')
+		size += len(line)
+		if size > cfg.MaxBytes {
+			return nil, errs.New(StdinArgsTooLarge{Limit: cfg.MaxBytes})
+		}
+		if e != nil && e != io.EOF {
+			return nil, errs.New(FailToReadStdinArgs{}, e)
+		}
+
+		line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+		s := strings.TrimSpace(line)
+		if len(s) > 0 && !strings.HasPrefix(s, "#") {
+			if len(args) >= cfg.MaxArgs {
+				return nil, errs.New(TooManyStdinArgs{Limit: cfg.MaxArgs})
+			}
+			args = append(args, line)
+		}
+
+		if e == io.EOF {
+			return args, errs.Ok()
+		}
+	}
+}
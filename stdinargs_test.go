@@ -0,0 +1,162 @@
+package cliargdax_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+)
+
+var stdinArgsOptCfgs = []cliargs.OptCfg{
+	cliargs.OptCfg{Name: "name", HasArg: true},
+	cliargs.OptCfg{Name: "verbose"},
+}
+
+func TestStdinArgs_ReadArgsFromStdin(t *testing.T) {
+	input := "# files\n" +
+		"a.txt\n" +
+		"\n" +
+		"   \n" +
+		"b c.txt\r\n" +
+		"  # indented comment\n" +
+		"--verbose\n" +
+		"d.txt"
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "--name=x", "--args-from-stdin", "e.txt",
+	}, stdinArgsOptCfgs).ReadArgsFromStdin(cliargdax.StdinArgsCfg{
+		Reader: strings.NewReader(input),
+	})
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.Cmd().Args(),
+		[]string{"a.txt", "b c.txt", "d.txt", "e.txt"})
+	assert.True(t, conn.Cmd().HasOpt("verbose"))
+	assert.Equal(t, conn.RawArgs(),
+		[]string{"--name=x", "--args-from-stdin", "e.txt"})
+}
+
+func TestStdinArgs_disabledOrNoSentinel(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "--args-from-stdin",
+	}, stdinArgsOptCfgs)
+	_, err := setupWithOptCfgs(t, ds)
+	assert.Equal(t, err.Reason(),
+		cliargs.UnconfiguredOption{Option: "args-from-stdin"})
+
+	ds = cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "a.txt",
+	}, stdinArgsOptCfgs).ReadArgsFromStdin(cliargdax.StdinArgsCfg{
+		Reader: strings.NewReader("b.txt\n"),
+	})
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.Cmd().Args(), []string{"a.txt"})
+}
+
+func TestStdinArgs_sentinel(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "-", "a.txt", "-", "b.txt",
+	}, stdinArgsOptCfgs).ReadArgsFromStdin(cliargdax.StdinArgsCfg{
+		Sentinel: "-", Reader: strings.NewReader("x\ny\n"),
+	})
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.Cmd().Args(), []string{"x", "y", "a.txt", "b.txt"})
+}
+
+func TestStdinArgs_terminator(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "--", "--args-from-stdin",
+	}, stdinArgsOptCfgs).ReadArgsFromStdin(cliargdax.StdinArgsCfg{
+		Reader: strings.NewReader("a.txt\n"),
+	})
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.Cmd().Args(), []string{"--args-from-stdin"})
+
+	ds = cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "--args-from-stdin", "--name=y",
+	}, stdinArgsOptCfgs).ReadArgsFromStdin(cliargdax.StdinArgsCfg{
+		Reader: strings.NewReader("a.txt\n--\n--verbose\n"),
+	})
+	conn, err = setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.Cmd().Args(), []string{"a.txt", "--verbose", "--name=y"})
+	assert.False(t, conn.Cmd().HasOpt("verbose"))
+}
+
+func TestStdinArgs_responseFile(t *testing.T) {
+	rsp := filepath.Join(t.TempDir(), "args.rsp")
+	assert.Nil(t, os.WriteFile(rsp, []byte("--name=x --args-from-stdin\n"), 0600))
+
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "@" + rsp, "b.txt",
+	}, stdinArgsOptCfgs).
+		ExpandResponseFiles(true).
+		ReadArgsFromStdin(cliargdax.StdinArgsCfg{
+			Reader: strings.NewReader("@a.txt\n"),
+		})
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.Cmd().OptArg("name"), "x")
+	assert.Equal(t, conn.Cmd().Args(), []string{"@a.txt", "b.txt"})
+}
+
+func TestStdinArgs_limits(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "--args-from-stdin",
+	}, stdinArgsOptCfgs).ReadArgsFromStdin(cliargdax.StdinArgsCfg{
+		Reader: strings.NewReader("a\n# comment\nb\n\nc\n"), MaxArgs: 3,
+	})
+	_, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	ds = cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "--args-from-stdin",
+	}, stdinArgsOptCfgs).ReadArgsFromStdin(cliargdax.StdinArgsCfg{
+		Reader: strings.NewReader("a\nb\nc\nd\n"), MaxArgs: 3,
+	})
+	_, err = setupWithOptCfgs(t, ds)
+	assert.Equal(t, err.Reason(), cliargdax.TooManyStdinArgs{Limit: 3})
+	assert.Equal(t, cliargdax.FormatError(err, nil),
+		"too many arguments from the standard input: more than 3\n"+
+			"Try '--help' for more information.")
+
+	ds = cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "--args-from-stdin",
+	}, stdinArgsOptCfgs).ReadArgsFromStdin(cliargdax.StdinArgsCfg{
+		Reader: strings.NewReader("abc\ndef\n"), MaxBytes: 8,
+	})
+	_, err = setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	ds = cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "--args-from-stdin",
+	}, stdinArgsOptCfgs).ReadArgsFromStdin(cliargdax.StdinArgsCfg{
+		Reader: strings.NewReader("abc\ndefg\n"), MaxBytes: 8,
+	})
+	_, err = setupWithOptCfgs(t, ds)
+	assert.Equal(t, err.Reason(), cliargdax.StdinArgsTooLarge{Limit: 8})
+}
+
+type failingReader struct{}
+
+func (failingReader) Read(p []byte) (int, error) {
+	return 0, errors.New("broken pipe")
+}
+
+func TestStdinArgs_readError(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "--args-from-stdin",
+	}, stdinArgsOptCfgs).ReadArgsFromStdin(cliargdax.StdinArgsCfg{
+		Reader: failingReader{},
+	})
+	_, err := setupWithOptCfgs(t, ds)
+	assert.Equal(t, err.Reason(), cliargdax.FailToReadStdinArgs{})
+	assert.Equal(t, err.Cause().Error(), "broken pipe")
+}
@@ -0,0 +1,222 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/sttk/cliargs"
+	"github.com/sttk/sabi/errs"
+)
+
+// reasonErr is an error which carries an error reason of this package from an
+// event handler of an option to the results of parsing.
+type reasonErr struct {
+	option string
+	reason any
+	cause  error
+}
+
+func (e reasonErr) Error() string {
+	return fmt.Sprintf("%T%+v", e.reason, e.reason)
+}
+
+func (e reasonErr) Unwrap() error {
+	return e.cause
+}
+
+func (e reasonErr) toErr() errs.Err {
+	if e.cause != nil {
+		return errs.New(e.reason, e.cause)
+	}
+	return errs.New(e.reason)
+}
+
+// fieldDecoder is the struct to convert option arguments to a value of a
+// field of an option store of which type is not supported by cliargs package.
+// The field isArray indicates whether the option can take multiple option
+// arguments, and the function set converts option arguments and sets the
+// result to the field.
+// If the function init is not nil, it initializes the field before parsing.
+type fieldDecoder struct {
+	isArray bool
+	set     func(fld reflect.Value, name string, a []string) error
+	init    func(fld reflect.Value)
+}
+
+// valueParser is the function type to convert an option argument to a value
+// of a type which is not supported by cliargs package.
+// The arguments name and s are the option name and the option argument.
+type valueParser func(name, s string) (reflect.Value, error)
+
+// parserFor returns the valueParser for the type, and the string which
+// describes the expected format of option arguments.
+// If no valueParser is available for the type, this function returns false
+// as the third result.
+func parserFor(
+	t reflect.Type, sf reflect.StructField, decs decoderMap,
+) (valueParser, string, bool) {
+	if p, ok := decs.parserFor(t); ok {
+		return p, "", true
+	}
+	if p, format, ok := textParserFor(t); ok {
+		return p, format, true
+	}
+	if p, format, ok := timeParserFor(t, sf); ok {
+		return p, format, true
+	}
+	if p, format, ok := netParserFor(t, sf); ok {
+		return p, format, true
+	}
+	return nil, "", false
+}
+
+// decoderFor returns the fieldDecoder for the type of the struct field, and
+// the string which describes the expected format of option arguments.
+// A type T of which valueParser is available is supported also as the
+// pointer type *T and the slice type []T.
+// If the type is supported by cliargs package, this function returns false as
+// the third result.
+func decoderFor(
+	sf reflect.StructField, decs decoderMap,
+) (fieldDecoder, string, bool) {
+	if d, ok := mapDecoderFor(sf); ok {
+		return d, "", true
+	}
+
+	t := sf.Type
+
+	if p, format, ok := parserFor(t, sf, decs); ok {
+		return fieldDecoder{
+			set: func(fld reflect.Value, name string, a []string) error {
+				v, e := p(name, a[len(a)-1])
+				if e != nil {
+					return e
+				}
+				fld.Set(v)
+				return nil
+			},
+		}, format, true
+	}
+
+	if t.Kind() == reflect.Ptr {
+		if p, format, ok := parserFor(t.Elem(), sf, decs); ok {
+			return fieldDecoder{
+				set: func(fld reflect.Value, name string, a []string) error {
+					v, e := p(name, a[len(a)-1])
+					if e != nil {
+						return e
+					}
+					ptr := reflect.New(t.Elem())
+					ptr.Elem().Set(v)
+					fld.Set(ptr)
+					return nil
+				},
+			}, format, true
+		}
+	}
+
+	if t.Kind() == reflect.Slice {
+		if p, format, ok := parserFor(t.Elem(), sf, decs); ok {
+			return fieldDecoder{
+				isArray: true,
+				set: func(fld reflect.Value, name string, a []string) error {
+					arr := reflect.MakeSlice(t, len(a), len(a))
+					for i, s := range a {
+						v, e := p(name, s)
+						if e != nil {
+							return e
+						}
+						arr.Index(i).Set(v)
+					}
+					fld.Set(arr)
+					return nil
+				},
+			}, format, true
+		}
+	}
+
+	return fieldDecoder{}, "", false
+}
+
+// makeOptCfgFor makes an OptCfg for the field of an option store.
+// The name, aliases, default values, description, and argument help are
+// determined by cliargs.MakeOptCfgsFor function with a struct which has only
+// a field of the same struct tags, so that the struct tags are interpreted in
+// the same way as cliargs package.
+// If the field type is supported by cliargs package, the event handler made by
+// cliargs package is used via the field of the struct, otherwise the event
+// handler made from the fieldDecoder is used.
+func makeOptCfgFor(
+	sf reflect.StructField, fld reflect.Value, decs decoderMap,
+) (cliargs.OptCfg, *fieldDecoder, errs.Err) {
+	tmpSf := reflect.StructField{Name: sf.Name, Type: sf.Type, Tag: sf.Tag}
+	if !sf.IsExported() {
+		tmpSf.Name = "X" + sf.Name
+	}
+
+	dec, format, isCustom := decoderFor(sf, decs)
+	if isCustom {
+		if dec.isArray {
+			tmpSf.Type = reflect.TypeOf([]string{})
+		} else {
+			tmpSf.Type = reflect.TypeOf("")
+		}
+	}
+
+	tmp := reflect.New(reflect.StructOf([]reflect.StructField{tmpSf}))
+	cfgs, e := cliargs.MakeOptCfgsFor(tmp.Interface())
+	if e != nil {
+		if r, ok := e.(cliargs.IllegalOptionType); ok {
+			r.Field = sf.Name
+			if r.Option == tmpSf.Name {
+				r.Option = sf.Name
+			}
+			e = r
+		}
+		return cliargs.OptCfg{}, nil, errs.New(e)
+	}
+
+	cfg := cfgs[0]
+	if cfg.Name == tmpSf.Name {
+		cfg.Name = sf.Name
+	}
+
+	if isCustom {
+		if len(cfg.ArgHelp) == 0 && len(format) > 0 {
+			cfg.ArgHelp = "<" + format + ">"
+		}
+		if dec.init != nil && fld.CanSet() {
+			dec.init(fld)
+		}
+		name := cfg.Name
+		onParsed := func(a []string) error {
+			if a == nil || !fld.CanSet() {
+				return nil
+			}
+			return dec.set(fld, name, a)
+		}
+		cfg.OnParsed = &onParsed
+		return cfg, &dec, errs.Ok()
+	}
+
+	if cfg.OnParsed != nil {
+		setter := *cfg.OnParsed
+		tmpFld := tmp.Elem().Field(0)
+		onParsed := func(a []string) error {
+			if !fld.CanSet() {
+				return setter(a)
+			}
+			tmpFld.Set(fld)
+			e := setter(a)
+			fld.Set(tmpFld)
+			return e
+		}
+		cfg.OnParsed = &onParsed
+	}
+
+	return cfg, nil, errs.Ok()
+}
@@ -0,0 +1,89 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"reflect"
+
+	"github.com/sttk/sabi/errs"
+)
+
+type /* error reasons */ (
+	// OptionStoreIsNotPointer is the error reason which indicates that an
+	// option store passed to a constructor of DaxSrc is not a pointer, like a
+	// struct passed by value.
+	// The field Type is the type name of the option store.
+	OptionStoreIsNotPointer struct {
+		Type string
+	}
+
+	// OptionStoreIsNil is the error reason which indicates that an option store
+	// passed to a constructor of DaxSrc is nil or a nil pointer.
+	// The field Type is the type name of the option store, which is empty if
+	// it is an untyped nil.
+	OptionStoreIsNil struct {
+		Type string
+	}
+
+	// OptionStoreIsNotStruct is the error reason which indicates that an option
+	// store passed to a constructor of DaxSrc is a pointer but does not point
+	// to a struct.
+	// The field Type is the type name of the option store.
+	OptionStoreIsNotStruct struct {
+		Type string
+	}
+
+	// OptionStoreHasNoExportedField is the error reason which indicates that
+	// the struct of an option store passed to a constructor of DaxSrc has
+	// fields but none of them are exported, so no option can be stored.
+	// The field Type is the type name of the option store.
+	OptionStoreHasNoExportedField struct {
+		Type string
+	}
+)
+
+// checkOptionStore checks the option store passed to a constructor of
+// DaxSrc, and returns the option store to be parsed into.
+// A pointer to a pointer to a struct is dereferenced by one level, and if the
+// pointer to the struct is nil, a new struct instance is allocated to it.
+func checkOptionStore(options any) (any, errs.Err) {
+	if options == nil {
+		return nil, errs.New(OptionStoreIsNil{})
+	}
+
+	v := reflect.ValueOf(options)
+	typ := v.Type().String()
+	if v.Kind() != reflect.Ptr {
+		return options, errs.New(OptionStoreIsNotPointer{Type: typ})
+	}
+	if v.IsNil() {
+		return options, errs.New(OptionStoreIsNil{Type: typ})
+	}
+
+	if v.Elem().Kind() == reflect.Ptr {
+		v = v.Elem()
+		if v.IsNil() {
+			if v.Type().Elem().Kind() != reflect.Struct {
+				return options, errs.New(OptionStoreIsNotStruct{Type: typ})
+			}
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		options = v.Interface()
+	}
+
+	t := v.Type().Elem()
+	if t.Kind() != reflect.Struct {
+		return options, errs.New(OptionStoreIsNotStruct{Type: typ})
+	}
+	if t.NumField() == 0 {
+		return options, errs.Ok()
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if sf := t.Field(i); sf.IsExported() || sf.Anonymous {
+			return options, errs.Ok()
+		}
+	}
+	return options, errs.New(OptionStoreHasNoExportedField{Type: typ})
+}
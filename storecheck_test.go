@@ -0,0 +1,109 @@
+package cliargdax_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+)
+
+type storeCheckOptions struct {
+	Foo string `optcfg:"foo"`
+}
+
+type storeCheckUnexported struct {
+	foo string
+}
+
+func TestNewDaxSrcForOptions_notPointer(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsForOptions(
+		[]string{"app", "--foo=x"}, storeCheckOptions{},
+	)
+	_, err := setupWithOptCfgs(t, ds)
+	assert.Equal(t, err.Reason(), cliargdax.OptionStoreIsNotPointer{
+		Type: "cliargdax_test.storeCheckOptions",
+	})
+	assert.Equal(t, cliargdax.FormatError(err, nil),
+		"the option store is not a pointer: cliargdax_test.storeCheckOptions\n"+
+			"Try '--help' for more information.")
+}
+
+func TestNewDaxSrcForOptions_nil(t *testing.T) {
+	ds := cliargdax.NewDaxSrcForOptions(nil)
+	_, err := setupWithOptCfgs(t, ds)
+	assert.Equal(t, err.Reason(), cliargdax.OptionStoreIsNil{})
+
+	var options *storeCheckOptions
+	ds = cliargdax.NewDaxSrcWithArgsForOptions([]string{"app"}, options)
+	_, err = setupWithOptCfgs(t, ds)
+	assert.Equal(t, err.Reason(), cliargdax.OptionStoreIsNil{
+		Type: "*cliargdax_test.storeCheckOptions",
+	})
+}
+
+func TestNewDaxSrcForOptions_notStruct(t *testing.T) {
+	n := 0
+	ds := cliargdax.NewDaxSrcForOptionsWithExtra(&n, nil)
+	_, err := setupWithOptCfgs(t, ds)
+	assert.Equal(t, err.Reason(), cliargdax.OptionStoreIsNotStruct{
+		Type: "*int",
+	})
+
+	var p *int
+	ds = cliargdax.NewDaxSrcForOptions(&p)
+	_, err = setupWithOptCfgs(t, ds)
+	assert.Equal(t, err.Reason(), cliargdax.OptionStoreIsNotStruct{
+		Type: "**int",
+	})
+}
+
+func TestNewDaxSrcForOptions_noExportedField(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsForOptions(
+		[]string{"app"}, &storeCheckUnexported{},
+	)
+	_, err := setupWithOptCfgs(t, ds)
+	assert.Equal(t, err.Reason(), cliargdax.OptionStoreHasNoExportedField{
+		Type: "*cliargdax_test.storeCheckUnexported",
+	})
+}
+
+func TestNewDaxSrcForOptions_pointerToPointer(t *testing.T) {
+	options := &storeCheckOptions{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions(
+		[]string{"app", "--foo=x"}, &options,
+	)
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, options.Foo, "x")
+	assert.Equal(t, conn.Options(), options)
+
+	var nilOptions *storeCheckOptions
+	ds = cliargdax.NewDaxSrcWithArgsForOptions(
+		[]string{"app", "--foo=y"}, &nilOptions,
+	)
+	_, err = setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, nilOptions.Foo, "y")
+}
+
+func TestNewDaxSrcWithSubCmdsForOptions_invalidStore(t *testing.T) {
+	var options *storeCheckOptions
+	ds := cliargdax.NewDaxSrcWithSubCmdsForOptions(map[string]any{
+		"add":    &storeCheckOptions{},
+		"commit": storeCheckOptions{},
+		"push":   options,
+	})
+	_, err := setupWithOptCfgs(t, ds)
+	assert.Equal(t, err.Reason(), cliargdax.OptionStoreIsNotPointer{
+		Type: "cliargdax_test.storeCheckOptions",
+	})
+
+	ds = cliargdax.NewDaxSrcWithSubCmdsForOptions(map[string]any{
+		"add":  &storeCheckOptions{},
+		"push": options,
+	})
+	_, err = setupWithOptCfgs(t, ds)
+	assert.Equal(t, err.Reason(), cliargdax.OptionStoreIsNil{
+		Type: "*cliargdax_test.storeCheckOptions",
+	})
+}
@@ -0,0 +1,155 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"strconv"
+
+	"github.com/sttk/cliargs"
+	"github.com/sttk/sabi/errs"
+)
+
+type /* error reasons */ (
+	// OptionStoresCollide is the error reason which indicates that an option
+	// name or alias is configured by two option stores.
+	// The fields Option, Store, and OtherStore are the conflicting name or
+	// alias, and the keys of the two option stores.
+	// The key of the option store passed to the constructor of DaxSrc is an
+	// empty string.
+	OptionStoresCollide struct {
+		Option     string
+		Store      string
+		OtherStore string
+	}
+)
+
+// optStore is the struct type which holds an option store and its key.
+type optStore struct {
+	key     string
+	options any
+}
+
+// AddOptions is the method to add an option store, which is a pointer of a
+// struct instance of any type, with the specified key.
+// The options of all the option stores added by this method, and of the option
+// store passed to the constructor, are parsed at once, and each option store
+// can be retrieved by DaxConn#OptionsByKey method.
+// If an option name or alias is configured by multiple option stores, the
+// Setup method of this DaxSrc returns an errs.Err with the reason:
+// OptionStoresCollide.
+// If this method is called with a key which is already added, the option store
+// for the key is replaced.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) AddOptions(key string, opts any) *DaxSrc {
+	for i, s := range ds.addedStores {
+		if s.key == key {
+			ds.addedStores[i].options = opts
+			return ds
+		}
+	}
+	ds.addedStores = append(ds.addedStores, optStore{key: key, options: opts})
+	return ds
+}
+
+// OptionsByKey is the method to retrieve the option store added by
+// DaxSrc#AddOptions method with the specified key.
+// If no option store is added with the key, this method returns nil.
+func (conn *DaxConn) OptionsByKey(key string) any {
+	conn.ds.parseLazily()
+	conn.ds.mutex.RLock()
+	defer conn.ds.mutex.RUnlock()
+	for _, s := range conn.ds.addedStores {
+		if s.key == key {
+			return s.options
+		}
+	}
+	return nil
+}
+
+// storesOf returns an array of the option stores which consists of the option
+// store passed to the constructor, if it is not nil, and the added ones.
+func storesOf(options any, added []optStore) []optStore {
+	var stores []optStore
+	if options != nil {
+		stores = append(stores, optStore{options: options})
+	}
+	return append(stores, added...)
+}
+
+// optionStores returns the option stores of this DaxSrc.
+// The map of a dynamic option store is not an option store to be parsed into.
+func (ds *DaxSrc) optionStores() []optStore {
+	if ds.isDynamic {
+		return storesOf(nil, ds.addedStores)
+	}
+	return storesOf(ds.options, ds.addedStores)
+}
+
+// buildOptCfgsForStores makes the option configurations, the metadata of
+// options, and the bindings of command arguments from all the option stores,
+// and checks that no option name or alias is configured by multiple stores.
+func buildOptCfgsForStores(
+	stores []optStore, decs decoderMap, mapping NameMapping,
+) ([]cliargs.OptCfg, map[string]optMeta, []argBinding, errs.Err) {
+	if len(stores) == 1 {
+		return buildOptCfgs(stores[0].options, decs, mapping)
+	}
+
+	var cfgs []cliargs.OptCfg
+	var binds []argBinding
+	metas := make(map[string]optMeta)
+	owners := make(map[string]string)
+	positions := make(map[int]bool)
+
+	for _, s := range stores {
+		sCfgs, sMetas, sBinds, err := makeOptCfgsFor(s.options, decs, mapping)
+		if err.IsNotOk() {
+			return nil, nil, nil, err
+		}
+
+		names := make(map[string]bool)
+		for _, cfg := range sCfgs {
+			names[cfg.Name] = true
+			for _, a := range cfg.Aliases {
+				names[a] = true
+			}
+		}
+		for _, cfg := range sCfgs {
+			for _, name := range append([]string{cfg.Name}, cfg.Aliases...) {
+				if other, exists := owners[name]; exists {
+					return nil, nil, nil, errs.New(OptionStoresCollide{
+						Option: name, Store: other, OtherStore: s.key,
+					})
+				}
+			}
+		}
+		for name := range names {
+			owners[name] = s.key
+		}
+
+		for _, b := range sBinds {
+			if positions[b.index] {
+				pos := "rest"
+				if b.index >= 0 {
+					pos = strconv.Itoa(b.index)
+				}
+				return nil, nil, nil,
+					errs.New(IllegalArgPosition{Field: b.field, Position: pos})
+			}
+			positions[b.index] = true
+		}
+
+		cfgs = append(cfgs, sCfgs...)
+		binds = append(binds, sBinds...)
+		for name, m := range sMetas {
+			metas[name] = m
+		}
+	}
+
+	if err := ValidateOptCfgs(cfgs); err.IsNotOk() {
+		return nil, nil, nil, err
+	}
+	return setChoicesArgHelp(cfgs, metas), metas, binds, errs.Ok()
+}
@@ -0,0 +1,99 @@
+package cliargdax_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+)
+
+func TestStores_AddOptions(t *testing.T) {
+	type LoggingOpts struct {
+		Level string `optcfg:"log-level=info"`
+	}
+	type ServerOpts struct {
+		Port int  `optcfg:"port,p=80"`
+		TLS  bool `optcfg:"tls"`
+	}
+
+	logging := LoggingOpts{}
+	server := ServerOpts{}
+	ds := cliargdax.NewDaxSrcWithArgs([]string{
+		"app", "--log-level=debug", "-p", "8080", "file",
+	}).AddOptions("logging", &logging).AddOptions("server", &server)
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	assert.Equal(t, logging.Level, "debug")
+	assert.Equal(t, server.Port, 8080)
+	assert.False(t, server.TLS)
+
+	assert.Equal(t, conn.OptionsByKey("logging"), &logging)
+	assert.Equal(t, conn.OptionsByKey("server"), &server)
+	assert.Nil(t, conn.OptionsByKey("metrics"))
+	assert.Equal(t, conn.Options(), &logging)
+
+	assert.Equal(t, len(conn.OptCfgs()), 3)
+	assert.Equal(t, conn.Cmd().Args(), []string{"file"})
+}
+
+func TestStores_AddOptions_withConstructorStore(t *testing.T) {
+	type Options struct {
+		Verbose bool `optcfg:"verbose,v"`
+	}
+	type MetricsOpts struct {
+		Addr string `optcfg:"metrics-addr"`
+	}
+
+	opts := Options{}
+	metrics := MetricsOpts{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions([]string{
+		"app", "-v", "--metrics-addr=:9090",
+	}, &opts).AddOptions("metrics", &metrics)
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	assert.True(t, opts.Verbose)
+	assert.Equal(t, metrics.Addr, ":9090")
+	assert.Equal(t, conn.Options(), &opts)
+	assert.Equal(t, conn.OptionsByKey("metrics"), &metrics)
+}
+
+func TestStores_AddOptions_collision(t *testing.T) {
+	type LoggingOpts struct {
+		Verbose bool `optcfg:"verbose"`
+	}
+	type ServerOpts struct {
+		Version bool `optcfg:"version,verbose"`
+	}
+
+	ds := cliargdax.NewDaxSrcWithArgs([]string{"app"}).
+		AddOptions("logging", &LoggingOpts{}).
+		AddOptions("server", &ServerOpts{})
+	_, err := setupWithOptCfgs(t, ds)
+	switch r := err.Reason().(type) {
+	case cliargdax.OptionStoresCollide:
+		assert.Equal(t, r.Option, "verbose")
+		assert.Equal(t, r.Store, "logging")
+		assert.Equal(t, r.OtherStore, "server")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestStores_AddOptions_sameKey(t *testing.T) {
+	type Options struct {
+		Name string `optcfg:"name"`
+	}
+
+	first := Options{}
+	second := Options{}
+	ds := cliargdax.NewDaxSrcWithArgs([]string{"app", "--name=foo"}).
+		AddOptions("a", &first).
+		AddOptions("a", &second)
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, first.Name, "")
+	assert.Equal(t, second.Name, "foo")
+	assert.Equal(t, conn.OptionsByKey("a"), &second)
+}
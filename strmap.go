@@ -0,0 +1,85 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"strings"
+)
+
+// defaultStringMapSep is the separator of the option arguments of an array
+// option in the map returned by DaxConn#OptStringMap method by default.
+const defaultStringMapSep = ","
+
+// OptStringMapSeparator is the method to set the separator with which the
+// option arguments of an array option are joined in the map returned by
+// DaxConn#OptStringMap method.
+// The separator is "," by default, and the separator of the optsep struct tag
+// is used instead for the option which has it.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) OptStringMapSeparator(sep string) *DaxSrc {
+	ds.strMapSep = &sep
+	return ds
+}
+
+// OptStringMap is the method to retrieve the options of the parsing result as
+// a flat map of strings, for example to pass them to a template engine.
+// The keys of the map are the option names, and the values are "true" for
+// options which take no option argument, the option arguments for options
+// which take one, and the option arguments joined with the separator set by
+// DaxSrc#OptStringMapSeparator method for array options.
+// The options which are neither given nor have default values are omitted.
+// The values of secret options are replaced with "****", so use
+// OptStringMapIncludingSecrets method to get them.
+// The map is made for each call from the current result of parsing, so the
+// overrides by WithOptOverride method and the results of reparsing are
+// reflected.
+func (conn *DaxConn) OptStringMap() map[string]string {
+	return conn.optStringMap(false)
+}
+
+// OptStringMapIncludingSecrets is the method to retrieve the options of the
+// parsing result as a flat map of strings, including the values of secret
+// options as they are.
+// See OptStringMap method for details.
+func (conn *DaxConn) OptStringMapIncludingSecrets() map[string]string {
+	return conn.optStringMap(true)
+}
+
+func (conn *DaxConn) optStringMap(includeSecrets bool) map[string]string {
+	conn.ds.parseLazily()
+	conn.ds.mutex.RLock()
+	defer conn.ds.mutex.RUnlock()
+
+	ds := conn.ds
+	var secrets map[string]bool
+	if !includeSecrets {
+		secrets = ds.secretOpts()
+	}
+
+	sep := defaultStringMapSep
+	if ds.strMapSep != nil {
+		sep = *ds.strMapSep
+	}
+
+	cmd := conn.overriddenCmd()
+	opts := cmdOpts(cmd, ds.optCfgs, ds.counts)
+	for _, name := range conn.overriddenOptNames() {
+		opts[name] = cmd.OptArgs(name)
+	}
+	m := make(map[string]string, len(opts))
+	for name, a := range opts {
+		switch {
+		case secrets[name]:
+			m[name] = redacted
+		case len(a) == 0:
+			m[name] = "true"
+		case len(ds.metas[name].sep) > 0:
+			m[name] = strings.Join(a, ds.metas[name].sep)
+		default:
+			m[name] = strings.Join(a, sep)
+		}
+	}
+	return m
+}
@@ -0,0 +1,60 @@
+package cliargdax_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+)
+
+type strMapOptions struct {
+	Verbose  bool     `optcfg:"verbose,v"`
+	Quiet    bool     `optcfg:"quiet" optnegatable:"true"`
+	Level    int      `optcfg:"level=1"`
+	Tags     []string `optcfg:"tag"`
+	Paths    []string `optcfg:"path" optsep:":"`
+	Name     string   `optcfg:"name"`
+	Password string   `optcfg:"password" optsecret:"true"`
+}
+
+func TestDaxConn_OptStringMap(t *testing.T) {
+	options := strMapOptions{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions([]string{
+		"app", "-v", "--tag=a", "--tag=b", "--path=/x:/y", "--password=pw",
+		"arg",
+	}, &options)
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	assert.Equal(t, conn.OptStringMap(), map[string]string{
+		"verbose":  "true",
+		"level":    "1",
+		"tag":      "a,b",
+		"path":     "/x:/y",
+		"password": "****",
+	})
+	assert.Equal(t, conn.OptStringMapIncludingSecrets()["password"], "pw")
+
+	conn.WithOptOverride("name", "foo")
+	assert.Equal(t, conn.OptStringMap()["name"], "foo")
+}
+
+func TestDaxConn_OptStringMap_separator(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "tag", HasArg: true, IsArray: true},
+		cliargs.OptCfg{Name: "dry-run"},
+	}
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "--tag=a", "--tag=b", "--dry-run",
+	}, optCfgs).OptStringMapSeparator(" ")
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.OptStringMap(), map[string]string{
+		"tag": "a b", "dry-run": "true",
+	})
+
+	_, err = conn.Reparse(optCfgs, 2)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.OptStringMap(), map[string]string{"dry-run": "true"})
+}
@@ -0,0 +1,215 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"sort"
+
+	"github.com/sttk/cliargs"
+	"github.com/sttk/sabi/errs"
+)
+
+type /* error reasons */ (
+	// UnknownSubCommand is the error reason which indicates that the first
+	// non option-format argument in command line arguments is not a registered
+	// sub command name.
+	// The field Name is the unknown sub command name.
+	UnknownSubCommand struct {
+		Name string
+	}
+)
+
+type subCmdCfg struct {
+	optCfgs []cliargs.OptCfg
+	options any
+}
+
+// SubCmdName is the method to retrieve the name of the sub command which is
+// specified in command line arguments or is the default sub command.
+// If no sub command is specified and no default sub command is set, this
+// method returns an empty string.
+func (conn *DaxConn) SubCmdName() string {
+	conn.ds.parseLazily()
+	conn.ds.mutex.RLock()
+	defer conn.ds.mutex.RUnlock()
+	return conn.ds.subCmdName
+}
+
+// SubCmd is the method to retrieve a cliargs.Cmd struct instance that stores
+// the results of parsing the command line arguments of the sub command.
+// The Name field of the retrieved cliargs.Cmd is the sub command name.
+func (conn *DaxConn) SubCmd() cliargs.Cmd {
+	conn.ds.parseLazily()
+	conn.ds.mutex.RLock()
+	defer conn.ds.mutex.RUnlock()
+	return conn.ds.subCmd
+}
+
+// SubOptCfgs is the method to retrieve an array of cliargs.OptCfg struct
+// instances which are used to parse the command line arguments of the sub
+// command.
+// Like DaxConn#OptCfgs method, the returned array is a copy.
+func (conn *DaxConn) SubOptCfgs() []cliargs.OptCfg {
+	conn.ds.parseLazily()
+	conn.ds.mutex.RLock()
+	defer conn.ds.mutex.RUnlock()
+	return copyOptCfgs(conn.ds.subOptCfgs)
+}
+
+// SubOptions is the method to retrieve the option store of the sub command,
+// which is passed as a map value to NewDaxSrcWithSubCmdsForOptions function.
+// If the sub command has no option store, this method returns nil.
+func (conn *DaxConn) SubOptions() any {
+	conn.ds.parseLazily()
+	conn.ds.mutex.RLock()
+	defer conn.ds.mutex.RUnlock()
+	return conn.ds.subOptions
+}
+
+// DefaultSubCmd is the method to set the sub command name which is used when
+// no sub command is specified in command line arguments.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) DefaultSubCmd(name string) *DaxSrc {
+	ds.defaultSubCmd = name
+	return ds
+}
+
+func (ds *DaxSrc) parseSubCmd() errs.Err {
+	var topArgs, subArgs []string
+	var name string
+
+	i, arg, exists := ds.findSubCmdArg()
+	if exists {
+		topArgs = ds.osArgs[0:i]
+		subArgs = ds.osArgs[i:]
+		name = arg
+	} else {
+		topArgs = ds.osArgs
+		if len(ds.defaultSubCmd) > 0 {
+			subArgs = []string{ds.defaultSubCmd}
+			name = ds.defaultSubCmd
+		}
+	}
+
+	r, err := ds.parseArgs(
+		topArgs, ds.optCfgs, ds.optionStores(), ds.extraOptCfgs,
+	)
+	if err.IsNotOk() {
+		if _, ok := err.Reason().(MultipleParseErrors); ok {
+			ds.setResult(r)
+		}
+		return err
+	}
+	ds.setResult(r)
+
+	if len(name) == 0 || r.helpRequested || r.versionRequested {
+		ds.handleHelp()
+		ds.handleDeprecations()
+		return errs.Ok()
+	}
+
+	sub, ok := ds.subCmds[name]
+	if !ok {
+		return errs.New(UnknownSubCommand{Name: name})
+	}
+
+	sr, err := ds.parseArgs(
+		subArgs, sub.optCfgs, storesOf(sub.options, nil), nil,
+	)
+	if err.IsNotOk() {
+		return err
+	}
+	ds.subCmdName = name
+	ds.subCmd = sr.cmd
+	ds.unknownOpts = append(ds.unknownOpts, sr.unknownOpts...)
+	ds.wildcardOpts = append(ds.wildcardOpts, sr.wildcardOpts...)
+	ds.warnings = append(ds.warnings, sr.warnings...)
+	ds.deprecations = append(ds.deprecations, sr.deprecations...)
+	ds.subOptCfgs = sr.optCfgs
+	ds.subOptions = sub.options
+	ds.helpRequested = sr.helpRequested
+	ds.versionRequested = sr.versionRequested
+	ds.handleHelp()
+	ds.handleDeprecations()
+
+	return errs.Ok()
+}
+
+// findSubCmdArg finds the first command argument in command line arguments,
+// which is the sub command name, and returns its index.
+// Unlike cliargs.FindFirstArg function, the arguments are scanned with the
+// option configurations of the top level command, so that an option argument
+// given without "=", like file of --out file, is not taken as the sub command
+// name.
+func (ds *DaxSrc) findSubCmdArg() (int, string, bool) {
+	cfgs := ds.optCfgs
+	if stores := ds.optionStores(); len(stores) > 0 {
+		cfgs, _, _, _ = ds.buildOptCfgsWithExtra(stores, ds.extraOptCfgs)
+	}
+
+	sc := argScanner{
+		allowSlash:      ds.mode.allowSlash,
+		allowSingleDash: ds.mode.allowSingleDash,
+	}
+	for _, tok := range scanArgsBy(sc, ds.osArgs, cfgs) {
+		if !tok.isOpt() && !tok.isTerm {
+			return tok.index, tok.value, true
+		}
+	}
+	return -1, "", false
+}
+
+// NewDaxSrcWithSubCmds is the constructor function for cliargdax.DaxSrc
+// struct that takes a map of which keys are sub command names and of which
+// values are arrays of cliargs.OptCfg for the sub commands.
+// The Setup method of the created DaxSrc treats the first non option-format
+// argument as a sub command name, and parses the arguments before it without
+// configurations and the arguments after it with the OptCfg array of the sub
+// command.
+func NewDaxSrcWithSubCmds(subCfgs map[string][]cliargs.OptCfg) *DaxSrc {
+	subCmds := make(map[string]subCmdCfg, len(subCfgs))
+	names := make([]string, 0, len(subCfgs))
+	for name, cfgs := range subCfgs {
+		subCmds[name] = subCmdCfg{optCfgs: cfgs}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	ds := &DaxSrc{subCmds: subCmds}
+	for _, name := range names {
+		if ds.cfgErr = ValidateOptCfgs(subCfgs[name]); ds.cfgErr.IsNotOk() {
+			break
+		}
+	}
+	return ds
+}
+
+// NewDaxSrcWithSubCmdsForOptions is the constructor function for
+// cliargdax.DaxSrc struct that takes a map of which keys are sub command
+// names and of which values are option stores for the sub commands.
+// The Setup method of the created DaxSrc treats the first non option-format
+// argument as a sub command name, and parses the arguments before it without
+// configurations and the arguments after it for the option store of the sub
+// command.
+// Like NewDaxSrcForOptions, the option stores are checked in the order of the
+// sub command names, and the Setup method of the created DaxSrc returns the
+// error of the first invalid one.
+func NewDaxSrcWithSubCmdsForOptions(subOpts map[string]any) *DaxSrc {
+	names := make([]string, 0, len(subOpts))
+	for name := range subOpts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	ds := &DaxSrc{subCmds: make(map[string]subCmdCfg, len(subOpts))}
+	for _, name := range names {
+		opts, err := checkOptionStore(subOpts[name])
+		if err.IsNotOk() && ds.cfgErr.IsOk() {
+			ds.cfgErr = err
+		}
+		ds.subCmds[name] = subCmdCfg{options: opts}
+	}
+	return ds
+}
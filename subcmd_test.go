@@ -0,0 +1,212 @@
+package cliargdax_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+)
+
+func TestSubCmd_NewDaxSrcWithSubCmds_ok(t *testing.T) {
+	defer resetOsArgs()
+
+	os.Args = []string{"/path/to/app", "--verbose", "add", "--all", "file"}
+
+	ds := cliargdax.NewDaxSrcWithSubCmds(map[string][]cliargs.OptCfg{
+		"add":    []cliargs.OptCfg{cliargs.OptCfg{Name: "all"}},
+		"commit": []cliargs.OptCfg{cliargs.OptCfg{Name: "message", HasArg: true}},
+	})
+
+	ag := &noopAsyncGroup{}
+	err := ds.Setup(ag)
+	defer ds.Close()
+	assert.True(t, err.IsOk())
+
+	dc, err := ds.CreateDaxConn()
+	assert.True(t, err.IsOk())
+	conn := dc.(*cliargdax.DaxConn)
+
+	cmd := conn.Cmd()
+	assert.Equal(t, cmd.Name, "app")
+	assert.Equal(t, cmd.Args(), []string{})
+	assert.True(t, cmd.HasOpt("verbose"))
+
+	assert.Equal(t, conn.SubCmdName(), "add")
+
+	subCmd := conn.SubCmd()
+	assert.Equal(t, subCmd.Name, "add")
+	assert.Equal(t, subCmd.Args(), []string{"file"})
+	assert.True(t, subCmd.HasOpt("all"))
+	assert.False(t, subCmd.HasOpt("verbose"))
+
+	assert.Equal(t, len(conn.SubOptCfgs()), 1)
+	assert.Equal(t, conn.SubOptCfgs()[0].Name, "all")
+	assert.Nil(t, conn.SubOptions())
+}
+
+func TestSubCmd_NewDaxSrcWithSubCmds_unknownSubCmd(t *testing.T) {
+	defer resetOsArgs()
+
+	os.Args = []string{"/path/to/app", "--verbose", "push", "--all"}
+
+	ds := cliargdax.NewDaxSrcWithSubCmds(map[string][]cliargs.OptCfg{
+		"add": []cliargs.OptCfg{cliargs.OptCfg{Name: "all"}},
+	})
+
+	ag := &noopAsyncGroup{}
+	err := ds.Setup(ag)
+	defer ds.Close()
+
+	switch r := err.Reason().(type) {
+	case cliargdax.UnknownSubCommand:
+		assert.Equal(t, r.Name, "push")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestSubCmd_NewDaxSrcWithSubCmds_subCmdError(t *testing.T) {
+	defer resetOsArgs()
+
+	os.Args = []string{"/path/to/app", "add", "--force"}
+
+	ds := cliargdax.NewDaxSrcWithSubCmds(map[string][]cliargs.OptCfg{
+		"add": []cliargs.OptCfg{cliargs.OptCfg{Name: "all"}},
+	})
+
+	ag := &noopAsyncGroup{}
+	err := ds.Setup(ag)
+	defer ds.Close()
+
+	switch r := err.Reason().(type) {
+	case cliargs.UnconfiguredOption:
+		assert.Equal(t, r.Option, "force")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestSubCmd_NewDaxSrcWithSubCmds_noSubCmd(t *testing.T) {
+	defer resetOsArgs()
+
+	os.Args = []string{"/path/to/app", "--verbose"}
+
+	ds := cliargdax.NewDaxSrcWithSubCmds(map[string][]cliargs.OptCfg{
+		"add": []cliargs.OptCfg{cliargs.OptCfg{Name: "all"}},
+	})
+
+	ag := &noopAsyncGroup{}
+	err := ds.Setup(ag)
+	defer ds.Close()
+	assert.True(t, err.IsOk())
+
+	dc, err := ds.CreateDaxConn()
+	assert.True(t, err.IsOk())
+	conn := dc.(*cliargdax.DaxConn)
+
+	assert.True(t, conn.Cmd().HasOpt("verbose"))
+	assert.Equal(t, conn.SubCmdName(), "")
+	assert.Equal(t, conn.SubCmd().Name, "")
+	assert.Nil(t, conn.SubOptCfgs())
+	assert.Nil(t, conn.SubOptions())
+}
+
+func TestSubCmd_DefaultSubCmd(t *testing.T) {
+	defer resetOsArgs()
+
+	os.Args = []string{"/path/to/app", "--verbose"}
+
+	ds := cliargdax.NewDaxSrcWithSubCmds(map[string][]cliargs.OptCfg{
+		"status": []cliargs.OptCfg{
+			cliargs.OptCfg{Name: "short", HasArg: true, Default: []string{"1"}},
+		},
+	}).DefaultSubCmd("status")
+
+	ag := &noopAsyncGroup{}
+	err := ds.Setup(ag)
+	defer ds.Close()
+	assert.True(t, err.IsOk())
+
+	dc, err := ds.CreateDaxConn()
+	assert.True(t, err.IsOk())
+	conn := dc.(*cliargdax.DaxConn)
+
+	assert.True(t, conn.Cmd().HasOpt("verbose"))
+	assert.Equal(t, conn.SubCmdName(), "status")
+	assert.Equal(t, conn.SubCmd().Name, "status")
+	assert.Equal(t, conn.SubCmd().Args(), []string{})
+	assert.Equal(t, conn.SubCmd().OptArg("short"), "1")
+}
+
+func TestSubCmd_NewDaxSrcWithSubCmdsForOptions(t *testing.T) {
+	defer resetOsArgs()
+
+	os.Args = []string{"/path/to/app", "commit", "--message=hello", "file"}
+
+	type AddOptions struct {
+		All bool `optcfg:"all"`
+	}
+	type CommitOptions struct {
+		Message string `optcfg:"message,m"`
+	}
+	addOptions := AddOptions{}
+	commitOptions := CommitOptions{}
+
+	ds := cliargdax.NewDaxSrcWithSubCmdsForOptions(map[string]any{
+		"add":    &addOptions,
+		"commit": &commitOptions,
+	})
+
+	ag := &noopAsyncGroup{}
+	err := ds.Setup(ag)
+	defer ds.Close()
+	assert.True(t, err.IsOk())
+
+	dc, err := ds.CreateDaxConn()
+	assert.True(t, err.IsOk())
+	conn := dc.(*cliargdax.DaxConn)
+
+	assert.Equal(t, conn.SubCmdName(), "commit")
+	assert.Equal(t, conn.SubCmd().Args(), []string{"file"})
+	assert.Equal(t, conn.SubOptCfgs()[0].Name, "message")
+
+	subCfgs := conn.SubOptCfgs()
+	subCfgs[0].Name = "x"
+	subCfgs[0].Aliases[0] = "y"
+	assert.Equal(t, conn.SubOptCfgs()[0].Name, "message")
+	assert.Equal(t, conn.SubOptCfgs()[0].Aliases, []string{"m"})
+
+	opts := conn.SubOptions().(*CommitOptions)
+	assert.Equal(t, opts.Message, "hello")
+	assert.False(t, addOptions.All)
+}
+
+func TestSubCmd_topOptionWithArg(t *testing.T) {
+	defer resetOsArgs()
+
+	os.Args = []string{"/path/to/app", "--out", "file", "-v", "build", "x"}
+
+	topOptions := struct {
+		Out     string `optcfg:"out,o"`
+		Verbose bool   `optcfg:"verbose,v"`
+	}{}
+	ds := cliargdax.NewDaxSrcWithSubCmds(map[string][]cliargs.OptCfg{
+		"build": []cliargs.OptCfg{cliargs.OptCfg{Name: "all"}},
+	}).AddOptions("top", &topOptions)
+
+	err := ds.Setup(&noopAsyncGroup{})
+	defer ds.Close()
+	assert.True(t, err.IsOk())
+
+	dc, err := ds.CreateDaxConn()
+	assert.True(t, err.IsOk())
+	conn := dc.(*cliargdax.DaxConn)
+
+	assert.Equal(t, topOptions.Out, "file")
+	assert.True(t, topOptions.Verbose)
+	assert.Equal(t, conn.Cmd().Args(), []string{})
+	assert.Equal(t, conn.SubCmdName(), "build")
+	assert.Equal(t, conn.SubCmd().Args(), []string{"x"})
+}
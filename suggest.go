@@ -0,0 +1,122 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/sttk/cliargs"
+)
+
+type /* error reasons */ (
+	// UnconfiguredOptionWithSuggestion is the error reason which indicates that
+	// an option which is not configured is given in command line arguments, and
+	// that there are configured option names or aliases similar to it.
+	// The fields Option and Suggestions are the given option name and the
+	// similar names, which are at most three and in the order of similarity.
+	// The error of cliargs.UnconfiguredOption is set as the cause.
+	UnconfiguredOptionWithSuggestion struct {
+		Option      string
+		Suggestions []string
+	}
+)
+
+// maxSuggestions is the maximum number of suggested option names.
+const maxSuggestions = 3
+
+// maxSuggestionDistance is the maximum edit distance of suggested option names.
+const maxSuggestionDistance = 2
+
+// EditDistance is the function to calculate the Levenshtein distance between
+// the two strings, which is the minimum number of single-character insertions,
+// deletions, and substitutions to change one into the other.
+// This function is used to suggest option names similar to an unknown option,
+// and can be used to suggest sub command names, too.
+func EditDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = minInt(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
+
+func minInt(n int, a ...int) int {
+	for _, m := range a {
+		if m < n {
+			n = m
+		}
+	}
+	return n
+}
+
+// suggestOptNames returns the option names and aliases in the option
+// configurations which are similar to the specified name.
+// A name is similar if its edit distance is within maxSuggestionDistance or
+// it starts with the specified name.
+// One-character names are neither suggested nor given suggestions.
+func suggestOptNames(name string, optCfgs []cliargs.OptCfg) []string {
+	var names []string
+	for _, cfg := range optCfgs {
+		for _, n := range append([]string{cfg.Name}, cfg.Aliases...) {
+			if n != "*" {
+				names = append(names, n)
+			}
+		}
+	}
+	return similarNames(name, names)
+}
+
+// similarNames returns the names which are similar to the specified name in
+// the same way as suggestOptNames function.
+func similarNames(name string, names []string) []string {
+	if len([]rune(name)) < 2 {
+		return nil
+	}
+
+	type candidate struct {
+		name string
+		dist int
+	}
+	var cands []candidate
+	seen := make(map[string]bool)
+
+	for _, n := range names {
+		if n == name || len([]rune(n)) < 2 || seen[n] {
+			continue
+		}
+		seen[n] = true
+		d := EditDistance(name, n)
+		if d <= maxSuggestionDistance || strings.HasPrefix(n, name) {
+			cands = append(cands, candidate{name: n, dist: d})
+		}
+	}
+
+	sort.SliceStable(cands, func(i, j int) bool {
+		return cands[i].dist < cands[j].dist
+	})
+	if len(cands) > maxSuggestions {
+		cands = cands[:maxSuggestions]
+	}
+
+	var similar []string
+	for _, c := range cands {
+		similar = append(similar, c.name)
+	}
+	return similar
+}
@@ -0,0 +1,65 @@
+package cliargdax_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+)
+
+func TestSuggest_EditDistance(t *testing.T) {
+	assert.Equal(t, cliargdax.EditDistance("", ""), 0)
+	assert.Equal(t, cliargdax.EditDistance("abc", ""), 3)
+	assert.Equal(t, cliargdax.EditDistance("", "abc"), 3)
+	assert.Equal(t, cliargdax.EditDistance("qux", "quux"), 1)
+	assert.Equal(t, cliargdax.EditDistance("kitten", "sitting"), 3)
+	assert.Equal(t, cliargdax.EditDistance("café", "cafe"), 1)
+}
+
+func TestSuggest_UnconfiguredOption(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "--colr",
+	}, []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "color", Aliases: []string{"colour"}},
+		cliargs.OptCfg{Name: "colors-file", HasArg: true},
+		cliargs.OptCfg{Name: "cols", HasArg: true},
+		cliargs.OptCfg{Name: "cool"},
+		cliargs.OptCfg{Name: "verbose", Aliases: []string{"v"}},
+	})
+	_, err := setupWithOptCfgs(t, ds)
+	switch r := err.Reason().(type) {
+	case cliargdax.UnconfiguredOptionWithSuggestion:
+		assert.Equal(t, r.Option, "colr")
+		assert.Equal(t, r.Suggestions, []string{"color", "cols", "colour"})
+		assert.Equal(t, err.Cause(), cliargs.UnconfiguredOption{Option: "colr"})
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestSuggest_prefixMatch(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "--dry",
+	}, []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "dry-run-mode"},
+		cliargs.OptCfg{Name: "secret-dry"},
+	}).Hidden("secret-dry")
+	_, err := setupWithOptCfgs(t, ds)
+	switch r := err.Reason().(type) {
+	case cliargdax.UnconfiguredOptionWithSuggestion:
+		assert.Equal(t, r.Suggestions, []string{"dry-run-mode"})
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestSuggest_noSuggestion(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "--foo", "-x",
+	}, []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "verbose", Aliases: []string{"v"}},
+	})
+	_, err := setupWithOptCfgs(t, ds)
+	assert.Equal(t, err.Reason(), cliargs.UnconfiguredOption{Option: "foo"})
+}
@@ -0,0 +1,114 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"io"
+	"os"
+
+	"golang.org/x/term"
+)
+
+// TerminalSizeFunc is the type of the function which reports whether the
+// io.Writer is a terminal and returns its width in cells.
+// The width is ignored if it is not positive.
+type TerminalSizeFunc func(w io.Writer) (width int, isTerminal bool)
+
+const (
+	ansiReset = "\x1b[0m"
+	ansiBold  = "\x1b[1m"
+	ansiCyan  = "\x1b[36m"
+)
+
+// TerminalSize is the method to set the function which detects the terminal
+// to which the help text is written, instead of the default function which
+// checks whether the io.Writer is an *os.File connected to a terminal and
+// gets its width.
+// This is mainly to make the help output deterministic in tests.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) TerminalSize(fn TerminalSizeFunc) *DaxSrc {
+	ds.termSize = fn
+	return ds
+}
+
+// HelpColor is the method to enable or disable colorizing the option names
+// and the headings in the help text with ANSI escape sequences.
+// Even if enabled, the help text is not colorized when it is not written to a
+// terminal, or when the environment variable NO_COLOR is set to a non-empty
+// value.
+// The help text retrieved by DaxConn#HelpText method and the documents in
+// Markdown or man page format are never colorized.
+// This is disabled by default.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) HelpColor(enabled bool) *DaxSrc {
+	ds.helpColor = enabled
+	return ds
+}
+
+// detectTerminal is the default TerminalSizeFunc.
+func detectTerminal(w io.Writer) (int, bool) {
+	f, ok := w.(*os.File)
+	if !ok {
+		return 0, false
+	}
+	fd := int(f.Fd())
+	if !term.IsTerminal(fd) {
+		return 0, false
+	}
+	width, _, e := term.GetSize(fd)
+	if e != nil {
+		return 0, true
+	}
+	return width, true
+}
+
+// helpStyle is the width and the coloring of the help text which is written
+// to an io.Writer.
+type helpStyle struct {
+	width int
+	color bool
+}
+
+// helpStyleFor determines the help style for the io.Writer, which is nil if
+// the help text is not written to any writer.
+// The width is the one set with HelpWidth method, the width of the terminal,
+// the value of the environment variable COLUMNS, or 80, in this priority.
+func (ds *DaxSrc) helpStyleFor(w io.Writer) helpStyle {
+	termWidth, isTerminal := 0, false
+	if w != nil {
+		fn := ds.termSize
+		if fn == nil {
+			fn = detectTerminal
+		}
+		termWidth, isTerminal = fn(w)
+	}
+
+	s := helpStyle{width: ds.helpWidth}
+	if s.width <= 0 {
+		if isTerminal && termWidth > 0 {
+			s.width = termWidth
+		} else {
+			s.width = terminalWidth()
+		}
+	}
+	s.color = ds.helpColor && isTerminal && len(os.Getenv("NO_COLOR")) == 0
+	return s
+}
+
+// heading returns the heading colorized if coloring is enabled.
+func (s helpStyle) heading(text string) string {
+	if !s.color {
+		return text
+	}
+	return ansiBold + text + ansiReset
+}
+
+// flag returns the option flag colorized if coloring is enabled.
+func (s helpStyle) flag(text string) string {
+	if !s.color {
+		return text
+	}
+	return ansiCyan + text + ansiReset
+}
@@ -0,0 +1,77 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+// StopAtFirstArg is the method to enable or disable the mode to stop parsing
+// options at the first command argument, like POSIX getopt.
+// If enabled, the first command argument and all arguments after it are
+// treated as command arguments even if they look like options, which is
+// useful for wrapper commands like: mytool exec prog --prog-flag.
+// In this mode, "--" after the first command argument is also a command
+// argument, and DaxConn#ArgsAfterTerminator method returns the arguments
+// after "--" only when it is given before the first command argument.
+// This mode is disabled by default, and options and command arguments can be
+// intermixed.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) StopAtFirstArg(stop bool) *DaxSrc {
+	ds.mode.stopAtFirstArg = stop
+	return ds
+}
+
+// HasTerminator is the method to check whether the option terminator "--" is
+// given in command line arguments.
+// This method is used to distinguish the case that "--" is given with no
+// argument after it from the case that "--" is not given.
+func (conn *DaxConn) HasTerminator() bool {
+	conn.ds.parseLazily()
+	conn.ds.mutex.RLock()
+	defer conn.ds.mutex.RUnlock()
+	return conn.ds.hasTerm
+}
+
+// ArgsBeforeTerminator is the method to retrieve the command arguments which
+// are given before the option terminator "--".
+// If "--" is not given, this method returns all command arguments.
+func (conn *DaxConn) ArgsBeforeTerminator() []string {
+	conn.ds.parseLazily()
+	conn.ds.mutex.RLock()
+	defer conn.ds.mutex.RUnlock()
+	args := conn.ds.cmd.Args()
+	if !conn.ds.hasTerm {
+		return append([]string{}, args...)
+	}
+	return append([]string{}, args[:conn.ds.termIndex]...)
+}
+
+// ArgsAfterTerminator is the method to retrieve the command arguments which
+// are given after the option terminator "--", verbatim even if they look
+// like options.
+// If "--" is not given, this method returns an empty array.
+func (conn *DaxConn) ArgsAfterTerminator() []string {
+	conn.ds.parseLazily()
+	conn.ds.mutex.RLock()
+	defer conn.ds.mutex.RUnlock()
+	if !conn.ds.hasTerm {
+		return []string{}
+	}
+	return append([]string{}, conn.ds.cmd.Args()[conn.ds.termIndex:]...)
+}
+
+// findTerminator returns the index of the first command argument after the
+// option terminator "--" in command arguments, and whether "--" is given.
+// The terminator inserted implicitly by the mode to stop parsing options at
+// the first command argument is not regarded as given.
+func findTerminator(toks []argToken) (int, bool) {
+	n := 0
+	for _, tok := range toks {
+		if tok.isTerm && !tok.isImplicit {
+			return n, true
+		}
+		if !tok.isOpt() && !tok.isTerm {
+			n++
+		}
+	}
+	return n, false
+}
@@ -0,0 +1,143 @@
+package cliargdax_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+)
+
+func TestTerminator_ArgsAfterTerminator(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgs([]string{
+		"mytool", "run", "--verbose", "--", "docker", "--rm", "-it", "--", "x",
+	})
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.True(t, conn.HasTerminator())
+	assert.Equal(t, conn.ArgsBeforeTerminator(), []string{"run"})
+	assert.Equal(t, conn.ArgsAfterTerminator(),
+		[]string{"docker", "--rm", "-it", "--", "x"})
+	assert.Equal(t, conn.Cmd().Args(),
+		[]string{"run", "docker", "--rm", "-it", "--", "x"})
+}
+
+func TestTerminator_noTerminator(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgs([]string{"mytool", "run", "-v", "x"})
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.False(t, conn.HasTerminator())
+	assert.Equal(t, conn.ArgsBeforeTerminator(), []string{"run", "x"})
+	assert.Equal(t, conn.ArgsAfterTerminator(), []string{})
+}
+
+func TestTerminator_nothingAfterTerminator(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgs([]string{"mytool", "run", "--"})
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.True(t, conn.HasTerminator())
+	assert.Equal(t, conn.ArgsBeforeTerminator(), []string{"run"})
+	assert.Equal(t, conn.ArgsAfterTerminator(), []string{})
+}
+
+func TestTerminator_optionArgument(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "sep", HasArg: true},
+		cliargs.OptCfg{Name: "color"},
+	}
+
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"mytool", "--sep", "--", "a", "--no-color", "--", "b"},
+		optCfgs,
+	).Negatable("color")
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.Cmd().OptArg("sep"), "--")
+	assert.Equal(t, conn.Cmd().OptArg("color"), "false")
+	assert.True(t, conn.HasTerminator())
+	assert.Equal(t, conn.ArgsBeforeTerminator(), []string{"a"})
+	assert.Equal(t, conn.ArgsAfterTerminator(), []string{"b"})
+}
+
+func TestTerminator_StopAtFirstArg(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "verbose", Aliases: []string{"v"}},
+		cliargs.OptCfg{Name: "dir", Aliases: []string{"d"}, HasArg: true},
+	}
+
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"mytool", "-v", "--dir", "exec", "exec", "prog", "--prog-flag", "-x",
+	}, optCfgs).StopAtFirstArg(true)
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.True(t, conn.Cmd().HasOpt("verbose"))
+	assert.Equal(t, conn.Cmd().OptArg("dir"), "exec")
+	assert.Equal(t, conn.Cmd().Args(),
+		[]string{"exec", "prog", "--prog-flag", "-x"})
+	assert.False(t, conn.HasTerminator())
+	assert.Equal(t, conn.ArgsAfterTerminator(), []string{})
+	assert.Equal(t, conn.OptCount("verbose"), 1)
+}
+
+func TestTerminator_StopAtFirstArg_withTerminator(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "verbose", Aliases: []string{"v"}},
+	}
+
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"mytool", "-v", "prog", "--", "-v",
+	}, optCfgs).StopAtFirstArg(true)
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.Cmd().Args(), []string{"prog", "--", "-v"})
+	assert.False(t, conn.HasTerminator())
+	assert.Equal(t, conn.ArgsBeforeTerminator(), []string{"prog", "--", "-v"})
+	assert.Equal(t, conn.OptCount("verbose"), 1)
+
+	ds = cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"mytool", "-v", "--", "prog", "-v",
+	}, optCfgs).StopAtFirstArg(true)
+
+	conn, err = setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.Cmd().Args(), []string{"prog", "-v"})
+	assert.True(t, conn.HasTerminator())
+	assert.Equal(t, conn.ArgsAfterTerminator(), []string{"prog", "-v"})
+}
+
+func TestTerminator_StopAtFirstArg_disabledByDefault(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "force", Aliases: []string{"f"}},
+	}
+
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"mytool", "file.txt", "--force"}, optCfgs,
+	)
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.True(t, conn.Cmd().HasOpt("force"))
+	assert.Equal(t, conn.Cmd().Args(), []string{"file.txt"})
+}
+
+func TestTerminator_StopAtFirstArg_optionStore(t *testing.T) {
+	type Options struct {
+		Verbose bool `optcfg:"verbose,v"`
+	}
+
+	options := Options{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions(
+		[]string{"mytool", "prog", "-v"}, &options,
+	).StopAtFirstArg(true)
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.False(t, options.Verbose)
+	assert.Equal(t, conn.Cmd().Args(), []string{"prog", "-v"})
+}
@@ -0,0 +1,57 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"encoding"
+	"reflect"
+)
+
+type /* error reasons */ (
+	// OptionArgUnmarshalFailed is the error reason which indicates that the
+	// UnmarshalText method of a field type of an option store, or the decoder
+	// registered for the field type, failed for an option argument.
+	// The fields Option, Value, and Cause are the option name, the option
+	// argument, and the error returned by the UnmarshalText method or the
+	// decoder.
+	OptionArgUnmarshalFailed struct {
+		Option string
+		Value  string
+		Cause  error
+	}
+)
+
+var textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).
+	Elem()
+
+// textParserFor returns the valueParser for a type of which pointer type
+// implements encoding.TextUnmarshaler interface.
+// time.Time and net.IP are excluded because they have the built-in
+// conversions.
+func textParserFor(t reflect.Type) (valueParser, string, bool) {
+	if t.Kind() == reflect.Ptr || t.Kind() == reflect.Interface {
+		return nil, "", false
+	}
+	if t == timeType || t == ipType {
+		return nil, "", false
+	}
+	if !reflect.PtrTo(t).Implements(textUnmarshalerType) {
+		return nil, "", false
+	}
+
+	p := func(name, s string) (reflect.Value, error) {
+		v := reflect.New(t)
+		u := v.Interface().(encoding.TextUnmarshaler)
+		if e := u.UnmarshalText([]byte(s)); e != nil {
+			return reflect.Value{}, reasonErr{
+				option: name,
+				reason: OptionArgUnmarshalFailed{Option: name, Value: s, Cause: e},
+				cause:  e,
+			}
+		}
+		return v.Elem(), nil
+	}
+	return p, "", true
+}
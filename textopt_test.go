@@ -0,0 +1,110 @@
+package cliargdax_test
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+)
+
+type logLevel int
+
+const (
+	levelInfo logLevel = iota
+	levelDebug
+)
+
+func (l *logLevel) UnmarshalText(text []byte) error {
+	switch strings.ToLower(string(text)) {
+	case "info":
+		*l = levelInfo
+	case "debug":
+		*l = levelDebug
+	default:
+		return fmt.Errorf("unknown level: %s", text)
+	}
+	return nil
+}
+
+type version struct {
+	Major, Minor int
+}
+
+func (v *version) UnmarshalText(text []byte) error {
+	_, e := fmt.Sscanf(string(text), "%d.%d", &v.Major, &v.Minor)
+	return e
+}
+
+func TestTextOpt_textUnmarshalerField(t *testing.T) {
+	type Options struct {
+		Level    logLevel   `optcfg:"level=info"`
+		Version  version    `optcfg:"version,v" optarg:"<ver>"`
+		Requires []version  `optcfg:"require"`
+		Min      *version   `optcfg:"min"`
+		Levels   []logLevel `optcfg:"levels"`
+	}
+
+	options := Options{}
+	conn, err := setupForOptions(t, []string{
+		"app", "-v", "1.2", "--require=0.1", "--require", "3.4",
+		"--levels=DEBUG", "--levels=info",
+	}, &options)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, options.Level, levelInfo)
+	assert.Equal(t, options.Version, version{1, 2})
+	assert.Equal(t, options.Requires, []version{{0, 1}, {3, 4}})
+	assert.Nil(t, options.Min)
+	assert.Equal(t, options.Levels, []logLevel{levelDebug, levelInfo})
+
+	cfgs := conn.OptCfgs()
+	assert.True(t, cfgs[0].HasArg)
+	assert.False(t, cfgs[0].IsArray)
+	assert.Equal(t, cfgs[0].Default, []string{"info"})
+	assert.Equal(t, cfgs[1].ArgHelp, "<ver>")
+	assert.True(t, cfgs[2].IsArray)
+
+	options = Options{}
+	_, err = setupForOptions(t, []string{
+		"app", "--level", "debug", "--min=2.0",
+	}, &options)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, options.Level, levelDebug)
+	assert.Equal(t, *options.Min, version{2, 0})
+}
+
+func TestTextOpt_textUnmarshalerField_failed(t *testing.T) {
+	type Options struct {
+		Level logLevel `optcfg:"level"`
+	}
+
+	options := Options{}
+	_, err := setupForOptions(t, []string{"app", "--level=trace"}, &options)
+	switch r := err.Reason().(type) {
+	case cliargdax.OptionArgUnmarshalFailed:
+		assert.Equal(t, r.Option, "level")
+		assert.Equal(t, r.Value, "trace")
+		assert.Equal(t, r.Cause.Error(), "unknown level: trace")
+		assert.True(t, errors.Is(err, r.Cause))
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestTextOpt_textUnmarshalerField_invalidDefault(t *testing.T) {
+	type Options struct {
+		Level logLevel `optcfg:"level=trace"`
+	}
+
+	options := Options{}
+	_, err := setupForOptions(t, []string{"app"}, &options)
+	switch r := err.Reason().(type) {
+	case cliargdax.FailToParseDefault:
+		assert.Equal(t, r.Option, "level")
+		assert.Equal(t, r.Input, "trace")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
@@ -0,0 +1,87 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"reflect"
+	"time"
+)
+
+type /* error reasons */ (
+	// FailToParseDuration is the error reason which indicates that an option
+	// argument cannot be converted to a time.Duration value.
+	// The fields Option, Field, and Input are the option name, the field name,
+	// and the option argument.
+	FailToParseDuration struct {
+		Option string
+		Field  string
+		Input  string
+	}
+
+	// FailToParseTime is the error reason which indicates that an option
+	// argument cannot be converted to a time.Time value with the layout.
+	// The fields Option, Field, Input, and Layout are the option name, the
+	// field name, the option argument, and the expected layout.
+	FailToParseTime struct {
+		Option string
+		Field  string
+		Input  string
+		Layout string
+	}
+)
+
+var (
+	durationType = reflect.TypeOf(time.Duration(0))
+	timeType     = reflect.TypeOf(time.Time{})
+)
+
+// timeParserFor returns the valueParser for time.Duration or time.Time type.
+// A time.Duration value is converted by time.ParseDuration function, and a
+// time.Time value is converted with the layout specified with the struct tag:
+// optlayout, or time.RFC3339 by default.
+func timeParserFor(
+	t reflect.Type, sf reflect.StructField,
+) (valueParser, string, bool) {
+	switch t {
+	case durationType:
+		p := func(name, s string) (reflect.Value, error) {
+			d, e := time.ParseDuration(s)
+			if e != nil {
+				return reflect.Value{}, reasonErr{
+					option: name,
+					reason: FailToParseDuration{
+						Option: name, Field: sf.Name, Input: s,
+					},
+					cause: e,
+				}
+			}
+			return reflect.ValueOf(d), nil
+		}
+		return p, "duration", true
+
+	case timeType:
+		layout := sf.Tag.Get("optlayout")
+		if len(layout) == 0 {
+			layout = time.RFC3339
+		}
+		p := func(name, s string) (reflect.Value, error) {
+			tm, e := time.Parse(layout, s)
+			if e != nil {
+				return reflect.Value{}, reasonErr{
+					option: name,
+					reason: FailToParseTime{
+						Option: name, Field: sf.Name, Input: s, Layout: layout,
+					},
+					cause: e,
+				}
+			}
+			return reflect.ValueOf(tm), nil
+		}
+		return p, layout, true
+
+	default:
+		return nil, "", false
+	}
+}
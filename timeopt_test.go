@@ -0,0 +1,163 @@
+package cliargdax_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/sabi/errs"
+)
+
+func TestTimeOpt_durationField(t *testing.T) {
+	type Options struct {
+		Timeout  time.Duration   `optcfg:"timeout,t" optdesc:"timeout"`
+		Interval *time.Duration  `optcfg:"interval"`
+		Retries  []time.Duration `optcfg:"retry"`
+		Wait     time.Duration   `optcfg:"wait=1m30s"`
+	}
+
+	options := Options{}
+	conn, err := setupForOptions(t, []string{
+		"app", "--timeout", "5s", "--interval=100ms",
+		"--retry=1s", "--retry", "2s",
+	}, &options)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, options.Timeout, 5*time.Second)
+	assert.Equal(t, *options.Interval, 100*time.Millisecond)
+	assert.Equal(t, options.Retries, []time.Duration{time.Second, 2 * time.Second})
+	assert.Equal(t, options.Wait, 90*time.Second)
+
+	cfgs := conn.OptCfgs()
+	assert.Equal(t, cfgs[0].Name, "timeout")
+	assert.Equal(t, cfgs[0].Aliases, []string{"t"})
+	assert.True(t, cfgs[0].HasArg)
+	assert.False(t, cfgs[0].IsArray)
+	assert.Equal(t, cfgs[0].ArgHelp, "<duration>")
+	assert.True(t, cfgs[2].HasArg)
+	assert.True(t, cfgs[2].IsArray)
+}
+
+func TestTimeOpt_durationField_notGiven(t *testing.T) {
+	type Options struct {
+		Timeout  time.Duration  `optcfg:"timeout"`
+		Interval *time.Duration `optcfg:"interval"`
+	}
+
+	options := Options{}
+	_, err := setupForOptions(t, []string{"app"}, &options)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, options.Timeout, time.Duration(0))
+	assert.Nil(t, options.Interval)
+}
+
+func TestTimeOpt_durationField_invalid(t *testing.T) {
+	type Options struct {
+		Timeout time.Duration `optcfg:"timeout"`
+	}
+
+	options := Options{}
+	_, err := setupForOptions(t, []string{"app", "--timeout=5x"}, &options)
+	switch r := err.Reason().(type) {
+	case cliargdax.FailToParseDuration:
+		assert.Equal(t, r.Option, "timeout")
+		assert.Equal(t, r.Field, "Timeout")
+		assert.Equal(t, r.Input, "5x")
+		assert.NotNil(t, err.Cause())
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestTimeOpt_timeField(t *testing.T) {
+	type Options struct {
+		Since time.Time   `optcfg:"since"`
+		Until *time.Time  `optcfg:"until" optlayout:"2006-01-02"`
+		Days  []time.Time `optcfg:"day" optlayout:"2006-01-02"`
+	}
+
+	options := Options{}
+	conn, err := setupForOptions(t, []string{
+		"app", "--since=2023-04-05T06:07:08Z", "--until", "2023-05-06",
+		"--day=2023-01-01", "--day=2023-01-02",
+	}, &options)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, options.Since,
+		time.Date(2023, 4, 5, 6, 7, 8, 0, time.UTC))
+	assert.Equal(t, *options.Until, time.Date(2023, 5, 6, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, options.Days, []time.Time{
+		time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2023, 1, 2, 0, 0, 0, 0, time.UTC),
+	})
+
+	cfgs := conn.OptCfgs()
+	assert.True(t, cfgs[0].HasArg)
+	assert.Equal(t, cfgs[0].ArgHelp, "<"+time.RFC3339+">")
+	assert.Equal(t, cfgs[1].ArgHelp, "<2006-01-02>")
+}
+
+func TestTimeOpt_timeField_argHelp(t *testing.T) {
+	type Options struct {
+		Since time.Time `optcfg:"since" optarg:"<date>" optlayout:"2006-01-02"`
+	}
+
+	options := Options{}
+	conn, err := setupForOptions(t, []string{"app"}, &options)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.OptCfgs()[0].ArgHelp, "<date>")
+}
+
+func TestTimeOpt_timeField_invalid(t *testing.T) {
+	type Options struct {
+		Until time.Time `optcfg:"until" optlayout:"2006-01-02"`
+	}
+
+	options := Options{}
+	_, err := setupForOptions(t, []string{"app", "--until=05/06/2023"}, &options)
+	switch r := err.Reason().(type) {
+	case cliargdax.FailToParseTime:
+		assert.Equal(t, r.Option, "until")
+		assert.Equal(t, r.Field, "Until")
+		assert.Equal(t, r.Input, "05/06/2023")
+		assert.Equal(t, r.Layout, "2006-01-02")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestTimeOpt_timeField_invalidDefault(t *testing.T) {
+	type Options struct {
+		Until time.Time `optcfg:"until=tomorrow" optlayout:"2006-01-02"`
+	}
+
+	options := Options{}
+	_, err := setupForOptions(t, []string{"app"}, &options)
+	switch r := err.Reason().(type) {
+	case cliargdax.FailToParseDefault:
+		assert.Equal(t, r.Option, "until")
+		assert.Equal(t, r.Input, "tomorrow")
+		switch err.Cause().(errs.Err).Reason().(type) {
+		case cliargdax.FailToParseTime:
+		default:
+			assert.Fail(t, err.Error())
+		}
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestTimeOpt_durationField_range(t *testing.T) {
+	type Options struct {
+		Timeout time.Duration `optcfg:"timeout" optmax:"10"`
+	}
+
+	options := Options{}
+	_, err := setupForOptions(t, []string{"app"}, &options)
+	switch r := err.Reason().(type) {
+	case cliargdax.IllegalOptionRange:
+		assert.Equal(t, r.Option, "timeout")
+		assert.Equal(t, r.Field, "Timeout")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
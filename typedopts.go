@@ -0,0 +1,75 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/sttk/sabi/errs"
+)
+
+type /* error reasons */ (
+	// OptionsTypeMismatch is the error reason which indicates that the option
+	// store held by a DaxSrc is not of the requested type.
+	// The fields Want and Got are the names of the requested type and of the
+	// type of the held option store.
+	OptionsTypeMismatch struct {
+		Want string
+		Got  string
+	}
+)
+
+// GetOptions is the function to retrieve the option store of the DaxConn as a
+// pointer of the type parameter T, instead of asserting the type of the value
+// returned by DaxConn#Options method.
+// If the option store is not of the type *T, this function returns an
+// errs.Err with the reason: OptionsTypeMismatch.
+// If no option store is held, this function allocates a new T and sets the
+// results of parsing, which can be retrieved by DaxConn#Cmd method, to it with
+// the option configurations made from T by the same struct tags as
+// NewDaxSrcForOptions function.
+func GetOptions[T any](conn *DaxConn) (*T, errs.Err) {
+	opts := conn.Options()
+	if opts != nil {
+		if p, ok := opts.(*T); ok {
+			return p, errs.Ok()
+		}
+		return nil, errs.New(OptionsTypeMismatch{
+			Want: reflect.TypeOf((*T)(nil)).String(),
+			Got:  fmt.Sprintf("%T", opts),
+		})
+	}
+
+	p := new(T)
+	cfgs, err := MakeOptCfgsFor(p)
+	if err.IsNotOk() {
+		return nil, err
+	}
+
+	cmd := conn.Cmd()
+	for _, cfg := range cfgs {
+		var arr []string
+		for _, name := range append([]string{cfg.Name}, cfg.Aliases...) {
+			if cmd.HasOpt(name) {
+				arr = append(append([]string{}, arr...), cmd.OptArgs(name)...)
+			}
+		}
+		if arr == nil && cfg.Default != nil {
+			arr = cfg.Default
+		}
+		if cfg.OnParsed == nil {
+			continue
+		}
+		if e := (*cfg.OnParsed)(arr); e != nil {
+			if re, ok := e.(reasonErr); ok {
+				return nil, re.toErr()
+			}
+			return nil, errs.New(e)
+		}
+	}
+
+	return p, errs.Ok()
+}
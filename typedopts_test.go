@@ -0,0 +1,72 @@
+package cliargdax_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+)
+
+func TestGetOptions_ok(t *testing.T) {
+	type Options struct {
+		Name string `optcfg:"name"`
+	}
+	opts := Options{}
+	conn, err := setupForOptions(t, []string{"app", "--name=foo"}, &opts)
+	assert.True(t, err.IsOk())
+
+	p, err := cliargdax.GetOptions[Options](conn)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, p, &opts)
+	assert.Equal(t, p.Name, "foo")
+}
+
+func TestGetOptions_typeMismatch(t *testing.T) {
+	type Options struct {
+		Name string `optcfg:"name"`
+	}
+	type OtherOptions struct {
+		Name string `optcfg:"name"`
+	}
+	opts := Options{}
+	conn, err := setupForOptions(t, []string{"app"}, &opts)
+	assert.True(t, err.IsOk())
+
+	p, err := cliargdax.GetOptions[OtherOptions](conn)
+	assert.Nil(t, p)
+	switch r := err.Reason().(type) {
+	case cliargdax.OptionsTypeMismatch:
+		assert.Equal(t, r.Want, "*cliargdax_test.OtherOptions")
+		assert.Equal(t, r.Got, "*cliargdax_test.Options")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestGetOptions_decodeCmd(t *testing.T) {
+	type Options struct {
+		Verbose bool     `optcfg:"verbose,v"`
+		Port    int      `optcfg:"port=80"`
+		Tags    []string `optcfg:"tag"`
+		Name    string   `optcfg:"name"`
+	}
+	ds := cliargdax.NewDaxSrcWithArgs([]string{
+		"app", "-v", "--tag=a", "--tag=b", "file",
+	})
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	p, err := cliargdax.GetOptions[Options](conn)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, *p, Options{
+		Verbose: true, Port: 80, Tags: []string{"a", "b"},
+	})
+
+	ds = cliargdax.NewDaxSrcWithArgs([]string{"app", "--port=x"})
+	conn, err = setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	p, err = cliargdax.GetOptions[Options](conn)
+	assert.Nil(t, p)
+	assert.True(t, err.IsNotOk())
+}
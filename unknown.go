@@ -0,0 +1,76 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"github.com/sttk/cliargs"
+	"github.com/sttk/sabi/errs"
+)
+
+// IgnoreUnknownOpts is the method to enable or disable the tolerant mode for
+// unknown options.
+// If enabled, options which are not configured do not make Setup fail but are
+// removed from the parsing results, and they can be retrieved by
+// DaxConn#UnknownOpts method.
+// For each unknown option, an errs.Err with the reason:
+// cliargs.UnconfiguredOption is added to DaxConn#Warnings.
+// This mode cannot be used with the wildcard option configuration of which
+// name is "*", and if both are configured, Setup method returns an errs.Err
+// with the reason: WildcardWithIgnoreUnknownOpts.
+// This mode is disabled by default.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) IgnoreUnknownOpts(ignore bool) *DaxSrc {
+	ds.mode.ignoreUnknown = ignore
+	return ds
+}
+
+// UnknownOpts is the method to retrieve the unknown options which are ignored
+// in the tolerant mode enabled by DaxSrc#IgnoreUnknownOpts.
+// Each element is the option as written in command line arguments with its
+// option argument if given with "=", like --future=x.
+// If there is no unknown option, this method returns an empty array.
+func (conn *DaxConn) UnknownOpts() []string {
+	conn.ds.parseLazily()
+	conn.ds.mutex.RLock()
+	defer conn.ds.mutex.RUnlock()
+	return append([]string{}, conn.ds.unknownOpts...)
+}
+
+// removeUnknownOpts removes tokens of options which are not configured, and
+// records them as unknown options and warnings.
+func (r *parseResult) removeUnknownOpts(
+	toks []argToken, optCfgs []cliargs.OptCfg,
+) []argToken {
+	indexes := cfgIndexes(optCfgs)
+	for _, cfg := range optCfgs {
+		if cfg.Name == "*" {
+			return toks
+		}
+	}
+
+	known := toks[:0]
+	for _, tok := range toks {
+		if _, exists := indexes[tok.name]; !tok.isOpt() || exists {
+			known = append(known, tok)
+			continue
+		}
+
+		var s string
+		if tok.isShort {
+			s = "-" + tok.name
+		} else {
+			s = "--" + tok.name
+		}
+		if tok.hasValue {
+			s += "=" + tok.value
+		}
+
+		r.unknownOpts = append(r.unknownOpts, s)
+		r.warnings = append(r.warnings, errs.New(cliargs.UnconfiguredOption{
+			Option: tok.name,
+		}))
+	}
+	return known
+}
@@ -0,0 +1,134 @@
+package cliargdax_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+	"github.com/sttk/sabi/errs"
+)
+
+func TestUnknown_IgnoreUnknownOpts(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "verbose", Aliases: []string{"v"}},
+		cliargs.OptCfg{Name: "dir", HasArg: true},
+	}
+
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "--future=x", "-vq", "--dir", "/tmp", "--later", "a", "-z=1",
+	}, optCfgs).IgnoreUnknownOpts(true)
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.True(t, conn.Cmd().HasOpt("verbose"))
+	assert.Equal(t, conn.Cmd().OptArg("dir"), "/tmp")
+	assert.False(t, conn.Cmd().HasOpt("future"))
+	assert.Equal(t, conn.Cmd().Args(), []string{"a"})
+	assert.Equal(t, conn.UnknownOpts(),
+		[]string{"--future=x", "-q", "--later", "-z=1"})
+
+	warnings := conn.Warnings()
+	assert.Equal(t, len(warnings), 4)
+	switch r := warnings[0].Reason().(type) {
+	case cliargs.UnconfiguredOption:
+		assert.Equal(t, r.Option, "future")
+	default:
+		assert.Fail(t, warnings[0].Error())
+	}
+	switch r := warnings[1].Reason().(type) {
+	case cliargs.UnconfiguredOption:
+		assert.Equal(t, r.Option, "q")
+	default:
+		assert.Fail(t, warnings[1].Error())
+	}
+}
+
+func TestUnknown_IgnoreUnknownOpts_optionStore(t *testing.T) {
+	type Options struct {
+		Verbose bool `optcfg:"verbose,v"`
+	}
+
+	options := Options{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions(
+		[]string{"app", "--future", "-v", "--", "--after"}, &options,
+	).IgnoreUnknownOpts(true)
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.True(t, options.Verbose)
+	assert.Equal(t, conn.UnknownOpts(), []string{"--future"})
+	assert.Equal(t, conn.Cmd().Args(), []string{"--after"})
+}
+
+func TestUnknown_strictByDefault(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "verbose"},
+	}
+
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "--future=x"}, optCfgs,
+	)
+
+	_, err := setupWithOptCfgs(t, ds)
+	switch r := err.Reason().(type) {
+	case cliargs.UnconfiguredOption:
+		assert.Equal(t, r.Option, "future")
+	default:
+		assert.Fail(t, err.Error())
+	}
+
+	ds = cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "--verbose"}, optCfgs,
+	)
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.UnknownOpts(), []string{})
+	assert.Equal(t, conn.Warnings(), []errs.Err{})
+}
+
+func TestUnknown_IgnoreUnknownOpts_optenv(t *testing.T) {
+	type Options struct {
+		Verbose bool   `optcfg:"verbose,v"`
+		Host    string `optcfg:"host" optenv:"MY_APP_HOST"`
+		Port    int    `optcfg:"port" optenv:"MY_APP_PORT"`
+	}
+
+	t.Setenv("MY_APP_HOST", "example.com")
+	t.Setenv("MY_APP_PORT", "8080")
+
+	options := Options{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions(
+		[]string{"app", "--future", "-v", "--port", "80", "-z=1"}, &options,
+	).IgnoreUnknownOpts(true)
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.True(t, options.Verbose)
+	assert.Equal(t, options.Host, "example.com")
+	assert.Equal(t, options.Port, 80)
+	assert.Equal(t, conn.UnknownOpts(), []string{"--future", "-z=1"})
+}
+
+func TestUnknown_strictByDefault_optenv(t *testing.T) {
+	type Options struct {
+		Port int `optcfg:"port" optenv:"MY_APP_PORT"`
+	}
+
+	t.Setenv("MY_APP_PORT", "8080")
+
+	options := Options{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions(
+		[]string{"app", "--prot", "80"}, &options,
+	)
+
+	_, err := setupWithOptCfgs(t, ds)
+	switch r := err.Reason().(type) {
+	case cliargdax.UnconfiguredOptionWithSuggestion:
+		assert.Equal(t, r.Option, "prot")
+		assert.Equal(t, r.Suggestions, []string{"port"})
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"strings"
+
+	"github.com/sttk/cliargs"
+)
+
+// defaultUsageOptLimit is the number of optional options above which they are
+// collapsed into "[OPTIONS]" in the usage line.
+const defaultUsageOptLimit = 8
+
+// UsageOptLimit is the method to set the number of optional options above
+// which they are collapsed into "[OPTIONS]" in the usage line retrieved by
+// DaxConn#UsageLine method.
+// If this limit is not set or is not positive, the limit is 8.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) UsageOptLimit(limit int) *DaxSrc {
+	ds.usageOptLimit = limit
+	return ds
+}
+
+// UsageLine is the method to retrieve the one-line synopsis of the command,
+// like "Usage: app [-v] [--output <FILE>] --name <NAME> <src> [<dst>]", for
+// example to print it with an error without the full help text.
+// The required options are written without brackets, and the other rules are
+// same as UsageLine function.
+// Hidden options are not written, and the options are made from the option
+// stores even if the parsing failed.
+func (conn *DaxConn) UsageLine() string {
+	conn.ds.parseLazily()
+	conn.ds.mutex.RLock()
+	defer conn.ds.mutex.RUnlock()
+
+	ds := conn.ds
+	cfgs, metas := ds.usageOptCfgs()
+	required := make(map[string]bool)
+	for name := range ds.requiredOpts {
+		required[name] = true
+	}
+	for name, m := range metas {
+		if m.required {
+			required[name] = true
+		}
+	}
+	limit := ds.usageOptLimit
+	if limit <= 0 {
+		limit = defaultUsageOptLimit
+	}
+	return usageLine(ds.hintCmdName(), cfgs, required, ds.argCfgs, limit)
+}
+
+// UsageLine is the function to make the one-line synopsis of the command from
+// the command name, the option configurations, and the positional argument
+// configurations.
+// Each option is written in brackets with its first short name, or its name
+// if it has no short alias, and with the placeholder of its option argument,
+// which is its ArgHelp or its upper-cased name, like [--output <FILE>].
+// Array options are followed by "...".
+// If the options are more than 8, they are collapsed into "[OPTIONS]".
+// The positional arguments are written in the same forms as the help text,
+// like <src> [<dst>]...
+// The result is determined only by the arguments, in the order of the
+// arrays.
+func UsageLine(
+	cmdName string, cfgs []cliargs.OptCfg, positionals []ArgCfg,
+) string {
+	return usageLine(cmdName, cfgs, nil, positionals, defaultUsageOptLimit)
+}
+
+func usageLine(
+	cmdName string, cfgs []cliargs.OptCfg, required map[string]bool,
+	argCfgs []ArgCfg, limit int,
+) string {
+	var all, reqs []string
+	optionals := 0
+	for _, cfg := range cfgs {
+		if cfg.Name == "*" {
+			continue
+		}
+		s := usageOpt(cfg)
+		if !required[cfg.Name] {
+			s = "[" + s + "]"
+			optionals++
+		}
+		if cfg.IsArray {
+			s += "..."
+		}
+		if required[cfg.Name] {
+			reqs = append(reqs, s)
+		}
+		all = append(all, s)
+	}
+
+	words := []string{cmdName}
+	if optionals > limit {
+		words = append(words, msg(MsgUsageOptions))
+		words = append(words, reqs...)
+	} else {
+		words = append(words, all...)
+	}
+	words = append(words, usageArgs(argCfgs)...)
+	return msg(MsgUsage, "command", strings.Join(words, " "))
+}
+
+// usageOpt makes the form of the option in the usage line, like -v or
+// --output <FILE>.
+func usageOpt(cfg cliargs.OptCfg) string {
+	s := optArg(cfg.Name)
+	for _, a := range cfg.Aliases {
+		if len([]rune(a)) == 1 {
+			s = optArg(a)
+			break
+		}
+	}
+	if cfg.HasArg {
+		s += " " + argPlaceholder(cfg)
+	}
+	return s
+}
+
+// usageArgs makes the forms of the positional arguments in the usage line,
+// like <src> and [<dst>]...
+func usageArgs(argCfgs []ArgCfg) []string {
+	words := make([]string, 0, len(argCfgs))
+	for _, a := range argCfgs {
+		s := "<" + a.Name + ">"
+		if a.Variadic {
+			s += "..."
+		}
+		if !a.Required {
+			s = "[" + s + "]"
+		}
+		words = append(words, s)
+	}
+	return words
+}
+
+// usageOptCfgs returns the option configurations which are not hidden and
+// the metadata of options.
+// The option configurations made from the option stores are not set if the
+// parsing failed, so they are made again in that case.
+func (ds *DaxSrc) usageOptCfgs() ([]cliargs.OptCfg, map[string]optMeta) {
+	cfgs, metas := ds.visibleOptCfgs(), ds.metas
+	if len(cfgs) > 0 {
+		return cfgs, metas
+	}
+	stores := ds.optionStores()
+	if len(stores) == 0 {
+		return cfgs, metas
+	}
+	all, metas, _, _ := ds.buildOptCfgsWithExtra(stores, ds.extraOptCfgs)
+	for _, cfg := range all {
+		if !metas[cfg.Name].hidden {
+			cfgs = append(cfgs, cfg)
+		}
+	}
+	return cfgs, metas
+}
@@ -0,0 +1,82 @@
+package cliargdax_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+)
+
+var usageOptCfgs = []cliargs.OptCfg{
+	cliargs.OptCfg{Name: "verbose", Aliases: []string{"v"}},
+	cliargs.OptCfg{Name: "output", HasArg: true, ArgHelp: "FILE"},
+	cliargs.OptCfg{Name: "log-level", Aliases: []string{"l"}, HasArg: true},
+	cliargs.OptCfg{Name: "tag", HasArg: true, IsArray: true},
+	cliargs.OptCfg{Name: "*"},
+}
+
+var usageArgCfgs = []cliargdax.ArgCfg{
+	cliargdax.ArgCfg{Name: "src", Required: true},
+	cliargdax.ArgCfg{Name: "dst", Variadic: true},
+}
+
+func TestUsageLine(t *testing.T) {
+	assert.Equal(t,
+		cliargdax.UsageLine("mytool", usageOptCfgs, usageArgCfgs),
+		"Usage: mytool [-v] [--output FILE] [-l <LOG_LEVEL>] [--tag <TAG>]... "+
+			"<src> [<dst>...]")
+	assert.Equal(t, cliargdax.UsageLine("mytool", nil, nil), "Usage: mytool")
+}
+
+func TestUsageLine_collapse(t *testing.T) {
+	var cfgs []cliargs.OptCfg
+	for _, name := range []string{"a", "b", "c", "d", "e", "f", "g", "h"} {
+		cfgs = append(cfgs, cliargs.OptCfg{Name: name})
+	}
+	assert.Equal(t, cliargdax.UsageLine("app", cfgs, nil),
+		"Usage: app [-a] [-b] [-c] [-d] [-e] [-f] [-g] [-h]")
+
+	cfgs = append(cfgs, cliargs.OptCfg{Name: "i"})
+	assert.Equal(t, cliargdax.UsageLine("app", cfgs, usageArgCfgs),
+		"Usage: app [OPTIONS] <src> [<dst>...]")
+}
+
+func TestDaxConn_UsageLine(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"/path/to/mytool", "--output=o", "a"}, usageOptCfgs,
+	).
+		RequiredOpts("output").
+		Hidden("tag").
+		PositionalCfgs(usageArgCfgs)
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.UsageLine(),
+		"Usage: mytool [-v] --output FILE [-l <LOG_LEVEL>] <src> [<dst>...]")
+
+	ds = cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"/path/to/mytool", "--output=o", "a"}, usageOptCfgs,
+	).
+		RequiredOpts("output").
+		UsageOptLimit(2)
+	conn, err = setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.UsageLine(), "Usage: mytool [OPTIONS] --output FILE")
+}
+
+func TestDaxConn_UsageLine_parseFailed(t *testing.T) {
+	type Options struct {
+		Name  string   `optcfg:"name,n" optrequired:"true"`
+		Files []string `optcfg:"file" optarg:"PATH"`
+		Count int      `optcfg:"count"`
+	}
+	options := Options{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions(
+		[]string{"app", "--count=x"}, &options,
+	).Lazy()
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.True(t, conn.ParseErr().IsNotOk())
+	assert.Equal(t, conn.UsageLine(),
+		"Usage: app -n <NAME> [--file PATH]... [--count <COUNT>]")
+}
@@ -0,0 +1,520 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/sttk/cliargs"
+	"github.com/sttk/sabi/errs"
+)
+
+type /* error reasons */ (
+	// RequiredOptionMissing is the error reason which indicates that options
+	// which are required are not given in command line arguments.
+	// The field Option is the first missing option name, and the field Options
+	// is the array of all missing option names.
+	RequiredOptionMissing struct {
+		Option  string
+		Options []string
+	}
+
+	// OptionRequiresOther is the error reason which indicates that an option is
+	// given in command line arguments but another option which the option
+	// requires is not given.
+	// The field Option is the given option name, and the field RequiredOption
+	// is the name of the option which is required but not given.
+	OptionRequiresOther struct {
+		Option         string
+		RequiredOption string
+	}
+
+	// OptionValueNotInChoices is the error reason which indicates that an
+	// option argument is not one of the choices of the option.
+	// The fields Option, Value, and Choices are the option name, the option
+	// argument, and the choices.
+	OptionValueNotInChoices struct {
+		Option  string
+		Value   string
+		Choices []string
+	}
+
+	// OptionValueOutOfRange is the error reason which indicates that an option
+	// argument is out of the range of the option.
+	// The fields Option, Value, Min, and Max are the option name, the option
+	// argument, and the inclusive boundaries of the range.
+	// If Min or Max is empty, the range has no lower or upper boundary.
+	OptionValueOutOfRange struct {
+		Option string
+		Value  string
+		Min    string
+		Max    string
+	}
+
+	// IllegalOptionRange is the error reason which indicates that the optmin
+	// or optmax struct tag of a field of an option store is invalid, because
+	// the field is not a number or the boundary cannot be converted to the
+	// field type.
+	// The fields Option, Field, Min, and Max are the option name, the field
+	// name, and the boundaries specified with the struct tags.
+	IllegalOptionRange struct {
+		Option string
+		Field  string
+		Min    string
+		Max    string
+	}
+
+	// OptionValidationFailed is the error reason which indicates that a
+	// validator function of an option returned an error.
+	// The fields Option, Value, and Cause are the option name, the option
+	// argument, and the error returned by the validator function, of the first
+	// failure.
+	// The field Failures is the array of all failures, at most one for each
+	// option.
+	OptionValidationFailed struct {
+		Option   string
+		Value    string
+		Cause    error
+		Failures []OptionValidationFailed
+	}
+)
+
+type optValidator struct {
+	option    string
+	validator func(value string) error
+}
+
+type optDependency struct {
+	option   string
+	required string
+}
+
+// RequiredOpts is the method to set names of options which must be given in
+// command line arguments.
+// After parsing, if a required option is not given and has no default value,
+// Setup method returns an errs.Err with the reason: RequiredOptionMissing.
+// This method is for DaxSrc instances with cliargs.OptCfg arrays.
+// For option stores, the struct tag: optrequired:"true" is also available.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) RequiredOpts(names ...string) *DaxSrc {
+	if ds.requiredOpts == nil {
+		ds.requiredOpts = make(map[string]bool, len(names))
+	}
+	for _, name := range names {
+		ds.requiredOpts[name] = true
+	}
+	return ds
+}
+
+// Requires is the method to declare that the option specified as the first
+// argument requires the options specified as the rest arguments.
+// After parsing, if the option is given in command line arguments but one of
+// the required options is not, Setup method returns an errs.Err with the
+// reason: OptionRequiresOther.
+// An option which has a value only from its default value, an environment
+// variable, or the config file does not require the other options, but such
+// a value of a required option satisfies the requirement.
+// Requirements can be chained by calling this method multiple times for the
+// same option, and they are checked in the declaration order.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) Requires(name string, requiredNames ...string) *DaxSrc {
+	for _, req := range requiredNames {
+		ds.dependencies = append(ds.dependencies, optDependency{
+			option: name, required: req,
+		})
+	}
+	return ds
+}
+
+// Choices is the method to set the choices of option arguments of the
+// option specified as the first argument.
+// After parsing, if an option argument of the option is not one of the
+// choices, Setup method returns an errs.Err with the reason:
+// OptionValueNotInChoices.
+// If the ArgHelp field of the option's cliargs.OptCfg is empty, it is set to
+// the enumeration of the choices, like {json|yaml|table}, so that help texts
+// can show them.
+// For option stores, the struct tag: optchoices:"json,yaml,table" is also
+// available.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) Choices(name string, choices ...string) *DaxSrc {
+	if ds.choices == nil {
+		ds.choices = make(map[string]optChoices)
+	}
+	ds.choices[name] = optChoices{values: choices}
+	return ds
+}
+
+// ChoicesFold is the method to set the choices of option arguments of the
+// option specified as the first argument, which are matched
+// case-insensitively.
+// For option stores, the struct tag: optchoicesfold:"true" with optchoices
+// is also available.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) ChoicesFold(name string, choices ...string) *DaxSrc {
+	if ds.choices == nil {
+		ds.choices = make(map[string]optChoices)
+	}
+	ds.choices[name] = optChoices{values: choices, fold: true}
+	return ds
+}
+
+// Validate is the method to add a validator function for the option
+// specified as the first argument.
+// After parsing, validator functions are invoked with each option argument of
+// the option, including ones given by its aliases, the environment variable,
+// or the default value.
+// If the option takes no option argument, validator functions are invoked
+// with an empty string once.
+// If the option name is "*", the validator function is applied to every
+// option given in command line arguments.
+// The validator functions of an option are invoked in the declaration order,
+// and stop at the first failure.
+// If validator functions of some options fail, Setup method returns an
+// errs.Err with the reason: OptionValidationFailed.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) Validate(
+	name string, validator func(value string) error,
+) *DaxSrc {
+	ds.validators = append(ds.validators, optValidator{
+		option: name, validator: validator,
+	})
+	return ds
+}
+
+// applyMetas merges per-option settings of this DaxSrc into the metadata of
+// the parse result, and decorates the OptCfg array with them.
+func (ds *DaxSrc) applyMetas(r *parseResult) {
+	if r.metas == nil {
+		r.metas = make(map[string]optMeta, len(r.optCfgs))
+	}
+
+	var cfgs []cliargs.OptCfg
+	for i, cfg := range r.optCfgs {
+		m := r.metas[cfg.Name]
+		if ds.requiredOpts[cfg.Name] {
+			m.required = true
+		}
+		if c, exists := ds.choices[cfg.Name]; exists {
+			m.choices = c
+		}
+		if ds.negatables[cfg.Name] && !cfg.HasArg {
+			m.negatable = true
+		}
+		if ds.fromFileOpts[cfg.Name] {
+			m.fromFile = true
+		}
+		if ds.hiddenOpts[cfg.Name] {
+			m.hidden = true
+		}
+		if d, exists := ds.deprecatedOpts[cfg.Name]; exists {
+			m.deprecated = true
+			m.deprecation = d
+		}
+		if meta, exists := ds.optMetas[cfg.Name]; exists {
+			if cfgs == nil {
+				cfgs = append([]cliargs.OptCfg{}, r.optCfgs...)
+			}
+			mergeOptMeta(&cfgs[i], &m, meta)
+		}
+		r.metas[cfg.Name] = m
+	}
+	if cfgs != nil {
+		r.optCfgs = cfgs
+	}
+
+	r.optCfgs = setChoicesArgHelp(r.optCfgs, r.metas)
+}
+
+// setChoicesArgHelp sets the choices of options as the argument help, like
+// {a|b|c}, to the option configurations of which argument helps are empty.
+// If any argument help is set, this function returns a modified copy of the
+// option configurations.
+func setChoicesArgHelp(
+	optCfgs []cliargs.OptCfg, metas map[string]optMeta,
+) []cliargs.OptCfg {
+	var cfgs []cliargs.OptCfg
+	for i, cfg := range optCfgs {
+		m := metas[cfg.Name]
+		if len(m.choices.values) > 0 && len(cfg.ArgHelp) == 0 && cfg.HasArg {
+			if cfgs == nil {
+				cfgs = append([]cliargs.OptCfg{}, optCfgs...)
+			}
+			cfgs[i].ArgHelp = "{" + strings.Join(m.choices.values, "|") + "}"
+		}
+	}
+	if cfgs == nil {
+		return optCfgs
+	}
+	return cfgs
+}
+
+func (ds *DaxSrc) validate(r parseResult) errs.Err {
+	err := checkRequired(r)
+	if err.IsNotOk() {
+		return err
+	}
+	err = ds.checkDependencies(r)
+	if err.IsNotOk() {
+		return err
+	}
+	err = checkChoices(r)
+	if err.IsNotOk() {
+		return err
+	}
+	err = checkRanges(r)
+	if err.IsNotOk() {
+		return err
+	}
+	if len(ds.subCmds) == 0 {
+		err = checkPositionals(r, ds.argCfgs)
+		if err.IsNotOk() {
+			return err
+		}
+	}
+	return ds.runValidators(r)
+}
+
+func checkRequired(r parseResult) errs.Err {
+	var missing []string
+
+	for _, cfg := range r.optCfgs {
+		m := r.metas[cfg.Name]
+		if !m.required {
+			continue
+		}
+		if r.cmd.HasOpt(cfg.Name) || len(m.defaults) > 0 {
+			continue
+		}
+		missing = append(missing, cfg.Name)
+	}
+
+	if len(missing) > 0 {
+		return errs.New(RequiredOptionMissing{
+			Option: missing[0], Options: missing,
+		})
+	}
+
+	return errs.Ok()
+}
+
+func (ds *DaxSrc) checkDependencies(r parseResult) errs.Err {
+	for _, dep := range ds.dependencies {
+		src := r.sources[dep.option]
+		isGiven := src == SourceCLI || src == SourceAlias
+		if isGiven && !r.cmd.HasOpt(dep.required) {
+			return errs.New(OptionRequiresOther{
+				Option: dep.option, RequiredOption: dep.required,
+			})
+		}
+	}
+	return errs.Ok()
+}
+
+func checkChoices(r parseResult) errs.Err {
+	for _, cfg := range r.optCfgs {
+		m := r.metas[cfg.Name]
+		c := m.choices
+		if len(c.values) == 0 {
+			continue
+		}
+		for _, v := range m.elements(r.cmd.OptArgs(cfg.Name)) {
+			if !c.contains(v) {
+				return errs.New(OptionValueNotInChoices{
+					Option: cfg.Name, Value: v, Choices: c.values,
+				})
+			}
+		}
+	}
+	return errs.Ok()
+}
+
+type optChoices struct {
+	values []string
+	fold   bool
+}
+
+func (c optChoices) contains(v string) bool {
+	for _, s := range c.values {
+		if s == v || (c.fold && strings.EqualFold(s, v)) {
+			return true
+		}
+	}
+	return false
+}
+
+func checkRanges(r parseResult) errs.Err {
+	for _, cfg := range r.optCfgs {
+		m := r.metas[cfg.Name]
+		rng := m.rng
+		if rng == nil {
+			continue
+		}
+		for _, v := range m.elements(r.cmd.OptArgs(cfg.Name)) {
+			if !rng.contains(v) {
+				return errs.New(OptionValueOutOfRange{
+					Option: cfg.Name, Value: v, Min: rng.min, Max: rng.max,
+				})
+			}
+		}
+	}
+	return errs.Ok()
+}
+
+// optRange is the range of the option arguments of an option, of which the
+// bounds and the option arguments are compared as the kind of the field, so
+// that integers are compared without being converted to floating point
+// numbers.
+type optRange struct {
+	min, max string
+	compare  func(a, b string) (int, error)
+}
+
+func newOptRange(
+	name, field string, t reflect.Type, min, max string,
+) (*optRange, errs.Err) {
+	if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+
+	var compare func(a, b string) (int, error)
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64:
+		compare = func(a, b string) (int, error) {
+			x, e := strconv.ParseInt(a, 0, t.Bits())
+			if e != nil {
+				return 0, e
+			}
+			y, e := strconv.ParseInt(b, 0, t.Bits())
+			return compareNums(x, y), e
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32,
+		reflect.Uint64:
+		compare = func(a, b string) (int, error) {
+			x, e := strconv.ParseUint(a, 0, t.Bits())
+			if e != nil {
+				return 0, e
+			}
+			y, e := strconv.ParseUint(b, 0, t.Bits())
+			return compareNums(x, y), e
+		}
+	case reflect.Float32, reflect.Float64:
+		compare = func(a, b string) (int, error) {
+			x, e := strconv.ParseFloat(a, t.Bits())
+			if e != nil {
+				return 0, e
+			}
+			y, e := strconv.ParseFloat(b, t.Bits())
+			return compareNums(x, y), e
+		}
+	default:
+		return nil, errs.New(IllegalOptionRange{
+			Option: name, Field: field, Min: min, Max: max,
+		})
+	}
+
+	for _, b := range []string{min, max} {
+		if len(b) == 0 {
+			continue
+		}
+		if _, e := compare(b, b); e != nil {
+			return nil, errs.New(IllegalOptionRange{
+				Option: name, Field: field, Min: min, Max: max,
+			}, e)
+		}
+	}
+
+	return &optRange{min: min, max: max, compare: compare}, errs.Ok()
+}
+
+func compareNums[T int64 | uint64 | float64](x, y T) int {
+	switch {
+	case x < y:
+		return -1
+	case x > y:
+		return 1
+	default:
+		return 0
+	}
+}
+
+func (rng *optRange) contains(v string) bool {
+	if len(rng.min) > 0 {
+		if c, _ := rng.compare(v, rng.min); c < 0 {
+			return false
+		}
+	}
+	if len(rng.max) > 0 {
+		if c, _ := rng.compare(v, rng.max); c > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (ds *DaxSrc) runValidators(r parseResult) errs.Err {
+	if len(ds.validators) == 0 {
+		return errs.Ok()
+	}
+
+	var failures []OptionValidationFailed
+
+	for _, name := range givenOptNames(r) {
+		values := r.cmd.OptArgs(name)
+		if len(values) == 0 {
+			values = []string{""}
+		}
+
+	validators:
+		for _, v := range ds.validators {
+			if v.option != name && v.option != "*" {
+				continue
+			}
+			for _, value := range values {
+				if e := v.validator(value); e != nil {
+					failures = append(failures, OptionValidationFailed{
+						Option: name, Value: value, Cause: e,
+					})
+					break validators
+				}
+			}
+		}
+	}
+
+	if len(failures) > 0 {
+		f := failures[0]
+		f.Failures = failures
+		return errs.New(f, f.Cause)
+	}
+
+	return errs.Ok()
+}
+
+// givenOptNames returns the names of options given in command line arguments.
+// The names of configured options are in the order of the configurations, and
+// the names of other options accepted by the wildcard configuration follow in
+// the order of their first occurrences.
+func givenOptNames(r parseResult) []string {
+	var names []string
+	added := make(map[string]bool)
+	add := func(name string) {
+		if !added[name] && r.cmd.HasOpt(name) {
+			added[name] = true
+			names = append(names, name)
+		}
+	}
+	for _, cfg := range r.optCfgs {
+		add(cfg.Name)
+	}
+	for _, tok := range scanArgsWith(r.osArgs, r.optCfgs) {
+		if tok.isOpt() {
+			add(tok.name)
+		}
+	}
+	return names
+}
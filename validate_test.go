@@ -0,0 +1,547 @@
+package cliargdax_test
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+	"github.com/sttk/sabi/errs"
+)
+
+func setupWithOptCfgs(
+	t *testing.T, ds *cliargdax.DaxSrc,
+) (*cliargdax.DaxConn, errs.Err) {
+	err := ds.Setup(&noopAsyncGroup{})
+	t.Cleanup(ds.Close)
+	if err.IsNotOk() {
+		return nil, err
+	}
+	dc, err := ds.CreateDaxConn()
+	assert.True(t, err.IsOk())
+	return dc.(*cliargdax.DaxConn), err
+}
+
+func TestValidate_RequiredOpts_ok(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "foo", Aliases: []string{"f"}},
+		cliargs.OptCfg{Name: "bar", HasArg: true},
+		cliargs.OptCfg{Name: "baz", HasArg: true, Default: []string{"1"}},
+	}
+
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "-f", "--bar=2"}, optCfgs,
+	).RequiredOpts("foo", "bar", "baz")
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.True(t, conn.Cmd().HasOpt("foo"))
+}
+
+func TestValidate_RequiredOpts_missing(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "foo"},
+		cliargs.OptCfg{Name: "bar", HasArg: true},
+		cliargs.OptCfg{Name: "baz", HasArg: true},
+		cliargs.OptCfg{Name: "qux", HasArg: true, Default: []string{"1"}},
+	}
+
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "--bar=1"}, optCfgs,
+	).RequiredOpts("foo", "bar").RequiredOpts("baz", "qux")
+
+	_, err := setupWithOptCfgs(t, ds)
+	switch r := err.Reason().(type) {
+	case cliargdax.RequiredOptionMissing:
+		assert.Equal(t, r.Option, "foo")
+		assert.Equal(t, r.Options, []string{"foo", "baz"})
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestValidate_optrequired(t *testing.T) {
+	type Options struct {
+		Foo  bool   `optcfg:"foo" optrequired:"true"`
+		Bar  int    `optcfg:"bar" optrequired:"true" optdefault:"1"`
+		Baz  string `optcfg:"baz" optrequired:"true" optenv:"MY_APP_BAZ"`
+		Qux  string `optcfg:"qux" optrequired:"true"`
+		Quux string `optcfg:"quux" optrequired:"false"`
+	}
+
+	options := Options{}
+	_, err := setupForOptions(t, []string{"app"}, &options)
+	switch r := err.Reason().(type) {
+	case cliargdax.RequiredOptionMissing:
+		assert.Equal(t, r.Option, "foo")
+		assert.Equal(t, r.Options, []string{"foo", "baz", "qux"})
+	default:
+		assert.Fail(t, err.Error())
+	}
+
+	t.Setenv("MY_APP_BAZ", "x")
+
+	options = Options{}
+	_, err = setupForOptions(t, []string{"app", "--foo", "--qux=y"}, &options)
+	assert.True(t, err.IsOk())
+	assert.True(t, options.Foo)
+	assert.Equal(t, options.Bar, 1)
+	assert.Equal(t, options.Baz, "x")
+	assert.Equal(t, options.Qux, "y")
+}
+
+func TestValidate_RequiredOpts_lazy(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "foo"},
+	}
+
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app"}, optCfgs,
+	).RequiredOpts("foo").Lazy()
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	switch r := conn.ParseErr().Reason().(type) {
+	case cliargdax.RequiredOptionMissing:
+		assert.Equal(t, r.Option, "foo")
+		assert.Equal(t, r.Options, []string{"foo"})
+	default:
+		assert.Fail(t, conn.ParseErr().Error())
+	}
+}
+
+func TestValidate_Requires_ok(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "tls"},
+		cliargs.OptCfg{Name: "tls-cert", HasArg: true},
+		cliargs.OptCfg{Name: "tls-key", HasArg: true},
+	}
+
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "--tls", "--tls-cert=a", "--tls-key=b"}, optCfgs,
+	).Requires("tls-cert", "tls").Requires("tls-cert", "tls-key")
+
+	_, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	ds = cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "--tls"}, optCfgs,
+	).Requires("tls-cert", "tls", "tls-key")
+
+	_, err = setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+}
+
+func TestValidate_Requires_error(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "tls"},
+		cliargs.OptCfg{Name: "tls-cert", HasArg: true},
+		cliargs.OptCfg{Name: "tls-key", HasArg: true},
+	}
+
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "--tls-cert=a"}, optCfgs,
+	).Requires("tls-cert", "tls").Requires("tls-cert", "tls-key")
+
+	_, err := setupWithOptCfgs(t, ds)
+	switch r := err.Reason().(type) {
+	case cliargdax.OptionRequiresOther:
+		assert.Equal(t, r.Option, "tls-cert")
+		assert.Equal(t, r.RequiredOption, "tls")
+	default:
+		assert.Fail(t, err.Error())
+	}
+
+	ds = cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "--tls-cert=a", "--tls"}, optCfgs,
+	).Requires("tls-cert", "tls", "tls-key")
+
+	_, err = setupWithOptCfgs(t, ds)
+	switch r := err.Reason().(type) {
+	case cliargdax.OptionRequiresOther:
+		assert.Equal(t, r.Option, "tls-cert")
+		assert.Equal(t, r.RequiredOption, "tls-key")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestValidate_Requires_default(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "tls"},
+		cliargs.OptCfg{Name: "tls-cert", HasArg: true, Default: []string{"a"}},
+		cliargs.OptCfg{Name: "tls-key", HasArg: true, Default: []string{"b"}},
+	}
+
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app"}, optCfgs,
+	).Requires("tls-cert", "tls")
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.Cmd().OptArg("tls-cert"), "a")
+
+	ds = cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "--tls", "--tls-cert=c"}, optCfgs,
+	).Requires("tls-cert", "tls", "tls-key")
+
+	_, err = setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+
+	ds = cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "--tls-cert=c"}, optCfgs,
+	).Requires("tls-cert", "tls")
+
+	_, err = setupWithOptCfgs(t, ds)
+	switch r := err.Reason().(type) {
+	case cliargdax.OptionRequiresOther:
+		assert.Equal(t, r.Option, "tls-cert")
+		assert.Equal(t, r.RequiredOption, "tls")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestValidate_Requires_forOptions(t *testing.T) {
+	type Options struct {
+		Export    bool   `optcfg:"export"`
+		OutputDir string `optcfg:"output-dir,o"`
+	}
+
+	options := Options{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions(
+		[]string{"app", "-o", "dir"}, &options,
+	).Requires("output-dir", "export")
+
+	_, err := setupWithOptCfgs(t, ds)
+	switch r := err.Reason().(type) {
+	case cliargdax.OptionRequiresOther:
+		assert.Equal(t, r.Option, "output-dir")
+		assert.Equal(t, r.RequiredOption, "export")
+	default:
+		assert.Fail(t, err.Error())
+	}
+
+	options = Options{}
+	ds = cliargdax.NewDaxSrcWithArgsForOptions(
+		[]string{"app", "-o", "dir", "--export"}, &options,
+	).Requires("output-dir", "export")
+
+	_, err = setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, options.OutputDir, "dir")
+}
+
+func TestValidate_Choices_ok(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "format", HasArg: true},
+		cliargs.OptCfg{Name: "level", HasArg: true, ArgHelp: "<level>"},
+	}
+
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "--format", "yaml", "--level=WARN"}, optCfgs,
+	).Choices("format", "json", "yaml", "table").
+		ChoicesFold("level", "debug", "info", "warn")
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.Cmd().OptArg("format"), "yaml")
+	assert.Equal(t, conn.Cmd().OptArg("level"), "WARN")
+
+	cfgs := conn.OptCfgs()
+	assert.Equal(t, cfgs[0].ArgHelp, "{json|yaml|table}")
+	assert.Equal(t, cfgs[1].ArgHelp, "<level>")
+	assert.Equal(t, optCfgs[0].ArgHelp, "")
+}
+
+func TestValidate_Choices_error(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "format", HasArg: true, IsArray: true},
+	}
+
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "--format", "json", "--format", "YAML"}, optCfgs,
+	).Choices("format", "json", "yaml", "table")
+
+	_, err := setupWithOptCfgs(t, ds)
+	switch r := err.Reason().(type) {
+	case cliargdax.OptionValueNotInChoices:
+		assert.Equal(t, r.Option, "format")
+		assert.Equal(t, r.Value, "YAML")
+		assert.Equal(t, r.Choices, []string{"json", "yaml", "table"})
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestValidate_optchoices(t *testing.T) {
+	type Options struct {
+		Format string `optcfg:"format=json" optchoices:"json,yaml,table"`
+		Level  string `optcfg:"level" optchoices:"debug,info" optchoicesfold:"true"`
+	}
+
+	options := Options{}
+	conn, err := setupForOptions(t, []string{"app", "--level=Info"}, &options)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, options.Format, "json")
+	assert.Equal(t, options.Level, "Info")
+	assert.Equal(t, conn.OptCfgs()[0].ArgHelp, "{json|yaml|table}")
+	assert.Equal(t, conn.OptCfgs()[1].ArgHelp, "{debug|info}")
+
+	options = Options{}
+	_, err = setupForOptions(t, []string{"app", "--format=Json"}, &options)
+	switch r := err.Reason().(type) {
+	case cliargdax.OptionValueNotInChoices:
+		assert.Equal(t, r.Option, "format")
+		assert.Equal(t, r.Value, "Json")
+		assert.Equal(t, r.Choices, []string{"json", "yaml", "table"})
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestValidate_optminOptmax(t *testing.T) {
+	type Options struct {
+		Port  int     `optcfg:"port=8080" optmin:"1" optmax:"65535"`
+		Ratio float64 `optcfg:"ratio" optmin:"0.0" optmax:"1.0"`
+		Sizes []uint  `optcfg:"sizes" optmax:"10"`
+		Level int8    `optcfg:"level" optmin:"-3"`
+	}
+
+	options := Options{}
+	_, err := setupForOptions(t, []string{
+		"app", "--port=65535", "--ratio=0", "--sizes=10", "--level=-3",
+	}, &options)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, options.Port, 65535)
+	assert.Equal(t, options.Ratio, 0.0)
+	assert.Equal(t, options.Sizes, []uint{10})
+	assert.Equal(t, options.Level, int8(-3))
+
+	options = Options{}
+	_, err = setupForOptions(t, []string{
+		"app", "--sizes=3", "--sizes=11",
+	}, &options)
+	switch r := err.Reason().(type) {
+	case cliargdax.OptionValueOutOfRange:
+		assert.Equal(t, r.Option, "sizes")
+		assert.Equal(t, r.Value, "11")
+		assert.Equal(t, r.Min, "")
+		assert.Equal(t, r.Max, "10")
+	default:
+		assert.Fail(t, err.Error())
+	}
+
+	options = Options{}
+	_, err = setupForOptions(t, []string{"app", "--ratio=1.5"}, &options)
+	switch r := err.Reason().(type) {
+	case cliargdax.OptionValueOutOfRange:
+		assert.Equal(t, r.Option, "ratio")
+		assert.Equal(t, r.Value, "1.5")
+		assert.Equal(t, r.Min, "0.0")
+		assert.Equal(t, r.Max, "1.0")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestValidate_optminOptmax_largeIntegers(t *testing.T) {
+	type Options struct {
+		Num  int64  `optcfg:"num" optmax:"9223372036854775806"`
+		Size uint64 `optcfg:"size" optmin:"18446744073709551614"`
+	}
+
+	options := Options{}
+	_, err := setupForOptions(t, []string{
+		"app", "--num=9223372036854775806", "--size=18446744073709551615",
+	}, &options)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, options.Num, int64(math.MaxInt64-1))
+	assert.Equal(t, options.Size, uint64(math.MaxUint64))
+
+	options = Options{}
+	_, err = setupForOptions(t, []string{
+		"app", "--num=9223372036854775807",
+	}, &options)
+	switch r := err.Reason().(type) {
+	case cliargdax.OptionValueOutOfRange:
+		assert.Equal(t, r.Option, "num")
+		assert.Equal(t, r.Value, "9223372036854775807")
+	default:
+		assert.Fail(t, err.Error())
+	}
+
+	options = Options{}
+	_, err = setupForOptions(t, []string{
+		"app", "--size=18446744073709551613",
+	}, &options)
+	switch r := err.Reason().(type) {
+	case cliargdax.OptionValueOutOfRange:
+		assert.Equal(t, r.Option, "size")
+		assert.Equal(t, r.Value, "18446744073709551613")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestValidate_optminOptmax_defaultOutOfRange(t *testing.T) {
+	type Options struct {
+		Port int `optcfg:"port=0" optmin:"1" optmax:"65535"`
+	}
+
+	options := Options{}
+	_, err := setupForOptions(t, []string{"app", "--port=80"}, &options)
+	switch r := err.Reason().(type) {
+	case cliargdax.OptionValueOutOfRange:
+		assert.Equal(t, r.Option, "port")
+		assert.Equal(t, r.Value, "0")
+		assert.Equal(t, r.Min, "1")
+		assert.Equal(t, r.Max, "65535")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestValidate_optminOptmax_illegalRange(t *testing.T) {
+	type Options struct {
+		Name string `optcfg:"name" optmin:"1"`
+	}
+
+	options := Options{}
+	_, err := setupForOptions(t, []string{"app"}, &options)
+	switch r := err.Reason().(type) {
+	case cliargdax.IllegalOptionRange:
+		assert.Equal(t, r.Option, "name")
+		assert.Equal(t, r.Field, "Name")
+		assert.Equal(t, r.Min, "1")
+		assert.Equal(t, r.Max, "")
+	default:
+		assert.Fail(t, err.Error())
+	}
+
+	type Options2 struct {
+		Port int `optcfg:"port" optmax:"x"`
+	}
+
+	options2 := Options2{}
+	_, err = setupForOptions(t, []string{"app"}, &options2)
+	switch r := err.Reason().(type) {
+	case cliargdax.IllegalOptionRange:
+		assert.Equal(t, r.Option, "port")
+		assert.Equal(t, r.Field, "Port")
+		assert.Equal(t, r.Max, "x")
+	default:
+		assert.Fail(t, err.Error())
+	}
+}
+
+func TestValidate_Validate(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "config", Aliases: []string{"c"}, HasArg: true},
+		cliargs.OptCfg{Name: "tag", HasArg: true, IsArray: true},
+	}
+
+	var called []string
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "-c", "a.yml", "--tag", "x", "--tag", "y"}, optCfgs,
+	).Validate("config", func(v string) error {
+		called = append(called, "config:"+v)
+		return nil
+	}).Validate("tag", func(v string) error {
+		called = append(called, "tag:"+v)
+		return nil
+	})
+
+	_, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, called, []string{"config:a.yml", "tag:x", "tag:y"})
+}
+
+func TestValidate_Validate_error(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "config", HasArg: true},
+		cliargs.OptCfg{Name: "tag", HasArg: true, IsArray: true},
+		cliargs.OptCfg{Name: "name", HasArg: true},
+	}
+
+	errNotYaml := errors.New("not yaml")
+	errEmpty := errors.New("empty")
+	var called []string
+
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "--config=a.json", "--tag=", "--tag=", "--name=foo",
+	}, optCfgs).Validate("config", func(v string) error {
+		called = append(called, "config1:"+v)
+		if !strings.HasSuffix(v, ".yml") {
+			return errNotYaml
+		}
+		return nil
+	}).Validate("config", func(v string) error {
+		called = append(called, "config2:"+v)
+		return nil
+	}).Validate("tag", func(v string) error {
+		called = append(called, "tag:"+v)
+		if len(v) == 0 {
+			return errEmpty
+		}
+		return nil
+	}).Validate("name", func(v string) error {
+		called = append(called, "name:"+v)
+		return nil
+	})
+
+	_, err := setupWithOptCfgs(t, ds)
+	switch r := err.Reason().(type) {
+	case cliargdax.OptionValidationFailed:
+		assert.Equal(t, r.Option, "config")
+		assert.Equal(t, r.Value, "a.json")
+		assert.Equal(t, r.Cause, errNotYaml)
+		assert.Equal(t, len(r.Failures), 2)
+		assert.Equal(t, r.Failures[0].Option, "config")
+		assert.Equal(t, r.Failures[1].Option, "tag")
+		assert.Equal(t, r.Failures[1].Value, "")
+		assert.Equal(t, r.Failures[1].Cause, errEmpty)
+	default:
+		assert.Fail(t, err.Error())
+	}
+	assert.Equal(t, errors.Unwrap(err), errNotYaml)
+	assert.Equal(t, called, []string{"config1:a.json", "tag:", "name:foo"})
+}
+
+func TestValidate_Validate_wildcard(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "foo", HasArg: true},
+		cliargs.OptCfg{Name: "bar"},
+		cliargs.OptCfg{Name: "baz", HasArg: true},
+	}
+
+	var called []string
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs(
+		[]string{"app", "--bar", "--foo=1"}, optCfgs,
+	).Validate("*", func(v string) error {
+		called = append(called, v)
+		return nil
+	})
+
+	_, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, called, []string{"1", ""})
+}
+
+func TestValidate_Validate_wildcardWithoutOptCfgs(t *testing.T) {
+	var called []string
+	ds := cliargdax.NewDaxSrcWithArgs(
+		[]string{"app", "-ab", "--foo=1", "--foo=2", "--", "--bar"},
+	).Validate("*", func(v string) error {
+		called = append(called, v)
+		return nil
+	})
+
+	_, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, called, []string{"", "", "1", "2"})
+}
@@ -0,0 +1,87 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"strings"
+
+	"github.com/sttk/cliargs"
+)
+
+// VersionInfo is the struct to hold the metadata of an application which is
+// printed as the version text.
+// The field Version is required, and the other fields are printed only if
+// they are not empty.
+type VersionInfo struct {
+	Version   string
+	Commit    string
+	BuildDate string
+}
+
+// String is the method to make the version text from this VersionInfo.
+// The text consists of the version in the first line, and the commit hash
+// and the build date in the following lines.
+func (info VersionInfo) String() string {
+	lines := []string{info.Version}
+	if len(info.Commit) > 0 {
+		lines = append(lines, msg(MsgVersionCommit, "commit", info.Commit))
+	}
+	if len(info.BuildDate) > 0 {
+		lines = append(lines, msg(MsgVersionBuilt, "date", info.BuildDate))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// EnableVersionOpt is the method to enable the option to request the version
+// text, which is the version string specified as the first argument.
+// The option name and aliases can be specified as the rest arguments, and
+// they are version and V by default.
+// When the option is given in command line arguments,
+// DaxConn#VersionRequested method returns true, and errors of parsing and
+// validation are suppressed in the same way as the help option.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) EnableVersionOpt(
+	version string, nameAndAliases ...string,
+) *DaxSrc {
+	return ds.EnableVersionInfo(VersionInfo{Version: version}, nameAndAliases...)
+}
+
+// EnableVersionInfo is the method to enable the option to request the
+// version text, which is made from the VersionInfo specified as the first
+// argument.
+// Except for the version text, this method is same as EnableVersionOpt
+// method.
+// This method returns this DaxSrc instance itself for method chaining.
+func (ds *DaxSrc) EnableVersionInfo(
+	info VersionInfo, nameAndAliases ...string,
+) *DaxSrc {
+	if len(nameAndAliases) == 0 {
+		nameAndAliases = []string{"version", "V"}
+	}
+	ds.versionOpt = &cliargs.OptCfg{
+		Name:    nameAndAliases[0],
+		Aliases: nameAndAliases[1:],
+		Desc:    msg(MsgVersionOptDesc),
+	}
+	ds.versionInfo = info
+	return ds
+}
+
+// VersionRequested is the method to check whether the option enabled by
+// DaxSrc#EnableVersionOpt or DaxSrc#EnableVersionInfo method is given in
+// command line arguments.
+func (conn *DaxConn) VersionRequested() bool {
+	conn.ds.parseLazily()
+	conn.ds.mutex.RLock()
+	defer conn.ds.mutex.RUnlock()
+	return conn.ds.versionRequested
+}
+
+// Version is the method to retrieve the version text, which is the version
+// string specified with DaxSrc#EnableVersionOpt method or the text made from
+// the VersionInfo specified with DaxSrc#EnableVersionInfo method.
+func (conn *DaxConn) Version() string {
+	return conn.ds.versionInfo.String()
+}
@@ -0,0 +1,75 @@
+package cliargdax_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+)
+
+func TestVersion_EnableVersionOpt(t *testing.T) {
+	optCfgs := []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "foo"},
+	}
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{"app", "-V"}, optCfgs).
+		EnableVersionOpt("1.2.3")
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.True(t, conn.VersionRequested())
+	assert.False(t, conn.HelpRequested())
+	assert.Equal(t, conn.Version(), "1.2.3")
+
+	cfgs := conn.OptCfgs()
+	assert.Equal(t, len(cfgs), 2)
+	assert.Equal(t, cfgs[1].Name, "version")
+	assert.Equal(t, cfgs[1].Aliases, []string{"V"})
+}
+
+func TestVersion_EnableVersionOpt_nameAndAliases(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{"app", "--ver"},
+		[]cliargs.OptCfg{}).EnableVersionOpt("1.0", "ver", "v")
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.True(t, conn.VersionRequested())
+	assert.Equal(t, conn.OptCfgs()[1].Name, "ver")
+	assert.Equal(t, conn.OptCfgs()[1].Aliases, []string{"v"})
+}
+
+func TestVersion_EnableVersionOpt_notGiven(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{"app"},
+		[]cliargs.OptCfg{cliargs.OptCfg{Name: "foo"}}).EnableVersionOpt("1.0")
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.False(t, conn.VersionRequested())
+}
+
+func TestVersion_EnableVersionOpt_suppressErrors(t *testing.T) {
+	type Options struct {
+		Name string `optcfg:"name" optrequired:"true"`
+	}
+
+	options := Options{}
+	ds := cliargdax.NewDaxSrcWithArgsForOptions(
+		[]string{"app", "--version"}, &options,
+	).EnableVersionOpt("1.0")
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.True(t, conn.VersionRequested())
+}
+
+func TestVersion_EnableVersionInfo(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{"app", "--version"},
+		[]cliargs.OptCfg{}).EnableVersionInfo(cliargdax.VersionInfo{
+		Version: "app 2.0.0", Commit: "abc1234", BuildDate: "2023-06-01",
+	})
+
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.True(t, conn.VersionRequested())
+	assert.Equal(t, conn.Version(), "app 2.0.0\ncommit: abc1234\nbuilt: 2023-06-01")
+}
@@ -0,0 +1,74 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"unicode"
+)
+
+// wideChars is the table of the characters of which East Asian Width is Wide
+// or Fullwidth, which occupy two cells in a terminal.
+var wideChars = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{0x1100, 0x115f, 1}, // Hangul Jamo
+		{0x231a, 0x231b, 1},
+		{0x2329, 0x232a, 1},
+		{0x2e80, 0x303e, 1}, // CJK Radicals, Kangxi, CJK Symbols
+		{0x3041, 0x33ff, 1}, // Hiragana, Katakana, Bopomofo, CJK Compatibility
+		{0x3400, 0x4dbf, 1}, // CJK Unified Ideographs Extension A
+		{0x4e00, 0x9fff, 1}, // CJK Unified Ideographs
+		{0xa000, 0xa4cf, 1}, // Yi
+		{0xa960, 0xa97f, 1}, // Hangul Jamo Extended-A
+		{0xac00, 0xd7a3, 1}, // Hangul Syllables
+		{0xf900, 0xfaff, 1}, // CJK Compatibility Ideographs
+		{0xfe10, 0xfe19, 1}, // Vertical Forms
+		{0xfe30, 0xfe6f, 1}, // CJK Compatibility Forms, Small Form Variants
+		{0xff00, 0xff60, 1}, // Fullwidth Forms
+		{0xffe0, 0xffe6, 1},
+	},
+	R32: []unicode.Range32{
+		{0x1f300, 0x1f64f, 1}, // Miscellaneous Symbols and Pictographs, Emoticons
+		{0x1f900, 0x1f9ff, 1}, // Supplemental Symbols and Pictographs
+		{0x20000, 0x2fffd, 1}, // CJK Unified Ideographs Extension B and later
+		{0x30000, 0x3fffd, 1},
+	},
+}
+
+// runeWidth returns the number of cells which the character occupies in a
+// terminal, which is 2 for East Asian wide characters, 0 for combining marks
+// and control characters, and 1 for the others.
+func runeWidth(r rune) int {
+	switch {
+	case r < 0x20 || r == 0x7f:
+		return 0
+	case r < 0x1100:
+		if unicode.Is(unicode.Mn, r) {
+			return 0
+		}
+		return 1
+	case unicode.Is(wideChars, r):
+		return 2
+	case unicode.Is(unicode.Mn, r):
+		return 0
+	default:
+		return 1
+	}
+}
+
+// textWidth returns the number of cells which the text occupies in a
+// terminal.
+func textWidth(s string) int {
+	n := 0
+	for _, r := range s {
+		n += runeWidth(r)
+	}
+	return n
+}
+
+// isWideRune reports whether the character is an East Asian wide character,
+// before and after which a line can be broken even without white spaces.
+func isWideRune(r rune) bool {
+	return r >= 0x1100 && unicode.Is(wideChars, r)
+}
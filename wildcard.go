@@ -0,0 +1,78 @@
+// Copyright (C) 2023 Takayuki Sato. All Rights Reserved.
+// This program is free software under MIT License.
+// See the file LICENSE in this distribution for more details.
+
+package cliargdax
+
+import (
+	"github.com/sttk/cliargs"
+	"github.com/sttk/sabi/errs"
+)
+
+type /* error reasons */ (
+	// WildcardWithIgnoreUnknownOpts is the error reason which indicates that
+	// the wildcard option configuration of which name is "*" is configured
+	// while the tolerant mode for unknown options is enabled by
+	// DaxSrc#IgnoreUnknownOpts method, which conflict because the wildcard
+	// accepts unknown options and the tolerant mode removes them.
+	WildcardWithIgnoreUnknownOpts struct{}
+)
+
+// WildcardOpts is the method to retrieve the names of the options which are
+// given in command line arguments and are accepted only by the wildcard
+// option configuration of which name is "*", in the order of their first
+// occurrences.
+// When the wildcard is configured, options which are not configured are
+// accepted and recorded here, and no option name is suggested for them, but
+// the validations of the configured options, like required options and
+// choices, still run.
+// If no option configuration is given, all options are accepted as if only
+// the wildcard is configured, so they are also returned.
+// If there is no such option, this method returns an empty array.
+func (conn *DaxConn) WildcardOpts() []string {
+	conn.ds.parseLazily()
+	conn.ds.mutex.RLock()
+	defer conn.ds.mutex.RUnlock()
+	return append([]string{}, conn.ds.wildcardOpts...)
+}
+
+// hasWildcard reports whether the option configurations have the wildcard
+// configuration of which name is "*".
+func hasWildcard(optCfgs []cliargs.OptCfg) bool {
+	for _, cfg := range optCfgs {
+		if cfg.Name == "*" {
+			return true
+		}
+	}
+	return false
+}
+
+// checkWildcard checks that the wildcard configuration is not configured
+// while the tolerant mode for unknown options is enabled.
+func (r *parseResult) checkWildcard() errs.Err {
+	if r.mode.ignoreUnknown && hasWildcard(r.optCfgs) {
+		return errs.New(WildcardWithIgnoreUnknownOpts{})
+	}
+	return errs.Ok()
+}
+
+// collectWildcardOpts records the names of the options in the tokens which
+// are accepted only by the wildcard configuration.
+func (r *parseResult) collectWildcardOpts(
+	toks []argToken, optCfgs []cliargs.OptCfg,
+) {
+	if !hasWildcard(optCfgs) {
+		return
+	}
+	indexes := cfgIndexes(optCfgs)
+	seen := make(map[string]bool)
+	for _, tok := range toks {
+		if !tok.isOpt() || seen[tok.name] {
+			continue
+		}
+		if _, exists := indexes[tok.name]; !exists {
+			seen[tok.name] = true
+			r.wildcardOpts = append(r.wildcardOpts, tok.name)
+		}
+	}
+}
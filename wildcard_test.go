@@ -0,0 +1,84 @@
+package cliargdax_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/sttk/cliargdax"
+	"github.com/sttk/cliargs"
+)
+
+func TestWildcard_WildcardOpts(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "--foo", "--bar=1", "-x", "--foo", "--bar=2", "-v", "arg",
+	}, []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "foo"},
+		cliargs.OptCfg{Name: "verbose", Aliases: []string{"v"}},
+		cliargs.OptCfg{Name: "*"},
+	})
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.WildcardOpts(), []string{"bar", "x"})
+	assert.Equal(t, conn.Cmd().OptArgs("bar"), []string{"1", "2"})
+	assert.Equal(t, conn.UnknownOpts(), []string{})
+
+	ds = cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "--foo",
+	}, []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "foo"},
+	})
+	conn, err = setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.WildcardOpts(), []string{})
+
+	ds = cliargdax.NewDaxSrcWithArgs([]string{"app", "--foo", "-b"})
+	conn, err = setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.WildcardOpts(), []string{"foo", "b"})
+}
+
+func TestWildcard_validationsStillRun(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "--fmt=json", "--format=xml",
+	}, []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "format", HasArg: true},
+		cliargs.OptCfg{Name: "level", HasArg: true},
+		cliargs.OptCfg{Name: "*"},
+	}).Choices("format", "json", "yaml")
+	_, err := setupWithOptCfgs(t, ds)
+	assert.Equal(t, err.Reason(), cliargdax.OptionValueNotInChoices{
+		Option: "format", Value: "xml", Choices: []string{"json", "yaml"},
+	})
+
+	ds = cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "--fmt=json", "--format=json",
+	}, []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "format", HasArg: true},
+		cliargs.OptCfg{Name: "level", HasArg: true},
+		cliargs.OptCfg{Name: "*"},
+	}).RequiredOpts("level")
+	_, err = setupWithOptCfgs(t, ds)
+	assert.Equal(t, err.Reason(), cliargdax.RequiredOptionMissing{
+		Option: "level", Options: []string{"level"},
+	})
+}
+
+func TestWildcard_withIgnoreUnknownOpts(t *testing.T) {
+	ds := cliargdax.NewDaxSrcWithArgsAndOptCfgs([]string{
+		"app", "--foo",
+	}, []cliargs.OptCfg{
+		cliargs.OptCfg{Name: "foo"},
+		cliargs.OptCfg{Name: "*"},
+	}).IgnoreUnknownOpts(true)
+	_, err := setupWithOptCfgs(t, ds)
+	assert.Equal(t, err.Reason(), cliargdax.WildcardWithIgnoreUnknownOpts{})
+	assert.Equal(t, cliargdax.FormatError(err, nil),
+		"the wildcard option \"*\" cannot be configured when unknown options are ignored\n"+
+			"Try '--help' for more information.")
+
+	ds = cliargdax.NewDaxSrcWithArgs([]string{"app", "--foo"}).
+		IgnoreUnknownOpts(true)
+	conn, err := setupWithOptCfgs(t, ds)
+	assert.True(t, err.IsOk())
+	assert.Equal(t, conn.WildcardOpts(), []string{"foo"})
+}